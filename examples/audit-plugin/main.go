@@ -0,0 +1,112 @@
+// Command audit-plugin is an example of external audit rules for yaswag. Run it with
+//
+//	go build -o org-rules ./examples/audit-plugin
+//	yaswag audit --input ./swagger.yaml --rule-plugin ./org-rules
+//
+// yaswag writes a JSON request to stdin. {"action": "describe"} asks for the rules and
+// {"action": "check", "document": {...}} for the findings of a document. Plugins can be
+// written in any language that reads and writes JSON.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+type request struct {
+	Version  int               `json:"version"`
+	Action   string            `json:"action"`
+	Document *openapi.Document `json:"document"`
+}
+
+type rule struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+}
+
+type finding struct {
+	RuleID         string `json:"rule_id"`
+	Location       string `json:"location"`
+	Pointer        string `json:"pointer,omitempty"`
+	Message        string `json:"message"`
+	Recommendation string `json:"recommendation"`
+}
+
+type response struct {
+	Rules    []rule    `json:"rules,omitempty"`
+	Findings []finding `json:"findings,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+var rules = []rule{
+	{ID: "ORG_VERSIONED_PATH", Name: "Paths must start with a version", Severity: "warning"},
+	{ID: "ORG_OPERATION_TAGGED", Name: "Operations must have a tag", Severity: "info"},
+}
+
+func main() {
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		reply(response{Error: err.Error()})
+		return
+	}
+	switch req.Action {
+	case "describe":
+		reply(response{Rules: rules})
+	case "check":
+		reply(response{Findings: check(req.Document)})
+	default:
+		reply(response{Error: fmt.Sprintf("unknown action %q", req.Action)})
+	}
+}
+
+func check(doc *openapi.Document) []finding {
+	findings := []finding{}
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pointer := "/paths/" + strings.ReplaceAll(strings.ReplaceAll(path, "~", "~0"), "/", "~1")
+		if !strings.HasPrefix(path, "/v") {
+			findings = append(findings, finding{
+				RuleID:         "ORG_VERSIONED_PATH",
+				Location:       path,
+				Pointer:        pointer,
+				Message:        fmt.Sprintf("Path '%s' does not start with an API version", path),
+				Recommendation: "Prefix the path with its major version, such as /v1",
+			})
+		}
+		item := doc.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *openapi.Operation
+		}{{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete}, {"patch", item.Patch}} {
+			method, op := m.method, m.op
+			if op != nil && len(op.Tags) == 0 {
+				findings = append(findings, finding{
+					RuleID:         "ORG_OPERATION_TAGGED",
+					Location:       fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+					Pointer:        pointer + "/" + method,
+					Message:        "Operation has no tag",
+					Recommendation: "Tag the operation with the team that owns it",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func reply(resp response) {
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
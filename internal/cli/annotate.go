@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/annotate"
+)
+
+func (c *CLI) runAnnotate(args []string) error {
+	fs := flag.NewFlagSet("annotate", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	out := fs.String("out", "", "Directory to write the Go files to")
+	pkg := fs.String("package", "", "Package name of the Go files (default: the name of the output directory, or handlers)")
+	force := fs.Bool("force", false, "Overwrite files that already exist")
+	showHelp := fs.Bool("help", false, "Show help for annotate command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.AnnotateHelp())
+		return nil
+	}
+
+	if *out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	root, err := yamlnode.Parse(result.data)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	name := *pkg
+	if name == "" {
+		name = packageName(*out)
+	}
+	annotated, err := annotate.Node(root, annotate.WithPackage(name))
+	if err != nil {
+		return err
+	}
+	for _, w := range annotated.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	if err := annotated.Write(*out, *force); err != nil {
+		if !*force {
+			return fmt.Errorf("%w (use --force to overwrite)", err)
+		}
+		return err
+	}
+	fmt.Printf("Wrote %d files of package %s to %s\n", len(annotated.Files), annotated.Package, *out)
+	return nil
+}
+
+// packageName returns the name of a directory when it is a valid package name, or handlers.
+func packageName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "handlers"
+	}
+	name := strings.ToLower(filepath.Base(abs))
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return "handlers"
+		}
+	}
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return "handlers"
+	}
+	return name
+}
+
+func (c *CLI) AnnotateHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Write an OpenAPI specification as Go source with YaSwag annotations.\n\n")
+	help.WriteString("Every object schema becomes a struct with !model and !field annotations, and every\n")
+	help.WriteString("operation a handler stub with its route, !query, !path, !header, !body, !ok, !error\n")
+	help.WriteString("and !secure annotations. Running 'yaswag generate' on the files gives back the\n")
+	help.WriteString("paths, parameters, bodies, responses and models of the specification. What annotations\n")
+	help.WriteString("cannot express is changed or dropped with a warning on stderr, such as enum, pattern,\n")
+	help.WriteString("minimum and other validation keywords, readOnly, and formats other than those of the\n")
+	help.WriteString("Go types: an integer without a format gains format int32.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag annotate --input <spec> --out <dir> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --out <dir>       Directory to write the Go files to (created if missing)\n")
+	help.WriteString("  --package <name>  Package name (default: the directory name, or handlers)\n")
+	help.WriteString("  --force           Overwrite files that already exist\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Files:\n")
+	help.WriteString("  doc.go            package comment with !api, !info, !server, !tag and !security\n")
+	help.WriteString("  models.go         a struct per object schema\n")
+	help.WriteString("  <tag>.go          the handlers of the operations whose first tag is <tag>\n")
+	help.WriteString("  handlers.go       the handlers of untagged operations\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag annotate --input ./openapi.yaml --out ./handlers\n")
+	help.WriteString("  yaswag generate --source ./handlers --output ./openapi.yaml\n")
+	return help.String()
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/pkg/bundle"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+func (c *CLI) runBundle(args []string) error {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path, URL, or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	dereference := fs.Bool("dereference", false, "Inline every $ref instead of bundling external ones into components")
+	showHelp := fs.Bool("help", false, "Show help for bundle command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.BundleHelp())
+		return nil
+	}
+
+	var opts []bundle.Option
+	if *dereference {
+		opts = append(opts, bundle.WithDereference())
+	}
+	root, fromStdin, err := loadBundle(*input, opts)
+	if err != nil {
+		return err
+	}
+
+	outputFormat := c.determineOutputFormat(*format, *outputPath, *input, fromStdin || isURL(*input))
+	formatter := output.NewFormatter(output.Options{
+		Format: outputFormat,
+		Indent: *pretty,
+		Pretty: *pretty > 0,
+	})
+	data, err := formatter.FormatNode(root)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	return c.writeOutput(*outputPath, data, "Bundled specification")
+}
+
+// loadBundle bundles the input; relative $refs of stdin are resolved against the working directory.
+func loadBundle(input string, opts []bundle.Option) (*yaml.Node, bool, error) {
+	if isURL(input) {
+		root, err := bundle.URL(input, opts...)
+		return root, false, err
+	}
+	if input != "" && input != "-" {
+		root, err := bundle.File(input, opts...)
+		return root, false, err
+	}
+	stdinRes, err := readFromStdinOrFile(input, true)
+	if err != nil {
+		return nil, false, err
+	}
+	root, err := bundle.Data(stdinRes.data, "", opts...)
+	return root, true, err
+}
+
+func (c *CLI) BundleHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Bundle an OpenAPI specification split across several files into one.\n\n")
+	help.WriteString("External $refs (./schemas/user.yaml#/User, https://...) are resolved relative to\n")
+	help.WriteString("the file or URL they appear in. The values they refer to are copied into\n")
+	help.WriteString("components once, named after the referenced key or file, and the $refs point\n")
+	help.WriteString("at the copies. Referenced path items are copied in place.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag bundle --input <spec> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path, URL, or - for stdin (refs relative to the working directory)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --dereference     Replace every $ref, internal ones included, with the value it\n")
+	help.WriteString("                    refers to; fails on circular references\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag bundle --input ./api/openapi.yaml --output ./dist/openapi.yaml\n")
+	help.WriteString("  yaswag bundle --input ./api/openapi.yaml --dereference --format json\n")
+	help.WriteString("  yaswag bundle --input ./api/openapi.yaml | yaswag validate --input -\n")
+	return help.String()
+}
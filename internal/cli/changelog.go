@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/changelog"
+)
+
+func (c *CLI) runChangelog(args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	from := fs.String("from", "", "Previous specification: file path, URL, or git revision (ref or ref:path)")
+	to := fs.String("to", "", "New specification: file path, URL, or - for stdin")
+	outputFormat := fs.String("output-format", "markdown", "Output format: markdown or html")
+	title := fs.String("title", "", "Release title (default: info.version of the new specification)")
+	date := fs.String("date", time.Now().Format("2006-01-02"), "Release date; empty to leave it out")
+	appendPath := fs.String("append", "", "Add the release to this Markdown changelog file instead of printing it")
+	showHelp := fs.Bool("help", false, "Show help for changelog command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.ChangelogHelp())
+		return nil
+	}
+
+	if *from == "" {
+		return fmt.Errorf("--from is required")
+	}
+	if *outputFormat != "markdown" && *outputFormat != "html" {
+		return fmt.Errorf("unsupported output format: %s (want markdown or html)", *outputFormat)
+	}
+	if *appendPath != "" && *outputFormat != "markdown" {
+		return fmt.Errorf("--append requires markdown output")
+	}
+
+	toDoc, err := loadDiffRevision(*to)
+	if err != nil {
+		return err
+	}
+	fromDoc, err := loadDiffBase(*from, *to)
+	if err != nil {
+		return err
+	}
+	log := changelog.Build(fromDoc, toDoc, changelog.WithTitle(*title), changelog.WithDate(*date))
+
+	if *outputFormat == "html" {
+		page, err := changelog.FormatHTML(log)
+		if err != nil {
+			return fmt.Errorf("failed to format HTML: %w", err)
+		}
+		fmt.Print(page)
+		return nil
+	}
+	section := changelog.FormatMarkdown(log)
+	if *appendPath != "" {
+		if err := changelog.AddToFile(*appendPath, section); err != nil {
+			return err
+		}
+		fmt.Printf("Added %s to %s\n", log.Title, *appendPath)
+		return nil
+	}
+	fmt.Print(section)
+	return nil
+}
+
+func (c *CLI) ChangelogHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Generate a changelog between two versions of an OpenAPI specification.\n\n")
+	help.WriteString("Changes are grouped by tag into new, deprecated and removed endpoints, new and\n")
+	help.WriteString("removed fields, changed schemas and other changes. Breaking changes are marked.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag changelog --from <spec> --to <spec> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --from <spec>          Previous specification: file path, URL, or git revision.\n")
+	help.WriteString("                         A bare revision (v1.2.0) reads the --to file at that\n")
+	help.WriteString("                         revision; use ref:path for another path\n")
+	help.WriteString("  --to <spec>            New specification: file path, URL, or - for stdin\n")
+	help.WriteString("  --output-format <type> Output format: markdown or html (default: markdown)\n")
+	help.WriteString("  --title <text>         Release title (default: info.version of --to)\n")
+	help.WriteString("  --date <date>          Release date (default: today; empty to leave it out)\n")
+	help.WriteString("  --append <path>        Add the release to a Markdown changelog file, above the\n")
+	help.WriteString("                         previous releases; the file is created if missing\n")
+	help.WriteString("  --help                 Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag changelog --from ./v1.yaml --to ./v2.yaml\n")
+	help.WriteString("  yaswag changelog --from v1.2.0 --to ./openapi.yaml --append CHANGELOG.md\n")
+	help.WriteString("  yaswag changelog --from ./v1.yaml --to ./v2.yaml --output-format html > changes.html\n")
+	return help.String()
+}
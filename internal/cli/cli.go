@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/fathurrohman26/yaswag/internal/parser"
@@ -12,6 +13,7 @@ import (
 	"github.com/fathurrohman26/yaswag/pkg/mcp"
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 	"github.com/fathurrohman26/yaswag/pkg/output"
+	"github.com/fathurrohman26/yaswag/pkg/report"
 	"github.com/fathurrohman26/yaswag/pkg/swaggerui"
 	"github.com/fathurrohman26/yaswag/pkg/validator"
 )
@@ -60,13 +62,24 @@ func (c *CLI) Run() error {
 
 	// Command dispatcher
 	commands := map[string]func([]string) error{
-		"generate": c.runGenerate,
-		"validate": c.runValidate,
-		"format":   c.runFormat,
-		"serve":    c.runServe,
-		"editor":   c.runEditor,
-		"mcp":      c.runMCP,
-		"audit":    c.runAudit,
+		"generate":  c.runGenerate,
+		"validate":  c.runValidate,
+		"format":    c.runFormat,
+		"serve":     c.runServe,
+		"editor":    c.runEditor,
+		"mcp":       c.runMCP,
+		"audit":     c.runAudit,
+		"lint":      c.runLint,
+		"diff":      c.runDiff,
+		"changelog": c.runChangelog,
+		"bundle":    c.runBundle,
+		"split":     c.runSplit,
+		"merge":     c.runMerge,
+		"filter":    c.runFilter,
+		"convert":   c.runConvert,
+		"upgrade":   c.runUpgrade,
+		"annotate":  c.runAnnotate,
+		"export":    c.runExport,
 	}
 
 	if handler, ok := commands[cmd]; ok {
@@ -91,10 +104,17 @@ func (c *CLI) handleBuiltinCommand(cmd string) bool {
 
 func (c *CLI) runGenerate(args []string) error {
 	fs := flag.NewFlagSet("generate", flag.ExitOnError)
-	source := fs.String("source", ".", "Source directory to scan for annotations")
-	format := fs.String("format", "yaml", "Output format (json or yaml)")
-	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
-	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	var src sourceFlags
+	src.register(fs)
+	var out generateFlags
+	fs.StringVar(&out.format, "format", "yaml", "Output format (json or yaml)")
+	fs.StringVar(&out.outputPath, "output", "", "Output file path (empty for stdout)")
+	fs.StringVar(&out.outputDir, "output-dir", "", "Write the default spec and every group spec to this directory")
+	fs.StringVar(&out.group, "group", "", "Generate the spec of a single group (empty for the default spec)")
+	fs.IntVar(&out.pretty, "pretty", 2, "Indentation spaces for pretty printing")
+	fs.StringVar(&out.keyOrder, "key-order", string(output.KeyOrderAlphabetical), "Order of paths, responses, schemas and properties: alphabetical, source or canonical")
+	strict := fs.Bool("strict", false, "Fail when annotations have errors or warnings")
+	watchMode := fs.Bool("watch", false, "Watch the sources and regenerate when they change")
 	showHelp := fs.Bool("help", false, "Show help for generate command")
 
 	if err := fs.Parse(args); err != nil {
@@ -106,30 +126,84 @@ func (c *CLI) runGenerate(args []string) error {
 		return nil
 	}
 
-	openAPIDoc, err := c.parseAndGenerate(*source)
-	if err != nil {
+	if out.outputDir != "" && (out.outputPath != "" || out.group != "") {
+		return fmt.Errorf("--output-dir cannot be combined with --output or --group")
+	}
+	if *watchMode && out.outputPath == "" && out.outputDir == "" {
+		return fmt.Errorf("--watch requires --output or --output-dir")
+	}
+	if _, err := output.ParseKeyOrder(out.keyOrder); err != nil {
 		return err
 	}
 
-	data, err := c.formatOutput(openAPIDoc, *format, *pretty)
+	generate := func() ([]generatedSpec, error) {
+		p, err := c.parseSources(src.roots(), *strict, src.parserOptions()...)
+		if err != nil {
+			return nil, err
+		}
+		return c.renderSpecs(p, out)
+	}
+
+	specs, err := generate()
 	if err != nil {
 		return err
 	}
+	for _, spec := range specs {
+		if err := c.writeOutput(spec.path, spec.data, spec.label); err != nil {
+			return err
+		}
+	}
+
+	if *watchMode {
+		return c.watchGenerate(src.roots(), specs, generate)
+	}
+	return nil
+}
+
+// generateFlags holds the flags that control which specs generate emits and where.
+type generateFlags struct {
+	format     string
+	outputPath string
+	outputDir  string
+	group      string
+	pretty     int
+	keyOrder   string
+}
 
-	return c.writeOutput(*outputPath, data, "OpenAPI specification")
+// generatedSpec is a rendered spec together with its destination ("" for stdout).
+type generatedSpec struct {
+	path  string
+	label string
+	data  []byte
 }
 
-func (c *CLI) parseAndGenerate(source string) (*openapi.Document, error) {
-	p := parser.New()
-	if err := p.ParseDir(source); err != nil {
+// parseSources parses the annotations of all sources and reports their diagnostics.
+func (c *CLI) parseSources(sources []string, strict bool, opts ...parser.Option) (*parser.Parser, error) {
+	p := parser.New(opts...)
+	if err := p.ParseDirs(sources...); err != nil {
 		return nil, fmt.Errorf("failed to parse source: %w", err)
 	}
 
+	if err := c.reportDiagnostics(p.Diagnostics(), strict); err != nil {
+		return nil, err
+	}
+
 	spec := p.GetSpec()
-	if spec.Info == nil || spec.Info.Title == "" {
-		return nil, fmt.Errorf("no YaSwag annotations found in %s", source)
+	if (spec.Info == nil || spec.Info.Title == "") && len(p.Groups()) == 0 {
+		return nil, fmt.Errorf("no YaSwag annotations found in %s", strings.Join(sources, ", "))
 	}
+	return p, nil
+}
 
+// generateSpec generates the document of a group, or of the default spec when group is empty.
+func generateSpec(p *parser.Parser, group string) (*openapi.Document, error) {
+	if group != "" {
+		return p.GenerateGroup(group)
+	}
+	if spec := p.GetSpec(); spec.Info == nil || spec.Info.Title == "" {
+		return nil, fmt.Errorf("the default spec has no !info annotation, use --group or --output-dir (groups: %s)",
+			strings.Join(p.Groups(), ", "))
+	}
 	doc := p.Generate()
 	if doc == nil {
 		return nil, fmt.Errorf("failed to generate OpenAPI document")
@@ -137,16 +211,79 @@ func (c *CLI) parseAndGenerate(source string) (*openapi.Document, error) {
 	return doc, nil
 }
 
-func (c *CLI) formatOutput(doc *openapi.Document, format string, pretty int) ([]byte, error) {
+// renderSpecs renders the requested spec, or with --output-dir the default spec as openapi.<format>
+// and each group as <group>.<format>. The default spec is skipped in that case when it has no !info annotation.
+func (c *CLI) renderSpecs(p *parser.Parser, out generateFlags) ([]generatedSpec, error) {
+	if out.outputDir == "" {
+		data, err := c.renderSpec(p, out.group, out)
+		if err != nil {
+			return nil, err
+		}
+		return []generatedSpec{{path: out.outputPath, label: "OpenAPI specification", data: data}}, nil
+	}
+
+	outputFormat, err := output.ParseFormat(out.format)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(out.outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	names := p.Groups()
+	if spec := p.GetSpec(); spec.Info != nil && spec.Info.Title != "" {
+		names = append([]string{""}, names...)
+	}
+	var specs []generatedSpec
+	for _, name := range names {
+		data, err := c.renderSpec(p, name, out)
+		if err != nil {
+			return nil, err
+		}
+		file, label := "openapi", "OpenAPI specification"
+		if name != "" {
+			file, label = name, fmt.Sprintf("OpenAPI specification for group %s", name)
+		}
+		path := filepath.Join(out.outputDir, file+"."+string(outputFormat))
+		specs = append(specs, generatedSpec{path: path, label: label, data: data})
+	}
+	return specs, nil
+}
+
+func (c *CLI) renderSpec(p *parser.Parser, group string, out generateFlags) ([]byte, error) {
+	doc, err := generateSpec(p, group)
+	if err != nil {
+		return nil, err
+	}
+	return c.formatOutput(doc, out.format, out.pretty, out.keyOrder)
+}
+
+// reportDiagnostics prints annotation diagnostics to stderr, failing in strict mode.
+func (c *CLI) reportDiagnostics(diagnostics []parser.Diagnostic, strict bool) error {
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d.String())
+	}
+	if strict && len(diagnostics) > 0 {
+		return fmt.Errorf("%d annotation problem(s) found (--strict)", len(diagnostics))
+	}
+	return nil
+}
+
+func (c *CLI) formatOutput(doc *openapi.Document, format string, pretty int, keyOrder string) ([]byte, error) {
 	outputFormat, err := output.ParseFormat(format)
 	if err != nil {
 		return nil, err
 	}
+	order, err := output.ParseKeyOrder(keyOrder)
+	if err != nil {
+		return nil, err
+	}
 
 	formatter := output.NewFormatter(output.Options{
-		Format: outputFormat,
-		Indent: pretty,
-		Pretty: pretty > 0,
+		Format:   outputFormat,
+		Indent:   pretty,
+		Pretty:   pretty > 0,
+		KeyOrder: order,
 	})
 
 	data, err := formatter.Format(doc)
@@ -159,6 +296,7 @@ func (c *CLI) formatOutput(doc *openapi.Document, format string, pretty int) ([]
 func (c *CLI) runValidate(args []string) error {
 	fs := flag.NewFlagSet("validate", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin")
+	outputFormat := fs.String("output-format", "text", outputFormatUsage)
 	showHelp := fs.Bool("help", false, "Show help for validate command")
 
 	if err := fs.Parse(args); err != nil {
@@ -170,19 +308,42 @@ func (c *CLI) runValidate(args []string) error {
 		return nil
 	}
 
+	format, err := report.ParseFormat(*outputFormat)
+	if err != nil {
+		return err
+	}
+
 	v := validator.New()
 	result, err := c.validateInput(v, *input)
 	if err != nil {
 		return err
 	}
 
-	fmt.Print(validator.FormatResult(result))
+	if err := c.outputValidationResult(result, format); err != nil {
+		return err
+	}
 	if !result.Valid {
 		os.Exit(1)
 	}
 	return nil
 }
 
+func (c *CLI) outputValidationResult(result *validator.ValidationResult, format report.Format) error {
+	switch format {
+	case report.FormatText:
+		fmt.Print(validator.FormatResult(result))
+	case report.FormatJSON:
+		data, err := validator.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return c.writeReport(validator.ToReport(result), format)
+	}
+	return nil
+}
+
 func (c *CLI) validateInput(v *validator.Validator, input string) (*validator.ValidationResult, error) {
 	if isURL(input) {
 		return v.ValidateInput(input)
@@ -194,7 +355,11 @@ func (c *CLI) validateInput(v *validator.Validator, input string) (*validator.Va
 	}
 
 	if stdinRes.fromStdin {
-		return v.Validate(stdinRes.data)
+		result, err := v.Validate(stdinRes.data)
+		if result != nil {
+			result.Source = validator.StdinSource
+		}
+		return result, err
 	}
 	return v.ValidateInput(input)
 }
@@ -326,6 +491,10 @@ func (c *CLI) runServe(args []string) error {
 	fs := flag.NewFlagSet("serve", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin")
 	port := fs.Int("port", 8080, "Port to serve on")
+	var src sourceFlags
+	src.register(fs)
+	group := fs.String("group", "", "With --source, serve the spec of a single group")
+	watchMode := fs.Bool("watch", false, "With --source, regenerate on changes and reload open pages")
 	showHelp := fs.Bool("help", false, "Show help for serve command")
 
 	if err := fs.Parse(args); err != nil {
@@ -338,6 +507,15 @@ func (c *CLI) runServe(args []string) error {
 	}
 
 	server := swaggerui.NewServer(*port)
+	if len(src.sources) > 0 {
+		if *input != "" {
+			return fmt.Errorf("--input and --source cannot be combined")
+		}
+		return c.serveSources(server, &src, *group, *watchMode)
+	}
+	if *watchMode {
+		return fmt.Errorf("--watch requires --source")
+	}
 	if err := c.setServerSpec(server, *input, true); err != nil {
 		return err
 	}
@@ -456,7 +634,18 @@ func (c *CLI) printMCPValidationResult(result *validator.ValidationResult) {
 func (c *CLI) runAudit(args []string) error {
 	fs := flag.NewFlagSet("audit", flag.ExitOnError)
 	input := fs.String("input", "", "Input file path, URL, or - for stdin")
-	format := fs.String("format", "text", "Output format: text or json (default: text)")
+	var outputFormat string
+	fs.StringVar(&outputFormat, "output-format", "text", outputFormatUsage)
+	fs.StringVar(&outputFormat, "format", "text", "Alias for --output-format")
+	configPath := fs.String("config", "", "Audit config file (default: "+audit.DefaultConfigFile+" if present)")
+	failOn := fs.String("fail-on", "error", "Exit with 1 on findings of this severity or above: error, warning, info or none")
+	baselinePath := fs.String("baseline", "", "Baseline file: report only findings not recorded in it (created if missing)")
+	updateBaseline := fs.Bool("update-baseline", false, "Rewrite the baseline file with the current findings")
+	var pluginCommands []string
+	fs.Func("rule-plugin", "Command of an external rule plugin (repeatable)", func(s string) error {
+		pluginCommands = append(pluginCommands, s)
+		return nil
+	})
 	showHelp := fs.Bool("help", false, "Show help for audit command")
 
 	if err := fs.Parse(args); err != nil {
@@ -468,13 +657,118 @@ func (c *CLI) runAudit(args []string) error {
 		return nil
 	}
 
-	auditor := audit.New()
+	format, err := report.ParseFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	failAt, err := parseFailOn(*failOn)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadAuditConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	plugins, err := loadRulePlugins(pluginCommands)
+	if err != nil {
+		return err
+	}
+	var pluginRules []audit.Rule
+	for _, p := range plugins {
+		pluginRules = append(pluginRules, p.Rules()...)
+	}
+
+	auditor := audit.New(audit.WithConfig(cfg), audit.WithRules(pluginRules...))
+	if cfg != nil {
+		if err := cfg.Check(auditor.Rules()); err != nil {
+			return err
+		}
+	}
+	if *updateBaseline && *baselinePath == "" {
+		return fmt.Errorf("--update-baseline requires --baseline")
+	}
 	result, err := c.auditInput(auditor, *input)
 	if err != nil {
 		return err
 	}
+	for _, p := range plugins {
+		if err := p.Err(); err != nil {
+			return err
+		}
+	}
+	if *baselinePath != "" {
+		if err := applyBaseline(result, *baselinePath, *updateBaseline); err != nil {
+			return err
+		}
+	}
 
-	return c.outputAuditResult(result, *format)
+	if err := c.outputAuditResult(result, format); err != nil {
+		return err
+	}
+	if failAt != "" && result.HasFindings(failAt) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// parseFailOn parses the --fail-on flag of audit; "none" never fails and returns "".
+func parseFailOn(s string) (audit.Severity, error) {
+	if strings.EqualFold(s, "none") {
+		return "", nil
+	}
+	return audit.ParseSeverity(s)
+}
+
+// loadRulePlugins starts each plugin command, split on spaces into a program and its arguments.
+func loadRulePlugins(commands []string) ([]*audit.ExecPlugin, error) {
+	plugins := make([]*audit.ExecPlugin, 0, len(commands))
+	for _, command := range commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty --rule-plugin command")
+		}
+		p, err := audit.LoadExecPlugin(fields[0], fields[1:]...)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// applyBaseline leaves only the findings of result that are not in the baseline file. The file
+// is written with the current findings when it does not exist yet or update is set.
+func applyBaseline(result *audit.AuditResult, path string, update bool) error {
+	if !update {
+		if _, err := os.Stat(path); err == nil {
+			baseline, err := audit.LoadBaseline(path)
+			if err != nil {
+				return err
+			}
+			baseline.Apply(result)
+			return nil
+		}
+	}
+	baseline := audit.NewBaseline(result)
+	if err := baseline.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Recorded %d finding(s) in baseline %s\n", len(baseline.Findings), path)
+	baseline.Apply(result)
+	return nil
+}
+
+// loadAuditConfig loads the audit config given on the command line or the config file of the
+// working directory. It returns nil when there is neither.
+func loadAuditConfig(path string) (*audit.Config, error) {
+	if path == "" {
+		if _, err := os.Stat(audit.DefaultConfigFile); err != nil {
+			return nil, nil
+		}
+		path = audit.DefaultConfigFile
+	}
+	return audit.LoadConfig(path)
 }
 
 func (c *CLI) auditInput(auditor *audit.Auditor, input string) (*audit.AuditResult, error) {
@@ -493,23 +787,18 @@ func (c *CLI) auditInput(auditor *audit.Auditor, input string) (*audit.AuditResu
 	return auditor.AuditFile(input)
 }
 
-func (c *CLI) outputAuditResult(result *audit.AuditResult, format string) error {
-	switch strings.ToLower(format) {
-	case "json":
+func (c *CLI) outputAuditResult(result *audit.AuditResult, format report.Format) error {
+	switch format {
+	case report.FormatText:
+		fmt.Print(audit.FormatText(result))
+	case report.FormatJSON:
 		data, err := audit.FormatJSON(result)
 		if err != nil {
 			return fmt.Errorf("failed to format JSON: %w", err)
 		}
 		fmt.Println(string(data))
 	default:
-		fmt.Print(audit.FormatText(result))
-	}
-
-	// Exit with non-zero if there are error-level findings
-	for _, f := range result.Findings {
-		if f.Severity == audit.SeverityError {
-			os.Exit(1)
-		}
+		return c.writeReport(audit.ToReport(result), format)
 	}
 	return nil
 }
@@ -528,10 +817,21 @@ func (c *CLI) Help() string {
 	help.WriteString("  generate    Generate OpenAPI specification from Go annotations\n")
 	help.WriteString("  validate    Validate an existing OpenAPI specification\n")
 	help.WriteString("  format      Format an OpenAPI specification file\n")
+	help.WriteString("  bundle      Bundle a multi-file OpenAPI specification into one file\n")
+	help.WriteString("  split       Split an OpenAPI specification into one file per path and component\n")
+	help.WriteString("  merge       Merge several OpenAPI specifications into one\n")
+	help.WriteString("  filter      Reduce an OpenAPI specification by tag, path or extension\n")
+	help.WriteString("  convert     Convert an OpenAPI specification between versions 3.0, 3.1 and 3.2\n")
+	help.WriteString("  upgrade     Upgrade a Swagger 2.0 specification to OpenAPI 3.x\n")
+	help.WriteString("  annotate    Write an OpenAPI specification as Go source with annotations\n")
+	help.WriteString("  export      Export operations as Postman, HAR, .http or curl requests\n")
 	help.WriteString("  serve       Serve OpenAPI specification with Swagger UI\n")
 	help.WriteString("  editor      Launch Swagger Editor for creating/editing specifications\n")
 	help.WriteString("  mcp         Start MCP server for AI assistant integration\n")
 	help.WriteString("  audit       Perform security audit on OpenAPI specification\n")
+	help.WriteString("  lint        Lint OpenAPI specification against a configurable ruleset\n")
+	help.WriteString("  diff        Detect breaking changes between two OpenAPI specifications\n")
+	help.WriteString("  changelog   Generate a changelog between two specification versions\n")
 	help.WriteString("  version     Show version information\n")
 	help.WriteString("  help        Show this help message\n\n")
 	help.WriteString("Use 'yaswag [command] --help' for more information about a command.\n")
@@ -544,14 +844,38 @@ func (c *CLI) GenerateHelp() string {
 	help.WriteString("Usage:\n")
 	help.WriteString("  yaswag generate [options]\n\n")
 	help.WriteString("Options:\n")
-	help.WriteString("  --source <path>   Source directory to scan for annotations (default: .)\n")
+	help.WriteString("  --source <path>   Source directory to scan for annotations, repeatable (default: .)\n")
+	help.WriteString("  --include <glob>  Only parse matching files, relative to the source, repeatable\n")
+	help.WriteString("  --exclude <glob>  Skip matching files and directories, repeatable\n")
+	help.WriteString("  --tags <list>     Comma-separated build tags for //go:build constraints\n")
 	help.WriteString("  --format <type>   Output format: json or yaml (default: yaml)\n")
 	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --group <name>    Generate the spec of a single group (default: the ungrouped spec)\n")
+	help.WriteString("  --output-dir <dir> Write the default spec and every group spec to a directory\n")
 	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --key-order <order> Order of paths, response codes, schemas and properties:\n")
+	help.WriteString("                    alphabetical (default), source (as annotated) or canonical (fields\n")
+	help.WriteString("                    in the order of the OpenAPI specification, paths grouped by\n")
+	help.WriteString("                    resource, response codes by status class, default last)\n")
+	help.WriteString("  --strict          Fail when annotations have errors or warnings\n")
+	help.WriteString("  --watch           Regenerate whenever the sources change (needs --output or --output-dir)\n")
+	help.WriteString("  --workers <n>     Number of files parsed concurrently (default: all CPUs)\n")
+	help.WriteString("  --cache-dir <dir> Cache per-file parse results; unchanged files are skipped\n")
 	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Annotation problems (unknown or malformed annotations, duplicate operationIds,\n")
+	help.WriteString("unknown schemas or security schemes) are printed to stderr as file:line:col.\n\n")
+	help.WriteString("Groups are declared with group=name on !api, routes and !model; ungrouped models\n")
+	help.WriteString("are shared by every group and ungrouped routes only go to the default spec.\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag generate --source ./api --format yaml --output ./swagger.yaml\n")
 	help.WriteString("  yaswag generate --source . --format json\n")
+	help.WriteString("  yaswag generate --source ./api --strict\n")
+	help.WriteString("  yaswag generate --source . --cache-dir .yaswag-cache\n")
+	help.WriteString("  yaswag generate --source . --group admin --output ./admin.yaml\n")
+	help.WriteString("  yaswag generate --source . --output-dir ./specs\n")
+	help.WriteString("  yaswag generate --source . --output ./swagger.yaml --watch\n")
+	help.WriteString("  yaswag generate --source . --key-order canonical --output ./swagger.yaml\n")
+	help.WriteString("  yaswag generate --source ./cmd/billing --source ./internal --exclude '**/mocks/**' --tags billing\n")
 	return help.String()
 }
 
@@ -563,9 +887,11 @@ func (c *CLI) ValidateHelp() string {
 	help.WriteString("  <command> | yaswag validate\n\n")
 	help.WriteString("Options:\n")
 	help.WriteString("  --input <path>    Input file path, URL, or - for stdin\n")
+	help.WriteString("  --output-format <type> Output format: text, json, sarif, junit or github (default: text)\n")
 	help.WriteString("  --help            Show this help message\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag validate --input ./swagger.yaml\n")
+	help.WriteString("  yaswag validate --input ./swagger.yaml --output-format sarif > validate.sarif\n")
 	help.WriteString("  yaswag validate --input https://petstore3.swagger.io/api/v3/openapi.json\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag validate\n")
 	help.WriteString("  cat swagger.yaml | yaswag validate\n")
@@ -601,9 +927,15 @@ func (c *CLI) ServeHelp() string {
 	help.WriteString("Options:\n")
 	help.WriteString("  --input <path>    Input file path, URL, or - for stdin\n")
 	help.WriteString("  --port <n>        Port to serve on (default: 8080)\n")
+	help.WriteString("  --source <path>   Generate the spec from Go annotations instead of --input, repeatable\n")
+	help.WriteString("  --group <name>    With --source, serve the spec of a single group\n")
+	help.WriteString("  --watch           With --source, regenerate on changes and reload open pages\n")
 	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("The --include, --exclude, --tags, --workers and --cache-dir options of generate\n")
+	help.WriteString("also apply with --source.\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag serve --input ./swagger.yaml\n")
+	help.WriteString("  yaswag serve --source ./ --watch\n")
 	help.WriteString("  yaswag serve --input ./swagger.yaml --port 9090\n")
 	help.WriteString("  yaswag serve --input https://example.com/api/swagger.yaml\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag serve\n")
@@ -681,20 +1013,60 @@ func (c *CLI) AuditHelp() string {
 	help.WriteString("  - API keys in query parameters\n")
 	help.WriteString("  - OAuth URLs not using HTTPS\n")
 	help.WriteString("  - Deprecated endpoints without security\n")
-	help.WriteString("  - OAuth scopes referenced but not defined\n\n")
+	help.WriteString("  - OAuth scopes referenced but not defined\n")
+	help.WriteString("  - OWASP API Top 10 risks: unbounded inputs, integer IDs, missing 401/403\n")
+	help.WriteString("    and 429 responses, plain HTTP servers and basic auth, open\n")
+	help.WriteString("    additionalProperties, exposed sensitive fields and bearer token setup\n\n")
 	help.WriteString("Usage:\n")
 	help.WriteString("  yaswag audit [options]\n")
 	help.WriteString("  <command> | yaswag audit\n\n")
 	help.WriteString("Options:\n")
 	help.WriteString("  --input <path>    Input file path, URL, or - for stdin\n")
-	help.WriteString("  --format <type>   Output format: text or json (default: text)\n")
+	help.WriteString("  --output-format <type> Output format: text, json, sarif, junit or github (default: text)\n")
+	help.WriteString("  --format <type>   Alias for --output-format\n")
+	help.WriteString("  --config <path>   Audit config file (default: " + audit.DefaultConfigFile + " if present)\n")
+	help.WriteString("  --fail-on <level> Exit with 1 on findings of this severity or above:\n")
+	help.WriteString("                    error, warning, info or none (default: error)\n")
+	help.WriteString("  --baseline <path> Report only findings not recorded in this baseline file;\n")
+	help.WriteString("                    the file is created from the current findings if missing\n")
+	help.WriteString("  --update-baseline Rewrite the baseline file with the current findings\n")
+	help.WriteString("  --rule-plugin <command>\n")
+	help.WriteString("                    Run the rules of an external plugin (repeatable)\n")
 	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Config File:\n")
+	help.WriteString("  rules:\n")
+	help.WriteString("    DEPRECATED_NO_SECURITY: off     # disable a rule\n")
+	help.WriteString("    UNPROTECTED_WRITE: error        # override its severity\n")
+	help.WriteString("  ignore:\n")
+	help.WriteString("    - rule: UNPROTECTED_WRITE       # or * for every rule\n")
+	help.WriteString("      location: POST /webhooks      # or pointer: /paths/~1webhooks/post\n")
+	help.WriteString("      reason: signed by the sender\n\n")
+	help.WriteString("  Findings can also be suppressed in the specification itself with\n")
+	help.WriteString("  x-yaswag-ignore: [RULE_ID] on an operation or any object that contains them.\n\n")
+	help.WriteString("Baselines:\n")
+	help.WriteString("  Each finding has a fingerprint: its rule and a hash of its location and JSON\n")
+	help.WriteString("  pointer, with array elements named by what they are (a parameter by name and\n")
+	help.WriteString("  location, a scope by its value) rather than their index. Line numbers and\n")
+	help.WriteString("  messages are left out, so moving or rewording things keeps fingerprints. With\n")
+	help.WriteString("  --baseline, findings whose fingerprint is in the file are left out, so only\n")
+	help.WriteString("  new ones fail the build.\n\n")
+	help.WriteString("Rule Plugins:\n")
+	help.WriteString("  A plugin is a program that reads a JSON request from stdin and writes a\n")
+	help.WriteString("  JSON response to stdout. {\"action\": \"describe\"} asks for its rules\n")
+	help.WriteString("  ({\"rules\": [{\"id\", \"name\", \"severity\"}]}); {\"action\": \"check\",\n")
+	help.WriteString("  \"document\": {...}} asks for findings ({\"findings\": [{\"rule_id\",\n")
+	help.WriteString("  \"location\", \"pointer\", \"message\", \"recommendation\"}]}). Plugin rules\n")
+	help.WriteString("  can be configured and ignored like built-in ones.\n\n")
 	help.WriteString("Exit Codes:\n")
-	help.WriteString("  0    No ERROR-level issues found\n")
-	help.WriteString("  1    ERROR-level issues found\n\n")
+	help.WriteString("  0    No issues at or above the --fail-on severity\n")
+	help.WriteString("  1    Issues at or above the --fail-on severity found\n\n")
 	help.WriteString("Examples:\n")
 	help.WriteString("  yaswag audit --input ./swagger.yaml\n")
 	help.WriteString("  yaswag audit --input ./swagger.yaml --format json\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --output-format github\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --config ./audit.yaml --fail-on warning\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --baseline audit-baseline.json\n")
+	help.WriteString("  yaswag audit --input ./swagger.yaml --rule-plugin ./org-rules\n")
 	help.WriteString("  yaswag audit --input https://petstore3.swagger.io/api/v3/openapi.json\n")
 	help.WriteString("  yaswag generate --source ./api | yaswag audit\n")
 	help.WriteString("  cat swagger.yaml | yaswag audit\n")
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+func (c *CLI) runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	to := fs.String("to", "", "Target OpenAPI version: 3.0, 3.1, 3.2 or a full version such as 3.0.3")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	showHelp := fs.Bool("help", false, "Show help for convert command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.ConvertHelp())
+		return nil
+	}
+
+	if *to == "" {
+		return fmt.Errorf("--to is required")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	root, err := yamlnode.Parse(result.data)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	warnings, err := openapi.ConvertNode(root, *to)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	formatter := output.NewFormatter(output.Options{
+		Format: c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin),
+		Indent: *pretty,
+		Pretty: *pretty > 0,
+	})
+	data, err := formatter.FormatNode(root)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	return c.writeOutput(*outputPath, data, "Converted specification")
+}
+
+func (c *CLI) ConvertHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Convert an OpenAPI specification between versions 3.0, 3.1 and 3.2.\n\n")
+	help.WriteString("3.0 to 3.1: nullable becomes a \"null\" type, a schema example becomes examples, and\n")
+	help.WriteString("boolean exclusiveMinimum/exclusiveMaximum become the bound itself.\n")
+	help.WriteString("3.1 to 3.0: the reverse; type arrays become nullable or anyOf, const becomes enum,\n")
+	help.WriteString("$ref siblings move to allOf and components.pathItems are inlined. What 3.0 cannot\n")
+	help.WriteString("represent, such as webhooks or prefixItems, is removed with a warning on stderr.\n")
+	help.WriteString("3.2 to 3.1 removes what 3.2 added, such as query operations, with a warning.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag convert --input <spec> --to <version> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --to <version>    Target version: 3.0, 3.1, 3.2 or a full version such as 3.0.3\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag convert --input ./openapi.yaml --to 3.1 --output ./openapi-3.1.yaml\n")
+	help.WriteString("  yaswag generate --source . | yaswag convert --input - --to 3.0\n")
+	return help.String()
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/diff"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func (c *CLI) runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	base := fs.String("base", "", "Base specification: file path, URL, or git revision (ref or ref:path)")
+	input := fs.String("input", "", "Revised specification: file path, URL, or - for stdin")
+	outputFormat := fs.String("output-format", "text", "Output format: text, json or markdown")
+	failOn := fs.String("fail-on", "breaking", "Exit with 1 on changes of this level or above: breaking, non-breaking, info or none")
+	showHelp := fs.Bool("help", false, "Show help for diff command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.DiffHelp())
+		return nil
+	}
+
+	if *base == "" {
+		return fmt.Errorf("--base is required")
+	}
+	if *outputFormat != "text" && *outputFormat != "json" && *outputFormat != "markdown" {
+		return fmt.Errorf("unsupported output format: %s (want text, json or markdown)", *outputFormat)
+	}
+	var failAt diff.Level
+	if !strings.EqualFold(*failOn, "none") {
+		level, err := diff.ParseLevel(*failOn)
+		if err != nil {
+			return err
+		}
+		failAt = level
+	}
+
+	revisionDoc, err := loadDiffRevision(*input)
+	if err != nil {
+		return err
+	}
+	baseDoc, err := loadDiffBase(*base, *input)
+	if err != nil {
+		return err
+	}
+
+	result := diff.Compare(baseDoc, revisionDoc)
+	result.Base = *base
+	result.Revision = *input
+	if result.Revision == "" || result.Revision == "-" {
+		result.Revision = "stdin"
+	}
+
+	if err := outputDiffResult(result, *outputFormat); err != nil {
+		return err
+	}
+	if failAt != "" && result.HasChanges(failAt) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func loadDiffRevision(input string) (*openapi.Document, error) {
+	if isURL(input) {
+		return diff.LoadURL(input)
+	}
+	stdinRes, err := readFromStdinOrFile(input, true)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Parse(stdinRes.data)
+}
+
+// loadDiffBase loads the base specification from a URL, a file, or a git revision. A bare
+// revision such as origin/main refers to the input file as of that revision.
+func loadDiffBase(base, input string) (*openapi.Document, error) {
+	if isURL(base) {
+		return diff.LoadURL(base)
+	}
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		return diff.LoadFile(base)
+	}
+
+	dir, object := ".", base
+	if !strings.Contains(base, ":") {
+		if input == "" || input == "-" || isURL(input) {
+			return nil, fmt.Errorf("--base %s is not a file; give the path as %s:<path> to compare against a git revision", base, base)
+		}
+		root, path, err := repoPath(input)
+		if err != nil {
+			return nil, err
+		}
+		dir, object = root, base+":"+path
+	}
+	data, err := gitShow(dir, object)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Parse(data)
+}
+
+// repoPath returns the root of the git repository a file is in, and the path of the file relative
+// to it as git show takes it after a revision. The repository is the one of the file's directory,
+// not of the working directory.
+func repoPath(path string) (root, rel string, err error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	out, err := exec.Command("git", "-C", filepath.Dir(abs), "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find the git repository of %s: %w", path, err)
+	}
+	root = strings.TrimSpace(string(out))
+	// Symlinks are resolved on both sides, as git reports the real path of the repository
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	rel, err = filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", "", fmt.Errorf("%s is not in the git repository at %s", path, root)
+	}
+	return root, filepath.ToSlash(rel), nil
+}
+
+// gitShow returns the contents of a git object such as origin/main:openapi.yaml, read from the
+// repository at dir.
+func gitShow(dir, object string) ([]byte, error) {
+	cmd := exec.Command("git", "-C", dir, "show", object)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to read %s from git: %s", object, msg)
+		}
+		return nil, fmt.Errorf("failed to read %s from git: %w", object, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func outputDiffResult(result *diff.Result, format string) error {
+	switch format {
+	case "json":
+		data, err := diff.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(diff.FormatMarkdown(result))
+	default:
+		fmt.Print(diff.FormatText(result))
+	}
+	return nil
+}
+
+func (c *CLI) DiffHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Compare two OpenAPI specifications and classify every change as breaking,\n")
+	help.WriteString("non-breaking or informational for existing clients.\n\n")
+	help.WriteString("Breaking changes include removed endpoints, parameters and response properties,\n")
+	help.WriteString("new required parameters and request properties, narrowed enums and limits in\n")
+	help.WriteString("requests, changed types and formats, and authentication added to an endpoint.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag diff --base <spec> --input <spec> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --base <spec>          Base specification: file path, URL, or git revision.\n")
+	help.WriteString("                         A bare revision (origin/main) reads the --input file\n")
+	help.WriteString("                         at that revision; use ref:path for another path\n")
+	help.WriteString("  --input <spec>         Revised specification: file path, URL, or - for stdin\n")
+	help.WriteString("  --output-format <type> Output format: text, json or markdown (default: text)\n")
+	help.WriteString("  --fail-on <level>      Exit with 1 on changes of this level or above:\n")
+	help.WriteString("                         breaking, non-breaking, info or none (default: breaking)\n")
+	help.WriteString("  --help                 Show this help message\n\n")
+	help.WriteString("Exit Codes:\n")
+	help.WriteString("  0    No changes at or above the --fail-on level\n")
+	help.WriteString("  1    Changes at or above the --fail-on level found\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag diff --base ./v1.yaml --input ./v2.yaml\n")
+	help.WriteString("  yaswag diff --base origin/main --input ./openapi.yaml\n")
+	help.WriteString("  yaswag diff --base v1.2.0:api/openapi.yaml --input ./api/openapi.yaml --output-format markdown\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag diff --base origin/main:openapi.yaml\n")
+	return help.String()
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/export"
+)
+
+func (c *CLI) runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	to := fs.String("to", "", "Export format: postman, har, http or curl")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	showHelp := fs.Bool("help", false, "Show help for export command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.ExportHelp())
+		return nil
+	}
+
+	if *to == "" {
+		return fmt.Errorf("--to is required")
+	}
+	format, err := export.ParseFormat(*to)
+	if err != nil {
+		return err
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	root, err := yamlnode.Parse(result.data)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	data, err := export.Node(root, format, export.WithVersion(c.info.version))
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(*outputPath, data, "Exported requests")
+}
+
+func (c *CLI) ExportHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Export the operations of an OpenAPI specification as requests for API clients.\n\n")
+	help.WriteString("Every operation becomes a request with its path, query, header and cookie parameters,\n")
+	help.WriteString("credentials for its security schemes and a body generated from its schema. Values\n")
+	help.WriteString("come from the examples and defaults of the specification, or placeholders by type.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag export --input <spec> --to <format> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --to <format>     Export format: postman, har, http or curl\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Formats:\n")
+	help.WriteString("  postman           Postman collection v2.1: a folder per tag, the server URL as the\n")
+	help.WriteString("                    baseUrl variable and the security schemes as auth, with their\n")
+	help.WriteString("                    secrets as collection variables\n")
+	help.WriteString("  har               HTTP Archive 1.2: an entry per operation with an example request\n")
+	help.WriteString("                    and success response, secrets written as <scheme> placeholders\n")
+	help.WriteString("  http              .http file for the VS Code REST Client and JetBrains HTTP client,\n")
+	help.WriteString("                    with the server URL and credentials as file variables\n")
+	help.WriteString("  curl              Shell script with a curl command per operation, credentials read\n")
+	help.WriteString("                    from environment variables such as $API_KEY\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag export --input ./openapi.yaml --to postman --output ./collection.json\n")
+	help.WriteString("  yaswag export --input ./openapi.yaml --to har --output ./requests.har\n")
+	help.WriteString("  yaswag export --input ./openapi.yaml --to http --output ./api.http\n")
+	help.WriteString("  yaswag export --input ./openapi.yaml --to curl --output ./smoke.sh && API_KEY=secret sh ./smoke.sh\n")
+	return help.String()
+}
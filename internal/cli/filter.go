@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/filter"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+func (c *CLI) runFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	var tags, excludeTags, paths, excludeX stringList
+	input := fs.String("input", "", "Input file path, URL, or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	fs.Var(&tags, "tags", "Keep only operations with one of these tags (repeatable, comma-separated)")
+	fs.Var(&excludeTags, "exclude-tags", "Remove operations with one of these tags (repeatable, comma-separated)")
+	fs.Var(&paths, "paths", "Keep only paths matching one of these globs, such as /v2/** (repeatable, comma-separated)")
+	fs.Var(&excludeX, "exclude-x", "Remove everything marked with this extension, such as x-internal (repeatable, comma-separated)")
+	keepUnused := fs.Bool("keep-unused", false, "Keep components that are no longer referenced")
+	showHelp := fs.Bool("help", false, "Show help for filter command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.FilterHelp())
+		return nil
+	}
+
+	root, fromStdin, err := loadBundle(*input, nil)
+	if err != nil {
+		return err
+	}
+
+	opts := []filter.Option{
+		filter.WithTags(tags...),
+		filter.WithExcludeTags(excludeTags...),
+		filter.WithPaths(paths...),
+		filter.WithExcludeExtensions(excludeX...),
+	}
+	if *keepUnused {
+		opts = append(opts, filter.WithKeepUnused())
+	}
+	filtered, err := filter.Node(root, opts...)
+	if err != nil {
+		return err
+	}
+
+	formatter := output.NewFormatter(output.Options{
+		Format: c.determineOutputFormat(*format, *outputPath, *input, fromStdin || isURL(*input)),
+		Indent: *pretty,
+		Pretty: *pretty > 0,
+	})
+	data, err := formatter.FormatNode(filtered)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	return c.writeOutput(*outputPath, data, "Filtered specification")
+}
+
+func (c *CLI) FilterHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Produce a reduced OpenAPI specification, such as the public subset of an internal one.\n\n")
+	help.WriteString("Operations are kept when they match the tag and path filters; path items left without\n")
+	help.WriteString("operations are removed. Components, security schemes and tags that only the removed\n")
+	help.WriteString("parts referred to are pruned; those that were never referenced are kept.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag filter --input <spec> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>          Input file path, URL, or - for stdin\n")
+	help.WriteString("  --output <path>         Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>         Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>            Indentation spaces (default: 2)\n")
+	help.WriteString("  --tags <tags>           Keep only operations with at least one of the tags\n")
+	help.WriteString("  --exclude-tags <tags>   Remove operations with any of the tags, and the tags\n")
+	help.WriteString("  --paths <globs>         Keep only paths matching a glob; * matches within a segment,\n")
+	help.WriteString("                          ** any number of segments\n")
+	help.WriteString("  --exclude-x <name>      Remove operations, parameters, properties, components and\n")
+	help.WriteString("                          anything else marked with the extension (e.g. x-internal: true)\n")
+	help.WriteString("  --keep-unused           Keep components that are no longer referenced\n")
+	help.WriteString("  --help                  Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag filter --input ./openapi.yaml --tags public --exclude-x x-internal --paths '/v2/**'\n")
+	help.WriteString("  yaswag filter --input ./openapi.yaml --exclude-tags admin --output ./public.yaml\n")
+	return help.String()
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+)
+
+// stringList is a flag that can be repeated; each value may also hold a comma-separated list.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
+
+// sourceFlags holds the flags that control which Go files are parsed and how.
+type sourceFlags struct {
+	sources  stringList
+	include  stringList
+	exclude  stringList
+	tags     stringList
+	workers  int
+	cacheDir string
+}
+
+// register adds the source flags to fs.
+func (f *sourceFlags) register(fs *flag.FlagSet) {
+	fs.Var(&f.sources, "source", "Source directory to scan for annotations (repeatable, default: .)")
+	fs.Var(&f.include, "include", "Only parse files matching this glob, relative to the source (repeatable)")
+	fs.Var(&f.exclude, "exclude", "Skip files and directories matching this glob, relative to the source (repeatable)")
+	fs.Var(&f.tags, "tags", "Comma-separated build tags; files are filtered by their //go:build constraints")
+	fs.IntVar(&f.workers, "workers", 0, "Number of files parsed concurrently (0 uses all CPUs)")
+	fs.StringVar(&f.cacheDir, "cache-dir", "", "Directory for the per-file parse cache (empty disables caching)")
+}
+
+// roots returns the source directories, defaulting to the current directory.
+func (f *sourceFlags) roots() []string {
+	if len(f.sources) == 0 {
+		return []string{"."}
+	}
+	return f.sources
+}
+
+// parserOptions converts the flags into parser options.
+func (f *sourceFlags) parserOptions() []parser.Option {
+	return []parser.Option{
+		parser.WithWorkers(f.workers),
+		parser.WithCacheDir(f.cacheDir),
+		parser.WithInclude(f.include...),
+		parser.WithExclude(f.exclude...),
+		parser.WithBuildTags(f.tags...),
+	}
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/lint"
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+func (c *CLI) runLint(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path, URL, or - for stdin")
+	rulesetPath := fs.String("ruleset", "", "Ruleset file (default: "+lint.DefaultRulesetFile+" if present, else the recommended ruleset)")
+	outputFormat := fs.String("output-format", "text", outputFormatUsage)
+	failSeverity := fs.String("fail-severity", "error", "Exit with 1 on findings of this severity or above: error, warn, info or hint")
+	showHelp := fs.Bool("help", false, "Show help for lint command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.LintHelp())
+		return nil
+	}
+
+	format, err := report.ParseFormat(*outputFormat)
+	if err != nil {
+		return err
+	}
+	failAt, err := lint.ParseSeverity(*failSeverity)
+	if err != nil {
+		return err
+	}
+	ruleset, err := loadLintRuleset(*rulesetPath)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.lintInput(lint.New(ruleset), *input)
+	if err != nil {
+		return err
+	}
+	if err := c.outputLintResult(result, ruleset, format); err != nil {
+		return err
+	}
+	if failAt != lint.SeverityOff && result.Count(failAt) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// loadLintRuleset loads the ruleset given on the command line, the ruleset file of the working
+// directory, or the recommended ruleset.
+func loadLintRuleset(path string) (*lint.Ruleset, error) {
+	if path == "" {
+		if _, err := os.Stat(lint.DefaultRulesetFile); err != nil {
+			return lint.Recommended(), nil
+		}
+		path = lint.DefaultRulesetFile
+	}
+	return lint.LoadRuleset(path)
+}
+
+func (c *CLI) lintInput(linter *lint.Linter, input string) (*lint.Result, error) {
+	if isURL(input) {
+		return linter.LintURL(input)
+	}
+
+	stdinRes, err := readFromStdinOrFile(input, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if stdinRes.fromStdin {
+		return linter.LintData(stdinRes.data)
+	}
+	return linter.LintFile(input)
+}
+
+func (c *CLI) outputLintResult(result *lint.Result, ruleset *lint.Ruleset, format report.Format) error {
+	switch format {
+	case report.FormatText:
+		fmt.Print(lint.FormatText(result))
+	case report.FormatJSON:
+		data, err := lint.FormatJSON(result)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return c.writeReport(lint.ToReport(result, ruleset), format)
+	}
+	return nil
+}
+
+func (c *CLI) LintHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Lint an OpenAPI specification against a configurable ruleset.\n\n")
+	help.WriteString("Rulesets use the Spectral format: each rule selects parts of the document with\n")
+	help.WriteString("JSONPath 'given' expressions and checks them with one of the functions truthy,\n")
+	help.WriteString("falsy, defined, undefined, pattern, casing, enumeration, length or schema.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag lint [options]\n")
+	help.WriteString("  <command> | yaswag lint\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>          Input file path, URL, or - for stdin\n")
+	help.WriteString("  --ruleset <path>        Ruleset file (default: " + lint.DefaultRulesetFile + " if present,\n")
+	help.WriteString("                          else the recommended ruleset)\n")
+	help.WriteString("  --output-format <type>  Output format: text, json, sarif, junit or github (default: text)\n")
+	help.WriteString("  --fail-severity <level> Exit with 1 on findings of this severity or above:\n")
+	help.WriteString("                          error, warn, info, hint or off (default: error)\n")
+	help.WriteString("  --help                  Show this help message\n\n")
+	help.WriteString("Example ruleset:\n")
+	help.WriteString("  extends: " + lint.RecommendedRuleset + "\n")
+	help.WriteString("  rules:\n")
+	help.WriteString("    operation-tags: off\n")
+	help.WriteString("    contact-email:\n")
+	help.WriteString("      description: The API should list a contact email.\n")
+	help.WriteString("      severity: error\n")
+	help.WriteString("      given: $.info.contact\n")
+	help.WriteString("      then:\n")
+	help.WriteString("        field: email\n")
+	help.WriteString("        function: truthy\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --ruleset ./rules.yaml --fail-severity warn\n")
+	help.WriteString("  yaswag lint --input ./swagger.yaml --output-format sarif > lint.sarif\n")
+	help.WriteString("  yaswag generate --source ./api | yaswag lint\n")
+	return help.String()
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// outputFormatUsage describes the --output-format flag of validate and audit.
+const outputFormatUsage = "Output format: text, json, sarif, junit or github"
+
+// writeReport prints a report in one of the machine-readable formats.
+func (c *CLI) writeReport(r *report.Report, format report.Format) error {
+	r.Version = c.info.version
+	var data []byte
+	var err error
+	switch format {
+	case report.FormatSARIF:
+		data, err = report.SARIF(r)
+		data = append(data, '\n')
+	case report.FormatJUnit:
+		data, err = report.JUnit(r)
+	case report.FormatGitHub:
+		data = report.GitHub(r)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format %s report: %w", format, err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/split"
+)
+
+func (c *CLI) runSplit(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputDir := fs.String("output-dir", "", "Directory to write the files to")
+	format := fs.String("format", "", "Format of the files (json or yaml, auto-detected from the input if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	showHelp := fs.Bool("help", false, "Show help for split command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.SplitHelp())
+		return nil
+	}
+
+	if *outputDir == "" {
+		return fmt.Errorf("--output-dir is required")
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	root, err := yamlnode.Parse(result.data)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	layout, err := split.Node(root, split.WithFormat(c.determineOutputFormat(*format, "", *input, result.fromStdin)))
+	if err != nil {
+		return err
+	}
+	if err := layout.Write(*outputDir, *pretty); err != nil {
+		return err
+	}
+	fmt.Printf("Split specification into %d files; the root file is %s\n", len(layout.Files), filepath.Join(*outputDir, layout.Root))
+	return nil
+}
+
+func (c *CLI) SplitHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Split an OpenAPI specification into a multi-file layout.\n\n")
+	help.WriteString("Every path is written to paths/, every component to components/<section>/,\n")
+	help.WriteString("and the root file openapi.yaml (or .json) $refs them. Internal $refs are\n")
+	help.WriteString("rewritten relative to the file they end up in. 'yaswag bundle' reverses it.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag split --input <spec> --output-dir <dir> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>      Input file path or - for stdin\n")
+	help.WriteString("  --output-dir <dir>  Directory to write the files to (created if missing)\n")
+	help.WriteString("  --format <type>     Format of the files: json or yaml (default: that of the input)\n")
+	help.WriteString("  --pretty <n>        Indentation spaces (default: 2)\n")
+	help.WriteString("  --help              Show this help message\n\n")
+	help.WriteString("Layout:\n")
+	help.WriteString("  openapi.yaml                      info, servers, tags and $refs to the files below\n")
+	help.WriteString("  paths/users_{id}.yaml             the path item of /users/{id}\n")
+	help.WriteString("  components/schemas/User.yaml      the User schema\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag split --input ./openapi.yaml --output-dir ./api\n")
+	help.WriteString("  yaswag generate --source . | yaswag split --input - --output-dir ./api\n")
+	return help.String()
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+func (c *CLI) runUpgrade(args []string) error {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	input := fs.String("input", "", "Input file path or - for stdin")
+	outputPath := fs.String("output", "", "Output file path (empty for stdout)")
+	to := fs.String("to", "3.0", "Target OpenAPI version: 3.0, 3.1, 3.2 or a full version such as 3.0.3")
+	format := fs.String("format", "", "Output format (json or yaml, auto-detected from extension if not specified)")
+	pretty := fs.Int("pretty", 2, "Indentation spaces for pretty printing")
+	showHelp := fs.Bool("help", false, "Show help for upgrade command")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *showHelp {
+		fmt.Println(c.UpgradeHelp())
+		return nil
+	}
+
+	result, err := readFromStdinOrFile(*input, true)
+	if err != nil {
+		return err
+	}
+	root, err := yamlnode.Parse(result.data)
+	if err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	warnings, err := openapi.UpgradeNode(root, *to)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	formatter := output.NewFormatter(output.Options{
+		Format: c.determineOutputFormat(*format, *outputPath, *input, result.fromStdin),
+		Indent: *pretty,
+		Pretty: *pretty > 0,
+		// Swagger 2.0 documents are often JSON; the upgraded one reads better as block YAML
+		BlockStyle: true,
+	})
+	data, err := formatter.FormatNode(root)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	// JSON is written without a final newline
+	if !strings.HasSuffix(string(data), "\n") {
+		data = append(data, '\n')
+	}
+	return c.writeOutput(*outputPath, data, "Upgraded specification")
+}
+
+func (c *CLI) UpgradeHelp() string {
+	help := strings.Builder{}
+	help.WriteString("Upgrade a Swagger 2.0 specification to OpenAPI 3.0, 3.1 or 3.2.\n\n")
+	help.WriteString("definitions, parameters, responses and securityDefinitions move to components; body\n")
+	help.WriteString("and formData parameters become request bodies and response schemas become content,\n")
+	help.WriteString("with the media types of consumes and produces; host, basePath and schemes become\n")
+	help.WriteString("servers. What OpenAPI 3 cannot express is dropped with a warning on stderr.\n")
+	help.WriteString("OpenAPI 3.x input is converted as with the convert command.\n\n")
+	help.WriteString("Usage:\n")
+	help.WriteString("  yaswag upgrade --input <spec> [options]\n\n")
+	help.WriteString("Options:\n")
+	help.WriteString("  --input <path>    Input file path or - for stdin\n")
+	help.WriteString("  --to <version>    Target version: 3.0, 3.1, 3.2 or a full version (default: 3.0)\n")
+	help.WriteString("  --output <path>   Output file path (empty for stdout)\n")
+	help.WriteString("  --format <type>   Output format: json or yaml (auto-detected if not specified)\n")
+	help.WriteString("  --pretty <n>      Indentation spaces (default: 2)\n")
+	help.WriteString("  --help            Show this help message\n\n")
+	help.WriteString("Examples:\n")
+	help.WriteString("  yaswag upgrade --input ./swagger.json --output ./openapi.json\n")
+	help.WriteString("  yaswag upgrade --input ./swagger.yaml --to 3.1 --output ./openapi.yaml\n")
+	return help.String()
+}
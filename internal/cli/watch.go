@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/internal/watch"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+	"github.com/fathurrohman26/yaswag/pkg/swaggerui"
+)
+
+// watchInterval is how often watched sources are polled for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchGenerate regenerates the specs whenever a source changes until interrupted.
+// Only specs whose content changed are rewritten, so annotation-free edits leave the output untouched.
+func (c *CLI) watchGenerate(roots []string, specs []generatedSpec, generate func() ([]generatedSpec, error)) error {
+	last := make(map[string][]byte)
+	for _, spec := range specs {
+		last[spec.path] = spec.data
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Watching %s for changes (press Ctrl+C to stop)\n", strings.Join(roots, ", "))
+	return watch.New(roots, watchInterval).Run(ctx, func() {
+		specs, err := generate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		for _, spec := range specs {
+			if bytes.Equal(last[spec.path], spec.data) {
+				continue
+			}
+			if err := c.writeOutput(spec.path, spec.data, spec.label); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				continue
+			}
+			last[spec.path] = spec.data
+		}
+	})
+}
+
+// serveSources serves the spec generated from the sources, optionally regenerating it on changes
+// and pushing a reload to open Swagger UI pages.
+func (c *CLI) serveSources(server *swaggerui.Server, src *sourceFlags, group string, watchMode bool) error {
+	generate := func() ([]byte, error) {
+		p, err := c.parseSources(src.roots(), false, src.parserOptions()...)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := generateSpec(p, group)
+		if err != nil {
+			return nil, err
+		}
+		return c.formatOutput(doc, "yaml", 2, string(output.KeyOrderAlphabetical))
+	}
+
+	data, err := generate()
+	if err != nil {
+		return err
+	}
+	server.SetSpecFromData(data)
+
+	if watchMode {
+		server.EnableLiveReload()
+		fmt.Printf("Watching %s for changes\n", strings.Join(src.roots(), ", "))
+		go func() {
+			_ = watch.New(src.roots(), watchInterval).Run(context.Background(), func() {
+				updated, err := generate()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					return
+				}
+				if !bytes.Equal(updated, data) {
+					data = updated
+					server.UpdateSpec(data)
+					fmt.Println("Spec regenerated, reloading Swagger UI")
+				}
+			})
+		}()
+	}
+	return server.Serve()
+}
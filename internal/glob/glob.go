@@ -0,0 +1,63 @@
+// Package glob matches slash-separated paths against glob patterns.
+// Patterns use path.Match syntax per segment, plus "**" which matches any number of segments.
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether the slash-separated name matches pattern.
+// A malformed pattern never matches; use Validate to report it.
+func Match(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// MatchAny reports whether name matches at least one of the patterns.
+func MatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if pattern is malformed.
+func Validate(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			return matchDoubleStar(pattern[1:], segments)
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// matchDoubleStar matches the rest of a pattern after "**" against every suffix of segments.
+func matchDoubleStar(rest, segments []string) bool {
+	for i := 0; i <= len(segments); i++ {
+		if matchSegments(rest, segments[i:]) {
+			return true
+		}
+	}
+	return false
+}
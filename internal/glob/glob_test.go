@@ -0,0 +1,60 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"main.go", "main.go", true},
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"cmd/*/main.go", "cmd/api/main.go", true},
+		{"cmd/*/main.go", "cmd/api/v2/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/api/v2/main.go", true},
+		{"**/mocks/**", "internal/mocks/user.go", true},
+		{"**/mocks/**", "mocks", true},
+		{"**/mocks/**", "internal/mockserver/user.go", false},
+		{"services/billing/**", "services/billing/api/handlers.go", true},
+		{"services/billing/**", "services/users/api/handlers.go", false},
+		{"**", "anything/at/all.go", true},
+		{"api/**/v?/*.go", "api/internal/v1/routes.go", true},
+		{"api/**/v?/*.go", "api/v10/routes.go", false},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.name, func(t *testing.T) {
+			if got := Match(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"**/*_gen.go", "vendor/**"}
+	if !MatchAny(patterns, "api/models_gen.go") {
+		t.Error("expected generated file to match")
+	}
+	if MatchAny(patterns, "api/models.go") {
+		t.Error("expected regular file not to match")
+	}
+	if MatchAny(nil, "api/models.go") {
+		t.Error("expected no patterns to match nothing")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, pattern := range []string{"**/*.go", "cmd/[a-z]*/main.go", "**"} {
+		if err := Validate(pattern); err != nil {
+			t.Errorf("Validate(%q) error = %v", pattern, err)
+		}
+	}
+	if err := Validate("cmd/[a-z/main.go"); err == nil {
+		t.Error("expected error for malformed pattern")
+	}
+}
@@ -3,7 +3,9 @@
 package parser
 
 import (
+	"go/token"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -18,7 +20,7 @@ type AnnotationType string
 
 const (
 	// API-level annotations
-	AnnotationAPI          AnnotationType = "api"          // !api 3.0.3
+	AnnotationAPI          AnnotationType = "api"          // !api 3.0.3 group=admin
 	AnnotationInfo         AnnotationType = "info"         // !info "Title" v1.0.0 "Description"
 	AnnotationContact      AnnotationType = "contact"      // !contact "Name" <email> (url)
 	AnnotationLicense      AnnotationType = "license"      // !license MIT https://...
@@ -31,7 +33,7 @@ const (
 	AnnotationLink         AnnotationType = "link"         // !link "Label" https://...
 
 	// Operation annotations
-	AnnotationRoute  AnnotationType = "route"  // !GET /path -> operationId "summary" #tag1 #tag2
+	AnnotationRoute  AnnotationType = "route"  // !GET /path -> operationId "summary" #tag1 #tag2 group=admin
 	AnnotationQuery  AnnotationType = "query"  // !query name:type "description" default=value required
 	AnnotationPath   AnnotationType = "path"   // !path id:integer "description" required
 	AnnotationHeader AnnotationType = "header" // !header X-Token:string "description"
@@ -41,7 +43,7 @@ const (
 	AnnotationSecure AnnotationType = "secure" // !secure api_key oauth2
 
 	// Schema annotations
-	AnnotationModel AnnotationType = "model" // !model "Description"
+	AnnotationModel AnnotationType = "model" // !model "Description" group=admin
 	AnnotationField AnnotationType = "field" // !field name:type "description" required example=value
 )
 
@@ -51,6 +53,7 @@ type Annotation struct {
 	RawLine string
 	Args    map[string]string
 	Tags    []string
+	Pos     token.Position // Source position, set when parsed from a Go file
 }
 
 // AnnotationParser parses YaSwag's eccentric annotation syntax.
@@ -79,7 +82,7 @@ type AnnotationParser struct {
 // NewAnnotationParser creates a new annotation parser for YaSwag's eccentric syntax.
 func NewAnnotationParser() *AnnotationParser {
 	return &AnnotationParser{
-		// !api 3.0.3 or !api v3.0.3
+		// !api 3.0.3 or !api v3.0.3, optionally followed by group=name
 		apiPattern: regexp.MustCompile(`^!api\s+v?([\d.]+)`),
 
 		// !info "Title" v1.0.0 "Description"
@@ -119,7 +122,7 @@ func NewAnnotationParser() *AnnotationParser {
 		linkPattern: regexp.MustCompile(`^!link\s+"([^"]+)"\s+(\S+)`),
 
 		// !GET /path -> operationId "summary" #tag1 #tag2
-		// !POST /path -> operationId "summary" #tag
+		// !POST /path -> operationId "summary" #tag group=public,admin
 		routePattern: regexp.MustCompile(`^!(GET|POST|PUT|DELETE|PATCH|OPTIONS|HEAD)\s+(\S+)\s+->\s+(\S+)(?:\s+"([^"]*)")?`),
 
 		// !query name:type "description" default=value required
@@ -137,11 +140,11 @@ func NewAnnotationParser() *AnnotationParser {
 		// !secure securityName1 securityName2
 		securePattern: regexp.MustCompile(`^!secure\s+(.+)`),
 
-		// !model "Description"
+		// !model "Description" group=admin
 		modelPattern: regexp.MustCompile(`^!model(?:\s+"([^"]*)")?`),
 
 		// !field name:type "description" required example=value
-		fieldPattern: regexp.MustCompile(`^!field\s+(\w+):(\w+)\??\s*(?:"([^"]*)")?`),
+		fieldPattern: regexp.MustCompile(`^!field\s+([\w-]+):(\w+)\??\s*(?:"([^"]*)")?`),
 	}
 }
 
@@ -217,6 +220,9 @@ func (p *AnnotationParser) parseSimplePatterns(line string) *Annotation {
 					args[key] = match[i+1]
 				}
 			}
+			if m.aType == AnnotationAPI {
+				addGroupArg(args, line)
+			}
 			return &Annotation{Type: m.aType, RawLine: line, Args: args}
 		}
 	}
@@ -228,17 +234,14 @@ func (p *AnnotationParser) parseRoutePattern(line string) *Annotation {
 	if match == nil {
 		return nil
 	}
-	return &Annotation{
-		Type:    AnnotationRoute,
-		RawLine: line,
-		Args: map[string]string{
-			"method":      strings.ToUpper(match[1]),
-			"path":        match[2],
-			"operationId": match[3],
-			"summary":     match[4],
-		},
-		Tags: extractTags(line),
+	args := map[string]string{
+		"method":      strings.ToUpper(match[1]),
+		"path":        match[2],
+		"operationId": match[3],
+		"summary":     match[4],
 	}
+	addGroupArg(args, line)
+	return &Annotation{Type: AnnotationRoute, RawLine: line, Args: args, Tags: extractTags(line[len(match[0]):])}
 }
 
 func (p *AnnotationParser) parseParamPattern(line string) *Annotation {
@@ -252,10 +255,11 @@ func (p *AnnotationParser) parseParamPattern(line string) *Annotation {
 		"type":        match[3],
 		"description": match[4],
 	}
-	if strings.Contains(line, " required") {
+	options := line[len(match[0]):]
+	if hasRequired(options) {
 		args["required"] = argTrue
 	}
-	if defMatch := regexp.MustCompile(`default=(\S+)`).FindStringSubmatch(line); defMatch != nil {
+	if defMatch := regexp.MustCompile(`default=(\S+)`).FindStringSubmatch(options); defMatch != nil {
 		args["default"] = strings.Trim(defMatch[1], `"'`)
 	}
 
@@ -275,7 +279,7 @@ func (p *AnnotationParser) parseBodyPattern(line string) *Annotation {
 		return nil
 	}
 	args := map[string]string{"schema": match[1], "description": match[2]}
-	if strings.Contains(line, " required") {
+	if hasRequired(line[len(match[0]):]) {
 		args["required"] = argTrue
 	}
 	return &Annotation{Type: AnnotationBody, RawLine: line, Args: args}
@@ -323,7 +327,9 @@ func (p *AnnotationParser) parseModelPattern(line string) *Annotation {
 	if match == nil {
 		return nil
 	}
-	return &Annotation{Type: AnnotationModel, RawLine: line, Args: map[string]string{"description": match[1]}}
+	args := map[string]string{"description": match[1]}
+	addGroupArg(args, line)
+	return &Annotation{Type: AnnotationModel, RawLine: line, Args: args}
 }
 
 func (p *AnnotationParser) parseFieldPattern(line string) *Annotation {
@@ -332,15 +338,22 @@ func (p *AnnotationParser) parseFieldPattern(line string) *Annotation {
 		return nil
 	}
 	args := map[string]string{"name": match[1], "type": match[2], "description": match[3]}
-	if strings.Contains(line, " required") {
+	options := line[len(match[0]):]
+	if hasRequired(options) {
 		args["required"] = argTrue
 	}
-	if exMatch := regexp.MustCompile(`example=("[^"]*"|\S+)`).FindStringSubmatch(line); exMatch != nil {
+	if exMatch := regexp.MustCompile(`example=("[^"]*"|\S+)`).FindStringSubmatch(options); exMatch != nil {
 		args["example"] = strings.Trim(exMatch[1], `"'`)
 	}
 	return &Annotation{Type: AnnotationField, RawLine: line, Args: args}
 }
 
+// hasRequired reports whether the options after the description of an annotation include required.
+// The description itself is left out, so "Not required" does not make a parameter required.
+func hasRequired(options string) bool {
+	return slices.Contains(strings.Fields(options), "required")
+}
+
 // extractTags extracts hashtag-style tags from a line (e.g., #users #admin)
 func extractTags(line string) []string {
 	var tags []string
@@ -352,16 +365,41 @@ func extractTags(line string) []string {
 	return tags
 }
 
+// groupPattern matches the group=name[,name...] option of !api, route and !model annotations.
+var groupPattern = regexp.MustCompile(`(?:^|\s)group=([\w,-]+)`)
+
+// quotedPattern matches quoted strings, which are ignored when looking for options.
+var quotedPattern = regexp.MustCompile(`"[^"]*"`)
+
+// addGroupArg stores the comma-separated group list of a line in args["group"], if the line has one.
+func addGroupArg(args map[string]string, line string) {
+	if match := groupPattern.FindStringSubmatch(quotedPattern.ReplaceAllString(line, "")); match != nil {
+		args["group"] = match[1]
+	}
+}
+
+// splitGroups splits a comma-separated group list, dropping empty names.
+func splitGroups(list string) []string {
+	var groups []string
+	for _, g := range strings.Split(list, ",") {
+		if g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
 // Helper functions for parsed data
 
 // ParsedAPI holds parsed !api data.
 type ParsedAPI struct {
 	Version string
+	Groups  []string
 }
 
 // GetAPI extracts API info from annotation.
 func GetAPI(a Annotation) ParsedAPI {
-	return ParsedAPI{Version: a.Args["version"]}
+	return ParsedAPI{Version: a.Args["version"], Groups: splitGroups(a.Args["group"])}
 }
 
 // ParsedInfo holds parsed !info data.
@@ -445,6 +483,7 @@ type ParsedRoute struct {
 	OperationID string
 	Summary     string
 	Tags        []string
+	Groups      []string
 }
 
 // GetRoute extracts route from annotation.
@@ -455,6 +494,7 @@ func GetRoute(a Annotation) ParsedRoute {
 		OperationID: a.Args["operationId"],
 		Summary:     a.Args["summary"],
 		Tags:        a.Tags,
+		Groups:      splitGroups(a.Args["group"]),
 	}
 }
 
@@ -517,12 +557,14 @@ func GetResponse(a Annotation) ParsedResponse {
 // ParsedModel holds parsed !model data.
 type ParsedModel struct {
 	Description string
+	Groups      []string
 }
 
 // GetModel extracts model from annotation.
 func GetModel(a Annotation) ParsedModel {
 	return ParsedModel{
 		Description: a.Args["description"],
+		Groups:      splitGroups(a.Args["group"]),
 	}
 }
 
@@ -97,6 +97,20 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationQuery, RawLine: `!query limit:integer "The number of results" default=10 required`, Args: map[string]string{"in": "query", "name": "limit", "type": "integer", "description": "The number of results", "required": "true", "default": "10"}},
 			},
 		},
+		{
+			name:  "options in the description are ignored",
+			input: `!query sort:string "Not required; default=name" default=id`,
+			expected: []Annotation{
+				{Type: AnnotationQuery, RawLine: `!query sort:string "Not required; default=name" default=id`, Args: map[string]string{"in": "query", "name": "sort", "type": "string", "description": "Not required; default=name", "default": "id"}},
+			},
+		},
+		{
+			name:  "hashes in the summary are not tags",
+			input: `!GET /issues -> getIssue "Get issue #1" #issues`,
+			expected: []Annotation{
+				{Type: AnnotationRoute, RawLine: `!GET /issues -> getIssue "Get issue #1" #issues`, Args: map[string]string{"method": "GET", "path": "/issues", "operationId": "getIssue", "summary": "Get issue #1"}, Tags: []string{"issues"}},
+			},
+		},
 		{
 			name:  "parse path parameter annotation",
 			input: `!path id:integer "The user ID" required`,
@@ -111,6 +125,13 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationHeader, RawLine: `!header X-Token:string "Authorization token"`, Args: map[string]string{"in": "header", "name": "X-Token", "type": "string", "description": "Authorization token"}},
 			},
 		},
+		{
+			name:  "required in a body description is ignored",
+			input: `!body Patch "Fields not required are kept"`,
+			expected: []Annotation{
+				{Type: AnnotationBody, RawLine: `!body Patch "Fields not required are kept"`, Args: map[string]string{"schema": "Patch", "description": "Fields not required are kept"}},
+			},
+		},
 		{
 			name:  "parse body annotation",
 			input: `!body CreateUserRequest "User data" required`,
@@ -167,6 +188,20 @@ func TestAnnotationParser_Parse(t *testing.T) {
 				{Type: AnnotationField, RawLine: `!field id:integer "User ID" required example=123`, Args: map[string]string{"name": "id", "type": "integer", "description": "User ID", "required": "true", "example": "123"}},
 			},
 		},
+		{
+			name:  "parse field annotation with a hyphenated name",
+			input: `!field created-at:string "Creation time" required`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field created-at:string "Creation time" required`, Args: map[string]string{"name": "created-at", "type": "string", "description": "Creation time", "required": "true"}},
+			},
+		},
+		{
+			name:  "options in a field description are ignored",
+			input: `!field note:string "Not required; example=x" example=y`,
+			expected: []Annotation{
+				{Type: AnnotationField, RawLine: `!field note:string "Not required; example=x" example=y`, Args: map[string]string{"name": "note", "type": "string", "description": "Not required; example=x", "example": "y"}},
+			},
+		},
 		{
 			name:  "parse field annotation with quoted example",
 			input: `!field name:string "User name" example="John Doe"`,
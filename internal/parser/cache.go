@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is mixed into every cache key. Bump it whenever fileResult or the
+// way annotations are extracted changes, so stale entries are never reused.
+const cacheVersion = "yaswag-parser-3"
+
+// cacheKey identifies the result of parsing the given file content at the given path.
+// The path is part of the key because results carry source positions.
+func cacheKey(path string, src []byte) string {
+	h := sha256.New()
+	h.Write([]byte(cacheVersion))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(src)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCached returns the cached result for key, or nil when there is none.
+// The cache is best effort: unreadable or corrupt entries are treated as misses.
+func (p *Parser) loadCached(key string) *fileResult {
+	if p.cacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(p.cacheDir, key+".gob"))
+	if err != nil {
+		return nil
+	}
+	var result fileResult
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&result); err != nil {
+		return nil
+	}
+	return &result
+}
+
+// storeCached writes result to the cache. Entries are written to a temporary file and
+// renamed into place so that concurrent runs never observe partial entries.
+func (p *Parser) storeCached(key string, result *fileResult) {
+	if p.cacheDir == "" {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(result); err != nil {
+		return
+	}
+	if err := os.MkdirAll(p.cacheDir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(p.cacheDir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(p.cacheDir, key+".gob")); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,291 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity classifies a parser diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic codes reported by the parser.
+const (
+	CodeUnknownAnnotation    = "unknown-annotation"     // !foo is not a YaSwag annotation
+	CodeMalformedAnnotation  = "malformed-annotation"   // a known annotation that does not match its syntax
+	CodeDuplicateOperationID = "duplicate-operation-id" // two routes share an operationId
+	CodeUnknownSchema        = "unknown-schema"         // a schema reference without a matching !model
+	CodeUnknownSecurity      = "unknown-security"       // !secure names a scheme without a matching !security
+)
+
+// Diagnostic describes a problem found in the annotations of a source file.
+type Diagnostic struct {
+	Pos      token.Position
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+// String formats the diagnostic like a compiler message: file:line:col: severity: message [code].
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", d.Pos, d.Severity, d.Message, d.Code)
+}
+
+// annotationSyntax maps each annotation keyword to its expected syntax, used as a hint for malformed annotations.
+var annotationSyntax = map[string]string{
+	"api":          `!api 3.0.3 group=name`,
+	"info":         `!info "Title" v1.0.0 "Description"`,
+	"contact":      `!contact "Name" <email> (url)`,
+	"license":      `!license Name URL`,
+	"server":       `!server URL "Description"`,
+	"tag":          `!tag name "Description"`,
+	"tos":          `!tos URL`,
+	"security":     `!security name:type:location "Description" [url]`,
+	"scope":        `!scope security_name scope_name "Description"`,
+	"externalDocs": `!externalDocs URL "Description"`,
+	"link":         `!link "Label" URL`,
+	"GET":          `!GET /path -> operationId "summary" #tag group=name`,
+	"POST":         `!POST /path -> operationId "summary" #tag group=name`,
+	"PUT":          `!PUT /path -> operationId "summary" #tag group=name`,
+	"DELETE":       `!DELETE /path -> operationId "summary" #tag group=name`,
+	"PATCH":        `!PATCH /path -> operationId "summary" #tag group=name`,
+	"OPTIONS":      `!OPTIONS /path -> operationId "summary" #tag group=name`,
+	"HEAD":         `!HEAD /path -> operationId "summary" #tag group=name`,
+	"query":        `!query name:type "description" default=value required`,
+	"path":         `!path name:type "description"`,
+	"header":       `!header name:type "description" required`,
+	"cookie":       `!cookie name:type "description" required`,
+	"body":         `!body SchemaRef "description" required`,
+	"ok":           `!ok [status] SchemaRef "description"`,
+	"error":        `!error status SchemaRef "description"`,
+	"secure":       `!secure securityName...`,
+	"model":        `!model "Description" group=name`,
+	"field":        `!field name:type "description" required example=value`,
+}
+
+// keywordPattern extracts the keyword of an annotation-like line (e.g. "query" from "!query ...").
+var keywordPattern = regexp.MustCompile(`^!(\w+)`)
+
+// commentLine is a single line of comment text together with its source position.
+type commentLine struct {
+	text string
+	pos  token.Position
+}
+
+// commentLines splits a comment group into trimmed lines, keeping the position of each line's first character.
+func (p *Parser) commentLines(cg *ast.CommentGroup) []commentLine {
+	var lines []commentLine
+	for _, c := range cg.List {
+		start := p.fset.Position(c.Slash)
+		body := c.Text[2:]
+		if strings.HasPrefix(c.Text, "/*") {
+			body = strings.TrimSuffix(body, "*/")
+		}
+
+		offset := 2
+		for i, line := range strings.Split(body, "\n") {
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			pos := start
+			pos.Line += i
+			pos.Offset += offset + indent
+			pos.Column = indent + 1
+			if i == 0 {
+				pos.Column = start.Column + 2 + indent
+			}
+			lines = append(lines, commentLine{text: strings.TrimSpace(line), pos: pos})
+			offset += len(line) + 1
+		}
+	}
+	return lines
+}
+
+// annotations parses the YaSwag annotations in a comment group, recording the position of each one.
+// When report is set, annotation-like lines that cannot be parsed are recorded as diagnostics.
+func (p *fileParser) annotations(cg *ast.CommentGroup, report bool) []Annotation {
+	if cg == nil {
+		return nil
+	}
+	var annotations []Annotation
+	for _, line := range p.commentLines(cg) {
+		if !strings.HasPrefix(line.text, "!") {
+			continue
+		}
+		a := p.annotationParser.parseLine(line.text)
+		if a == nil {
+			if report {
+				p.reportUnparsed(line)
+			}
+			continue
+		}
+		a.Pos = line.pos
+		annotations = append(annotations, *a)
+	}
+	return annotations
+}
+
+func (p *fileParser) reportUnparsed(line commentLine) {
+	match := keywordPattern.FindStringSubmatch(line.text)
+	if match == nil {
+		return
+	}
+	keyword := match[1]
+	if syntax, ok := annotationSyntax[keyword]; ok {
+		p.addDiagnostic(line.pos, SeverityError, CodeMalformedAnnotation,
+			fmt.Sprintf("malformed !%s annotation, expected %s", keyword, syntax))
+		return
+	}
+	message := fmt.Sprintf("unknown annotation !%s", keyword)
+	if suggestion := suggestKeyword(keyword); suggestion != "" {
+		message += fmt.Sprintf(", did you mean !%s?", suggestion)
+	}
+	p.addDiagnostic(line.pos, SeverityWarning, CodeUnknownAnnotation, message)
+}
+
+// suggestKeyword returns a known keyword that differs from the given one only in case.
+func suggestKeyword(keyword string) string {
+	for known := range annotationSyntax {
+		if strings.EqualFold(known, keyword) {
+			return known
+		}
+	}
+	return ""
+}
+
+func (p *fileParser) addDiagnostic(pos token.Position, severity Severity, code, message string) {
+	p.result.Diagnostics = append(p.result.Diagnostics, Diagnostic{Pos: pos, Severity: severity, Code: code, Message: message})
+}
+
+// referenceKind distinguishes the kinds of names an annotation can reference.
+type referenceKind int
+
+const (
+	referenceSchema referenceKind = iota
+	referenceSecurity
+)
+
+// reference records a name used by an annotation so that it can be checked once all files are parsed.
+type reference struct {
+	kind referenceKind
+	name string
+	pos  token.Position
+}
+
+func (p *Parser) addReference(kind referenceKind, name string, pos token.Position) {
+	p.references = append(p.references, reference{kind: kind, name: name, pos: pos})
+}
+
+// Diagnostics returns all problems found in the parsed annotations, ordered by position.
+// Cross-file checks (duplicate operationIds, unknown schemas and security schemes) are
+// evaluated against everything parsed so far.
+func (p *Parser) Diagnostics() []Diagnostic {
+	diagnostics := append([]Diagnostic(nil), p.diagnostics...)
+	diagnostics = append(diagnostics, p.checkOperationIDs()...)
+	diagnostics = append(diagnostics, p.checkReferences()...)
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].Pos, diagnostics[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return diagnostics
+}
+
+// checkOperationIDs reports duplicate operationIds within the default spec and within each group.
+func (p *Parser) checkOperationIDs() []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, spec := range p.allSpecs() {
+		diagnostics = append(diagnostics, checkSpecOperationIDs(spec)...)
+	}
+	return diagnostics
+}
+
+func checkSpecOperationIDs(spec *SpecData) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[string]token.Position)
+	for _, op := range spec.Operations {
+		if op.OperationID == "" {
+			continue
+		}
+		if first, ok := seen[op.OperationID]; ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Pos:      op.Pos,
+				Severity: SeverityError,
+				Code:     CodeDuplicateOperationID,
+				Message:  fmt.Sprintf("duplicate operationId %q, first defined at %s", op.OperationID, first),
+			})
+			continue
+		}
+		seen[op.OperationID] = op.Pos
+	}
+	return diagnostics
+}
+
+func (p *Parser) checkReferences() []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, ref := range p.references {
+		switch ref.kind {
+		case referenceSchema:
+			if !p.hasSchema(ref.name) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Pos:      ref.pos,
+					Severity: SeverityError,
+					Code:     CodeUnknownSchema,
+					Message:  fmt.Sprintf("unknown schema %q, no struct with a !model annotation has this name", ref.name),
+				})
+			}
+		case referenceSecurity:
+			if !p.hasSecurity(ref.name) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Pos:      ref.pos,
+					Severity: SeverityError,
+					Code:     CodeUnknownSecurity,
+					Message:  fmt.Sprintf("unknown security scheme %q, no !security annotation defines it", ref.name),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+func (p *Parser) hasSchema(name string) bool {
+	if _, ok := p.globalSchemas[name]; ok {
+		return true
+	}
+	for _, spec := range p.allSpecs() {
+		if _, ok := spec.Schemas[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Parser) hasSecurity(name string) bool {
+	for _, spec := range p.allSpecs() {
+		if _, ok := spec.Securities[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allSpecs returns the default spec followed by the spec of each group.
+func (p *Parser) allSpecs() []*SpecData {
+	specs := []*SpecData{p.spec}
+	for _, name := range p.Groups() {
+		specs = append(specs, p.groups[name])
+	}
+	return specs
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const diagnosticsTestContent = `package main
+
+// !api 3.0.3
+// !info "Diag API" v1.0.0
+// !security api_key:apiKey:header "API key"
+// !contributor "Nobody"
+func main() {}
+
+// !get /users -> listUsers
+func Lowercase() {}
+
+// !GET /users -> getUsers "List users"
+// !query limit "missing type"
+// !ok User[] "Users"
+// !secure api_key oauth
+func GetUsers() {}
+
+// !POST /users -> getUsers "Create user"
+// !body NewUser "User data" required
+// !ok 201 User "Created"
+func CreateUser() {}
+
+// !model "A user"
+type User struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+`
+
+func TestParser_Diagnostics(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", diagnosticsTestContent)
+
+	p := h.parse()
+	diagnostics := p.Diagnostics()
+
+	file := filepath.Join(h.tmpDir, "api.go")
+	want := []struct {
+		line, column int
+		severity     Severity
+		code         string
+	}{
+		{6, 4, SeverityWarning, CodeUnknownAnnotation},
+		{9, 4, SeverityWarning, CodeUnknownAnnotation},
+		{13, 4, SeverityError, CodeMalformedAnnotation},
+		{15, 4, SeverityError, CodeUnknownSecurity},
+		{18, 4, SeverityError, CodeDuplicateOperationID},
+		{19, 4, SeverityError, CodeUnknownSchema},
+	}
+
+	if len(diagnostics) != len(want) {
+		for _, d := range diagnostics {
+			t.Log(d)
+		}
+		t.Fatalf("got %d diagnostics, want %d", len(diagnostics), len(want))
+	}
+	for i, w := range want {
+		d := diagnostics[i]
+		if d.Pos.Filename != file || d.Pos.Line != w.line || d.Pos.Column != w.column {
+			t.Errorf("diagnostic %d at %s, want %s:%d:%d", i, d.Pos, file, w.line, w.column)
+		}
+		if d.Severity != w.severity || d.Code != w.code {
+			t.Errorf("diagnostic %d = %s/%s, want %s/%s", i, d.Severity, d.Code, w.severity, w.code)
+		}
+	}
+
+	if !strings.Contains(diagnostics[1].Message, "did you mean !GET?") {
+		t.Errorf("expected case suggestion, got %q", diagnostics[1].Message)
+	}
+	if !strings.Contains(diagnostics[2].Message, `!query name:type`) {
+		t.Errorf("expected syntax hint, got %q", diagnostics[2].Message)
+	}
+}
+
+func TestParser_DiagnosticsClean(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", parseDirTestContent+`
+// CreateUserRequest is the payload for creating users.
+// !model "Create user payload"
+type CreateUserRequest struct {
+	Name string `+"`json:\"name\"`"+`
+}
+
+// !model "Error payload"
+type Error struct {
+	Message string `+"`json:\"message\"`"+`
+}
+`)
+
+	p := h.parse()
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestParser_DiagnosticsBlockComment(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", `package main
+
+/*
+	!api 3.0.3
+	!info "Block API" v1.0.0
+	!server
+*/
+func main() {}
+`)
+
+	p := h.parse()
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %v", len(diagnostics), diagnostics)
+	}
+	if d := diagnostics[0]; d.Pos.Line != 6 || d.Pos.Column != 2 || d.Code != CodeMalformedAnnotation {
+		t.Errorf("got %s, want line 6 column 2 %s", d, CodeMalformedAnnotation)
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", "package main\n\n// !nope\nfunc main() {}\n")
+
+	p := h.parse()
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(diagnostics))
+	}
+	want := filepath.Join(h.tmpDir, "api.go") + ":3:4: warning: unknown annotation !nope [unknown-annotation]"
+	if got := diagnostics[0].String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
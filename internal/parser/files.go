@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fathurrohman26/yaswag/internal/glob"
+)
+
+// fileResult holds everything extracted from a single source file.
+// Fields are exported so that results can be stored in the on-disk cache.
+type fileResult struct {
+	Comments    [][]Annotation // Annotations of each comment group, applied to the API-level spec
+	Funcs       [][]Annotation // Annotations of each documented function, one operation candidate each
+	Schemas     []*SchemaData  // Structs annotated with !model
+	Diagnostics []Diagnostic   // Problems found in the file's comments
+}
+
+// fileParser extracts the annotations of one file into a fileResult.
+// It only reads from the embedded Parser, so several fileParsers can run concurrently.
+type fileParser struct {
+	*Parser
+	result *fileResult
+}
+
+// collectFiles lists the Go files to parse under each root, in root order and then lexical order.
+func (p *Parser) collectFiles(roots []string) ([]string, error) {
+	for _, pattern := range append(append([]string(nil), p.include...), p.exclude...) {
+		if err := glob.Validate(pattern); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		found, err := p.walkRoot(filepath.Clean(root))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range found {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[abs] {
+				seen[abs] = true
+				files = append(files, path)
+			}
+		}
+	}
+	return files, nil
+}
+
+func (p *Parser) walkRoot(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			// Don't skip the root directory itself
+			if path != root && p.skipDir(info.Name(), rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ok, err := p.selectFile(path, rel)
+		if ok {
+			files = append(files, path)
+		}
+		return err
+	})
+	return files, err
+}
+
+// skipDir reports whether a directory below a source root should not be walked.
+func (p *Parser) skipDir(name, rel string) bool {
+	// Skip vendor, hidden directories, and testdata
+	if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") {
+		return true
+	}
+	return glob.MatchAny(p.exclude, rel)
+}
+
+// selectFile reports whether a file below a source root should be parsed.
+func (p *Parser) selectFile(path, rel string) (bool, error) {
+	// Skip non-Go files and test files
+	if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+		return false, nil
+	}
+	if glob.MatchAny(p.exclude, rel) {
+		return false, nil
+	}
+	if len(p.include) > 0 && !glob.MatchAny(p.include, rel) {
+		return false, nil
+	}
+	if len(p.buildTags) == 0 {
+		return true, nil
+	}
+	ctx := build.Default
+	ctx.BuildTags = p.buildTags
+	ok, err := ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to read build constraints of %s: %w", path, err)
+	}
+	return ok, nil
+}
+
+// parseFiles parses the given files across the worker pool. Results are returned in the
+// order of files; on failure the error of the first failing file is returned.
+func (p *Parser) parseFiles(files []string) ([]*fileResult, error) {
+	results := make([]*fileResult, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(p.workers, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = p.parseFile(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
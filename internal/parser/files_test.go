@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+// writeMonorepo writes an API spread over many files and directories.
+func (h *testHelper) writeMonorepo() {
+	h.writeFile("main.go", parseDirTestContent)
+	for i := range 20 {
+		dir := filepath.Join(h.tmpDir, fmt.Sprintf("svc%02d", i))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			h.t.Fatal(err)
+		}
+		h.writeFile(fmt.Sprintf("svc%02d/handlers.go", i), fmt.Sprintf(`package svc
+
+// !tag svc%[1]d "Service %[1]d"
+
+// !GET /svc%[1]d/items/{id} -> getItem%[1]d "Get item" #svc%[1]d
+// !path id:integer "Item ID"
+// !ok Item%[1]d "The item"
+// !error 404 Error "Not found"
+func GetItem() {}
+
+// !model "Item of service %[1]d"
+type Item%[1]d struct {
+	ID   int    `+"`json:\"id\"`"+`
+	Name string `+"`json:\"name,omitempty\"`"+`
+}
+`, i))
+	}
+}
+
+func (h *testHelper) render(p *Parser) []byte {
+	data, err := output.NewFormatter(output.DefaultOptions()).Format(p.Generate())
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return data
+}
+
+func TestParser_ParseDirParallelDeterministic(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeMonorepo()
+
+	sequential := h.parse(WithWorkers(1))
+	want := h.render(sequential)
+	for range 5 {
+		p := h.parse(WithWorkers(8))
+		if got := h.render(p); !bytes.Equal(got, want) {
+			t.Fatalf("parallel output differs from sequential output:\n%s\n---\n%s", got, want)
+		}
+		if len(p.spec.Operations) != len(sequential.spec.Operations) {
+			t.Fatalf("got %d operations, want %d", len(p.spec.Operations), len(sequential.spec.Operations))
+		}
+		for i, op := range p.spec.Operations {
+			if op.OperationID != sequential.spec.Operations[i].OperationID {
+				t.Errorf("operation %d = %s, want %s", i, op.OperationID, sequential.spec.Operations[i].OperationID)
+			}
+		}
+	}
+}
+
+func TestParser_ParseDirCache(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeMonorepo()
+	cacheDir := t.TempDir()
+
+	want := h.render(h.parse())
+
+	cold := h.parse(WithCacheDir(cacheDir))
+	if got := h.render(cold); !bytes.Equal(got, want) {
+		t.Fatalf("cold cache output differs:\n%s", got)
+	}
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.gob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 21 {
+		t.Fatalf("got %d cache entries, want 21", len(entries))
+	}
+
+	warm := h.parse(WithCacheDir(cacheDir))
+	if got := h.render(warm); !bytes.Equal(got, want) {
+		t.Fatalf("warm cache output differs:\n%s", got)
+	}
+	if len(warm.Diagnostics()) != len(cold.Diagnostics()) {
+		t.Errorf("warm cache reported %d diagnostics, want %d", len(warm.Diagnostics()), len(cold.Diagnostics()))
+	}
+}
+
+func TestParser_ParseDirCacheInvalidation(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", parseDirTestContent)
+	cacheDir := t.TempDir()
+
+	h.parse(WithCacheDir(cacheDir))
+	h.writeFile("api.go", `package main
+
+// !api 3.0.3
+// !info "Changed API" v2.0.0
+func main() {}
+`)
+
+	p := h.parse(WithCacheDir(cacheDir))
+	if got := p.GetSpec().Info.Title; got != "Changed API" {
+		t.Errorf("Title = %q, want changed file to be re-parsed", got)
+	}
+}
+
+func TestParser_ParseDirCacheCorruptEntry(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", parseDirTestContent)
+	cacheDir := t.TempDir()
+
+	src, err := os.ReadFile(filepath.Join(h.tmpDir, "api.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := cacheKey(filepath.Join(h.tmpDir, "api.go"), src)
+	if err := os.WriteFile(filepath.Join(cacheDir, key+".gob"), []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := h.parse(WithCacheDir(cacheDir))
+	if got := p.GetSpec().Info.Title; got == "" {
+		t.Error("expected corrupt cache entry to be ignored")
+	}
+}
+
+func TestParser_ParseDirSyntaxError(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeMonorepo()
+	h.writeFile("broken.go", "package main\n\nfunc {\n")
+
+	if err := New(WithWorkers(4)).ParseDir(h.tmpDir); err == nil {
+		t.Error("expected error for file with syntax errors")
+	}
+}
+
+func operationIDs(p *Parser) []string {
+	var ids []string
+	for _, op := range p.spec.Operations {
+		ids = append(ids, op.OperationID)
+	}
+	return ids
+}
+
+func TestParser_ParseDirIncludeExclude(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeMonorepo()
+
+	p := h.parse(WithInclude("main.go", "svc0[0-2]/**"), WithExclude("svc01/**"))
+	want := []string{"getUsers", "createUser", "getItem0", "getItem2"}
+	if got := operationIDs(p); !slices.Equal(got, want) {
+		t.Errorf("operations = %v, want %v", got, want)
+	}
+	if _, ok := p.GetGlobalSchemas()["Item1"]; ok {
+		t.Error("expected schema from excluded directory to be skipped")
+	}
+}
+
+func TestParser_ParseDirInvalidPattern(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", parseDirTestContent)
+
+	if err := New(WithExclude("[")).ParseDir(h.tmpDir); err == nil {
+		t.Error("expected error for malformed glob pattern")
+	}
+}
+
+func TestParser_ParseDirBuildTags(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", parseDirTestContent)
+	h.writeFile("admin.go", `//go:build admin
+
+package main
+
+// !DELETE /users/{id} -> deleteUser "Delete user"
+// !path id:integer "User ID"
+// !ok 204 - "Deleted"
+func DeleteUser() {}
+`)
+
+	if got := operationIDs(h.parse()); !slices.Contains(got, "deleteUser") {
+		t.Errorf("without tags, expected all files to be parsed, got %v", got)
+	}
+	if got := operationIDs(h.parse(WithBuildTags("public"))); slices.Contains(got, "deleteUser") {
+		t.Errorf("expected admin-only file to be skipped, got %v", got)
+	}
+	if got := operationIDs(h.parse(WithBuildTags("admin"))); !slices.Contains(got, "deleteUser") {
+		t.Errorf("expected admin-only file with admin tag, got %v", got)
+	}
+}
+
+func TestParser_ParseDirs(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeMonorepo()
+
+	p := New()
+	err := p.ParseDirs(filepath.Join(h.tmpDir, "svc03"), filepath.Join(h.tmpDir, "svc01"), filepath.Join(h.tmpDir, "svc03"))
+	if err != nil {
+		t.Fatalf("ParseDirs() error = %v", err)
+	}
+	want := []string{"getItem3", "getItem1"}
+	if got := operationIDs(p); !slices.Equal(got, want) {
+		t.Errorf("operations = %v, want %v", got, want)
+	}
+}
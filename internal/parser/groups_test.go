@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+const groupsTestContent = `package main
+
+// !api 3.0.3
+// !info "Public API" v1.0.0 "Public endpoints"
+// !server https://api.example.com "Production"
+// !security api_key:apiKey:header "API key"
+func main() {}
+
+// !api 3.1.0 group=admin
+// !info "Admin API" v1.0.0 "Internal endpoints"
+// !security admin_token:http:bearer "Admin token"
+func admin() {}
+
+// !GET /users -> listUsers "List users" #users
+// !ok User[] "Users"
+func ListUsers() {}
+
+// !DELETE /users/{id} -> deleteUser "Delete user with group=public in summary" group=admin
+// !path id:integer "User ID"
+// !ok 204 - "Deleted"
+// !secure admin_token
+func DeleteUser() {}
+
+// !GET /health -> health "Health check" group=admin,ops
+// !ok 200 Health "Healthy"
+func Health() {}
+
+// !GET /status -> health "Status" group=ops
+// !ok 200 - "OK"
+func Status() {}
+
+// !model "A user"
+type User struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+
+// !model "Health report" group=admin,ops
+type Health struct {
+	Status string ` + "`json:\"status\"`" + `
+}
+`
+
+func pathsOf(p *Parser, group string) []string {
+	doc, err := p.GenerateGroup(group)
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestParser_Groups(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", groupsTestContent)
+	p := h.parse()
+
+	if got, want := p.Groups(), []string{"admin", "ops"}; !slices.Equal(got, want) {
+		t.Fatalf("Groups() = %v, want %v", got, want)
+	}
+
+	doc := p.Generate()
+	if _, ok := doc.Paths["/users"]; !ok || len(doc.Paths) != 1 {
+		t.Errorf("default spec paths = %v, want only /users", doc.Paths)
+	}
+	if _, ok := doc.Components.Schemas["Health"]; ok {
+		t.Error("expected grouped schema to be excluded from the default spec")
+	}
+
+	if got, want := pathsOf(p, "admin"), []string{"/health", "/users/{id}"}; !slices.Equal(got, want) {
+		t.Errorf("admin paths = %v, want %v", got, want)
+	}
+	if got, want := pathsOf(p, "ops"), []string{"/health", "/status"}; !slices.Equal(got, want) {
+		t.Errorf("ops paths = %v, want %v", got, want)
+	}
+
+	admin, _ := p.GenerateGroup("admin")
+	if admin.OpenAPI != "3.1.0" || admin.Info.Title != "Admin API" {
+		t.Errorf("admin spec = %s %q, want its own version and info", admin.OpenAPI, admin.Info.Title)
+	}
+	for _, name := range []string{"User", "Health"} {
+		if _, ok := admin.Components.Schemas[name]; !ok {
+			t.Errorf("expected schema %s in admin spec", name)
+		}
+	}
+	for _, name := range []string{"api_key", "admin_token"} {
+		if _, ok := admin.Components.SecuritySchemes[name]; !ok {
+			t.Errorf("expected security scheme %s in admin spec", name)
+		}
+	}
+	if _, ok := doc.Components.SecuritySchemes["admin_token"]; ok {
+		t.Error("expected admin security scheme to be excluded from the default spec")
+	}
+
+	ops, _ := p.GenerateGroup("ops")
+	if ops.OpenAPI != "3.0.3" || ops.Info.Title != "Public API" || len(ops.Servers) != 1 {
+		t.Errorf("expected ops spec to inherit version, info and servers, got %s %q %v", ops.OpenAPI, ops.Info.Title, ops.Servers)
+	}
+}
+
+func TestParser_GroupsDiagnostics(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", groupsTestContent)
+	p := h.parse()
+
+	// "health" is used in both admin and ops, but is unique within each group.
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Code != CodeDuplicateOperationID {
+		t.Fatalf("got %v, want one duplicate operationId in group ops", diagnostics)
+	}
+	if diagnostics[0].Pos.Line != 28 {
+		t.Errorf("duplicate reported at line %d, want 28", diagnostics[0].Pos.Line)
+	}
+}
+
+func TestParser_GenerateUnknownGroup(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+	h.writeFile("api.go", groupsTestContent)
+
+	if _, err := h.parse().GenerateGroup("missing"); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestAnnotationParser_Groups(t *testing.T) {
+	ap := NewAnnotationParser()
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`!api 3.1.0 group=admin`, []string{"admin"}},
+		{`!GET /users -> listUsers "List" #users group=public,admin`, []string{"public", "admin"}},
+		{`!GET /users -> listUsers "Filter by group=admin"`, nil},
+		{`!model "A user" group=internal-v2`, []string{"internal-v2"}},
+		{`!model "A user"`, nil},
+	}
+	for _, tt := range tests {
+		a := ap.parseLine(tt.line)
+		if a == nil {
+			t.Fatalf("parseLine(%q) = nil", tt.line)
+		}
+		if got := splitGroups(a.Args["group"]); !slices.Equal(got, tt.want) {
+			t.Errorf("groups of %q = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"maps"
 	"os"
-	"path/filepath"
 	"regexp"
+	"runtime"
 	"slices"
+	"sort"
 	"strings"
 
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
 
@@ -22,8 +25,27 @@ type Parser struct {
 	// Parsed specification data
 	spec *SpecData
 
-	// Global schemas (from !model annotations)
+	// Global schemas (from !model annotations without a group)
 	globalSchemas map[string]*SchemaData
+
+	// Names of all schemas in the order they were first declared
+	schemaOrder []string
+
+	// Specifications of named groups (from group= options), keyed by group name
+	groups map[string]*SpecData
+
+	// Problems found while parsing, and names to check once all files are parsed
+	diagnostics []Diagnostic
+	references  []reference
+
+	// Concurrency and caching settings for ParseDir
+	workers  int
+	cacheDir string
+
+	// File selection settings for ParseDir
+	include   []string
+	exclude   []string
+	buildTags []string
 }
 
 // SpecData holds all parsed data for an OpenAPI specification.
@@ -47,170 +69,294 @@ type LinkData struct {
 
 // OperationData holds parsed operation data.
 type OperationData struct {
-	Method      string
-	Path        string
-	OperationID string
-	Summary     string
-	Description string
-	Tags        []string
-	Deprecated  bool
-	Parameters  []*openapi.Parameter
-	RequestBody *openapi.RequestBody
-	Responses   openapi.Responses
-	Security    []openapi.SecurityRequirement
+	Method        string
+	Path          string
+	OperationID   string
+	Summary       string
+	Description   string
+	Tags          []string
+	Deprecated    bool
+	Parameters    []*openapi.Parameter
+	RequestBody   *openapi.RequestBody
+	Responses     openapi.Responses
+	ResponseOrder []string // Response status codes in the order they were declared
+	Security      []openapi.SecurityRequirement
+	Groups        []string       // Groups the operation belongs to, empty for the default spec
+	Pos           token.Position // Position of the route annotation
 }
 
 // SchemaData holds parsed schema data with examples.
 type SchemaData struct {
-	Name        string
-	Description string
-	Schema      *openapi.Schema
-	Examples    map[string]any
+	Name          string
+	Description   string
+	Schema        *openapi.Schema
+	Examples      map[string]any
+	Groups        []string // Groups the schema belongs to, empty when shared by all specs
+	PropertyOrder []string // Property names in the order the struct fields were declared
+}
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithWorkers sets the number of files parsed concurrently. Values below one use GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(p *Parser) {
+		p.workers = n
+	}
+}
+
+// WithCacheDir enables an on-disk cache of per-file results in dir, keyed by file content.
+func WithCacheDir(dir string) Option {
+	return func(p *Parser) {
+		p.cacheDir = dir
+	}
+}
+
+func newSpecData(version string) *SpecData {
+	return &SpecData{
+		Version:    version,
+		Info:       &openapi.Info{},
+		Schemas:    make(map[string]*SchemaData),
+		Securities: make(map[string]*openapi.SecurityScheme),
+	}
+}
+
+// WithInclude restricts parsing to files whose path relative to the source directory matches one of the glob patterns.
+func WithInclude(patterns ...string) Option {
+	return func(p *Parser) {
+		p.include = append(p.include, patterns...)
+	}
+}
+
+// WithExclude skips files and directories whose path relative to the source directory matches one of the glob patterns.
+func WithExclude(patterns ...string) Option {
+	return func(p *Parser) {
+		p.exclude = append(p.exclude, patterns...)
+	}
+}
+
+// WithBuildTags filters files by their //go:build constraints, as go build -tags would.
+func WithBuildTags(tags ...string) Option {
+	return func(p *Parser) {
+		p.buildTags = append(p.buildTags, tags...)
+	}
 }
 
 // New creates a new Parser instance.
-func New() *Parser {
-	return &Parser{
+func New(opts ...Option) *Parser {
+	p := &Parser{
 		fset:             token.NewFileSet(),
 		annotationParser: NewAnnotationParser(),
-		spec: &SpecData{
-			Version:    "3.0.3",
-			Info:       &openapi.Info{},
-			Schemas:    make(map[string]*SchemaData),
-			Securities: make(map[string]*openapi.SecurityScheme),
-		},
-		globalSchemas: make(map[string]*SchemaData),
+		spec:             newSpecData("3.0.3"),
+		globalSchemas:    make(map[string]*SchemaData),
+		groups:           make(map[string]*SpecData),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	if p.workers < 1 {
+		p.workers = runtime.GOMAXPROCS(0)
+	}
+	return p
 }
 
 // ParseDir parses all Go files in the given directory recursively.
+// Files are parsed concurrently and merged in lexical path order, so the result does not depend on scheduling.
 func (p *Parser) ParseDir(dir string) error {
-	// Clean the path to normalize it
-	root := filepath.Clean(dir)
-
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			name := info.Name()
-			// Don't skip the root directory itself
-			if path != root {
-				// Skip vendor, hidden directories, and testdata
-				if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") {
-					return filepath.SkipDir
-				}
-			}
-			return nil
-		}
-		// Skip non-Go files and test files
-		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
-			return nil
-		}
-		return p.parseFile(path)
-	})
+	return p.ParseDirs(dir)
 }
 
-func (p *Parser) parseFile(path string) error {
-	f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+// ParseDirs parses all Go files in the given directories recursively, in the order the directories are given.
+// A file reachable from several directories is parsed once.
+func (p *Parser) ParseDirs(dirs ...string) error {
+	files, err := p.collectFiles(dirs)
+	if err != nil {
+		return err
+	}
+	results, err := p.parseFiles(files)
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", path, err)
+		return err
+	}
+	for _, r := range results {
+		p.merge(r)
 	}
+	return nil
+}
+
+// parseFile extracts the annotations of a single file, using the cache when one is configured.
+func (p *Parser) parseFile(path string) (*fileResult, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	key := cacheKey(path, src)
+	if result := p.loadCached(key); result != nil {
+		return result, nil
+	}
+
+	f, err := parser.ParseFile(p.fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	fp := &fileParser{Parser: p, result: &fileResult{}}
 
 	// Parse all comment groups for API-level annotations
 	for _, cg := range f.Comments {
-		p.parseCommentGroup(cg)
+		fp.parseCommentGroup(cg)
 	}
 
 	// Parse function declarations for operation annotations
 	for _, decl := range f.Decls {
 		if fn, ok := decl.(*ast.FuncDecl); ok {
-			p.parseFuncDecl(fn)
+			fp.parseFuncDecl(fn)
 		}
 	}
 
 	// Parse type declarations for schema annotations
 	for _, decl := range f.Decls {
 		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
-			p.parseTypeDecl(genDecl)
+			fp.parseTypeDecl(genDecl)
 		}
 	}
 
+	p.storeCached(key, fp.result)
+	return fp.result, nil
+}
+
+// merge applies the result of a single file to the specification.
+func (p *Parser) merge(r *fileResult) {
+	for _, annotations := range r.Comments {
+		for _, spec := range p.specsFor(commentGroups(annotations)) {
+			for _, a := range annotations {
+				p.handleAnnotation(spec, a)
+			}
+		}
+	}
+	for _, annotations := range r.Funcs {
+		op := p.parseOperationAnnotations(annotations)
+		if op == nil {
+			continue
+		}
+		for _, spec := range p.specsFor(op.Groups) {
+			spec.Operations = append(spec.Operations, *op)
+		}
+	}
+	for _, schemaData := range r.Schemas {
+		if !slices.Contains(p.schemaOrder, schemaData.Name) {
+			p.schemaOrder = append(p.schemaOrder, schemaData.Name)
+		}
+		if len(schemaData.Groups) == 0 {
+			// Store schema globally by struct type name
+			p.globalSchemas[schemaData.Name] = schemaData
+			continue
+		}
+		for _, spec := range p.specsFor(schemaData.Groups) {
+			spec.Schemas[schemaData.Name] = schemaData
+		}
+	}
+	p.diagnostics = append(p.diagnostics, r.Diagnostics...)
+}
+
+// commentGroups returns the groups named by the !api annotation of a comment group.
+// All API-level annotations of that comment group apply to those groups.
+func commentGroups(annotations []Annotation) []string {
+	for _, a := range annotations {
+		if a.Type == AnnotationAPI {
+			return GetAPI(a).Groups
+		}
+	}
 	return nil
 }
 
-func (p *Parser) parseCommentGroup(cg *ast.CommentGroup) {
+// specsFor returns the specs of the given groups, creating them as needed, or the default spec when groups is empty.
+func (p *Parser) specsFor(groups []string) []*SpecData {
+	if len(groups) == 0 {
+		return []*SpecData{p.spec}
+	}
+	specs := make([]*SpecData, 0, len(groups))
+	for _, name := range groups {
+		spec, ok := p.groups[name]
+		if !ok {
+			spec = newSpecData("")
+			p.groups[name] = spec
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+func (p *fileParser) parseCommentGroup(cg *ast.CommentGroup) {
 	if cg == nil {
 		return
 	}
-	text := cg.Text()
-
-	annotations := p.annotationParser.Parse(text)
-	for _, a := range annotations {
-		p.handleAnnotation(a)
+	annotations := p.annotations(cg, true)
+	if len(annotations) > 0 {
+		p.result.Comments = append(p.result.Comments, annotations)
 	}
 }
 
-func (p *Parser) handleAnnotation(a Annotation) {
-	handlers := map[AnnotationType]func(Annotation){
-		AnnotationAPI:          func(a Annotation) { p.spec.Version = GetAPI(a).Version },
+func (p *Parser) handleAnnotation(spec *SpecData, a Annotation) {
+	handlers := map[AnnotationType]func(*SpecData, Annotation){
+		AnnotationAPI:          func(spec *SpecData, a Annotation) { spec.Version = GetAPI(a).Version },
 		AnnotationInfo:         p.handleInfo,
 		AnnotationContact:      p.handleContact,
 		AnnotationLicense:      p.handleLicense,
 		AnnotationServer:       p.handleServer,
 		AnnotationTag:          p.handleTag,
-		AnnotationTOS:          func(a Annotation) { p.spec.Info.TermsOfService = GetTOS(a).URL },
+		AnnotationTOS:          func(spec *SpecData, a Annotation) { spec.Info.TermsOfService = GetTOS(a).URL },
 		AnnotationSecurity:     p.handleSecurity,
 		AnnotationScope:        p.handleScope,
 		AnnotationExternalDocs: p.handleExternalDocs,
 		AnnotationLink:         p.handleLink,
 	}
 	if handler, ok := handlers[a.Type]; ok {
-		handler(a)
+		handler(spec, a)
 	}
 }
 
-func (p *Parser) handleInfo(a Annotation) {
+func (p *Parser) handleInfo(spec *SpecData, a Annotation) {
 	info := GetInfo(a)
-	p.spec.Info.Title = info.Title
-	p.spec.Info.Version = info.Version
-	p.spec.Info.Description = info.Description
+	spec.Info.Title = info.Title
+	spec.Info.Version = info.Version
+	spec.Info.Description = info.Description
 }
 
-func (p *Parser) handleContact(a Annotation) {
+func (p *Parser) handleContact(spec *SpecData, a Annotation) {
 	contact := GetContact(a)
-	p.spec.Info.Contact = &openapi.Contact{
+	spec.Info.Contact = &openapi.Contact{
 		Name:  contact.Name,
 		Email: contact.Email,
 		URL:   contact.URL,
 	}
 }
 
-func (p *Parser) handleLicense(a Annotation) {
+func (p *Parser) handleLicense(spec *SpecData, a Annotation) {
 	license := GetLicense(a)
-	p.spec.Info.License = &openapi.License{
+	spec.Info.License = &openapi.License{
 		Name: license.Name,
 		URL:  license.URL,
 	}
 }
 
-func (p *Parser) handleServer(a Annotation) {
+func (p *Parser) handleServer(spec *SpecData, a Annotation) {
 	server := GetServer(a)
-	p.spec.Servers = append(p.spec.Servers, openapi.Server{
+	spec.Servers = append(spec.Servers, openapi.Server{
 		URL:         server.URL,
 		Description: server.Description,
 	})
 }
 
-func (p *Parser) handleTag(a Annotation) {
+func (p *Parser) handleTag(spec *SpecData, a Annotation) {
 	tag := GetTag(a)
-	p.spec.Tags = append(p.spec.Tags, openapi.Tag{
+	spec.Tags = append(spec.Tags, openapi.Tag{
 		Name:        tag.Name,
 		Description: tag.Description,
 	})
 }
 
-func (p *Parser) handleSecurity(a Annotation) {
+func (p *Parser) handleSecurity(spec *SpecData, a Annotation) {
 	sec := GetSecurity(a)
 	scheme := &openapi.SecurityScheme{Description: sec.Description}
 
@@ -226,7 +372,7 @@ func (p *Parser) handleSecurity(a Annotation) {
 		scheme.Type = "openIdConnect"
 		scheme.OpenIDConnectURL = sec.URL
 	}
-	p.spec.Securities[sec.Name] = scheme
+	spec.Securities[sec.Name] = scheme
 }
 
 func (p *Parser) configureAPIKeySecurity(scheme *openapi.SecurityScheme, sec ParsedSecurity) {
@@ -258,9 +404,9 @@ func (p *Parser) configureOAuth2Security(scheme *openapi.SecurityScheme, sec Par
 	}
 }
 
-func (p *Parser) handleScope(a Annotation) {
+func (p *Parser) handleScope(spec *SpecData, a Annotation) {
 	scope := GetScope(a)
-	scheme, ok := p.spec.Securities[scope.Security]
+	scheme, ok := spec.Securities[scope.Security]
 	if !ok || scheme.Flows == nil {
 		return
 	}
@@ -280,20 +426,20 @@ func addScopeToFlow(flow *openapi.OAuthFlow, name, description string) {
 	flow.Scopes[name] = description
 }
 
-func (p *Parser) handleExternalDocs(a Annotation) {
+func (p *Parser) handleExternalDocs(spec *SpecData, a Annotation) {
 	extDocs := GetExternalDocs(a)
-	p.spec.ExternalDocs = &openapi.ExternalDocumentation{
+	spec.ExternalDocs = &openapi.ExternalDocumentation{
 		URL:         extDocs.URL,
 		Description: extDocs.Description,
 	}
 }
 
-func (p *Parser) handleLink(a Annotation) {
+func (p *Parser) handleLink(spec *SpecData, a Annotation) {
 	link := GetLink(a)
-	p.spec.Links = append(p.spec.Links, LinkData(link))
+	spec.Links = append(spec.Links, LinkData(link))
 }
 
-func (p *Parser) parseFuncDecl(fn *ast.FuncDecl) {
+func (p *fileParser) parseFuncDecl(fn *ast.FuncDecl) {
 	if fn.Doc == nil {
 		return
 	}
@@ -303,15 +449,11 @@ func (p *Parser) parseFuncDecl(fn *ast.FuncDecl) {
 		return
 	}
 
-	annotations := p.annotationParser.Parse(text)
+	annotations := p.annotations(fn.Doc, false)
 	if len(annotations) == 0 {
 		return
 	}
-
-	op := p.parseOperationAnnotations(annotations)
-	if op != nil {
-		p.spec.Operations = append(p.spec.Operations, *op)
-	}
+	p.result.Funcs = append(p.result.Funcs, annotations)
 }
 
 func (p *Parser) parseOperationAnnotations(annotations []Annotation) *OperationData {
@@ -349,10 +491,15 @@ func (p *Parser) applyRouteAnnotation(op *OperationData, a Annotation) {
 	op.OperationID = route.OperationID
 	op.Summary = route.Summary
 	op.Tags = route.Tags
+	op.Groups = route.Groups
+	op.Pos = a.Pos
 }
 
 func (p *Parser) applyParamAnnotation(op *OperationData, a Annotation) {
 	param := GetParam(a)
+	if _, ok := typeSchemaMapping[param.Type]; !ok {
+		p.addReference(referenceSchema, param.Type, a.Pos)
+	}
 	op.Parameters = append(op.Parameters, &openapi.Parameter{
 		Name:        param.Name,
 		In:          openapi.ParameterLocation(param.In),
@@ -365,6 +512,7 @@ func (p *Parser) applyParamAnnotation(op *OperationData, a Annotation) {
 
 func (p *Parser) applyBodyAnnotation(op *OperationData, a Annotation) {
 	body := GetBody(a)
+	p.addReference(referenceSchema, schemaRefName(body.Schema), a.Pos)
 	op.RequestBody = &openapi.RequestBody{
 		Description: body.Description,
 		Required:    body.Required,
@@ -378,21 +526,26 @@ func (p *Parser) applyResponseAnnotation(op *OperationData, a Annotation) {
 	resp := GetResponse(a)
 	response := &openapi.Response{Description: resp.Description}
 	if resp.Schema != "" && resp.Schema != "-" && resp.Schema != "nil" && resp.Schema != "none" {
+		p.addReference(referenceSchema, schemaRefName(resp.Schema), a.Pos)
 		response.Content = map[string]openapi.MediaType{
 			"application/json": {Schema: p.parseSchemaRef(resp.Schema)},
 		}
 	}
+	if _, exists := op.Responses[resp.Status]; !exists {
+		op.ResponseOrder = append(op.ResponseOrder, resp.Status)
+	}
 	op.Responses[resp.Status] = response
 }
 
 func (p *Parser) applySecureAnnotation(op *OperationData, a Annotation) {
 	secure := GetSecure(a)
 	for _, name := range secure.Names {
+		p.addReference(referenceSecurity, name, a.Pos)
 		op.Security = append(op.Security, openapi.SecurityRequirement{name: []string{}})
 	}
 }
 
-func (p *Parser) parseTypeDecl(decl *ast.GenDecl) {
+func (p *fileParser) parseTypeDecl(decl *ast.GenDecl) {
 	for _, spec := range decl.Specs {
 		typeSpec, ok := spec.(*ast.TypeSpec)
 		if !ok {
@@ -414,29 +567,30 @@ func (p *Parser) parseTypeDecl(decl *ast.GenDecl) {
 			continue
 		}
 
-		annotations := p.annotationParser.Parse(docText)
+		annotations := p.annotations(decl.Doc, false)
 		for _, a := range annotations {
 			if a.Type == AnnotationModel {
 				model := GetModel(a)
 				schemaData := &SchemaData{
-					Name:        typeSpec.Name.Name,
-					Description: model.Description,
-					Schema:      p.structToSchema(structType, docText),
-					Examples:    make(map[string]any),
+					Name:          typeSpec.Name.Name,
+					Description:   model.Description,
+					Schema:        p.structToSchema(structType, docText),
+					Examples:      make(map[string]any),
+					Groups:        model.Groups,
+					PropertyOrder: p.propertyOrder(structType),
 				}
 				schemaData.Schema.Description = model.Description
 
 				// Parse field annotations from struct fields
 				p.parseStructFieldAnnotations(structType, schemaData)
 
-				// Store schema globally by struct type name
-				p.globalSchemas[typeSpec.Name.Name] = schemaData
+				p.result.Schemas = append(p.result.Schemas, schemaData)
 			}
 		}
 	}
 }
 
-func (p *Parser) parseStructFieldAnnotations(structType *ast.StructType, schemaData *SchemaData) {
+func (p *fileParser) parseStructFieldAnnotations(structType *ast.StructType, schemaData *SchemaData) {
 	for _, field := range structType.Fields.List {
 		jsonName := p.getFieldJSONName(field)
 		if jsonName == "" {
@@ -446,6 +600,17 @@ func (p *Parser) parseStructFieldAnnotations(structType *ast.StructType, schemaD
 	}
 }
 
+// propertyOrder returns the JSON names of the fields of a struct in declaration order.
+func (p *Parser) propertyOrder(structType *ast.StructType) []string {
+	var names []string
+	for _, field := range structType.Fields.List {
+		if name := p.getFieldJSONName(field); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func (p *Parser) getFieldJSONName(field *ast.Field) string {
 	if len(field.Names) == 0 {
 		return ""
@@ -460,11 +625,11 @@ func (p *Parser) getFieldJSONName(field *ast.Field) string {
 	return jsonName
 }
 
-func (p *Parser) applyFieldAnnotations(field *ast.Field, jsonName string, schemaData *SchemaData) {
+func (p *fileParser) applyFieldAnnotations(field *ast.Field, jsonName string, schemaData *SchemaData) {
 	if field.Doc == nil {
 		return
 	}
-	annotations := p.annotationParser.Parse(field.Doc.Text())
+	annotations := p.annotations(field.Doc, false)
 	for _, a := range annotations {
 		if a.Type == AnnotationField {
 			p.applyFieldInfo(jsonName, GetField(a), schemaData)
@@ -654,6 +819,11 @@ func (p *Parser) typeToSchema(typeName string) *openapi.Schema {
 	return openapi.RefTo(typeName)
 }
 
+// schemaRefName returns the schema name of a reference such as User, []User or User[].
+func schemaRefName(ref string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(ref, "[]"), "[]")
+}
+
 func (p *Parser) parseSchemaRef(ref string) *openapi.Schema {
 	// Check if it's an array type like []User or User[]
 	if strings.HasPrefix(ref, "[]") {
@@ -695,6 +865,58 @@ func (p *Parser) Generate() *openapi.Document {
 	return p.generateDocument(spec)
 }
 
+// Groups returns the names of all groups declared with group= options, sorted.
+func (p *Parser) Groups() []string {
+	names := make([]string, 0, len(p.groups))
+	for name := range p.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetGroupSpec returns the specification of a group, or nil if no annotation declares it.
+// Info, servers, tags, external docs, links and the OpenAPI version are inherited from the
+// default spec when the group does not set them; security schemes are merged, the group's own taking precedence.
+func (p *Parser) GetGroupSpec(name string) *SpecData {
+	group, ok := p.groups[name]
+	if !ok {
+		return nil
+	}
+	spec := *group
+	if spec.Version == "" {
+		spec.Version = p.spec.Version
+	}
+	if spec.Info.Title == "" {
+		spec.Info = p.spec.Info
+	}
+	if spec.Servers == nil {
+		spec.Servers = p.spec.Servers
+	}
+	if spec.Tags == nil {
+		spec.Tags = p.spec.Tags
+	}
+	if spec.ExternalDocs == nil {
+		spec.ExternalDocs = p.spec.ExternalDocs
+	}
+	if spec.Links == nil {
+		spec.Links = p.spec.Links
+	}
+	spec.Securities = make(map[string]*openapi.SecurityScheme)
+	maps.Copy(spec.Securities, p.spec.Securities)
+	maps.Copy(spec.Securities, group.Securities)
+	return &spec
+}
+
+// GenerateGroup generates an OpenAPI document for a single group. Schemas without a group are included in every group.
+func (p *Parser) GenerateGroup(name string) (*openapi.Document, error) {
+	spec := p.GetGroupSpec(name)
+	if spec == nil {
+		return nil, fmt.Errorf("unknown group %q", name)
+	}
+	return p.generateDocument(spec), nil
+}
+
 func (p *Parser) generateDocument(spec *SpecData) *openapi.Document {
 	doc := &openapi.Document{
 		OpenAPI:      spec.Version,
@@ -707,9 +929,47 @@ func (p *Parser) generateDocument(spec *SpecData) *openapi.Document {
 
 	p.addPaths(doc, spec.Operations)
 	p.addComponents(doc, spec)
+	p.recordSourceOrder(doc, spec)
 	return doc
 }
 
+// recordSourceOrder records the order paths, responses, schemas and properties were declared in,
+// so the document can be written in source order.
+func (p *Parser) recordSourceOrder(doc *openapi.Document, spec *SpecData) {
+	var paths []string
+	for _, op := range spec.Operations {
+		if !slices.Contains(paths, op.Path) {
+			paths = append(paths, op.Path)
+		}
+		pointer := yamlnode.Join("/paths", op.Path, strings.ToLower(op.Method), "responses")
+		doc.SetSourceOrder(pointer, op.ResponseOrder)
+	}
+	doc.SetSourceOrder("/paths", paths)
+
+	if doc.Components == nil || len(doc.Components.Schemas) == 0 {
+		return
+	}
+	var names []string
+	for _, name := range p.schemaOrder {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			continue
+		}
+		names = append(names, name)
+		if schemaData := p.schemaData(spec, name); schemaData != nil {
+			doc.SetSourceOrder(yamlnode.Join("/components/schemas", name, "properties"), schemaData.PropertyOrder)
+		}
+	}
+	doc.SetSourceOrder("/components/schemas", names)
+}
+
+// schemaData returns the schema of the given name that a spec uses.
+func (p *Parser) schemaData(spec *SpecData, name string) *SchemaData {
+	if schemaData, ok := spec.Schemas[name]; ok {
+		return schemaData
+	}
+	return p.globalSchemas[name]
+}
+
 func (p *Parser) buildInfo(spec *SpecData) openapi.Info {
 	info := *spec.Info
 	if len(spec.Links) > 0 {
@@ -3,6 +3,7 @@ package parser
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"testing"
 
@@ -34,8 +35,8 @@ func (h *testHelper) writeFile(name, content string) {
 	}
 }
 
-func (h *testHelper) parse() *Parser {
-	p := New()
+func (h *testHelper) parse(opts ...Option) *Parser {
+	p := New(opts...)
 	if err := p.ParseDir(h.tmpDir); err != nil {
 		h.t.Fatalf("ParseDir() error = %v", err)
 	}
@@ -712,3 +713,51 @@ type Order struct {
 	Status string ` + "`json:\"status,omitempty\"`" + `
 }
 `
+
+func TestParser_SourceOrder(t *testing.T) {
+	h := newTestHelper(t)
+	defer h.cleanup()
+
+	h.writeFile("api.go", sourceOrderTestContent)
+
+	doc := h.parse().Generate()
+	want := map[string][]string{
+		"/paths":                                {"/zoos", "/animals"},
+		"/paths/~1zoos/get/responses":           {"404", "200"},
+		"/paths/~1animals/get/responses":        {"200"},
+		"/components/schemas":                   {"Zoo", "Animal"},
+		"/components/schemas/Zoo/properties":    {"name", "city"},
+		"/components/schemas/Animal/properties": {"species", "age"},
+	}
+	if !reflect.DeepEqual(doc.SourceOrder, want) {
+		t.Errorf("SourceOrder = %v, want %v", doc.SourceOrder, want)
+	}
+}
+
+const sourceOrderTestContent = `package main
+
+// !api 3.0.3
+// !info "Test API" v1.0.0 "Description"
+func main() {}
+
+// !GET /zoos -> listZoos "List zoos"
+// !error 404 Zoo "Not found"
+// !ok Zoo "Success"
+func ListZoos() {}
+
+// !GET /animals -> listAnimals "List animals"
+// !ok Animal "Success"
+func ListAnimals() {}
+
+// !model "A zoo"
+type Zoo struct {
+	Name string ` + "`json:\"name\"`" + `
+	City string ` + "`json:\"city\"`" + `
+}
+
+// !model "An animal"
+type Animal struct {
+	Species string ` + "`json:\"species\"`" + `
+	Age     int    ` + "`json:\"age\"`" + `
+}
+`
@@ -0,0 +1,106 @@
+// Package watch detects changes to Go source files by polling directory trees.
+// Polling keeps the package dependency-free and behaves the same on every platform.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileState is what a poll remembers about a file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls Go source files below a set of root directories.
+type Watcher struct {
+	roots    []string
+	interval time.Duration
+	last     map[string]fileState
+}
+
+// New creates a Watcher for the given roots, polling at the given interval.
+func New(roots []string, interval time.Duration) *Watcher {
+	return &Watcher{roots: roots, interval: interval}
+}
+
+// Changed rescans the roots and reports whether any Go file was added, removed or modified
+// since the previous call. The first call records the initial state and reports false.
+func (w *Watcher) Changed() (bool, error) {
+	current, err := w.scan()
+	if err != nil {
+		return false, err
+	}
+	first := w.last == nil
+	changed := !first && !sameFiles(w.last, current)
+	w.last = current
+	return changed, nil
+}
+
+// Run calls onChange after every poll that detects a change, until ctx is canceled.
+// Scan errors, for example a directory removed while scanning, are retried on the next poll.
+func (w *Watcher) Run(ctx context.Context, onChange func()) error {
+	if _, err := w.Changed(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if changed, err := w.Changed(); err == nil && changed {
+				onChange()
+			}
+		}
+	}
+}
+
+func (w *Watcher) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	for _, root := range w.roots {
+		root = filepath.Clean(root)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != root && skipDir(info.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// skipDir mirrors the directories the parser never reads.
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")
+}
+
+func sameFiles(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, state := range a {
+		other, ok := b[path]
+		if !ok || !other.modTime.Equal(state.modTime) || other.size != state.size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Changed(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api", "main.go")
+	writeFile(t, file, "package main\n")
+
+	w := New([]string{dir}, time.Millisecond)
+	if changed, err := w.Changed(); err != nil || changed {
+		t.Fatalf("first Changed() = %v, %v, want false, nil", changed, err)
+	}
+	if changed, _ := w.Changed(); changed {
+		t.Error("expected no change without modifications")
+	}
+
+	writeFile(t, file, "package main\n\n// !api 3.0.3\n")
+	if changed, _ := w.Changed(); !changed {
+		t.Error("expected modified file to be detected")
+	}
+
+	writeFile(t, filepath.Join(dir, "api", "users.go"), "package main\n")
+	if changed, _ := w.Changed(); !changed {
+		t.Error("expected added file to be detected")
+	}
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := w.Changed(); !changed {
+		t.Error("expected removed file to be detected")
+	}
+}
+
+func TestWatcher_IgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+
+	w := New([]string{dir}, time.Millisecond)
+	if _, err := w.Changed(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "README.md"), "# docs\n")
+	writeFile(t, filepath.Join(dir, "vendor", "lib", "lib.go"), "package lib\n")
+	writeFile(t, filepath.Join(dir, ".git", "hooks.go"), "package hooks\n")
+	if changed, _ := w.Changed(); changed {
+		t.Error("expected non-Go files and skipped directories to be ignored")
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	writeFile(t, file, "package main\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	w := New([]string{dir}, 10*time.Millisecond)
+	go func() {
+		done <- w.Run(ctx, func() {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	// Keep modifying until the watcher has taken its initial snapshot and noticed a change.
+	for i := 0; ; i++ {
+		writeFile(t, file, "package main\n"+strings.Repeat("\n", i%2))
+		select {
+		case <-changes:
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Run() error = %v", err)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for change")
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package yamlnode
+
+// namedMaps are the fields of OpenAPI and JSON Schema objects whose values map names, rather than
+// fields, to values.
+var namedMaps = map[string]bool{
+	"paths": true, "webhooks": true, "callbacks": true, "pathItems": true,
+	"schemas": true, "responses": true, "parameters": true, "examples": true, "requestBodies": true,
+	"headers": true, "securitySchemes": true, "links": true, "content": true, "encoding": true,
+	"variables": true, "scopes": true, "mapping": true,
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true,
+	"securityDefinitions": true,
+}
+
+// Scope tells walks over an OpenAPI document what the keys of a mapping are, so that a property or
+// component named example or discriminator is not taken for the field of that name. The zero Scope
+// is that of the document root.
+type Scope struct {
+	Names bool   // The keys are names, such as those of properties or components, rather than fields
+	Field string // The field the mapping is the value of, or for a value of a map of names, that map's field
+}
+
+// Child returns the scope of the value of key in a mapping of this scope.
+func (s Scope) Child(key string) Scope {
+	if s.Names {
+		return Scope{Field: s.Field}
+	}
+	return Scope{Names: namedMaps[key], Field: key}
+}
+
+// Item returns the scope of the items of a sequence of this scope.
+func (s Scope) Item() Scope {
+	return Scope{Field: s.Field}
+}
+
+// IsExample reports whether the value of key is example data, where $ref has no meaning: the
+// example field of schemas, media types, parameters and headers, and the value of Example objects.
+func (s Scope) IsExample(key string) bool {
+	return !s.Names && (key == "example" || key == "value" && s.Field == "examples")
+}
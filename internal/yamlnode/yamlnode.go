@@ -0,0 +1,270 @@
+// Package yamlnode navigates YAML and JSON documents as yaml.Node trees addressed by JSON pointers (RFC 6901).
+// Working on nodes rather than decoded values keeps the line and column of everything that is reported.
+package yamlnode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse parses a YAML or JSON document and returns its root value node.
+func Parse(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty document")
+	}
+	return doc.Content[0], nil
+}
+
+// deref follows alias nodes to the node they refer to.
+func deref(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// Get returns the value of key in a mapping node, or nil if node is not a mapping or has no such key.
+func Get(node *yaml.Node, key string) *yaml.Node {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return deref(node.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// GetKey returns the key node of key in a mapping node, which is where problems with the key itself are reported.
+func GetKey(node *yaml.Node, key string) *yaml.Node {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// Pairs calls fn for each key and value of a mapping node, in document order.
+func Pairs(node *yaml.Node, fn func(key, value *yaml.Node)) {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], deref(node.Content[i+1]))
+	}
+}
+
+// Items returns the elements of a sequence node, or nil if node is not a sequence.
+func Items(node *yaml.Node) []*yaml.Node {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	items := make([]*yaml.Node, len(node.Content))
+	for i, item := range node.Content {
+		items[i] = deref(item)
+	}
+	return items
+}
+
+// Scalar returns the value of a scalar node, or "" if node is not a scalar.
+func Scalar(node *yaml.Node) string {
+	node = deref(node)
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// Escape escapes a single reference token: "~" becomes "~0" and "/" becomes "~1".
+func Escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// Unescape reverses Escape.
+func Unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// Join appends escaped reference tokens to a pointer.
+func Join(pointer string, tokens ...string) string {
+	var sb strings.Builder
+	sb.WriteString(pointer)
+	for _, token := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(Escape(token))
+	}
+	return sb.String()
+}
+
+// Index appends a sequence index to a pointer.
+func Index(pointer string, i int) string {
+	return pointer + "/" + strconv.Itoa(i)
+}
+
+// Resolve returns the node at pointer below root, or nil if there is none.
+// A leading "#" (as in "#/components/schemas/User") is accepted.
+func Resolve(root *yaml.Node, pointer string) *yaml.Node {
+	pointer = strings.TrimPrefix(pointer, "#")
+	node := deref(root)
+	if pointer == "" {
+		return node
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		node = step(node, Unescape(token))
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func step(node *yaml.Node, token string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return Get(node, token)
+	case yaml.SequenceNode:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(node.Content) {
+			return nil
+		}
+		return deref(node.Content[i])
+	default:
+		return nil
+	}
+}
+
+// Walk calls fn for node and every node below it, depth first, together with its pointer.
+// Returning false from fn skips the children of that node.
+func Walk(node *yaml.Node, pointer string, fn func(node *yaml.Node, pointer string) bool) {
+	walk(node, pointer, fn, make(map[*yaml.Node]bool))
+}
+
+func walk(node *yaml.Node, pointer string, fn func(*yaml.Node, string) bool, seen map[*yaml.Node]bool) {
+	node = deref(node)
+	// Anchors can be referenced several times; visit each node once
+	if node == nil || seen[node] {
+		return
+	}
+	seen[node] = true
+	if !fn(node, pointer) {
+		return
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		Pairs(node, func(key, value *yaml.Node) {
+			walk(value, Join(pointer, key.Value), fn, seen)
+		})
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			walk(item, Index(pointer, i), fn, seen)
+		}
+	}
+}
+
+// Position returns the line and column of the node at pointer. For mapping values that is the
+// position of their key, which is where an editor should jump to; 0, 0 means there is no such node.
+func Position(root *yaml.Node, pointer string) (line, column int) {
+	node := Resolve(root, pointer)
+	if node == nil {
+		return 0, 0
+	}
+	if i := strings.LastIndex(pointer, "/"); i >= 0 {
+		if key := GetKey(Resolve(root, pointer[:i]), Unescape(pointer[i+1:])); key != nil {
+			return key.Line, key.Column
+		}
+	}
+	return node.Line, node.Column
+}
+
+// maxRefHops bounds how many $ref indirections FollowRefs follows, so reference cycles cannot loop forever.
+const maxRefHops = 32
+
+// FollowRefs follows local "$ref" chains (JSON References such as "#/components/schemas/User")
+// starting at node. It returns node itself when it is not a reference, or nil when a reference
+// is external, cyclic or cannot be resolved.
+func FollowRefs(root, node *yaml.Node) *yaml.Node {
+	for range maxRefHops {
+		ref := Get(node, "$ref")
+		if ref == nil || ref.Kind != yaml.ScalarNode {
+			return node
+		}
+		if !strings.HasPrefix(ref.Value, "#") {
+			return nil
+		}
+		node = Resolve(root, ref.Value)
+	}
+	return nil
+}
+
+// Clone deep copies a node, replacing aliases with copies of what they refer to.
+func Clone(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return Clone(node.Alias)
+	}
+	c := *node
+	c.Anchor = ""
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = Clone(child)
+	}
+	return &c
+}
+
+// Ref returns the $ref of a mapping node, or "" when it has none.
+func Ref(node *yaml.Node) string {
+	ref := Get(node, "$ref")
+	if ref == nil || ref.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return ref.Value
+}
+
+// String returns a string scalar node.
+func String(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// Mapping returns a mapping node holding the given keys and values.
+func Mapping(content ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: content}
+}
+
+// SetPair sets the value of key in a mapping node, adding the key if it is missing.
+func SetPair(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, String(key), value)
+}
+
+// RemoveKey removes key and its value from a mapping node.
+func RemoveKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
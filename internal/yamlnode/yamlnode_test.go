@@ -0,0 +1,221 @@
+package yamlnode
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testDoc = `openapi: 3.0.3
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+components:
+  schemas:
+    a~b:
+      type: string
+tags:
+  - name: users
+  - name: admin
+`
+
+func TestResolve(t *testing.T) {
+	root, err := Parse([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    string
+		line    int
+	}{
+		{"/openapi", "3.0.3", 1},
+		{"#/paths/~1users~1{id}/get/operationId", "getUser", 5},
+		{"/components/schemas/a~0b/type", "string", 9},
+		{"/tags/1/name", "admin", 12},
+	}
+	for _, tt := range tests {
+		node := Resolve(root, tt.pointer)
+		if node == nil {
+			t.Errorf("Resolve(%q) = nil", tt.pointer)
+			continue
+		}
+		if node.Value != tt.want || node.Line != tt.line {
+			t.Errorf("Resolve(%q) = %q at line %d, want %q at line %d", tt.pointer, node.Value, node.Line, tt.want, tt.line)
+		}
+	}
+
+	for _, pointer := range []string{"/missing", "/tags/2", "/tags/x", "relative", "/openapi/deeper"} {
+		if node := Resolve(root, pointer); node != nil {
+			t.Errorf("Resolve(%q) = %v, want nil", pointer, node.Value)
+		}
+	}
+	if Resolve(root, "") != root || Resolve(root, "#") != root {
+		t.Error("expected empty pointer to resolve to the root")
+	}
+}
+
+func TestJoinEscape(t *testing.T) {
+	if got := Join("/paths", "/users/{id}", "get"); got != "/paths/~1users~1{id}/get" {
+		t.Errorf("Join() = %q", got)
+	}
+	if got := Join("", "a~b"); got != "/a~0b" {
+		t.Errorf("Join() = %q", got)
+	}
+	if got := Index("/tags", 3); got != "/tags/3" {
+		t.Errorf("Index() = %q", got)
+	}
+	if got := Unescape(Escape("~/x~1")); got != "~/x~1" {
+		t.Errorf("Unescape(Escape()) = %q", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root, err := Parse([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pointers []string
+	Walk(root, "", func(node *yaml.Node, pointer string) bool {
+		if node.Kind == yaml.ScalarNode {
+			pointers = append(pointers, pointer)
+		}
+		return pointer != "/components"
+	})
+	want := []string{"/openapi", "/paths/~1users~1{id}/get/operationId", "/tags/0/name", "/tags/1/name"}
+	if len(pointers) != len(want) {
+		t.Fatalf("Walk() visited %v, want %v", pointers, want)
+	}
+	for i := range want {
+		if pointers[i] != want[i] {
+			t.Errorf("pointer %d = %q, want %q", i, pointers[i], want[i])
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	root, err := Parse([]byte("base: &base\n  type: string\ncopy: *base\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Scalar(Resolve(root, "/copy/type")); got != "string" {
+		t.Errorf("alias not followed, got %q", got)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	root, err := Parse([]byte(`{"openapi": "3.1.0", "tags": [{"name": "x"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Scalar(Get(root, "openapi")); got != "3.1.0" {
+		t.Errorf("openapi = %q", got)
+	}
+	if len(Items(Get(root, "tags"))) != 1 {
+		t.Error("expected one tag")
+	}
+	if _, err := Parse(nil); err == nil {
+		t.Error("expected error for empty document")
+	}
+}
+
+func TestPosition(t *testing.T) {
+	root, err := Parse([]byte(testDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pointer   string
+		line, col int
+	}{
+		{"/paths/~1users~1{id}/get", 4, 5},
+		{"/tags/1", 12, 5},
+		{"/tags/1/name", 12, 5},
+		{"", 1, 1},
+		{"/missing", 0, 0},
+	}
+	for _, tt := range tests {
+		line, col := Position(root, tt.pointer)
+		if line != tt.line || col != tt.col {
+			t.Errorf("Position(%q) = %d:%d, want %d:%d", tt.pointer, line, col, tt.line, tt.col)
+		}
+	}
+}
+
+func TestFollowRefs(t *testing.T) {
+	root, err := Parse([]byte(`a: {$ref: "#/b"}
+b: {$ref: "#/c"}
+c: {type: string}
+loop: {$ref: "#/loop"}
+external: {$ref: "other.yaml#/x"}
+missing: {$ref: "#/nope"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FollowRefs(root, Get(root, "a")); got != Get(root, "c") {
+		t.Error("expected reference chain to resolve to c")
+	}
+	if got := FollowRefs(root, Get(root, "c")); got != Get(root, "c") {
+		t.Error("expected non-reference to be returned as is")
+	}
+	for _, key := range []string{"loop", "external", "missing"} {
+		if got := FollowRefs(root, Get(root, key)); got != nil {
+			t.Errorf("FollowRefs(%s) = %v, want nil", key, got)
+		}
+	}
+}
+
+func TestScope(t *testing.T) {
+	root := Scope{}
+	schemas := root.Child("components").Child("schemas")
+	if !schemas.Names {
+		t.Error("keys of components.schemas are not names")
+	}
+	schema := schemas.Child("example")
+	if schema.Names || schema.IsExample("type") {
+		t.Errorf("schema named example: %+v", schema)
+	}
+	if !schema.IsExample("example") {
+		t.Error("example field of a schema is not example data")
+	}
+	properties := schema.Child("properties")
+	if properties.IsExample("example") || properties.IsExample("discriminator") {
+		t.Error("property named example taken for example data")
+	}
+	if property := properties.Child("example"); property.Names || !property.IsExample("example") {
+		t.Errorf("property named example: %+v", property)
+	}
+
+	examples := root.Child("components").Child("examples")
+	if !examples.Child("value").IsExample("value") {
+		t.Error("value of an Example object is not example data")
+	}
+	if examples.IsExample("value") {
+		t.Error("example component named value taken for example data")
+	}
+	if root.Child("components").Child("parameters").Child("value").IsExample("value") {
+		t.Error("value field outside an Example object taken for example data")
+	}
+	if items := root.Child("paths").Child("/a").Child("parameters").Item(); items.Names {
+		t.Error("items of parameters are names")
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	node := Mapping()
+	SetPair(node, "$ref", String("#/a"))
+	SetPair(node, "type", String("object"))
+	if got := Ref(node); got != "#/a" {
+		t.Errorf("Ref() = %q", got)
+	}
+	RemoveKey(node, "$ref")
+	if Ref(node) != "" || Get(node, "type") == nil {
+		t.Errorf("RemoveKey() left %d nodes", len(node.Content))
+	}
+	if got := Ref(String("x")); got != "" {
+		t.Errorf("Ref(scalar) = %q", got)
+	}
+}
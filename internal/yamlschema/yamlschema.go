@@ -0,0 +1,306 @@
+// Package yamlschema checks YAML values against JSON Schema, working on yaml.Node trees so that
+// callers can report positions. It supports the keywords needed to check examples and lint
+// rules: $ref, allOf/anyOf/oneOf, enum, type (including nullable), required, properties,
+// additionalProperties, items, minItems/maxItems, minLength/maxLength, pattern and numeric bounds.
+// Unsupported keywords are ignored.
+package yamlschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// maxDepth bounds the nesting of subschemas that are followed, so recursive schemas terminate.
+const maxDepth = 32
+
+// matcher resolves $refs in schemas against the document root.
+type matcher struct {
+	root *yaml.Node
+}
+
+// keywordMatcher checks one group of schema keywords against a value. It returns a description
+// of the first mismatch, or "" when the value satisfies the keywords.
+type keywordMatcher func(m *matcher, schema, value *yaml.Node, at string, depth int) string
+
+// Match checks value against schema, resolving local $refs against root. It returns a description
+// of the first mismatch, prefixed with the JSON pointer of the offending part of value ("value"
+// for value itself), or "" when value satisfies the schema. Unresolvable references are satisfied.
+func Match(root, schema, value *yaml.Node) string {
+	m := &matcher{root: root}
+	return m.match(schema, value, "", 0)
+}
+
+func (m *matcher) match(schema, value *yaml.Node, at string, depth int) string {
+	if depth > maxDepth {
+		return ""
+	}
+	schema = yamlnode.FollowRefs(m.root, schema)
+	if schema == nil || schema.Kind != yaml.MappingNode {
+		return ""
+	}
+	matchers := []keywordMatcher{matchType, matchEnum, matchComposition, matchObject, matchArray, matchString, matchNumber}
+	for _, match := range matchers {
+		if msg := match(m, schema, value, at, depth); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func location(at string) string {
+	if at == "" {
+		return "value"
+	}
+	return at
+}
+
+// valueType returns the JSON Schema type of a YAML value.
+func valueType(value *yaml.Node) string {
+	switch value.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch value.Tag {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+	return "string"
+}
+
+func schemaTypes(schema *yaml.Node) []string {
+	typeNode := yamlnode.Get(schema, "type")
+	types := []string{}
+	if typeNode != nil && typeNode.Kind == yaml.ScalarNode {
+		types = append(types, typeNode.Value)
+	}
+	for _, item := range yamlnode.Items(typeNode) {
+		types = append(types, item.Value)
+	}
+	if len(types) > 0 && yamlnode.Scalar(yamlnode.Get(schema, "nullable")) == "true" {
+		types = append(types, "null")
+	}
+	return types
+}
+
+func matchType(_ *matcher, schema, value *yaml.Node, at string, _ int) string {
+	types := schemaTypes(schema)
+	if len(types) == 0 {
+		return ""
+	}
+	actual := valueType(value)
+	for _, t := range types {
+		if t == actual || t == "number" && actual == "integer" || t == "integer" && isWholeNumber(value) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", location(at), strings.Join(types, " or "), actual)
+}
+
+func isWholeNumber(value *yaml.Node) bool {
+	if valueType(value) != "number" {
+		return false
+	}
+	f, err := strconv.ParseFloat(value.Value, 64)
+	return err == nil && f == float64(int64(f))
+}
+
+func matchEnum(_ *matcher, schema, value *yaml.Node, at string, _ int) string {
+	enum := yamlnode.Items(yamlnode.Get(schema, "enum"))
+	if len(enum) == 0 {
+		return ""
+	}
+	actual := decodeValue(value)
+	for _, allowed := range enum {
+		if reflect.DeepEqual(actual, decodeValue(allowed)) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: %s is not one of the allowed enum values", location(at), yamlnode.Scalar(value))
+}
+
+// decodeValue decodes a node for comparison, normalizing all numbers to float64.
+func decodeValue(node *yaml.Node) any {
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil
+	}
+	return normalizeNumbers(v)
+}
+
+func normalizeNumbers(v any) any {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	}
+	return v
+}
+
+func matchComposition(m *matcher, schema, value *yaml.Node, at string, depth int) string {
+	for _, sub := range yamlnode.Items(yamlnode.Get(schema, "allOf")) {
+		if msg := m.match(sub, value, at, depth+1); msg != "" {
+			return msg
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		alternatives := yamlnode.Items(yamlnode.Get(schema, keyword))
+		if len(alternatives) > 0 && !m.matchesAny(alternatives, value, at, depth) {
+			return fmt.Sprintf("%s: does not match any %s alternative", location(at), keyword)
+		}
+	}
+	return ""
+}
+
+func (m *matcher) matchesAny(schemas []*yaml.Node, value *yaml.Node, at string, depth int) bool {
+	for _, sub := range schemas {
+		if m.match(sub, value, at, depth+1) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func matchObject(m *matcher, schema, value *yaml.Node, at string, depth int) string {
+	if value.Kind != yaml.MappingNode {
+		return ""
+	}
+	for _, name := range yamlnode.Items(yamlnode.Get(schema, "required")) {
+		if yamlnode.Get(value, name.Value) == nil {
+			return fmt.Sprintf("%s: missing required property %q", location(at), name.Value)
+		}
+	}
+	properties := yamlnode.Get(schema, "properties")
+	additional := yamlnode.Get(schema, "additionalProperties")
+	var msg string
+	yamlnode.Pairs(value, func(key, item *yaml.Node) {
+		if msg != "" {
+			return
+		}
+		if property := yamlnode.Get(properties, key.Value); property != nil {
+			msg = m.match(property, item, yamlnode.Join(at, key.Value), depth+1)
+			return
+		}
+		msg = m.matchAdditional(additional, key.Value, item, at, depth)
+	})
+	return msg
+}
+
+func (m *matcher) matchAdditional(additional *yaml.Node, key string, item *yaml.Node, at string, depth int) string {
+	if additional == nil {
+		return ""
+	}
+	if additional.Kind == yaml.ScalarNode && additional.Value == "false" {
+		return fmt.Sprintf("%s: unexpected property %q", location(at), key)
+	}
+	return m.match(additional, item, yamlnode.Join(at, key), depth+1)
+}
+
+func matchArray(m *matcher, schema, value *yaml.Node, at string, depth int) string {
+	if value.Kind != yaml.SequenceNode {
+		return ""
+	}
+	items := yamlnode.Items(value)
+	if msg := matchBounds(len(items), schema, "minItems", "maxItems", "items", at); msg != "" {
+		return msg
+	}
+	itemSchema := yamlnode.Get(schema, "items")
+	if itemSchema == nil {
+		return ""
+	}
+	for i, item := range items {
+		if msg := m.match(itemSchema, item, yamlnode.Index(at, i), depth+1); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func matchString(_ *matcher, schema, value *yaml.Node, at string, _ int) string {
+	if valueType(value) != "string" {
+		return ""
+	}
+	if msg := matchBounds(utf8.RuneCountInString(value.Value), schema, "minLength", "maxLength", "characters", at); msg != "" {
+		return msg
+	}
+	pattern := yamlnode.Scalar(yamlnode.Get(schema, "pattern"))
+	if pattern == "" {
+		return ""
+	}
+	// Patterns using ECMA-262 features Go does not support are skipped
+	re, err := regexp.Compile(pattern)
+	if err == nil && !re.MatchString(value.Value) {
+		return fmt.Sprintf("%s: %q does not match pattern %q", location(at), value.Value, pattern)
+	}
+	return ""
+}
+
+// matchBounds checks a count against the integer keywords minKey and maxKey.
+func matchBounds(n int, schema *yaml.Node, minKey, maxKey, unit, at string) string {
+	if limit, err := strconv.Atoi(yamlnode.Scalar(yamlnode.Get(schema, minKey))); err == nil && n < limit {
+		return fmt.Sprintf("%s: has %d %s, fewer than %s %d", location(at), n, unit, minKey, limit)
+	}
+	if limit, err := strconv.Atoi(yamlnode.Scalar(yamlnode.Get(schema, maxKey))); err == nil && n > limit {
+		return fmt.Sprintf("%s: has %d %s, more than %s %d", location(at), n, unit, maxKey, limit)
+	}
+	return ""
+}
+
+func matchNumber(_ *matcher, schema, value *yaml.Node, at string, _ int) string {
+	if t := valueType(value); t != "integer" && t != "number" {
+		return ""
+	}
+	n, err := strconv.ParseFloat(value.Value, 64)
+	if err != nil {
+		return ""
+	}
+	if limit, exclusive, ok := numericLimit(schema, "minimum", "exclusiveMinimum"); ok && (n < limit || exclusive && n == limit) {
+		return fmt.Sprintf("%s: %s is below the minimum %s", location(at), value.Value, formatLimit(limit))
+	}
+	if limit, exclusive, ok := numericLimit(schema, "maximum", "exclusiveMaximum"); ok && (n > limit || exclusive && n == limit) {
+		return fmt.Sprintf("%s: %s is above the maximum %s", location(at), value.Value, formatLimit(limit))
+	}
+	return ""
+}
+
+// numericLimit reads a bound that is either inclusive with a boolean exclusive flag (OpenAPI 3.0)
+// or given directly by the exclusive keyword (OpenAPI 3.1+).
+func numericLimit(schema *yaml.Node, inclusiveKey, exclusiveKey string) (limit float64, exclusive, ok bool) {
+	exclusiveNode := yamlnode.Get(schema, exclusiveKey)
+	if exclusiveNode != nil && exclusiveNode.Tag != "!!bool" {
+		if limit, err := strconv.ParseFloat(exclusiveNode.Value, 64); err == nil {
+			return limit, true, true
+		}
+	}
+	limit, err := strconv.ParseFloat(yamlnode.Scalar(yamlnode.Get(schema, inclusiveKey)), 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return limit, yamlnode.Scalar(exclusiveNode) == "true", true
+}
+
+func formatLimit(limit float64) string {
+	return strconv.FormatFloat(limit, 'g', -1, 64)
+}
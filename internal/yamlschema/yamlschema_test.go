@@ -0,0 +1,92 @@
+package yamlschema
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+const testSchemas = `
+schemas:
+  User:
+    type: object
+    required: [id]
+    additionalProperties: false
+    properties:
+      id: {type: integer, minimum: 1}
+      name: {type: string, minLength: 2, pattern: "^[A-Z]"}
+      role: {type: string, enum: [admin, user]}
+      tags: {type: array, maxItems: 2, items: {type: string}}
+      nickname: {type: string, nullable: true}
+      ratio: {type: number, exclusiveMaximum: 1}
+  Pet:
+    oneOf:
+      - {$ref: "#/schemas/User"}
+      - {type: string}
+  Tree:
+    type: object
+    properties:
+      child: {$ref: "#/schemas/Tree"}
+`
+
+func TestMatch(t *testing.T) {
+	root, err := yamlnode.Parse([]byte(testSchemas))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		schema string
+		value  string
+		want   string
+	}{
+		{"User", "{id: 1, name: Ada, role: admin, tags: [a], nickname: null, ratio: 0.5}", ""},
+		{"User", "{id: 1.0}", ""},
+		{"User", "{name: Ada}", `value: missing required property "id"`},
+		{"User", "{id: 0}", "/id: 0 is below the minimum 1"},
+		{"User", `{id: "1"}`, "/id: expected integer, got string"},
+		{"User", "{id: 1, name: A}", "/name: has 1 characters, fewer than minLength 2"},
+		{"User", "{id: 1, name: ada}", `/name: "ada" does not match pattern "^[A-Z]"`},
+		{"User", "{id: 1, role: root}", "/role: root is not one of the allowed enum values"},
+		{"User", "{id: 1, tags: [a, b, c]}", "/tags: has 3 items, more than maxItems 2"},
+		{"User", "{id: 1, tags: [1]}", "/tags/0: expected string, got integer"},
+		{"User", "{id: 1, ratio: 1}", "/ratio: 1 is above the maximum 1"},
+		{"User", "{id: 1, extra: true}", `value: unexpected property "extra"`},
+		{"Pet", "Rex", ""},
+		{"Pet", "{id: 2}", ""},
+		{"Pet", "42", "value: does not match any oneOf alternative"},
+		{"Tree", "{child: {child: {child: {}}}}", ""},
+	}
+	for _, tt := range tests {
+		value, err := yamlnode.Parse([]byte(tt.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema := yamlnode.Resolve(root, "/schemas/"+tt.schema)
+		if got := Match(root, schema, value); got != tt.want {
+			t.Errorf("Match(%s, %s) = %q, want %q", tt.schema, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatch_UnresolvableRef(t *testing.T) {
+	root, _ := yamlnode.Parse([]byte(`s: {$ref: "#/missing"}`))
+	value, _ := yamlnode.Parse([]byte("anything"))
+	if got := Match(root, yamlnode.Get(root, "s"), value); got != "" {
+		t.Errorf("expected unresolvable reference to be satisfied, got %q", got)
+	}
+	if !strings.Contains(Match(nil, mustParse(t, "{type: boolean}"), value), "expected boolean") {
+		t.Error("expected type mismatch without a root document")
+	}
+}
+
+func mustParse(t *testing.T, s string) *yaml.Node {
+	t.Helper()
+	node, err := yamlnode.Parse([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
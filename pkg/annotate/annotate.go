@@ -0,0 +1,1354 @@
+// Package annotate writes an OpenAPI document as Go source carrying YaSwag annotations: a model
+// struct per object schema and a handler stub per operation. Running the parser on the files gives
+// back an equivalent document, which moves a hand-written spec to code-first annotations.
+package annotate
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+// Option configures annotation
+type Option func(*generator)
+
+// WithPackage sets the package name of the files. The default is handlers.
+func WithPackage(name string) Option {
+	return func(g *generator) {
+		g.pkg = name
+	}
+}
+
+// Result is a document written as Go files
+type Result struct {
+	Package  string
+	Files    map[string][]byte // Formatted Go source by file name
+	Warnings []string          // What the annotations cannot express, and so was changed or dropped
+}
+
+// Document annotates an OpenAPI document, keeping paths, schemas and properties in source order.
+func Document(doc *openapi.Document, opts ...Option) (*Result, error) {
+	formatter := output.NewFormatter(output.Options{Format: output.FormatYAML, KeyOrder: output.KeyOrderSource})
+	root, err := formatter.Node(doc)
+	if err != nil {
+		return nil, err
+	}
+	return Node(root, opts...)
+}
+
+// Node annotates a document held as a YAML node, such as one read from a file. The node is not modified.
+func Node(root *yaml.Node, opts ...Option) (*Result, error) {
+	g := &generator{
+		pkg:     "handlers",
+		modelAt: make(map[string]*model),
+		names:   make(map[string]bool),
+		files:   make(map[string][]*handler),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if !token.IsIdentifier(g.pkg) {
+		return nil, fmt.Errorf("invalid package name %q", g.pkg)
+	}
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	if yamlnode.Get(root, "swagger") != nil {
+		return nil, fmt.Errorf("swagger 2.0 documents are not supported, upgrade the document to OpenAPI 3.x first")
+	}
+	if err := root.Decode(&g.doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if !strings.HasPrefix(g.doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", g.doc.OpenAPI)
+	}
+	g.root = root
+	return g.generate()
+}
+
+// Paths returns the files of the result in lexical order.
+func (r *Result) Paths() []string {
+	paths := make([]string, 0, len(r.Files))
+	for p := range r.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write writes the files to dir, creating it as needed. Unless overwrite is set, nothing is
+// written when one of the files already exists, so handlers that were filled in are not lost.
+func (r *Result) Write(dir string, overwrite bool) error {
+	if !overwrite {
+		for _, name := range r.Paths() {
+			file := filepath.Join(dir, name)
+			if _, err := os.Stat(file); err == nil {
+				return fmt.Errorf("%s already exists", file)
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	for _, name := range r.Paths() {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, r.Files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// maxDepth bounds how deep schemas are followed, so reference cycles cannot loop forever.
+const maxDepth = 32
+
+// Files that are not named after a tag
+const (
+	docFile     = "doc.go"
+	modelsFile  = "models.go"
+	handlerFile = "handlers.go"
+)
+
+// methods are the operations of a path item the route annotation supports, in the order they are written.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// reserved are names a model cannot take: the Go predeclared types and the types annotations
+// understand as primitives rather than schema references.
+var reserved = map[string]bool{
+	"any": true, "array": true, "bool": true, "boolean": true, "byte": true, "double": true,
+	"error": true, "float": true, "float32": true, "float64": true, "init": true, "int": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "integer": true, "main": true,
+	"number": true, "object": true, "rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true,
+}
+
+// wordPattern matches what annotations accept as a tag, security scheme or type name.
+var wordPattern = regexp.MustCompile(`^\w+$`)
+
+// paramNamePattern matches what annotations accept as a parameter or field name.
+var paramNamePattern = regexp.MustCompile(`^[\w-]+$`)
+
+// scopePattern matches what the !scope annotation accepts as a scope name.
+var scopePattern = regexp.MustCompile(`^[\w:]+$`)
+
+// versionPattern matches what the !api and !info annotations accept as a version.
+var versionPattern = regexp.MustCompile(`^[\d.]+`)
+
+// generator holds the state of a single annotation run
+type generator struct {
+	pkg      string
+	root     *yaml.Node
+	doc      openapi.Document
+	models   []*model          // Models in the order they are written
+	modelAt  map[string]*model // Models by the JSON pointer of their schema
+	names    map[string]bool   // Identifiers declared at package level
+	files    map[string][]*handler
+	schemes  map[string]string // Annotation names of security schemes
+	warnings []string
+	usesTime bool
+}
+
+// model is a struct generated from an object schema
+type model struct {
+	name        string
+	schema      string // Name of the component schema, empty for inline schemas
+	description string
+	fields      []*field
+}
+
+// field is a struct field generated from a schema property
+type field struct {
+	name        string
+	jsonName    string
+	goType      string
+	typeName    string // Type in the !field annotation
+	description string
+	example     string
+	required    bool
+}
+
+// handler is a handler stub generated from an operation
+type handler struct {
+	name        string
+	route       string // METHOD path
+	description string
+	annotations []string
+}
+
+// property is a schema property collected from a schema and its allOf members
+type property struct {
+	name    string
+	schema  *openapi.Schema
+	pointer string
+}
+
+func (g *generator) warnf(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+func (g *generator) generate() (*Result, error) {
+	g.declareModels()
+	for _, m := range g.models {
+		g.buildModel(m, g.doc.Components.Schemas[m.schema], yamlnode.Join("/components/schemas", m.schema))
+	}
+	g.declareSchemes()
+	g.collectHandlers()
+	if len(g.doc.Webhooks) > 0 {
+		g.warnf("webhooks are dropped: annotations cannot express them")
+	}
+
+	result := &Result{Package: g.pkg, Files: make(map[string][]byte)}
+	sources := map[string]string{docFile: g.renderDoc()}
+	if len(g.models) > 0 {
+		sources[modelsFile] = g.renderModels()
+	}
+	for file, handlers := range g.files {
+		sources[file] = g.renderHandlers(handlers)
+	}
+	for name, src := range sources {
+		data, err := format.Source([]byte(src))
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", name, err)
+		}
+		result.Files[name] = data
+	}
+	result.Warnings = g.warnings
+	return result, nil
+}
+
+// declareModels declares a model for every component schema that describes an object. Schemas
+// whose name is a valid identifier keep it, so references stay the same; others are renamed.
+func (g *generator) declareModels() {
+	if g.doc.Components == nil {
+		return
+	}
+	names := keys(g.root, "/components/schemas", g.doc.Components.Schemas)
+	var renamed []string
+	for _, name := range names {
+		schema := g.doc.Components.Schemas[name]
+		if !isModel(schema) {
+			continue
+		}
+		m := &model{schema: name, name: name}
+		if !isIdentifier(name) {
+			renamed = append(renamed, name)
+		} else {
+			g.names[name] = true
+		}
+		g.models = append(g.models, m)
+		g.modelAt[yamlnode.Join("/components/schemas", name)] = m
+	}
+	for _, name := range renamed {
+		m := g.modelAt[yamlnode.Join("/components/schemas", name)]
+		m.name = g.declare(identifier(name, "Schema"), "Schema")
+		g.warnf("schema %q is renamed to %s: its name is not a valid Go identifier", name, m.name)
+	}
+	for _, name := range names {
+		if !isModel(g.doc.Components.Schemas[name]) {
+			g.warnf("schema %q is not an object and is inlined where it is used", name)
+		}
+	}
+}
+
+// declare declares an identifier at package level, adding suffix and then a number when it is taken.
+func (g *generator) declare(name, suffix string) string {
+	candidate := name
+	for i := 1; g.names[candidate] || reserved[candidate]; i++ {
+		candidate = name + suffix
+		if i > 1 {
+			candidate += strconv.Itoa(i)
+		}
+	}
+	g.names[candidate] = true
+	return candidate
+}
+
+// isModel reports whether a component schema becomes a struct: it has properties, combines
+// schemas with allOf, or is an object that is not a map.
+func isModel(s *openapi.Schema) bool {
+	if s == nil || s.Ref != "" {
+		return false
+	}
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return true
+	}
+	typ, _ := schemaType(s)
+	return typ == openapi.TypeObject && s.AdditionalProperties == nil && len(s.OneOf) == 0 && len(s.AnyOf) == 0
+}
+
+// inlineModel returns the model of an inline object schema, declaring it under name the first time.
+func (g *generator) inlineModel(s *openapi.Schema, pointer, name string) *model {
+	if m, ok := g.modelAt[pointer]; ok {
+		return m
+	}
+	m := &model{name: g.declare(name, "Model")}
+	g.modelAt[pointer] = m
+	g.models = append(g.models, m)
+	g.buildModel(m, s, pointer)
+	return m
+}
+
+func (g *generator) buildModel(m *model, s *openapi.Schema, pointer string) {
+	context := "schema " + m.name
+	m.description = g.text(s.Description, context)
+	if len(s.AllOf) > 0 {
+		g.warnf("%s: allOf is flattened into one struct", context)
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		g.warnf("%s: oneOf and anyOf are dropped", context)
+	}
+	g.warnDropped(context, droppedKeywords(s))
+
+	var properties []property
+	var required []string
+	g.collect(s, pointer, &properties, &required, 0)
+
+	fieldNames := make(map[string]bool)
+	for _, p := range properties {
+		f := &field{jsonName: p.name, required: slices.Contains(required, p.name)}
+		f.name = uniqueField(fieldNames, identifier(p.name, "Field"))
+		f.goType = g.goType(p.schema, p.pointer, m.name+f.name, context, 0)
+		if f.goType == m.name {
+			f.goType = "*" + f.goType
+		}
+		f.typeName = g.typeName(p.schema, 0)
+		fieldContext := fmt.Sprintf("%s property %s", context, p.name)
+		g.checkSchema(p.schema, f.goType, fieldContext)
+		f.description = g.text(p.schema.Description, fieldContext)
+		example := p.schema.Example
+		if example == nil && len(p.schema.Examples) > 0 {
+			example = p.schema.Examples[0]
+		}
+		if example, ok := exampleValue(example); ok {
+			f.example = example
+		}
+		if !paramNamePattern.MatchString(p.name) {
+			g.warnf("%s: the name cannot be annotated, so its description and example are dropped", fieldContext)
+		}
+		m.fields = append(m.fields, f)
+	}
+}
+
+// annotatedFormats are the formats that generate gives the Go types and annotation types annotate
+// writes. Other types have no format.
+var annotatedFormats = map[string]string{
+	"int": "int32", "integer": "int32", "int64": "int64",
+	"float32": "float", "float": "float", "float64": "double", "number": "double",
+	"time.Time": "date-time",
+}
+
+// checkSchema warns about the keywords of a property or parameter schema that annotations cannot
+// carry, and about a format that changes because the type written for it always has another one.
+// Models are checked where they are declared.
+func (g *generator) checkSchema(s *openapi.Schema, written, context string) {
+	for depth := 0; s != nil && s.Ref != "" && depth <= maxDepth; depth++ {
+		target, pointer := g.schemaRef(s.Ref)
+		if _, ok := g.modelAt[pointer]; ok {
+			return
+		}
+		s = target
+	}
+	if s == nil || s.Ref != "" || len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return
+	}
+	dropped := droppedKeywords(s)
+	switch typ, _ := schemaType(s); typ {
+	case openapi.TypeArray:
+		if item, ok := strings.CutPrefix(written, "[]"); ok {
+			g.checkSchema(s.Items, item, context+" items")
+		} else if s.Items != nil {
+			dropped = append(dropped, "items")
+		}
+	case openapi.TypeObject:
+	default:
+		want := annotatedFormats[strings.TrimPrefix(written, "*")]
+		switch {
+		case s.Format == want:
+		case want == "":
+			dropped = append(dropped, "format "+s.Format)
+		case s.Format == "":
+			g.warnf("%s: gains format %s, which %s always has", context, want, written)
+		default:
+			g.warnf("%s: format %s becomes %s", context, s.Format, want)
+		}
+	}
+	g.warnDropped(context, dropped)
+}
+
+// droppedKeywords returns the keywords of a schema that neither annotations nor Go types carry.
+func droppedKeywords(s *openapi.Schema) []string {
+	return setKeywords([]keyword{
+		{"title", s.Title != ""},
+		{"default", s.Default != nil},
+		{"enum", len(s.Enum) > 0},
+		{"minimum", s.Minimum != nil},
+		{"maximum", s.Maximum != nil},
+		{"exclusiveMinimum", s.ExclusiveMinimum != nil || s.ExclusiveMinimumFlag != nil},
+		{"exclusiveMaximum", s.ExclusiveMaximum != nil || s.ExclusiveMaximumFlag != nil},
+		{"multipleOf", s.MultipleOf != nil},
+		{"minLength", s.MinLength != nil},
+		{"maxLength", s.MaxLength != nil},
+		{"pattern", s.Pattern != ""},
+		{"minItems", s.MinItems != nil},
+		{"maxItems", s.MaxItems != nil},
+		{"uniqueItems", s.UniqueItems},
+		{"minProperties", s.MinProperties != nil},
+		{"maxProperties", s.MaxProperties != nil},
+		{"not", s.Not != nil},
+		{"readOnly", s.ReadOnly},
+		{"writeOnly", s.WriteOnly},
+		{"deprecated", s.Deprecated},
+		{"discriminator", s.Discriminator != nil},
+		{"externalDocs", s.ExternalDocs != nil},
+		{"xml", s.XML != nil},
+	})
+}
+
+// droppedParameterKeywords returns the fields of a parameter that annotations cannot carry.
+func droppedParameterKeywords(param *openapi.Parameter) []string {
+	return setKeywords([]keyword{
+		{"deprecated", param.Deprecated},
+		{"allowEmptyValue", param.AllowEmptyValue},
+		{"style", param.Style != ""},
+		{"explode", param.Explode != nil},
+		{"allowReserved", param.AllowReserved},
+		{"examples", len(param.Examples) > 0},
+	})
+}
+
+// keyword is a keyword of a schema or parameter and whether a document sets it.
+type keyword struct {
+	name string
+	set  bool
+}
+
+// setKeywords returns the names of the keywords that are set.
+func setKeywords(keywords []keyword) []string {
+	var names []string
+	for _, k := range keywords {
+		if k.set {
+			names = append(names, k.name)
+		}
+	}
+	return names
+}
+
+// warnDropped warns about keywords that are dropped, in one warning.
+func (g *generator) warnDropped(context string, dropped []string) {
+	switch len(dropped) {
+	case 0:
+	case 1:
+		g.warnf("%s: %s is dropped", context, dropped[0])
+	default:
+		g.warnf("%s: %s and %s are dropped", context, strings.Join(dropped[:len(dropped)-1], ", "), dropped[len(dropped)-1])
+	}
+}
+
+// collect gathers the properties and required names of a schema and its allOf members, following $refs.
+func (g *generator) collect(s *openapi.Schema, pointer string, properties *[]property, required *[]string, depth int) {
+	if s == nil || depth > maxDepth {
+		return
+	}
+	if s.Ref != "" {
+		target, targetPointer := g.schemaRef(s.Ref)
+		g.collect(target, targetPointer, properties, required, depth+1)
+		return
+	}
+	for i, member := range s.AllOf {
+		g.collect(member, yamlnode.Index(pointer+"/allOf", i), properties, required, depth+1)
+	}
+	for _, name := range keys(g.root, pointer+"/properties", s.Properties) {
+		p := property{name: name, schema: s.Properties[name], pointer: yamlnode.Join(pointer+"/properties", name)}
+		if i := slices.IndexFunc(*properties, func(q property) bool { return q.name == name }); i >= 0 {
+			(*properties)[i] = p
+			continue
+		}
+		*properties = append(*properties, p)
+	}
+	for _, name := range s.Required {
+		if !slices.Contains(*required, name) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaRef resolves a local schema $ref one step, returning the target and its pointer.
+func (g *generator) schemaRef(ref string) (*openapi.Schema, string) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, ""
+	}
+	pointer := strings.TrimPrefix(ref, "#")
+	node := yamlnode.Resolve(g.root, pointer)
+	if node == nil {
+		return nil, ""
+	}
+	var s openapi.Schema
+	if err := node.Decode(&s); err != nil {
+		return nil, ""
+	}
+	return &s, pointer
+}
+
+// goType returns the Go type of a struct field that yields the schema. Inline objects become models named name.
+func (g *generator) goType(s *openapi.Schema, pointer, name, context string, depth int) string {
+	if s == nil || depth > maxDepth {
+		return "any"
+	}
+	if s.Ref != "" {
+		target, targetPointer := g.schemaRef(s.Ref)
+		if target == nil {
+			g.warnf("%s: $ref %s cannot be resolved and becomes any", context, s.Ref)
+			return "any"
+		}
+		if m, ok := g.modelAt[targetPointer]; ok {
+			return m.name
+		}
+		return g.goType(target, targetPointer, name, context, depth+1)
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return g.goType(s.AllOf[0], pointer+"/allOf/0", name, context, depth+1)
+	}
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return g.inlineModel(s, pointer, name).name
+	}
+
+	typ, nullable := schemaType(s)
+	switch typ {
+	case openapi.TypeArray:
+		return "[]" + g.goType(s.Items, pointer+"/items", name+"Item", context, depth+1)
+	case openapi.TypeObject:
+		if s.AdditionalProperties != nil && !s.AdditionalProperties.IsFalse() {
+			return "map[string]" + g.goType(s.AdditionalProperties, pointer+"/additionalProperties", name+"Value", context, depth+1)
+		}
+		return "any"
+	case openapi.TypeString:
+		if s.Format == "date-time" {
+			g.usesTime = true
+			return "time.Time"
+		}
+	case "":
+		if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+			g.warnf("%s: oneOf and anyOf become any", context)
+		}
+		return "any"
+	}
+	goType := scalarGoType(typ, s.Format)
+	if nullable {
+		goType = "*" + goType
+	}
+	return goType
+}
+
+func scalarGoType(typ, format string) string {
+	switch typ {
+	case openapi.TypeInteger:
+		if format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case openapi.TypeNumber:
+		if format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case openapi.TypeBoolean:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// typeName returns the type of a schema as parameter and field annotations write it: a primitive
+// such as int64 or string, or the name of a model.
+func (g *generator) typeName(s *openapi.Schema, depth int) string {
+	if s == nil || depth > maxDepth {
+		return openapi.TypeString
+	}
+	if s.Ref != "" {
+		target, targetPointer := g.schemaRef(s.Ref)
+		if m, ok := g.modelAt[targetPointer]; ok {
+			return m.name
+		}
+		return g.typeName(target, depth+1)
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return g.typeName(s.AllOf[0], depth+1)
+	}
+	typ, _ := schemaType(s)
+	switch typ {
+	case openapi.TypeInteger:
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return openapi.TypeInteger
+	case openapi.TypeNumber:
+		if s.Format == "float" {
+			return "float"
+		}
+		return openapi.TypeNumber
+	case openapi.TypeString, openapi.TypeBoolean, openapi.TypeArray, openapi.TypeObject:
+		return typ
+	}
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return openapi.TypeObject
+	}
+	return openapi.TypeString
+}
+
+// schemaType returns the non-null type of a schema and whether null is allowed, as either 3.0 or 3.1 write it.
+func schemaType(s *openapi.Schema) (string, bool) {
+	typ, nullable := "", s.Nullable
+	for _, t := range s.Type {
+		if t == openapi.TypeNull {
+			nullable = true
+		} else if typ == "" {
+			typ = t
+		}
+	}
+	return typ, nullable
+}
+
+// declareSchemes gives every security scheme a name the !security and !secure annotations accept.
+func (g *generator) declareSchemes() {
+	g.schemes = make(map[string]string)
+	if g.doc.Components == nil {
+		return
+	}
+	for name := range g.doc.Components.SecuritySchemes {
+		g.schemes[name] = g.word(name, "security scheme")
+	}
+}
+
+// word makes a tag or security scheme name out of word characters only, as annotations require.
+func (g *generator) word(name, kind string) string {
+	if wordPattern.MatchString(name) {
+		return name
+	}
+	w := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return '_'
+		}
+		return r
+	}, name)
+	g.warnf("%s %q is renamed to %s: annotations only accept letters, digits and underscores", kind, name, w)
+	return w
+}
+
+func (g *generator) collectHandlers() {
+	for _, path := range keys(g.root, "/paths", g.doc.Paths) {
+		item := g.doc.Paths[path]
+		pointer := yamlnode.Join("/paths", path)
+		if item.Ref != "" {
+			g.warnf("%s: path item $refs are not followed, convert the document with yaswag bundle first", path)
+			continue
+		}
+		if item.Trace != nil {
+			g.warnf("TRACE %s is dropped: annotations cannot express TRACE operations", path)
+		}
+		for _, method := range methods {
+			op := operation(item, method)
+			if op == nil {
+				continue
+			}
+			h := g.handler(item, op, strings.ToUpper(method), path, yamlnode.Join(pointer, method))
+			file := handlerFile
+			if len(op.Tags) > 0 {
+				file = tagFile(op.Tags[0])
+			}
+			g.files[file] = append(g.files[file], h)
+		}
+	}
+}
+
+func operation(item *openapi.PathItem, method string) *openapi.Operation {
+	switch method {
+	case "get":
+		return item.Get
+	case "put":
+		return item.Put
+	case "post":
+		return item.Post
+	case "delete":
+		return item.Delete
+	case "options":
+		return item.Options
+	case "head":
+		return item.Head
+	case "patch":
+		return item.Patch
+	}
+	return nil
+}
+
+// tagFile returns the file of the handlers whose first tag is tag.
+func tagFile(tag string) string {
+	base := strings.ToLower(identifier(tag, ""))
+	if base == "" || base+".go" == docFile || base+".go" == modelsFile {
+		return handlerFile
+	}
+	return base + ".go"
+}
+
+func (g *generator) handler(item *openapi.PathItem, op *openapi.Operation, method, path, pointer string) *handler {
+	route := method + " " + path
+	h := &handler{route: route, description: op.Description}
+
+	operationID := op.OperationID
+	base := identifier(operationID, "")
+	if base == "" {
+		base = identifier(strings.ToLower(method)+" "+path, "")
+	}
+	if base[0] >= '0' && base[0] <= '9' {
+		base = "Op" + base
+	}
+	h.name = g.declare(base, "Handler")
+	if operationID == "" || strings.ContainsFunc(operationID, unicode.IsSpace) {
+		replacement := strings.ToLower(h.name[:1]) + h.name[1:]
+		if operationID == "" {
+			g.warnf("%s has no operationId, annotations require one: it becomes %s", route, replacement)
+		} else {
+			g.warnf("%s: operationId %q cannot be annotated with spaces and becomes %s", route, operationID, replacement)
+		}
+		operationID = replacement
+	}
+
+	line := fmt.Sprintf("!%s %s -> %s", method, path, operationID)
+	if summary := g.text(op.Summary, route+" summary"); summary != "" {
+		line += " " + quote(summary)
+	}
+	for _, tag := range op.Tags {
+		line += " #" + g.word(tag, "tag")
+	}
+	h.annotations = append(h.annotations, line)
+
+	h.annotations = append(h.annotations, g.secure(op, route)...)
+	h.annotations = append(h.annotations, g.parameters(item, op, route, pointer)...)
+	if op.RequestBody != nil {
+		if line := g.body(op.RequestBody, route, pointer+"/requestBody", h.name+"Request"); line != "" {
+			h.annotations = append(h.annotations, line)
+		}
+	}
+	for _, status := range keys(g.root, pointer+"/responses", op.Responses) {
+		if line := g.response(op.Responses[status], status, route, yamlnode.Join(pointer+"/responses", status), h.name); line != "" {
+			h.annotations = append(h.annotations, line)
+		}
+	}
+	if len(op.Callbacks) > 0 {
+		g.warnf("%s: callbacks are dropped", route)
+	}
+	if op.Deprecated {
+		g.warnf("%s: deprecated is dropped", route)
+	}
+	return h
+}
+
+func (g *generator) secure(op *openapi.Operation, route string) []string {
+	requirements := op.Security
+	if requirements == nil {
+		requirements = g.doc.Security
+	}
+	var names []string
+	for _, requirement := range requirements {
+		if len(requirement) > 1 {
+			g.warnf("%s: the schemes of a security requirement become alternatives, annotations cannot require them together", route)
+		}
+		for _, name := range sortedKeys(requirement) {
+			if !slices.Contains(names, g.schemes[name]) && g.schemes[name] != "" {
+				names = append(names, g.schemes[name])
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []string{"!secure " + strings.Join(names, " ")}
+}
+
+func (g *generator) parameters(item *openapi.PathItem, op *openapi.Operation, route, pointer string) []string {
+	type located struct {
+		param   *openapi.Parameter
+		pointer string
+	}
+	var params []located
+	add := func(list []*openapi.Parameter, listPointer string) {
+		for i, param := range list {
+			paramPointer := yamlnode.Index(listPointer, i)
+			if param.Ref != "" {
+				var target openapi.Parameter
+				if paramPointer = g.decodeRef(param.Ref, &target); paramPointer == "" {
+					g.warnf("%s: parameter $ref %s cannot be resolved", route, param.Ref)
+					continue
+				}
+				param = &target
+			}
+			i := slices.IndexFunc(params, func(p located) bool {
+				return p.param.Name == param.Name && p.param.In == param.In
+			})
+			if i >= 0 {
+				params[i] = located{param, paramPointer}
+				continue
+			}
+			params = append(params, located{param, paramPointer})
+		}
+	}
+	add(item.Parameters, pointer[:strings.LastIndex(pointer, "/")]+"/parameters")
+	add(op.Parameters, pointer+"/parameters")
+
+	var lines []string
+	for _, p := range params {
+		if line := g.parameter(p.param, route); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (g *generator) parameter(param *openapi.Parameter, route string) string {
+	context := fmt.Sprintf("%s parameter %s", route, param.Name)
+	if !paramNamePattern.MatchString(param.Name) {
+		g.warnf("%s is dropped: annotations only accept letters, digits, underscores and hyphens in names", context)
+		return ""
+	}
+	schema := param.Schema
+	if schema == nil {
+		for _, mediaType := range sortedKeys(param.Content) {
+			schema = param.Content[mediaType].Schema
+			break
+		}
+	}
+	typeName := g.typeName(schema, 0)
+	line := fmt.Sprintf("!%s %s:%s", param.In, param.Name, typeName)
+	g.warnDropped(context, droppedParameterKeywords(param))
+	if schema != nil && schema.Default != nil && param.Example == nil {
+		// The default is carried by the default= of the annotation
+		defaultless := *schema
+		defaultless.Default = nil
+		schema = &defaultless
+	}
+	g.checkSchema(schema, typeName, context)
+	if description := g.text(param.Description, context); description != "" {
+		line += " " + quote(description)
+	}
+
+	value := param.Example
+	if value == nil && schema != nil {
+		value = schema.Default
+	}
+	if value != nil {
+		if example, ok := exampleValue(value); ok && !strings.ContainsAny(example, " \t\"") {
+			line += " default=" + example
+		} else {
+			g.warnf("%s: the default %v cannot be annotated and is dropped", context, value)
+		}
+	}
+	if param.Required && param.In != openapi.ParameterInPath {
+		line += " required"
+	}
+	return line
+}
+
+// decodeRef decodes the target of a local $ref, following $ref chains, and returns its pointer or "" when it cannot be resolved.
+func (g *generator) decodeRef(ref string, v any) string {
+	for range maxDepth {
+		if !strings.HasPrefix(ref, "#") {
+			return ""
+		}
+		pointer := strings.TrimPrefix(ref, "#")
+		node := yamlnode.Resolve(g.root, pointer)
+		if node == nil {
+			return ""
+		}
+		if next := yamlnode.Get(node, "$ref"); next != nil && next.Kind == yaml.ScalarNode {
+			ref = next.Value
+			continue
+		}
+		if err := node.Decode(v); err != nil {
+			return ""
+		}
+		return pointer
+	}
+	return ""
+}
+
+func (g *generator) body(body *openapi.RequestBody, route, pointer, name string) string {
+	context := route + " request body"
+	if body.Ref != "" {
+		var target openapi.RequestBody
+		if pointer = g.decodeRef(body.Ref, &target); pointer == "" {
+			g.warnf("%s: $ref %s cannot be resolved", context, body.Ref)
+			return ""
+		}
+		body = &target
+	}
+	schema, schemaPointer := g.mediaSchema(body.Content, pointer+"/content", context)
+	ref := g.schemaReference(schema, schemaPointer, name, context)
+	if ref == "" {
+		g.warnf("%s is dropped: annotations can only reference a model or an array of models", context)
+		return ""
+	}
+	line := "!body " + ref
+	if description := g.text(body.Description, context); description != "" {
+		line += " " + quote(description)
+	}
+	if body.Required {
+		line += " required"
+	}
+	return line
+}
+
+func (g *generator) response(response *openapi.Response, status, route, pointer, handlerName string) string {
+	context := fmt.Sprintf("%s response %s", route, status)
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		g.warnf("%s is dropped: annotations only accept numeric status codes", context)
+		return ""
+	}
+	if response.Ref != "" {
+		var target openapi.Response
+		if pointer = g.decodeRef(response.Ref, &target); pointer == "" {
+			g.warnf("%s: $ref %s cannot be resolved", context, response.Ref)
+			return ""
+		}
+		response = &target
+	}
+	if len(response.Headers) > 0 {
+		g.warnf("%s: headers are dropped", context)
+	}
+
+	ref := "-"
+	if schema, schemaPointer := g.mediaSchema(response.Content, pointer+"/content", context); schema != nil {
+		name := handlerName + "Response"
+		if code >= 300 {
+			name = handlerName + status + "Response"
+		}
+		if ref = g.schemaReference(schema, schemaPointer, name, context); ref == "" {
+			g.warnf("%s: the schema is dropped, annotations can only reference a model or an array of models", context)
+			ref = "-"
+		}
+	}
+
+	keyword := "ok"
+	if code >= 400 {
+		keyword = "error"
+	}
+	line := fmt.Sprintf("!%s %s %s", keyword, status, ref)
+	if description := g.text(response.Description, context); description != "" {
+		line += " " + quote(description)
+	}
+	return line
+}
+
+// mediaSchema returns the schema of the JSON media type of a request or response, or of the
+// first media type when there is no JSON one. Annotations always write application/json.
+func (g *generator) mediaSchema(content map[string]openapi.MediaType, pointer, context string) (*openapi.Schema, string) {
+	mediaTypes := keys(g.root, pointer, content)
+	if len(mediaTypes) == 0 {
+		return nil, ""
+	}
+	mediaType := mediaTypes[0]
+	if slices.Contains(mediaTypes, "application/json") {
+		mediaType = "application/json"
+	}
+	if mediaType != "application/json" || len(mediaTypes) > 1 {
+		g.warnf("%s: only application/json is annotated, with the schema of %s", context, mediaType)
+	}
+	return content[mediaType].Schema, yamlnode.Join(pointer, mediaType, "schema")
+}
+
+// schemaReference returns how !body and !ok annotations refer to a schema: the name of its model,
+// or []name for an array of models. Inline objects become models named name. It returns "" when
+// the schema is neither.
+func (g *generator) schemaReference(s *openapi.Schema, pointer, name, context string) string {
+	if s == nil {
+		return ""
+	}
+	typ, _ := schemaType(s)
+	if typ == openapi.TypeArray && s.Items != nil {
+		item := g.goType(s.Items, pointer+"/items", name+"Item", context, 0)
+		if g.isModelName(item) {
+			return "[]" + item
+		}
+		return ""
+	}
+	goType := g.goType(s, pointer, name, context, 0)
+	if g.isModelName(goType) {
+		return goType
+	}
+	if typ == openapi.TypeObject || (typ == "" && s.Ref == "" && len(s.OneOf) == 0 && len(s.AnyOf) == 0) {
+		// A free-form object still gets a model, so the body or response keeps a schema
+		return g.inlineModel(s, pointer, name).name
+	}
+	return ""
+}
+
+func (g *generator) isModelName(name string) bool {
+	return slices.ContainsFunc(g.models, func(m *model) bool { return m.name == name })
+}
+
+// text fits a description on one annotation line, joining its lines and replacing double quotes.
+func (g *generator) text(s, context string) string {
+	t := strings.ReplaceAll(strings.Join(strings.Fields(s), " "), `"`, "'")
+	if strings.ContainsAny(strings.TrimSpace(s), "\"\n") {
+		g.warnf("%s: the description is rewritten to fit on one annotation line", context)
+	}
+	return t
+}
+
+// quote quotes text that g.text made fit on an annotation line. Annotations do not unescape,
+// so it cannot use %q.
+func quote(s string) string {
+	return `"` + s + `"`
+}
+
+// exampleValue formats a scalar example as annotations write it, quoting strings with spaces.
+func exampleValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, "\"\n") {
+			return "", false
+		}
+		if strings.ContainsAny(v, " \t") {
+			return `"` + v + `"`, true
+		}
+		return v, true
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(v), true
+	case time.Time:
+		return v.Format(time.RFC3339), true
+	}
+	return "", false
+}
+
+func (g *generator) renderDoc() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Package %s holds the handlers and models of %s.\n", g.pkg, g.doc.Info.Title)
+	b.WriteString("//\n")
+	for _, line := range g.apiAnnotations() {
+		fmt.Fprintf(&b, "// %s\n", line)
+	}
+	fmt.Fprintf(&b, "package %s\n", g.pkg)
+	return b.String()
+}
+
+func (g *generator) apiAnnotations() []string {
+	info := g.doc.Info
+	version := strings.TrimPrefix(g.doc.OpenAPI, "v")
+	lines := []string{"!api " + version}
+
+	title := g.text(info.Title, "info title")
+	if title == "" {
+		g.warnf("info has no title, annotations require one: it becomes API")
+		title = "API"
+	}
+	infoVersion := strings.TrimPrefix(info.Version, "v")
+	if prefix := versionPattern.FindString(infoVersion); prefix != infoVersion {
+		g.warnf("info version %q cannot be annotated: annotations only accept digits and dots", info.Version)
+		infoVersion = strings.TrimRight(prefix, ".")
+		if infoVersion == "" {
+			infoVersion = "0"
+		}
+	}
+	line := fmt.Sprintf("!info %s v%s", quote(title), infoVersion)
+	if description := g.text(info.Description, "info description"); description != "" {
+		line += " " + quote(description)
+	}
+	lines = append(lines, line)
+
+	if c := info.Contact; c != nil {
+		line := "!contact " + quote(g.text(c.Name, "contact name"))
+		if c.Email != "" {
+			line += " <" + c.Email + ">"
+		}
+		if c.URL != "" {
+			line += " (" + c.URL + ")"
+		}
+		lines = append(lines, line)
+	}
+	if l := info.License; l != nil {
+		name := l.Name
+		if name == "" {
+			name = l.Identifier
+		}
+		if strings.ContainsFunc(name, unicode.IsSpace) {
+			g.warnf("license name %q cannot be annotated with spaces: they become hyphens", name)
+			name = strings.Join(strings.Fields(name), "-")
+		}
+		line := "!license " + name
+		if l.URL != "" {
+			line += " " + l.URL
+		}
+		lines = append(lines, line)
+	}
+	if info.TermsOfService != "" {
+		lines = append(lines, "!tos "+info.TermsOfService)
+	}
+	if docs := g.doc.ExternalDocs; docs != nil {
+		lines = append(lines, withDescription("!externalDocs "+docs.URL, g.text(docs.Description, "externalDocs description")))
+	}
+	for _, server := range g.doc.Servers {
+		if len(server.Variables) > 0 {
+			g.warnf("server %s: variables are dropped", server.URL)
+		}
+		lines = append(lines, withDescription("!server "+server.URL, g.text(server.Description, "server "+server.URL)))
+	}
+	for _, tag := range g.doc.Tags {
+		lines = append(lines, withDescription("!tag "+g.word(tag.Name, "tag"), g.text(tag.Description, "tag "+tag.Name)))
+	}
+	if g.doc.Components != nil {
+		for _, name := range keys(g.root, "/components/securitySchemes", g.doc.Components.SecuritySchemes) {
+			lines = append(lines, g.security(name, g.doc.Components.SecuritySchemes[name])...)
+		}
+	}
+	return lines
+}
+
+func withDescription(line, description string) string {
+	if description == "" {
+		return line
+	}
+	return line + " " + quote(description)
+}
+
+// security returns the !security annotation of a security scheme and the !scope annotations of its OAuth2 scopes.
+func (g *generator) security(name string, scheme *openapi.SecurityScheme) []string {
+	context := "security scheme " + name
+	if scheme.Ref != "" {
+		var target openapi.SecurityScheme
+		if g.decodeRef(scheme.Ref, &target) == "" {
+			g.warnf("%s: $ref %s cannot be resolved", context, scheme.Ref)
+			return nil
+		}
+		scheme = &target
+	}
+	description := quote(g.text(scheme.Description, context))
+	word := g.schemes[name]
+	switch scheme.Type {
+	case "apiKey":
+		return []string{fmt.Sprintf("!security %s:apiKey:%s %s %s", word, scheme.In, description, scheme.Name)}
+	case "http":
+		if scheme.BearerFormat != "" {
+			g.warnf("%s: bearerFormat is dropped", context)
+		}
+		return []string{fmt.Sprintf("!security %s:http:%s %s", word, scheme.Scheme, description)}
+	case "openIdConnect":
+		return []string{fmt.Sprintf("!security %s:openIdConnect %s %s", word, description, scheme.OpenIDConnectURL)}
+	case "oauth2":
+		return g.oauth2(name, word, description, scheme.Flows)
+	}
+	g.warnf("%s is dropped: annotations cannot express %s schemes", context, scheme.Type)
+	return nil
+}
+
+func (g *generator) oauth2(name, word, description string, flows *openapi.OAuthFlows) []string {
+	context := "security scheme " + name
+	if flows == nil {
+		return []string{fmt.Sprintf("!security %s:oauth2 %s", word, description)}
+	}
+	candidates := []struct {
+		name string
+		flow *openapi.OAuthFlow
+	}{
+		{"implicit", flows.Implicit},
+		{"password", flows.Password},
+		{"clientCredentials", flows.ClientCredentials},
+		{"authorizationCode", flows.AuthorizationCode},
+	}
+	var lines []string
+	for _, c := range candidates {
+		if c.flow == nil {
+			continue
+		}
+		if lines != nil {
+			g.warnf("%s: the %s flow is dropped, annotations express a single flow", context, c.name)
+			continue
+		}
+		url := c.flow.TokenURL
+		if c.name == "implicit" || c.name == "authorizationCode" {
+			url = c.flow.AuthorizationURL
+		}
+		if c.name == "authorizationCode" && c.flow.TokenURL != "" && c.flow.TokenURL != url {
+			g.warnf("%s: the tokenUrl of the authorizationCode flow becomes its authorizationUrl", context)
+		}
+		lines = append(lines, fmt.Sprintf("!security %s:oauth2:%s %s %s", word, c.name, description, url))
+		scopesPointer := yamlnode.Join("/components/securitySchemes", name, "flows", c.name, "scopes")
+		for _, scope := range keys(g.root, scopesPointer, c.flow.Scopes) {
+			if !scopePattern.MatchString(scope) {
+				g.warnf("%s: scope %q is dropped, annotations only accept letters, digits, underscores and colons", context, scope)
+				continue
+			}
+			lines = append(lines, withDescription(fmt.Sprintf("!scope %s %s", word, scope), g.text(c.flow.Scopes[scope], context+" scope "+scope)))
+		}
+	}
+	return lines
+}
+
+func (g *generator) renderModels() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg)
+	if g.usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	for _, m := range g.models {
+		if m.schema != "" && m.schema != m.name {
+			fmt.Fprintf(&b, "// %s models the %q schema.\n", m.name, m.schema)
+		} else if m.schema != "" {
+			fmt.Fprintf(&b, "// %s models the %s schema.\n", m.name, m.schema)
+		} else {
+			fmt.Fprintf(&b, "// %s models an inline schema.\n", m.name)
+		}
+		b.WriteString("//\n")
+		fmt.Fprintf(&b, "// %s\n", withDescription("!model", m.description))
+		fmt.Fprintf(&b, "type %s struct {\n", m.name)
+		for i, f := range m.fields {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if paramNamePattern.MatchString(f.jsonName) {
+				fmt.Fprintf(&b, "\t// %s\n", f.annotation())
+			}
+			tag := f.jsonName
+			if !f.required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`\n", f.name, f.goType, tag)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+func (f *field) annotation() string {
+	line := withDescription(fmt.Sprintf("!field %s:%s", f.jsonName, f.typeName), f.description)
+	if f.required {
+		line += " required"
+	}
+	if f.example != "" {
+		line += " example=" + f.example
+	}
+	return line
+}
+
+func (g *generator) renderHandlers(handlers []*handler) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg)
+	b.WriteString("import \"net/http\"\n\n")
+	for _, h := range handlers {
+		fmt.Fprintf(&b, "// %s handles %s.\n", h.name, h.route)
+		if description := commentText(h.description); description != "" {
+			b.WriteString("//\n")
+			b.WriteString(description)
+		}
+		b.WriteString("//\n")
+		for _, line := range h.annotations {
+			fmt.Fprintf(&b, "// %s\n", line)
+		}
+		fmt.Fprintf(&b, "func %s(w http.ResponseWriter, r *http.Request) {\n", h.name)
+		b.WriteString("\thttp.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)\n")
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+// commentText writes a description as comment lines, leaving out lines that would read as annotations.
+func commentText(s string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(strings.TrimSpace(line), "!") {
+			continue
+		}
+		if line == "" {
+			b.WriteString("//\n")
+			continue
+		}
+		fmt.Fprintf(&b, "// %s\n", line)
+	}
+	return b.String()
+}
+
+// identifier turns a name into an exported Go identifier: "pet-status" becomes PetStatus. It
+// returns fallback followed by the identifier when that starts with a digit.
+func identifier(name, fallback string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	id := b.String()
+	if id == "" || unicode.IsDigit(rune(id[0])) {
+		return fallback + id
+	}
+	return id
+}
+
+// isIdentifier reports whether a schema name can be used as a Go type name as it is.
+func isIdentifier(name string) bool {
+	return token.IsIdentifier(name) && wordPattern.MatchString(name) && !reserved[name]
+}
+
+// uniqueField returns name, numbered when the struct already has a field of that name.
+func uniqueField(names map[string]bool, name string) string {
+	candidate := name
+	for i := 2; names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	names[candidate] = true
+	return candidate
+}
+
+// keys returns the keys of m in the order the mapping at pointer declares them, followed by any
+// others in lexical order.
+func keys[V any](root *yaml.Node, pointer string, m map[string]V) []string {
+	var ordered []string
+	seen := make(map[string]bool)
+	yamlnode.Pairs(yamlnode.Resolve(root, pointer), func(key, _ *yaml.Node) {
+		if _, ok := m[key.Value]; ok && !seen[key.Value] {
+			ordered = append(ordered, key.Value)
+			seen[key.Value] = true
+		}
+	})
+	var rest []string
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(ordered, rest...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
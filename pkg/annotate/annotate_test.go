@@ -0,0 +1,459 @@
+package annotate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/parser"
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+const spec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.2.0
+  description: |
+    Pets of the "store".
+    Second line.
+servers:
+  - url: https://api.example.com
+    description: Production
+tags:
+  - name: pets
+    description: Everything about pets
+security:
+  - api_key: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      description: Lists every pet.
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, description: How many pets to return, schema: {type: integer, format: int32}, example: 20}
+        - {name: X-Trace, in: header, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: A page of pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+        "400":
+          $ref: "#/components/responses/Problem"
+    post:
+      operationId: createPet
+      tags: [pets]
+      security:
+        - oauth: [write:pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+        default:
+          description: Unexpected error
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, description: Pet id, schema: {type: integer, format: int64}}
+    delete:
+      operationId: deletePet
+      tags: [pets]
+      responses:
+        "204": {description: Deleted}
+components:
+  responses:
+    Problem:
+      description: Bad request
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  securitySchemes:
+    api_key: {type: apiKey, in: header, name: X-API-Key, description: API key}
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://auth.example.com/authorize
+          scopes:
+            write:pets: modify pets
+  schemas:
+    Pet:
+      type: object
+      description: A pet
+      required: [id, name]
+      properties:
+        id: {type: integer, format: int64, example: 10}
+        name: {type: string, description: Name of the pet, example: Rex the dog}
+        tag: {type: string, nullable: true}
+        owner: {$ref: "#/components/schemas/Owner"}
+        born: {type: string, format: date-time}
+        labels: {type: object, additionalProperties: {type: string}}
+        nick-name: {type: string, description: What the pet answers to}
+    Owner:
+      allOf:
+        - $ref: "#/components/schemas/Named"
+        - type: object
+          properties:
+            email: {type: string}
+    Named:
+      type: object
+      properties:
+        name: {type: string}
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+    Kind:
+      type: string
+      enum: [dog, cat]
+`
+
+func annotateSpec(t *testing.T, opts ...Option) *Result {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Node(root, opts...)
+	if err != nil {
+		t.Fatalf("Node() error = %v", err)
+	}
+	return result
+}
+
+func TestNode(t *testing.T) {
+	result := annotateSpec(t, WithPackage("petstore"))
+
+	if got, want := result.Paths(), []string{"doc.go", "models.go", "pets.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Paths() = %v, want %v", got, want)
+	}
+	for _, name := range result.Paths() {
+		if !strings.HasPrefix(string(result.Files[name]), "package petstore") && !strings.Contains(string(result.Files[name]), "\npackage petstore\n") {
+			t.Errorf("%s is not in package petstore:\n%s", name, result.Files[name])
+		}
+	}
+
+	contains := map[string][]string{
+		"doc.go": {
+			`// !api 3.0.3`,
+			`// !info "Pets" v1.2.0 "Pets of the 'store'. Second line."`,
+			`// !server https://api.example.com "Production"`,
+			`// !tag pets "Everything about pets"`,
+			`// !security api_key:apiKey:header "API key" X-API-Key`,
+			`// !security oauth:oauth2:implicit "" https://auth.example.com/authorize`,
+			`// !scope oauth write:pets "modify pets"`,
+		},
+		"models.go": {
+			"import \"time\"",
+			"// !model \"A pet\"\ntype Pet struct {",
+			"// !field id:int64 required example=10\n\tId int64 `json:\"id\"`",
+			"// !field name:string \"Name of the pet\" required example=\"Rex the dog\"\n\tName string `json:\"name\"`",
+			"Tag *string `json:\"tag,omitempty\"`",
+			"Owner Owner `json:\"owner,omitempty\"`",
+			"Born time.Time `json:\"born,omitempty\"`",
+			"Labels map[string]string `json:\"labels,omitempty\"`",
+			"type CreatePetRequest struct {",
+		},
+		"pets.go": {
+			"// ListPets handles GET /pets.\n//\n// Lists every pet.\n//\n",
+			`// !GET /pets -> listPets "List pets" #pets`,
+			`// !secure api_key`,
+			`// !query limit:integer "How many pets to return" default=20`,
+			`// !header X-Trace:string required`,
+			`// !ok 200 []Pet "A page of pets"`,
+			`// !error 400 Error "Bad request"`,
+			`// !secure oauth`,
+			`// !body CreatePetRequest required`,
+			`// !ok 201 Pet "Created"`,
+			`// !path id:int64 "Pet id"`,
+			`// !ok 204 - "Deleted"`,
+			"func DeletePet(w http.ResponseWriter, r *http.Request) {",
+		},
+	}
+	for name, wants := range contains {
+		for _, want := range wants {
+			if !strings.Contains(string(result.Files[name]), want) {
+				t.Errorf("%s does not contain %q:\n%s", name, want, result.Files[name])
+			}
+		}
+	}
+
+	wantWarnings := []string{
+		`schema "Kind" is not an object and is inlined where it is used`,
+		"schema Owner: allOf is flattened into one struct",
+		"POST /pets response default is dropped: annotations only accept numeric status codes",
+		"info description: the description is rewritten to fit on one annotation line",
+	}
+	for _, want := range wantWarnings {
+		if !slices.Contains(result.Warnings, want) {
+			t.Errorf("Warnings = %q, missing %q", result.Warnings, want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := annotateSpec(t).Write(dir, false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	p := parser.New()
+	if err := p.ParseDir(dir); err != nil {
+		t.Fatalf("ParseDir() error = %v", err)
+	}
+	for _, d := range p.Diagnostics() {
+		t.Errorf("unexpected diagnostic: %s", d)
+	}
+	doc := p.Generate()
+
+	if doc.OpenAPI != "3.0.3" || doc.Info.Title != "Pets" || doc.Info.Version != "1.2.0" {
+		t.Errorf("info = %s %+v", doc.OpenAPI, doc.Info)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com" {
+		t.Errorf("Servers = %+v", doc.Servers)
+	}
+
+	list := doc.Paths["/pets"].Get
+	if list == nil || list.OperationID != "listPets" || list.Summary != "List pets" || !reflect.DeepEqual(list.Tags, []string{"pets"}) {
+		t.Fatalf("GET /pets = %+v", list)
+	}
+	if len(list.Parameters) != 2 || list.Parameters[0].Name != "limit" || list.Parameters[0].Example != int64(20) || !list.Parameters[1].Required {
+		t.Errorf("GET /pets parameters = %+v", list.Parameters)
+	}
+	if got := list.Responses["200"].Content["application/json"].Schema; got.Items == nil || got.Items.Ref != "#/components/schemas/Pet" {
+		t.Errorf("GET /pets 200 schema = %+v", got)
+	}
+	if got := list.Responses["400"]; got.Description != "Bad request" || got.Content["application/json"].Schema.Ref != "#/components/schemas/Error" {
+		t.Errorf("GET /pets 400 = %+v", got)
+	}
+
+	create := doc.Paths["/pets"].Post
+	if create == nil || create.RequestBody == nil || !create.RequestBody.Required {
+		t.Fatalf("POST /pets = %+v", create)
+	}
+	if !reflect.DeepEqual(create.Security, []openapi.SecurityRequirement{{"oauth": {}}}) {
+		t.Errorf("POST /pets security = %v", create.Security)
+	}
+	if del := doc.Paths["/pets/{id}"].Delete; del == nil || len(del.Parameters) != 1 || del.Parameters[0].In != openapi.ParameterInPath {
+		t.Errorf("DELETE /pets/{id} = %+v", del)
+	}
+
+	schemas := doc.Components.Schemas
+	pet := schemas["Pet"]
+	if pet == nil || pet.Description != "A pet" || !reflect.DeepEqual(pet.Required, []string{"id", "name"}) {
+		t.Fatalf("Pet = %+v", pet)
+	}
+	if got := pet.Properties["id"]; got.Format != "int64" || got.Example != int64(10) {
+		t.Errorf("Pet.id = %+v", got)
+	}
+	if got := pet.Properties["name"]; got.Description != "Name of the pet" || got.Example != "Rex the dog" {
+		t.Errorf("Pet.name = %+v", got)
+	}
+	if got := pet.Properties["tag"]; !got.Nullable {
+		t.Errorf("Pet.tag = %+v, want nullable", got)
+	}
+	if got := pet.Properties["owner"]; got.Ref != "#/components/schemas/Owner" {
+		t.Errorf("Pet.owner = %+v", got)
+	}
+	if got := pet.Properties["born"]; got.Format != "date-time" {
+		t.Errorf("Pet.born = %+v", got)
+	}
+	if got := pet.Properties["nick-name"]; got == nil || got.Description != "What the pet answers to" {
+		t.Errorf("Pet.nick-name = %+v", got)
+	}
+	if owner := schemas["Owner"]; owner == nil || owner.Properties["name"] == nil || owner.Properties["email"] == nil {
+		t.Errorf("Owner = %+v, want the properties of Named and its own", owner)
+	}
+	if _, ok := schemas["Kind"]; ok {
+		t.Errorf("Kind is not an object and should not become a model")
+	}
+	if doc.Components.SecuritySchemes["api_key"].Name != "X-API-Key" {
+		t.Errorf("api_key = %+v", doc.Components.SecuritySchemes["api_key"])
+	}
+	if flow := doc.Components.SecuritySchemes["oauth"].Flows.Implicit; flow == nil || flow.Scopes["write:pets"] != "modify pets" {
+		t.Errorf("oauth implicit flow = %+v", flow)
+	}
+}
+
+func TestNode_Names(t *testing.T) {
+	root, err := yamlnode.Parse([]byte(`openapi: 3.1.0
+info: {title: Names, version: 1.0.0-beta}
+tags:
+  - name: pet-store
+paths:
+  /items/{item-id}:
+    get:
+      tags: [pet-store]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/line-item"}
+components:
+  schemas:
+    line-item:
+      type: object
+      properties:
+        node: {$ref: "#/components/schemas/line-item"}
+    GetItemsItemId:
+      type: object
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Node(root)
+	if err != nil {
+		t.Fatalf("Node() error = %v", err)
+	}
+
+	contains := map[string][]string{
+		"doc.go":      {`// !info "Names" v1.0.0`, `// !tag pet_store`},
+		"models.go":   {"type LineItem struct {", "Node *LineItem", "type GetItemsItemId struct {"},
+		"petstore.go": {`// !GET /items/{item-id} -> getItemsItemIdHandler #pet_store`, "// !ok 200 LineItem", "func GetItemsItemIdHandler("},
+	}
+	for name, wants := range contains {
+		for _, want := range wants {
+			if !strings.Contains(string(result.Files[name]), want) {
+				t.Errorf("%s does not contain %q:\n%s", name, want, result.Files[name])
+			}
+		}
+	}
+	for _, want := range []string{
+		`schema "line-item" is renamed to LineItem: its name is not a valid Go identifier`,
+		`info version "1.0.0-beta" cannot be annotated: annotations only accept digits and dots`,
+		`tag "pet-store" is renamed to pet_store: annotations only accept letters, digits and underscores`,
+	} {
+		if !slices.Contains(result.Warnings, want) {
+			t.Errorf("Warnings = %q, missing %q", result.Warnings, want)
+		}
+	}
+}
+
+func TestNode_DroppedKeywords(t *testing.T) {
+	root, err := yamlnode.Parse([]byte(`openapi: 3.0.3
+info: {title: Keywords, version: 1.0.0}
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - {name: page, in: query, schema: {type: integer, minimum: 1, default: 1}}
+        - {name: id, in: query, deprecated: true, schema: {type: string, format: uuid}}
+        - {name: sort, in: query, schema: {$ref: "#/components/schemas/Sort"}}
+        - {name: limit, in: query, schema: {type: integer, format: int32}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Item"}
+components:
+  schemas:
+    Sort: {type: string, enum: [asc, desc]}
+    Item:
+      type: object
+      maxProperties: 10
+      properties:
+        id: {type: string, format: uuid, readOnly: true}
+        email: {type: string, format: email, pattern: "@", minLength: 3}
+        count: {type: integer, maximum: 100}
+        price: {type: number, format: double}
+        ratio: {type: number, format: decimal}
+        tags: {type: array, items: {type: string, maxLength: 8}}
+        born: {type: string, format: date-time}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Node(root)
+	if err != nil {
+		t.Fatalf("Node() error = %v", err)
+	}
+
+	want := []string{
+		"schema Item: maxProperties is dropped",
+		"schema Item property id: readOnly and format uuid are dropped",
+		"schema Item property email: minLength, pattern and format email are dropped",
+		"schema Item property count: gains format int32, which int always has",
+		"schema Item property count: maximum is dropped",
+		"schema Item property ratio: format decimal becomes double",
+		"schema Item property tags items: maxLength is dropped",
+		"GET /items parameter page: gains format int32, which integer always has",
+		"GET /items parameter page: minimum is dropped",
+		"GET /items parameter id: deprecated is dropped",
+		"GET /items parameter id: format uuid is dropped",
+		"GET /items parameter sort: enum is dropped",
+	}
+	for _, w := range want {
+		if !slices.Contains(result.Warnings, w) {
+			t.Errorf("Warnings = %q, missing %q", result.Warnings, w)
+		}
+	}
+	for _, w := range result.Warnings {
+		if strings.Contains(w, "price") || strings.Contains(w, "born") || strings.Contains(w, "parameter limit") {
+			t.Errorf("unexpected warning %q", w)
+		}
+	}
+}
+
+func TestNode_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		opts []Option
+	}{
+		{"swagger 2.0", "swagger: \"2.0\"\ninfo: {title: A, version: 1.0.0}\n", nil},
+		{"not a document", "- a\n- b\n", nil},
+		{"invalid package", "openapi: 3.0.3\ninfo: {title: A, version: 1.0.0}\n", []Option{WithPackage("my-api")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := yamlnode.Parse([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Node(root, tt.opts...); err == nil {
+				t.Error("Node() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestWrite_Existing(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pets.go"), []byte("package handlers\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := annotateSpec(t)
+	if err := result.Write(dir, false); err == nil {
+		t.Fatal("Write() error = nil, want an error for an existing file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "models.go")); !os.IsNotExist(err) {
+		t.Errorf("models.go was written although pets.go exists")
+	}
+	if err := result.Write(dir, true); err != nil {
+		t.Fatalf("Write() with overwrite error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "pets.go"))
+	if err != nil || !strings.Contains(string(data), "func ListPets(") {
+		t.Errorf("pets.go was not overwritten: %s", data)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 	"gopkg.in/yaml.v3"
 )
@@ -20,14 +22,42 @@ const (
 	SeverityInfo    Severity = "INFO"
 )
 
+// severityRanks orders severities from least to most severe.
+var severityRanks = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity parses a severity name, ignoring case. "warn" is accepted for WARNING.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToUpper(s))
+	if sev == "WARN" {
+		sev = SeverityWarning
+	}
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("unknown severity %q (use error, warning or info)", s)
+	}
+	return sev, nil
+}
+
+// AtLeast reports whether s is as severe as other or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRanks[s] >= severityRanks[other]
+}
+
 // Finding represents a single audit finding
 type Finding struct {
 	RuleID         string   `json:"rule_id"`
 	RuleName       string   `json:"rule_name"`
 	Severity       Severity `json:"severity"`
 	Location       string   `json:"location"`
+	Pointer        string   `json:"pointer,omitempty"`
+	Line           int      `json:"line,omitempty"`
+	Column         int      `json:"column,omitempty"`
 	Message        string   `json:"message"`
 	Recommendation string   `json:"recommendation"`
+	Fingerprint    string   `json:"fingerprint,omitempty"`
 }
 
 // TagCoverage tracks security coverage for a tag
@@ -52,18 +82,54 @@ type AuditResult struct {
 	EndpointsBySecurity  map[string][]string           `json:"endpoints_by_security"`
 	CoverageByTag        map[string]TagCoverage        `json:"coverage_by_tag"`
 	SecuritySchemes      map[string]SecuritySchemeInfo `json:"security_schemes"`
+	Source               string                        `json:"source,omitempty"`
+	Suppressed           int                           `json:"suppressed,omitempty"`
+	Baselined            int                           `json:"baselined,omitempty"`
+	Fixed                int                           `json:"fixed,omitempty"`
 }
 
 // Auditor performs security audits on OpenAPI documents
 type Auditor struct {
-	rules []Rule
+	rules  []Rule
+	config *Config
+}
+
+// Option configures an Auditor
+type Option func(*Auditor)
+
+// WithConfig applies an audit config: disabled rules are skipped, severities are overridden
+// and ignored findings are left out of the result.
+func WithConfig(cfg *Config) Option {
+	return func(a *Auditor) {
+		if cfg != nil {
+			a.config = cfg
+		}
+	}
+}
+
+// WithRules adds rules to the default ones, such as organization-specific checks or the rules
+// of an ExecPlugin.
+func WithRules(rules ...Rule) Option {
+	return func(a *Auditor) {
+		a.rules = append(a.rules, rules...)
+	}
 }
 
 // New creates a new Auditor with default rules
-func New() *Auditor {
-	return &Auditor{
-		rules: DefaultRules(),
+func New(opts ...Option) *Auditor {
+	a := &Auditor{
+		rules:  DefaultRules(),
+		config: &Config{},
+	}
+	for _, opt := range opts {
+		opt(a)
 	}
+	return a
+}
+
+// Rules returns the rules the auditor runs, including those its config disables.
+func (a *Auditor) Rules() []Rule {
+	return a.rules
 }
 
 // Audit performs a security audit on an OpenAPI document
@@ -83,22 +149,48 @@ func (a *Auditor) Audit(doc *openapi.Document) *AuditResult {
 	// Analyze tag coverage
 	a.analyzeTagCoverage(doc, result)
 
-	// Run all audit rules
+	// Run all enabled audit rules. Fingerprints identify array elements by what they are, looked up
+	// in the document as a YAML node.
+	var root yaml.Node
+	_ = root.Encode(doc)
 	for _, rule := range a.rules {
-		findings := rule.Check(doc)
-		result.Findings = append(result.Findings, findings...)
+		if !a.config.enabled(rule.ID()) {
+			continue
+		}
+		severity := a.config.severity(rule)
+		for _, f := range rule.Check(doc) {
+			if a.config.ignores(f) {
+				result.Suppressed++
+				continue
+			}
+			f.Severity = severity
+			keyed := f
+			keyed.Pointer = identityPointer(&root, f.Pointer)
+			f.Fingerprint = Fingerprint(keyed)
+			result.Findings = append(result.Findings, f)
+		}
 	}
 
 	return result
 }
 
+// HasFindings reports whether the result has findings at least as severe as sev.
+func (r *AuditResult) HasFindings(sev Severity) bool {
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(sev) {
+			return true
+		}
+	}
+	return false
+}
+
 // AuditFile audits an OpenAPI specification file
 func (a *Auditor) AuditFile(path string) (*AuditResult, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
-	return a.AuditData(data)
+	return a.auditSource(data, path)
 }
 
 // AuditData audits OpenAPI specification bytes (JSON or YAML)
@@ -108,7 +200,47 @@ func (a *Auditor) AuditData(data []byte) (*AuditResult, error) {
 	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("failed to parse spec: %w", err)
 	}
-	return a.Audit(&doc), nil
+	result := a.Audit(&doc)
+	if root, err := yamlnode.Parse(data); err == nil {
+		suppressInline(root, result)
+		locateFindings(root, result.Findings)
+	}
+	return result, nil
+}
+
+// suppressInline removes findings that an x-yaswag-ignore extension suppresses.
+func suppressInline(root *yaml.Node, result *AuditResult) {
+	kept := result.Findings[:0]
+	for _, f := range result.Findings {
+		if ignoredInline(root, f) {
+			result.Suppressed++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	result.Findings = kept
+}
+
+func (a *Auditor) auditSource(data []byte, source string) (*AuditResult, error) {
+	result, err := a.AuditData(data)
+	if result != nil {
+		result.Source = source
+	}
+	return result, err
+}
+
+// locateFindings sets the line and column of findings from their pointers.
+func locateFindings(root *yaml.Node, findings []Finding) {
+	for i := range findings {
+		if findings[i].Pointer != "" {
+			findings[i].Line, findings[i].Column = yamlnode.Position(root, findings[i].Pointer)
+		}
+	}
+}
+
+// operationPointer returns the JSON pointer of an operation.
+func operationPointer(path, method string) string {
+	return yamlnode.Join("/paths", path, strings.ToLower(method))
 }
 
 // AuditURL audits an OpenAPI specification from a URL
@@ -128,7 +260,7 @@ func (a *Auditor) AuditURL(url string) (*AuditResult, error) {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return a.AuditData(data)
+	return a.auditSource(data, url)
 }
 
 // analyzeSecuritySchemes extracts security scheme information
@@ -221,6 +221,11 @@ func TestScopeValidationRule(t *testing.T) {
 						{"oauth2": {"read", "admin"}}, // admin is not defined
 					},
 				},
+				Post: &openapi.Operation{
+					Security: []openapi.SecurityRequirement{
+						{"oauth2": {"admin", "delete"}}, // neither is defined
+					},
+				},
 			},
 		},
 	}
@@ -228,13 +233,22 @@ func TestScopeValidationRule(t *testing.T) {
 	rule := &ScopeValidationRule{}
 	findings := rule.Check(doc)
 
-	if len(findings) != 1 {
-		t.Errorf("got %d findings, want 1 (undefined 'admin' scope)", len(findings))
+	if len(findings) != 3 {
+		t.Fatalf("got %d findings, want 3 (undefined 'admin' and 'delete' scopes)", len(findings))
 	}
 
-	if len(findings) > 0 && !strings.Contains(findings[0].Message, "admin") {
+	if !strings.Contains(findings[0].Message, "admin") {
 		t.Errorf("Message should mention 'admin' scope, got %s", findings[0].Message)
 	}
+
+	// Each undefined scope has its own pointer, so its own fingerprint
+	fingerprints := make(map[string]bool)
+	for _, f := range findings {
+		fingerprints[Fingerprint(f)] = true
+	}
+	if len(fingerprints) != len(findings) {
+		t.Errorf("got %d fingerprints for %d findings", len(fingerprints), len(findings))
+	}
 }
 
 func TestDeprecatedSecurityRule(t *testing.T) {
@@ -417,16 +431,27 @@ func TestTagCoverage(t *testing.T) {
 func TestDefaultRules(t *testing.T) {
 	rules := DefaultRules()
 
-	if len(rules) != 5 {
-		t.Errorf("DefaultRules() returned %d rules, want 5", len(rules))
+	if len(rules) != 16 {
+		t.Errorf("DefaultRules() returned %d rules, want 16", len(rules))
 	}
 
 	expectedIDs := map[string]bool{
-		"UNPROTECTED_WRITE":     false,
-		"API_KEY_IN_QUERY":      false,
-		"OAUTH_HTTP":            false,
-		"DEPRECATED_NO_SECURITY": false,
-		"SCOPE_NOT_DEFINED":     false,
+		"UNPROTECTED_WRITE":          false,
+		"API_KEY_IN_QUERY":           false,
+		"OAUTH_HTTP":                 false,
+		"DEPRECATED_NO_SECURITY":     false,
+		"SCOPE_NOT_DEFINED":          false,
+		"UNBOUNDED_ARRAY":            false,
+		"UNBOUNDED_STRING":           false,
+		"INTEGER_ID_IN_PATH":         false,
+		"MISSING_AUTH_RESPONSES":     false,
+		"MISSING_RATE_LIMIT":         false,
+		"HTTP_SERVER_URL":            false,
+		"BASIC_AUTH_NO_TLS":          false,
+		"OPEN_ADDITIONAL_PROPERTIES": false,
+		"SENSITIVE_FIELD_EXPOSED":    false,
+		"BEARER_FORMAT_MISSING":      false,
+		"AUTH_HEADER_PARAM":          false,
 	}
 
 	for _, rule := range rules {
@@ -0,0 +1,166 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// Fingerprint returns a stable identifier for a finding: its rule ID and a hash of what it is
+// about, its location and pointer. Line numbers and the message are left out, so editing unrelated
+// parts of a spec or rewording a rule keeps fingerprints. Audit fingerprints findings with the
+// array indices of their pointers replaced by identityPointer, so reordering parameters, servers
+// or scopes keeps them too.
+func Fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{f.RuleID, f.Location, f.Pointer}, "\x00")))
+	return f.RuleID + ":" + hex.EncodeToString(sum[:8])
+}
+
+// identityPointer returns pointer with each array index replaced by the identity of the element it
+// selects, as elementIdentity returns it.
+func identityPointer(root *yaml.Node, pointer string) string {
+	if !strings.HasPrefix(pointer, "/") {
+		return pointer
+	}
+	node := yamlnode.Resolve(root, "")
+	tokens := strings.Split(pointer[1:], "/")
+	parent := ""
+	for n, token := range tokens {
+		if node != nil && node.Kind == yaml.SequenceNode {
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+				node = yamlnode.Resolve(node, "/"+token)
+				tokens[n] = yamlnode.Escape(elementIdentity(node, parent, token))
+				parent = token
+				continue
+			}
+		}
+		parent = yamlnode.Unescape(token)
+		node = yamlnode.Get(node, parent)
+	}
+	return "/" + strings.Join(tokens, "/")
+}
+
+// elementIdentity returns what identifies an array element independently of its position: the
+// reference, location and name of a parameter, the url of a server, the schemes of a security
+// requirement or the value of a scalar such as a scope. Other elements keep their index.
+func elementIdentity(node *yaml.Node, array, index string) string {
+	if node == nil {
+		return index
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.MappingNode:
+		if ref := yamlnode.Ref(node); ref != "" {
+			return ref
+		}
+		switch array {
+		case "parameters":
+			return yamlnode.Scalar(yamlnode.Get(node, "in")) + ":" + yamlnode.Scalar(yamlnode.Get(node, "name"))
+		case "servers":
+			return yamlnode.Scalar(yamlnode.Get(node, "url"))
+		case "security":
+			var schemes []string
+			yamlnode.Pairs(node, func(key, _ *yaml.Node) { schemes = append(schemes, key.Value) })
+			sort.Strings(schemes)
+			return strings.Join(schemes, "+")
+		}
+	}
+	return index
+}
+
+// Baseline records known findings, so that only new ones are reported.
+type Baseline struct {
+	Version  int               `json:"version"`
+	Findings []BaselineFinding `json:"findings"`
+}
+
+// BaselineFinding is a finding recorded in a baseline. Everything but the fingerprint, including
+// the message, is there for people reviewing the file.
+type BaselineFinding struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	Location    string `json:"location"`
+	Message     string `json:"message"`
+}
+
+// NewBaseline records the findings of an audit result.
+func NewBaseline(result *AuditResult) *Baseline {
+	b := &Baseline{Version: baselineVersion, Findings: []BaselineFinding{}}
+	seen := make(map[string]bool)
+	for _, f := range result.Findings {
+		fp := f.Fingerprint
+		if fp == "" {
+			fp = Fingerprint(f)
+		}
+		if seen[fp] {
+			continue
+		}
+		seen[fp] = true
+		b.Findings = append(b.Findings, BaselineFinding{Fingerprint: fp, RuleID: f.RuleID, Location: f.Location, Message: f.Message})
+	}
+	// Sorted, so the file only changes when the findings do
+	sort.Slice(b.Findings, func(i, j int) bool { return b.Findings[i].Fingerprint < b.Findings[j].Fingerprint })
+	return b
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("baseline %s has unsupported version %d", path, b.Version)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to a file.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Apply removes the findings recorded in the baseline from the result, leaving only new ones.
+// Baselined counts the removed findings and Fixed the baseline entries no longer found.
+func (b *Baseline) Apply(result *AuditResult) {
+	known := make(map[string]bool, len(b.Findings))
+	for _, f := range b.Findings {
+		known[f.Fingerprint] = true
+	}
+	found := make(map[string]bool)
+	kept := result.Findings[:0]
+	for _, f := range result.Findings {
+		if known[f.Fingerprint] {
+			found[f.Fingerprint] = true
+			result.Baselined++
+			continue
+		}
+		kept = append(kept, f)
+	}
+	result.Findings = kept
+	result.Fixed = len(known) - len(found)
+}
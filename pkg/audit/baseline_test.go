@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+func TestFingerprint(t *testing.T) {
+	f := Finding{RuleID: "UNPROTECTED_WRITE", Location: "POST /users", Pointer: "/paths/~1users/post", Message: "m", Line: 3}
+	fp := Fingerprint(f)
+	if !strings.HasPrefix(fp, "UNPROTECTED_WRITE:") {
+		t.Errorf("Fingerprint = %q, want the rule ID as prefix", fp)
+	}
+
+	moved := f
+	moved.Line, moved.Column, moved.Severity = 40, 5, SeverityError
+	moved.Message = "reworded"
+	if Fingerprint(moved) != fp {
+		t.Error("fingerprint changed with the position, severity or message")
+	}
+	other := f
+	other.Location = "POST /accounts"
+	if Fingerprint(other) == fp {
+		t.Error("fingerprint did not change with the location")
+	}
+}
+
+func TestFingerprint_ArrayElements(t *testing.T) {
+	spec := func(scopes, params string) string {
+		return `openapi: 3.0.3
+info: {title: T, version: "1"}
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows: {clientCredentials: {tokenUrl: "https://example.com/token", scopes: {read: Read}}}
+paths:
+  /users/{userId}:
+    get:
+      security: [{oauth: ` + scopes + `}]
+      parameters: ` + params + `
+      responses: {"200": {description: ok}}
+`
+	}
+	fingerprints := func(src string) map[string]string {
+		t.Helper()
+		result, err := New().AuditData([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make(map[string]string)
+		for _, f := range result.Findings {
+			if f.RuleID == "SCOPE_NOT_DEFINED" || f.RuleID == "INTEGER_ID_IN_PATH" || f.RuleID == "AUTH_HEADER_PARAM" {
+				out[f.Message] = f.Fingerprint
+			}
+		}
+		return out
+	}
+	before := fingerprints(spec("[read, admin, delete]",
+		"[{name: userId, in: path, required: true, schema: {type: integer}}, {name: Authorization, in: header, schema: {type: string}}]"))
+	after := fingerprints(spec("[delete, read, admin]",
+		"[{name: Authorization, in: header, schema: {type: string}}, {name: trace, in: query, schema: {type: string}}, {name: userId, in: path, required: true, schema: {type: integer}}]"))
+	if len(before) != 4 {
+		t.Fatalf("got findings %v, want two undefined scopes, an integer ID and an Authorization header", before)
+	}
+	for message, fp := range before {
+		if after[message] != fp {
+			t.Errorf("%s: fingerprint changed from %s to %s when the array was reordered", message, fp, after[message])
+		}
+	}
+}
+
+func TestIdentityPointer(t *testing.T) {
+	root, err := yamlnode.Parse([]byte(`paths:
+  /a:
+    get:
+      security: [{}, {oauth: [read, write], key: []}]
+      parameters: [{$ref: "#/components/parameters/Limit"}, {name: id, in: path}]
+      servers: [{url: "https://example.com"}]
+      requestBody: {content: {application/json: {schema: {allOf: [{type: object}]}}}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"/paths/~1a/get/security/1/oauth/1": "/paths/~1a/get/security/key+oauth/oauth/write",
+		"/paths/~1a/get/parameters/0":       "/paths/~1a/get/parameters/#~1components~1parameters~1Limit",
+		"/paths/~1a/get/parameters/1":       "/paths/~1a/get/parameters/path:id",
+		"/paths/~1a/get/servers/0/url":      "/paths/~1a/get/servers/https:~1~1example.com/url",
+		// Elements without an identity keep their index
+		"/paths/~1a/get/requestBody/content/application~1json/schema/allOf/0": "/paths/~1a/get/requestBody/content/application~1json/schema/allOf/0",
+		"/paths/~1a/get/parameters/7":                                         "/paths/~1a/get/parameters/7",
+	}
+	for pointer, want := range tests {
+		if got := identityPointer(root, pointer); got != want {
+			t.Errorf("identityPointer(%s) = %s, want %s", pointer, got, want)
+		}
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	result := auditWithConfig(t, "")
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := NewBaseline(result).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Findings) != 2 {
+		t.Fatalf("baseline has %d findings, want 2", len(baseline.Findings))
+	}
+
+	// One finding fixed, one new
+	baseline.Findings = baseline.Findings[:1]
+	baseline.Findings = append(baseline.Findings, BaselineFinding{Fingerprint: "GONE:0000000000000000"})
+	result = auditWithConfig(t, "")
+	baseline.Apply(result)
+	if len(result.Findings) != 1 || result.Baselined != 1 || result.Fixed != 1 {
+		t.Errorf("got %v, Baselined %d, Fixed %d", findingLocations(result), result.Baselined, result.Fixed)
+	}
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadBaseline(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	path := filepath.Join(dir, "baseline.json")
+	if err := (&Baseline{Version: 2}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseline(path); err == nil || !strings.Contains(err.Error(), "unsupported version") {
+		t.Errorf("got %v", err)
+	}
+}
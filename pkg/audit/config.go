@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// DefaultConfigFile is the audit config the CLI picks up from the working directory.
+const DefaultConfigFile = ".yaswag-audit.yaml"
+
+// IgnoreExtension is the specification extension that suppresses findings of the listed rule IDs
+// for the object it is set on and everything inside it, such as an operation.
+const IgnoreExtension = "x-yaswag-ignore"
+
+// Config turns rules on and off, overrides their severities and suppresses individual findings.
+//
+//	rules:
+//	  DEPRECATED_NO_SECURITY: off
+//	  UNPROTECTED_WRITE: error
+//	ignore:
+//	  - rule: UNPROTECTED_WRITE
+//	    location: POST /webhooks/stripe
+//	    reason: verified with a signature header
+type Config struct {
+	Rules  map[string]RuleConfig `yaml:"rules"`
+	Ignore []Ignore              `yaml:"ignore"`
+}
+
+// RuleConfig configures a single rule. In YAML it is either a mapping or a shorthand scalar:
+// "off" or "false" disables the rule, a severity enables it with that severity.
+type RuleConfig struct {
+	Enabled  *bool    `yaml:"enabled"`
+	Severity Severity `yaml:"severity"`
+}
+
+// Ignore suppresses the findings of a rule (or of every rule, with "*") at a location such as
+// "POST /users", or at a JSON pointer and everything below it.
+type Ignore struct {
+	Rule     string `yaml:"rule"`
+	Location string `yaml:"location"`
+	Pointer  string `yaml:"pointer"`
+	Reason   string `yaml:"reason"`
+}
+
+// UnmarshalYAML accepts the scalar shorthand of a rule config.
+func (rc *RuleConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		type plain RuleConfig
+		return node.Decode((*plain)(rc))
+	}
+	enabled := true
+	switch strings.ToLower(node.Value) {
+	case "off", "false":
+		enabled = false
+	case "on", "true":
+	default:
+		rc.Severity = Severity(node.Value)
+	}
+	rc.Enabled = &enabled
+	return nil
+}
+
+// LoadConfig reads an audit config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit config: %w", err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses an audit config document.
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid audit config: %w", err)
+	}
+	for id, rc := range cfg.Rules {
+		if rc.Severity == "" {
+			continue
+		}
+		sev, err := ParseSeverity(string(rc.Severity))
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", id, err)
+		}
+		rc.Severity = sev
+		cfg.Rules[id] = rc
+	}
+	for i, ig := range cfg.Ignore {
+		if ig.Rule == "" {
+			return nil, fmt.Errorf("ignore entry %d: rule is required (use * for every rule)", i+1)
+		}
+		if ig.Location == "" && ig.Pointer == "" {
+			return nil, fmt.Errorf("ignore entry %d: location or pointer is required", i+1)
+		}
+	}
+	return &cfg, nil
+}
+
+// Check reports rule IDs in the config that none of the rules have, which are usually typos.
+func (c *Config) Check(rules []Rule) error {
+	known := make(map[string]bool)
+	for _, r := range rules {
+		known[r.ID()] = true
+	}
+	var unknown []string
+	for id := range c.Rules {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	for _, ig := range c.Ignore {
+		if ig.Rule != "*" && !known[ig.Rule] {
+			unknown = append(unknown, ig.Rule)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown audit rules in config: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// enabled reports whether the config leaves the rule on.
+func (c *Config) enabled(id string) bool {
+	rc, ok := c.Rules[id]
+	return !ok || rc.Enabled == nil || *rc.Enabled
+}
+
+// severity returns the configured severity of a rule, or its own.
+func (c *Config) severity(r Rule) Severity {
+	if rc, ok := c.Rules[r.ID()]; ok && rc.Severity != "" {
+		return rc.Severity
+	}
+	return r.Severity()
+}
+
+// ignores reports whether an ignore entry suppresses the finding.
+func (c *Config) ignores(f Finding) bool {
+	for _, ig := range c.Ignore {
+		if ig.Rule != "*" && ig.Rule != f.RuleID {
+			continue
+		}
+		if ig.Location != "" && ig.Location != f.Location {
+			continue
+		}
+		if ig.Pointer != "" && !pointerWithin(f.Pointer, ig.Pointer) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// pointerWithin reports whether pointer is prefix or below it.
+func pointerWithin(pointer, prefix string) bool {
+	return pointer == prefix || strings.HasPrefix(pointer, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// ignoredInline reports whether an x-yaswag-ignore extension on the object holding the finding,
+// or on any object enclosing it, lists the finding's rule.
+func ignoredInline(root *yaml.Node, f Finding) bool {
+	if f.Pointer == "" {
+		return false
+	}
+	pointer := f.Pointer
+	for {
+		if ignoreListHas(yamlnode.Get(yamlnode.Resolve(root, pointer), IgnoreExtension), f.RuleID) {
+			return true
+		}
+		if pointer == "" {
+			return false
+		}
+		pointer = pointer[:strings.LastIndex(pointer, "/")]
+	}
+}
+
+// ignoreListHas reports whether an x-yaswag-ignore value, a rule ID or a list of them, names the rule.
+func ignoreListHas(list *yaml.Node, id string) bool {
+	if list != nil && list.Kind == yaml.ScalarNode {
+		return list.Value == id || list.Value == "*"
+	}
+	for _, item := range yamlnode.Items(list) {
+		if item.Value == id || item.Value == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+const configSpec = `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    post:
+      responses:
+        "200": {description: ok}
+        "429": {description: slow down, headers: {Retry-After: {schema: {type: integer}}}}
+  /webhooks:
+    post:
+      x-yaswag-ignore: [UNPROTECTED_WRITE]
+      responses:
+        "200": {description: ok}
+        "429": {description: slow down, headers: {Retry-After: {schema: {type: integer}}}}
+  /legacy:
+    x-yaswag-ignore: "*"
+    delete:
+      responses:
+        "200": {description: ok}
+        "429": {description: slow down, headers: {Retry-After: {schema: {type: integer}}}}
+components:
+  securitySchemes:
+    key:
+      type: apiKey
+      name: k
+      in: query
+`
+
+func auditWithConfig(t *testing.T, config string) *AuditResult {
+	t.Helper()
+	var opts []Option
+	if config != "" {
+		cfg, err := ParseConfig([]byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts = append(opts, WithConfig(cfg))
+	}
+	result, err := New(opts...).AuditData([]byte(configSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func findingLocations(result *AuditResult) []string {
+	var out []string
+	for _, f := range result.Findings {
+		out = append(out, string(f.Severity)+" "+f.RuleID+" "+f.Location)
+	}
+	return out
+}
+
+func TestInlineIgnore(t *testing.T) {
+	result := auditWithConfig(t, "")
+	got := strings.Join(findingLocations(result), "\n")
+	want := "WARNING UNPROTECTED_WRITE POST /users\nWARNING API_KEY_IN_QUERY SecurityScheme 'key'"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if result.Suppressed != 2 {
+		t.Errorf("Suppressed = %d, want 2", result.Suppressed)
+	}
+}
+
+func TestConfigRules(t *testing.T) {
+	result := auditWithConfig(t, `rules:
+  UNPROTECTED_WRITE: off
+  API_KEY_IN_QUERY: error
+`)
+	got := strings.Join(findingLocations(result), "\n")
+	if got != "ERROR API_KEY_IN_QUERY SecurityScheme 'key'" {
+		t.Errorf("got %q", got)
+	}
+	if !result.HasFindings(SeverityError) {
+		t.Error("HasFindings(ERROR) = false")
+	}
+
+	result = auditWithConfig(t, `rules:
+  API_KEY_IN_QUERY: {enabled: false}
+  UNPROTECTED_WRITE: {severity: info}
+`)
+	got = strings.Join(findingLocations(result), "\n")
+	if got != "INFO UNPROTECTED_WRITE POST /users" {
+		t.Errorf("got %q", got)
+	}
+	if result.HasFindings(SeverityWarning) {
+		t.Error("HasFindings(WARNING) = true for an INFO finding")
+	}
+}
+
+func TestConfigIgnore(t *testing.T) {
+	result := auditWithConfig(t, `ignore:
+  - rule: UNPROTECTED_WRITE
+    location: POST /users
+    reason: internal network only
+  - rule: "*"
+    pointer: /components/securitySchemes
+`)
+	if len(result.Findings) != 0 {
+		t.Errorf("got %v", findingLocations(result))
+	}
+	if result.Suppressed != 4 {
+		t.Errorf("Suppressed = %d, want 4", result.Suppressed)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct{ config, want string }{
+		{"rules:\n  UNPROTECTED_WRITE: fatal\n", "unknown severity"},
+		{"ignore:\n  - location: POST /users\n", "rule is required"},
+		{"ignore:\n  - rule: UNPROTECTED_WRITE\n", "location or pointer is required"},
+		{"rules: [a]\n", "invalid audit config"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseConfig([]byte(tt.config)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: got %v, want error containing %q", tt.config, err, tt.want)
+		}
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	cfg, err := ParseConfig([]byte("rules:\n  UNPROTECTED_WRTIE: off\nignore:\n  - rule: \"*\"\n    pointer: /paths\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.Check(New().Rules())
+	if err == nil || !strings.Contains(err.Error(), "UNPROTECTED_WRTIE") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for in, want := range map[string]Severity{"error": SeverityError, "Warning": SeverityWarning, "warn": SeverityWarning, "INFO": SeverityInfo} {
+		if got, err := ParseSeverity(in); err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %q, %v", in, got, err)
+		}
+	}
+	if !SeverityError.AtLeast(SeverityWarning) || SeverityInfo.AtLeast(SeverityWarning) {
+		t.Error("AtLeast ordering is wrong")
+	}
+}
@@ -41,7 +41,14 @@ func writeSummary(sb *strings.Builder, result *AuditResult) {
 
 	sb.WriteString(fmt.Sprintf("Total Endpoints: %d\n", result.TotalEndpoints))
 	sb.WriteString(fmt.Sprintf("Protected: %d (%d%%)\n", result.ProtectedEndpoints, protectedPct))
-	sb.WriteString(fmt.Sprintf("Unprotected: %d (%d%%)\n\n", result.UnprotectedEndpoints, 100-protectedPct))
+	sb.WriteString(fmt.Sprintf("Unprotected: %d (%d%%)\n", result.UnprotectedEndpoints, 100-protectedPct))
+	if result.Suppressed > 0 {
+		sb.WriteString(fmt.Sprintf("Suppressed Findings: %d\n", result.Suppressed))
+	}
+	if result.Baselined > 0 || result.Fixed > 0 {
+		sb.WriteString(fmt.Sprintf("Baselined Findings: %d (%d fixed since the baseline)\n", result.Baselined, result.Fixed))
+	}
+	sb.WriteString("\n")
 }
 
 func writeFindings(sb *strings.Builder, result *AuditResult) {
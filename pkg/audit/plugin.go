@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// PluginProtocolVersion is the version of the request plugins receive.
+const PluginProtocolVersion = 1
+
+// pluginTimeout bounds a single plugin invocation.
+const pluginTimeout = time.Minute
+
+// pluginRequest is written to the standard input of a plugin. Action is "describe", which
+// asks for the plugin's rules, or "check", which sends the document to audit.
+type pluginRequest struct {
+	Version  int               `json:"version"`
+	Action   string            `json:"action"`
+	Document *openapi.Document `json:"document,omitempty"`
+}
+
+// pluginResponse is read from the standard output of a plugin.
+type pluginResponse struct {
+	Rules    []pluginRule `json:"rules,omitempty"`
+	Findings []Finding    `json:"findings,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+type pluginRule struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+}
+
+// ExecPlugin runs audit rules kept outside yaswag in a subprocess. The plugin reads a JSON
+// request from stdin and writes a JSON response to stdout; the document is checked once per
+// audit and its findings shared by the plugin's rules. A response with an "error" field, a
+// non-zero exit status or a finding for a rule the plugin did not describe is an error.
+type ExecPlugin struct {
+	command string
+	args    []string
+	rules   []Rule
+
+	doc      *openapi.Document
+	findings map[string][]Finding
+	err      error
+}
+
+// LoadExecPlugin starts the plugin command to ask for its rules.
+func LoadExecPlugin(command string, args ...string) (*ExecPlugin, error) {
+	p := &ExecPlugin{command: command, args: args}
+	resp, err := p.run(pluginRequest{Version: PluginProtocolVersion, Action: "describe"})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Rules) == 0 {
+		return nil, fmt.Errorf("plugin %s: no rules described", command)
+	}
+	for _, r := range resp.Rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("plugin %s: rule without id", command)
+		}
+		severity, err := ParseSeverity(r.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: rule %s: %w", command, r.ID, err)
+		}
+		name := r.Name
+		if name == "" {
+			name = r.ID
+		}
+		p.rules = append(p.rules, &ExecRule{plugin: p, id: r.ID, name: name, severity: severity})
+	}
+	return p, nil
+}
+
+// Rules returns the rules the plugin described.
+func (p *ExecPlugin) Rules() []Rule {
+	return p.rules
+}
+
+// Err returns the error of the last check, so that a broken plugin cannot pass an audit unnoticed.
+func (p *ExecPlugin) Err() error {
+	return p.err
+}
+
+// check runs the plugin on a document, reusing the findings of the previous run for the same one.
+func (p *ExecPlugin) check(doc *openapi.Document) (map[string][]Finding, error) {
+	if doc == p.doc {
+		return p.findings, p.err
+	}
+	p.doc, p.findings, p.err = doc, nil, nil
+
+	resp, err := p.run(pluginRequest{Version: PluginProtocolVersion, Action: "check", Document: doc})
+	if err != nil {
+		p.err = err
+		return nil, err
+	}
+	known := make(map[string]bool, len(p.rules))
+	for _, r := range p.rules {
+		known[r.ID()] = true
+	}
+	p.findings = make(map[string][]Finding)
+	for _, f := range resp.Findings {
+		if !known[f.RuleID] {
+			p.err = fmt.Errorf("plugin %s: finding for undescribed rule %q", p.command, f.RuleID)
+			return nil, p.err
+		}
+		p.findings[f.RuleID] = append(p.findings[f.RuleID], f)
+	}
+	return p.findings, nil
+}
+
+func (p *ExecPlugin) run(req pluginRequest) (*pluginResponse, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s: %w: %s", p.command, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %w", p.command, err)
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid response: %w", p.command, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.command, resp.Error)
+	}
+	return &resp, nil
+}
+
+// ExecRule is a rule implemented by an ExecPlugin.
+type ExecRule struct {
+	plugin   *ExecPlugin
+	id       string
+	name     string
+	severity Severity
+}
+
+func (r *ExecRule) ID() string         { return r.id }
+func (r *ExecRule) Name() string       { return r.name }
+func (r *ExecRule) Severity() Severity { return r.severity }
+
+// Check returns the plugin's findings for this rule. They take the rule's severity. A plugin
+// that fails has no findings; see ExecPlugin.Err.
+func (r *ExecRule) Check(doc *openapi.Document) []Finding {
+	findings, err := r.plugin.check(doc)
+	if err != nil {
+		return nil
+	}
+	out := make([]Finding, 0, len(findings[r.id]))
+	for _, f := range findings[r.id] {
+		f.RuleName = r.name
+		f.Severity = r.severity
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// TestHelperPlugin is not a test: it is the plugin the tests below run, as this test binary
+// with YASWAG_TEST_PLUGIN set to the behaviour wanted.
+func TestHelperPlugin(t *testing.T) {
+	mode := os.Getenv("YASWAG_TEST_PLUGIN")
+	if mode == "" {
+		return
+	}
+	var req pluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var resp pluginResponse
+	switch {
+	case req.Action == "describe":
+		resp.Rules = []pluginRule{{ID: "ORG_TITLE", Name: "Title", Severity: "warning"}, {ID: "ORG_OTHER", Severity: "info"}}
+	case mode == "fail":
+		fmt.Fprintln(os.Stderr, "boom")
+		os.Exit(1)
+	case mode == "unknown":
+		resp.Findings = []Finding{{RuleID: "NOT_DESCRIBED"}}
+	default:
+		resp.Findings = []Finding{{RuleID: "ORG_TITLE", Location: "Info", Pointer: "/info/title", Message: "title is " + req.Document.Info.Title}}
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(resp)
+	os.Exit(0)
+}
+
+func loadTestPlugin(t *testing.T, mode string) *ExecPlugin {
+	t.Helper()
+	t.Setenv("YASWAG_TEST_PLUGIN", mode)
+	p, err := LoadExecPlugin(os.Args[0], "-test.run=^TestHelperPlugin$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestExecPlugin(t *testing.T) {
+	p := loadTestPlugin(t, "ok")
+	if len(p.Rules()) != 2 || p.Rules()[1].Name() != "ORG_OTHER" || p.Rules()[1].Severity() != SeverityInfo {
+		t.Fatalf("unexpected rules %+v", p.Rules())
+	}
+
+	result, err := New(WithRules(p.Rules()...)).AuditData([]byte(configSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Err() != nil {
+		t.Fatal(p.Err())
+	}
+	var found *Finding
+	for i, f := range result.Findings {
+		if f.RuleID == "ORG_TITLE" {
+			found = &result.Findings[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("plugin finding missing from %v", findingLocations(result))
+	}
+	if found.Message != "title is Test" || found.Severity != SeverityWarning || found.RuleName != "Title" || found.Line != 3 {
+		t.Errorf("got %+v", *found)
+	}
+}
+
+func TestExecPluginErrors(t *testing.T) {
+	doc := &openapi.Document{}
+	for mode, want := range map[string]string{"fail": "boom", "unknown": "undescribed rule"} {
+		p := loadTestPlugin(t, mode)
+		if got := p.Rules()[0].Check(doc); len(got) != 0 {
+			t.Errorf("%s: got findings %v", mode, got)
+		}
+		if err := p.Err(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want error containing %q", mode, err, want)
+		}
+	}
+}
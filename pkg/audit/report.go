@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// reportLevels maps audit severities to report levels.
+var reportLevels = map[Severity]report.Level{
+	SeverityError:   report.LevelError,
+	SeverityWarning: report.LevelWarning,
+	SeverityInfo:    report.LevelNote,
+}
+
+// ToReport converts an audit result for the machine-readable report formats.
+func ToReport(result *AuditResult) *report.Report {
+	r := &report.Report{Name: "audit"}
+	seen := make(map[string]bool)
+	for _, f := range result.Findings {
+		if !seen[f.RuleID] {
+			seen[f.RuleID] = true
+			r.Rules = append(r.Rules, report.Rule{ID: f.RuleID, Name: f.RuleName, Description: f.RuleName, Help: f.Recommendation})
+		}
+		r.Issues = append(r.Issues, report.Issue{
+			RuleID:  f.RuleID,
+			Level:   reportLevels[f.Severity],
+			Message: f.Message,
+			File:    result.Source,
+			Line:    f.Line,
+			Column:  f.Column,
+			Pointer: f.Pointer,
+		})
+	}
+	return r
+}
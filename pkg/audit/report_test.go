@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const positionSpec = `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    post:
+      responses:
+        "201":
+          description: Created
+components:
+  securitySchemes:
+    key:
+      type: apiKey
+      name: api_key
+      in: query
+    oauth:
+      type: oauth2
+      flows:
+        password:
+          tokenUrl: http://auth.example.com/token
+          scopes: {}
+`
+
+func TestAuditFile_Positions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(positionSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Rate limiting is not documented in the spec, which is beside the point here
+	cfg := &Config{Rules: map[string]RuleConfig{"MISSING_RATE_LIMIT": {Enabled: new(bool)}}}
+	result, err := New(WithConfig(cfg)).AuditFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Source != path {
+		t.Errorf("Source = %q, want %q", result.Source, path)
+	}
+
+	want := map[string]struct {
+		pointer      string
+		line, column int
+	}{
+		"UNPROTECTED_WRITE": {"/paths/~1users/post", 7, 5},
+		"API_KEY_IN_QUERY":  {"/components/securitySchemes/key/in", 16, 7},
+		"OAUTH_HTTP":        {"/components/securitySchemes/oauth/flows/password/tokenUrl", 21, 11},
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("findings = %+v", result.Findings)
+	}
+	for _, f := range result.Findings {
+		w, ok := want[f.RuleID]
+		if !ok {
+			t.Errorf("unexpected finding %s", f.RuleID)
+			continue
+		}
+		if f.Pointer != w.pointer || f.Line != w.line || f.Column != w.column {
+			t.Errorf("%s at %s %d:%d, want %s %d:%d", f.RuleID, f.Pointer, f.Line, f.Column, w.pointer, w.line, w.column)
+		}
+	}
+}
+
+func TestToReport(t *testing.T) {
+	result := &AuditResult{
+		Source: "spec.yaml",
+		Findings: []Finding{
+			{RuleID: "A", RuleName: "Rule A", Severity: SeverityError, Message: "first", Recommendation: "fix", Pointer: "/paths", Line: 5, Column: 1},
+			{RuleID: "A", RuleName: "Rule A", Severity: SeverityError, Message: "second"},
+			{RuleID: "B", RuleName: "Rule B", Severity: SeverityInfo, Message: "third"},
+		},
+	}
+	r := ToReport(result)
+	if r.Name != "audit" || len(r.Rules) != 2 || len(r.Issues) != 3 {
+		t.Fatalf("report = %+v", r)
+	}
+	if r.Rules[0].Help != "fix" {
+		t.Errorf("rule help = %q", r.Rules[0].Help)
+	}
+	if i := r.Issues[0]; i.Level != "error" || i.File != "spec.yaml" || i.Line != 5 || i.Pointer != "/paths" {
+		t.Errorf("issue = %+v", i)
+	}
+	if r.Issues[2].Level != "note" {
+		t.Errorf("INFO should map to note, got %s", r.Issues[2].Level)
+	}
+}
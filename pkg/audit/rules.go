@@ -18,5 +18,16 @@ func DefaultRules() []Rule {
 		&OAuthHTTPSRule{},
 		&DeprecatedSecurityRule{},
 		&ScopeValidationRule{},
+		&UnboundedArrayRule{},
+		&UnboundedStringRule{},
+		&IntegerIDRule{},
+		&MissingAuthResponsesRule{},
+		&MissingRateLimitRule{},
+		&HTTPServerRule{},
+		&BasicAuthNoTLSRule{},
+		&OpenAdditionalPropertiesRule{},
+		&SensitiveFieldRule{},
+		&BearerFormatRule{},
+		&AuthHeaderParamRule{},
 	}
 }
@@ -4,14 +4,15 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
 	"github.com/fathurrohman26/yaswag/pkg/openapi"
 )
 
 // UnprotectedWriteRule warns on POST/PUT/DELETE/PATCH without security
 type UnprotectedWriteRule struct{}
 
-func (r *UnprotectedWriteRule) ID() string       { return "UNPROTECTED_WRITE" }
-func (r *UnprotectedWriteRule) Name() string     { return "Unprotected write operation" }
+func (r *UnprotectedWriteRule) ID() string         { return "UNPROTECTED_WRITE" }
+func (r *UnprotectedWriteRule) Name() string       { return "Unprotected write operation" }
 func (r *UnprotectedWriteRule) Severity() Severity { return SeverityWarning }
 
 func (r *UnprotectedWriteRule) Check(doc *openapi.Document) []Finding {
@@ -31,6 +32,7 @@ func (r *UnprotectedWriteRule) Check(doc *openapi.Document) []Finding {
 					RuleName:       r.Name(),
 					Severity:       r.Severity(),
 					Location:       fmt.Sprintf("%s %s", entry.method, path),
+					Pointer:        operationPointer(path, entry.method),
 					Message:        fmt.Sprintf("%s endpoint has no security requirement", entry.method),
 					Recommendation: "Add authentication/authorization requirement to protect write operations",
 				})
@@ -43,8 +45,8 @@ func (r *UnprotectedWriteRule) Check(doc *openapi.Document) []Finding {
 // APIKeyInQueryRule warns when API keys use query params instead of headers
 type APIKeyInQueryRule struct{}
 
-func (r *APIKeyInQueryRule) ID() string       { return "API_KEY_IN_QUERY" }
-func (r *APIKeyInQueryRule) Name() string     { return "API key in query parameter" }
+func (r *APIKeyInQueryRule) ID() string         { return "API_KEY_IN_QUERY" }
+func (r *APIKeyInQueryRule) Name() string       { return "API key in query parameter" }
 func (r *APIKeyInQueryRule) Severity() Severity { return SeverityWarning }
 
 func (r *APIKeyInQueryRule) Check(doc *openapi.Document) []Finding {
@@ -60,6 +62,7 @@ func (r *APIKeyInQueryRule) Check(doc *openapi.Document) []Finding {
 				RuleName:       r.Name(),
 				Severity:       r.Severity(),
 				Location:       fmt.Sprintf("SecurityScheme '%s'", name),
+				Pointer:        yamlnode.Join("/components/securitySchemes", name, "in"),
 				Message:        fmt.Sprintf("API key '%s' is passed in query parameter", name),
 				Recommendation: "Use header-based API key for better security (prevents logging in URLs)",
 			})
@@ -71,8 +74,8 @@ func (r *APIKeyInQueryRule) Check(doc *openapi.Document) []Finding {
 // OAuthHTTPSRule warns when OAuth URLs don't use HTTPS
 type OAuthHTTPSRule struct{}
 
-func (r *OAuthHTTPSRule) ID() string       { return "OAUTH_HTTP" }
-func (r *OAuthHTTPSRule) Name() string     { return "OAuth URL not using HTTPS" }
+func (r *OAuthHTTPSRule) ID() string         { return "OAUTH_HTTP" }
+func (r *OAuthHTTPSRule) Name() string       { return "OAuth URL not using HTTPS" }
 func (r *OAuthHTTPSRule) Severity() Severity { return SeverityError }
 
 func (r *OAuthHTTPSRule) Check(doc *openapi.Document) []Finding {
@@ -93,13 +96,14 @@ func (r *OAuthHTTPSRule) Check(doc *openapi.Document) []Finding {
 func (r *OAuthHTTPSRule) checkOAuthFlows(schemeName string, flows *openapi.OAuthFlows) []Finding {
 	var findings []Finding
 
-	checkURL := func(urlType, url string) {
+	checkURL := func(flow, urlType, url string) {
 		if url != "" && strings.HasPrefix(url, "http://") {
 			findings = append(findings, Finding{
 				RuleID:         r.ID(),
 				RuleName:       r.Name(),
 				Severity:       r.Severity(),
 				Location:       fmt.Sprintf("SecurityScheme '%s' %s", schemeName, urlType),
+				Pointer:        yamlnode.Join("/components/securitySchemes", schemeName, "flows", flow, urlType),
 				Message:        fmt.Sprintf("OAuth %s uses HTTP instead of HTTPS", urlType),
 				Recommendation: "Use HTTPS for all OAuth URLs to protect tokens in transit",
 			})
@@ -107,17 +111,17 @@ func (r *OAuthHTTPSRule) checkOAuthFlows(schemeName string, flows *openapi.OAuth
 	}
 
 	if flows.Implicit != nil {
-		checkURL("authorizationUrl", flows.Implicit.AuthorizationURL)
+		checkURL("implicit", "authorizationUrl", flows.Implicit.AuthorizationURL)
 	}
 	if flows.Password != nil {
-		checkURL("tokenUrl", flows.Password.TokenURL)
+		checkURL("password", "tokenUrl", flows.Password.TokenURL)
 	}
 	if flows.ClientCredentials != nil {
-		checkURL("tokenUrl", flows.ClientCredentials.TokenURL)
+		checkURL("clientCredentials", "tokenUrl", flows.ClientCredentials.TokenURL)
 	}
 	if flows.AuthorizationCode != nil {
-		checkURL("authorizationUrl", flows.AuthorizationCode.AuthorizationURL)
-		checkURL("tokenUrl", flows.AuthorizationCode.TokenURL)
+		checkURL("authorizationCode", "authorizationUrl", flows.AuthorizationCode.AuthorizationURL)
+		checkURL("authorizationCode", "tokenUrl", flows.AuthorizationCode.TokenURL)
 	}
 
 	return findings
@@ -126,8 +130,8 @@ func (r *OAuthHTTPSRule) checkOAuthFlows(schemeName string, flows *openapi.OAuth
 // DeprecatedSecurityRule checks deprecated endpoints still have security
 type DeprecatedSecurityRule struct{}
 
-func (r *DeprecatedSecurityRule) ID() string       { return "DEPRECATED_NO_SECURITY" }
-func (r *DeprecatedSecurityRule) Name() string     { return "Deprecated endpoint without security" }
+func (r *DeprecatedSecurityRule) ID() string         { return "DEPRECATED_NO_SECURITY" }
+func (r *DeprecatedSecurityRule) Name() string       { return "Deprecated endpoint without security" }
 func (r *DeprecatedSecurityRule) Severity() Severity { return SeverityInfo }
 
 func (r *DeprecatedSecurityRule) Check(doc *openapi.Document) []Finding {
@@ -146,6 +150,7 @@ func (r *DeprecatedSecurityRule) Check(doc *openapi.Document) []Finding {
 					RuleName:       r.Name(),
 					Severity:       r.Severity(),
 					Location:       fmt.Sprintf("%s %s", entry.method, path),
+					Pointer:        yamlnode.Join(operationPointer(path, entry.method), "deprecated"),
 					Message:        "Deprecated endpoint has no security requirement",
 					Recommendation: "Consider adding security or removing the deprecated endpoint",
 				})
@@ -158,8 +163,8 @@ func (r *DeprecatedSecurityRule) Check(doc *openapi.Document) []Finding {
 // ScopeValidationRule validates OAuth scopes are defined and used
 type ScopeValidationRule struct{}
 
-func (r *ScopeValidationRule) ID() string       { return "SCOPE_NOT_DEFINED" }
-func (r *ScopeValidationRule) Name() string     { return "OAuth scope not defined" }
+func (r *ScopeValidationRule) ID() string         { return "SCOPE_NOT_DEFINED" }
+func (r *ScopeValidationRule) Name() string       { return "OAuth scope not defined" }
 func (r *ScopeValidationRule) Severity() Severity { return SeverityWarning }
 
 func (r *ScopeValidationRule) Check(doc *openapi.Document) []Finding {
@@ -212,20 +217,22 @@ func (r *ScopeValidationRule) collectDefinedScopes(doc *openapi.Document) map[st
 func (r *ScopeValidationRule) checkOperationScopes(path string, entry operationEntry, definedScopes map[string]map[string]bool) []Finding {
 	var findings []Finding
 
-	for _, secReq := range entry.op.Security {
+	for i, secReq := range entry.op.Security {
+		requirement := yamlnode.Index(yamlnode.Join(operationPointer(path, entry.method), "security"), i)
 		for schemeName, requiredScopes := range secReq {
 			schemeScopes, schemeExists := definedScopes[schemeName]
 			if !schemeExists {
 				continue // Not an OAuth scheme
 			}
 
-			for _, scope := range requiredScopes {
+			for j, scope := range requiredScopes {
 				if !schemeScopes[scope] {
 					findings = append(findings, Finding{
 						RuleID:         r.ID(),
 						RuleName:       r.Name(),
 						Severity:       r.Severity(),
 						Location:       fmt.Sprintf("%s %s", entry.method, path),
+						Pointer:        yamlnode.Index(yamlnode.Join(requirement, schemeName), j),
 						Message:        fmt.Sprintf("Scope '%s' used but not defined in security scheme '%s'", scope, schemeName),
 						Recommendation: "Define the scope in the security scheme or remove from operation",
 					})
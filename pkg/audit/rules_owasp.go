@@ -0,0 +1,464 @@
+package audit
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Rules for the OWASP API Security Top 10 (2023). The category each rule relates to is noted on it.
+
+// boundedFormats are string formats whose values have an inherent maximum length. Binary uploads
+// are limited by request size rather than maxLength, so they are left out too.
+var boundedFormats = map[string]bool{
+	"date": true, "date-time": true, "time": true, "uuid": true, "ipv4": true, "ipv6": true, "binary": true,
+}
+
+// UnboundedArrayRule flags input arrays without maxItems (API4: Unrestricted Resource Consumption)
+type UnboundedArrayRule struct{}
+
+func (r *UnboundedArrayRule) ID() string         { return "UNBOUNDED_ARRAY" }
+func (r *UnboundedArrayRule) Name() string       { return "Unbounded array" }
+func (r *UnboundedArrayRule) Severity() Severity { return SeverityWarning }
+
+func (r *UnboundedArrayRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	w := newSchemaWalker(doc)
+	w.walkInputs(func(location string, s *openapi.Schema, pointer string) {
+		if hasType(s, openapi.TypeArray) && s.MaxItems == nil {
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       schemaLocation(location, pointer),
+				Pointer:        pointer,
+				Message:        fmt.Sprintf("Array %s accepted as input has no maxItems", w.subject(pointer)),
+				Recommendation: "Set maxItems so clients cannot send arbitrarily large arrays",
+			})
+		}
+	})
+	return findings
+}
+
+// UnboundedStringRule flags input strings without maxLength (API4: Unrestricted Resource Consumption)
+type UnboundedStringRule struct{}
+
+func (r *UnboundedStringRule) ID() string         { return "UNBOUNDED_STRING" }
+func (r *UnboundedStringRule) Name() string       { return "Unbounded string" }
+func (r *UnboundedStringRule) Severity() Severity { return SeverityInfo }
+
+func (r *UnboundedStringRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	w := newSchemaWalker(doc)
+	w.walkInputs(func(location string, s *openapi.Schema, pointer string) {
+		if !hasType(s, openapi.TypeString) || s.MaxLength != nil || len(s.Enum) > 0 || boundedFormats[s.Format] {
+			return
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       schemaLocation(location, pointer),
+			Pointer:        pointer,
+			Message:        fmt.Sprintf("String %s accepted as input has no maxLength", w.subject(pointer)),
+			Recommendation: "Set maxLength (or an enum or bounded format) to limit the size of input strings",
+		})
+	})
+	return findings
+}
+
+// idParamPattern matches parameter names that identify a resource.
+var idParamPattern = regexp.MustCompile(`(?i)(^id$|[a-z0-9]_?id$)`)
+
+// IntegerIDRule flags sequential integer IDs in paths (API1: Broken Object Level Authorization)
+type IntegerIDRule struct{}
+
+func (r *IntegerIDRule) ID() string         { return "INTEGER_ID_IN_PATH" }
+func (r *IntegerIDRule) Name() string       { return "Integer ID in path" }
+func (r *IntegerIDRule) Severity() Severity { return SeverityInfo }
+
+func (r *IntegerIDRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	seen := make(map[string]bool)
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		operationParameters(doc, path, entry, func(p *openapi.Parameter, pointer string) {
+			if p.In != openapi.ParameterInPath || !idParamPattern.MatchString(p.Name) || p.Schema == nil || !hasType(p.Schema, openapi.TypeInteger) || seen[pointer] {
+				return
+			}
+			seen[pointer] = true
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       fmt.Sprintf("%s %s", entry.method, path),
+				Pointer:        pointer,
+				Message:        fmt.Sprintf("Path parameter '%s' is an integer ID that can be enumerated", p.Name),
+				Recommendation: "Use unguessable IDs (such as UUIDs) and check object-level authorization on every access",
+			})
+		})
+	})
+	return findings
+}
+
+// MissingAuthResponsesRule flags secured operations that do not document 401 and 403 responses
+// (API2: Broken Authentication)
+type MissingAuthResponsesRule struct{}
+
+func (r *MissingAuthResponsesRule) ID() string         { return "MISSING_AUTH_RESPONSES" }
+func (r *MissingAuthResponsesRule) Name() string       { return "Missing 401/403 responses" }
+func (r *MissingAuthResponsesRule) Severity() Severity { return SeverityWarning }
+
+func (r *MissingAuthResponsesRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		if !requiresAuth(doc, entry.op) {
+			return
+		}
+		var missing []string
+		for _, code := range []string{"401", "403"} {
+			if !hasResponse(entry.op, code) {
+				missing = append(missing, code)
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("%s %s", entry.method, path),
+			Pointer:        yamlnode.Join(operationPointer(path, entry.method), "responses"),
+			Message:        fmt.Sprintf("Secured operation does not document %s responses", strings.Join(missing, " and ")),
+			Recommendation: "Document 401 for missing or invalid credentials and 403 for insufficient permissions",
+		})
+	})
+	return findings
+}
+
+// rateLimitHeaderPattern matches the response headers that tell clients about rate limits.
+var rateLimitHeaderPattern = regexp.MustCompile(`(?i)^((x-)?rate-?limit(-.*)?|retry-after)$`)
+
+// MissingRateLimitRule flags operations that document neither 429 responses nor rate-limit headers
+// (API4: Unrestricted Resource Consumption)
+type MissingRateLimitRule struct{}
+
+func (r *MissingRateLimitRule) ID() string         { return "MISSING_RATE_LIMIT" }
+func (r *MissingRateLimitRule) Name() string       { return "Missing rate limiting" }
+func (r *MissingRateLimitRule) Severity() Severity { return SeverityInfo }
+
+func (r *MissingRateLimitRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		var message string
+		switch {
+		case !hasResponse(entry.op, "429"):
+			message = "Operation does not document a 429 Too Many Requests response"
+		case !hasRateLimitHeaders(doc, entry.op):
+			message = "Operation documents 429 but no rate-limit headers (such as RateLimit or Retry-After)"
+		default:
+			return
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("%s %s", entry.method, path),
+			Pointer:        yamlnode.Join(operationPointer(path, entry.method), "responses"),
+			Message:        message,
+			Recommendation: "Rate-limit the API and document 429 responses with RateLimit and Retry-After headers",
+		})
+	})
+	return findings
+}
+
+func hasRateLimitHeaders(doc *openapi.Document, op *openapi.Operation) bool {
+	for _, r := range op.Responses {
+		if r == nil {
+			continue
+		}
+		if resolved, _ := resolveResponse(doc, r, ""); resolved != nil {
+			for name := range resolved.Headers {
+				if rateLimitHeaderPattern.MatchString(name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HTTPServerRule flags server URLs that do not use TLS (API8: Security Misconfiguration)
+type HTTPServerRule struct{}
+
+func (r *HTTPServerRule) ID() string         { return "HTTP_SERVER_URL" }
+func (r *HTTPServerRule) Name() string       { return "Server URL not using HTTPS" }
+func (r *HTTPServerRule) Severity() Severity { return SeverityWarning }
+
+func (r *HTTPServerRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	forEachServer(doc, func(server openapi.Server, pointer string) {
+		if !isPlainHTTP(server.URL) {
+			return
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("Server '%s'", server.URL),
+			Pointer:        yamlnode.Join(pointer, "url"),
+			Message:        fmt.Sprintf("Server URL '%s' uses HTTP instead of HTTPS", server.URL),
+			Recommendation: "Serve the API over HTTPS only",
+		})
+	})
+	return findings
+}
+
+// forEachServer calls fn for the servers of the document, its path items and its operations.
+func forEachServer(doc *openapi.Document, fn func(server openapi.Server, pointer string)) {
+	visit := func(servers []openapi.Server, pointer string) {
+		for i, server := range servers {
+			fn(server, yamlnode.Index(pointer, i))
+		}
+	}
+	visit(doc.Servers, "/servers")
+	for _, path := range sortedKeys(doc.Paths) {
+		if item := doc.Paths[path]; item != nil {
+			visit(item.Servers, yamlnode.Join("/paths", path, "servers"))
+		}
+	}
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		visit(entry.op.Servers, yamlnode.Join(operationPointer(path, entry.method), "servers"))
+	})
+}
+
+// isPlainHTTP reports whether a server URL uses HTTP to a host other than the local machine.
+func isPlainHTTP(serverURL string) bool {
+	if !strings.HasPrefix(strings.ToLower(serverURL), "http://") {
+		return false
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return true
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1", "0.0.0.0":
+		return false
+	}
+	return true
+}
+
+// BasicAuthNoTLSRule flags HTTP basic authentication offered on servers without TLS
+// (API2: Broken Authentication)
+type BasicAuthNoTLSRule struct{}
+
+func (r *BasicAuthNoTLSRule) ID() string         { return "BASIC_AUTH_NO_TLS" }
+func (r *BasicAuthNoTLSRule) Name() string       { return "Basic auth without TLS" }
+func (r *BasicAuthNoTLSRule) Severity() Severity { return SeverityError }
+
+func (r *BasicAuthNoTLSRule) Check(doc *openapi.Document) []Finding {
+	var plain []string
+	forEachServer(doc, func(server openapi.Server, _ string) {
+		if isPlainHTTP(server.URL) {
+			plain = append(plain, server.URL)
+		}
+	})
+	if len(plain) == 0 || doc.Components == nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, name := range sortedKeys(doc.Components.SecuritySchemes) {
+		scheme := doc.Components.SecuritySchemes[name]
+		if scheme == nil || scheme.Type != "http" || !strings.EqualFold(scheme.Scheme, "basic") {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("SecurityScheme '%s'", name),
+			Pointer:        yamlnode.Join("/components/securitySchemes", name, "scheme"),
+			Message:        fmt.Sprintf("Basic auth '%s' sends credentials in clear text to %s", name, strings.Join(plain, ", ")),
+			Recommendation: "Use HTTPS for every server that accepts basic authentication, or switch to token-based authentication",
+		})
+	}
+	return findings
+}
+
+// OpenAdditionalPropertiesRule flags write request bodies that accept undeclared properties
+// (API3: Broken Object Property Level Authorization, mass assignment)
+type OpenAdditionalPropertiesRule struct{}
+
+func (r *OpenAdditionalPropertiesRule) ID() string { return "OPEN_ADDITIONAL_PROPERTIES" }
+func (r *OpenAdditionalPropertiesRule) Name() string {
+	return "Open additionalProperties on write body"
+}
+func (r *OpenAdditionalPropertiesRule) Severity() Severity { return SeverityInfo }
+
+func (r *OpenAdditionalPropertiesRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	w := newSchemaWalker(doc)
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		if entry.op.RequestBody == nil || !bodyWriteMethods[entry.method] {
+			return
+		}
+		body, at := resolveRequestBody(doc, entry.op.RequestBody, yamlnode.Join(operationPointer(path, entry.method), "requestBody"))
+		if body == nil {
+			return
+		}
+		location := fmt.Sprintf("%s %s", entry.method, path)
+		w.walkContent(body.Content, at, func(s *openapi.Schema, pointer string) {
+			if len(s.Properties) == 0 || !acceptsAnyProperty(s.AdditionalProperties) {
+				return
+			}
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       schemaLocation(location, pointer),
+				Pointer:        pointer,
+				Message:        fmt.Sprintf("Object %s in a write request body accepts undeclared properties", w.subject(pointer)),
+				Recommendation: "Set additionalProperties: false so clients cannot set fields they should not control",
+			})
+		})
+	})
+	return findings
+}
+
+// bodyWriteMethods are the methods whose request bodies change server state.
+var bodyWriteMethods = map[string]bool{"POST": true, "PUT": true, "PATCH": true}
+
+// acceptsAnyProperty reports whether an additionalProperties value allows arbitrary properties:
+// it is absent, true or the empty schema.
+func acceptsAnyProperty(additional *openapi.Schema) bool {
+	return additional == nil || additional.IsEmpty()
+}
+
+// sensitiveFieldPattern matches property names, lowercased without separators, that hold secrets
+// or personal data.
+var sensitiveFieldPattern = regexp.MustCompile(`^(password|passwd|passphrase|pin|ssn|socialsecuritynumber|cvv|cvc|creditcardnumber|cardnumber)$|password|secret|privatekey|apikey|token$`)
+
+// cursorFieldPattern matches pagination and idempotency tokens, which are not secrets.
+var cursorFieldPattern = regexp.MustCompile(`^((next|prev|previous)?page|next|prev|previous|continuation|sync|idempotency)token$`)
+
+// SensitiveFieldRule flags secrets and personal data returned in responses
+// (API3: Broken Object Property Level Authorization, excessive data exposure)
+type SensitiveFieldRule struct{}
+
+func (r *SensitiveFieldRule) ID() string         { return "SENSITIVE_FIELD_EXPOSED" }
+func (r *SensitiveFieldRule) Name() string       { return "Sensitive field in response" }
+func (r *SensitiveFieldRule) Severity() Severity { return SeverityWarning }
+
+func (r *SensitiveFieldRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	newSchemaWalker(doc).walkResponses(func(location string, s *openapi.Schema, pointer string) {
+		for _, name := range sortedKeys(s.Properties) {
+			prop := s.Properties[name]
+			if prop == nil || prop.WriteOnly || !isSensitiveField(name) {
+				continue
+			}
+			propPointer := yamlnode.Join(pointer, "properties", name)
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       schemaLocation(location, propPointer),
+				Pointer:        propPointer,
+				Message:        fmt.Sprintf("Sensitive property '%s' can be returned in responses", name),
+				Recommendation: "Mark the property writeOnly: true or remove it from response schemas",
+			})
+		}
+	})
+	return findings
+}
+
+func isSensitiveField(name string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	return sensitiveFieldPattern.MatchString(normalized) && !cursorFieldPattern.MatchString(normalized)
+}
+
+// BearerFormatRule flags bearer token schemes that do not declare the token format
+// (API2: Broken Authentication)
+type BearerFormatRule struct{}
+
+func (r *BearerFormatRule) ID() string         { return "BEARER_FORMAT_MISSING" }
+func (r *BearerFormatRule) Name() string       { return "Bearer token format not declared" }
+func (r *BearerFormatRule) Severity() Severity { return SeverityInfo }
+
+func (r *BearerFormatRule) Check(doc *openapi.Document) []Finding {
+	if doc.Components == nil {
+		return nil
+	}
+	var findings []Finding
+	for _, name := range sortedKeys(doc.Components.SecuritySchemes) {
+		scheme := doc.Components.SecuritySchemes[name]
+		if scheme == nil || scheme.Type != "http" || !strings.EqualFold(scheme.Scheme, "bearer") || scheme.BearerFormat != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			RuleID:         r.ID(),
+			RuleName:       r.Name(),
+			Severity:       r.Severity(),
+			Location:       fmt.Sprintf("SecurityScheme '%s'", name),
+			Pointer:        yamlnode.Join("/components/securitySchemes", name),
+			Message:        fmt.Sprintf("Bearer scheme '%s' does not declare bearerFormat", name),
+			Recommendation: "Set bearerFormat (for example JWT) so clients and gateways know how tokens are validated",
+		})
+	}
+	return findings
+}
+
+// AuthHeaderParamRule flags Authorization header parameters used instead of a security scheme
+// (API2: Broken Authentication)
+type AuthHeaderParamRule struct{}
+
+func (r *AuthHeaderParamRule) ID() string         { return "AUTH_HEADER_PARAM" }
+func (r *AuthHeaderParamRule) Name() string       { return "Authorization header as parameter" }
+func (r *AuthHeaderParamRule) Severity() Severity { return SeverityWarning }
+
+func (r *AuthHeaderParamRule) Check(doc *openapi.Document) []Finding {
+	var findings []Finding
+	seen := make(map[string]bool)
+	forEachOperation(doc, func(path string, entry operationEntry) {
+		operationParameters(doc, path, entry, func(p *openapi.Parameter, pointer string) {
+			if p.In != openapi.ParameterInHeader || !strings.EqualFold(p.Name, "Authorization") || seen[pointer] {
+				return
+			}
+			seen[pointer] = true
+			findings = append(findings, Finding{
+				RuleID:         r.ID(),
+				RuleName:       r.Name(),
+				Severity:       r.Severity(),
+				Location:       fmt.Sprintf("%s %s", entry.method, path),
+				Pointer:        pointer,
+				Message:        "Credentials are declared as an Authorization header parameter instead of a security scheme",
+				Recommendation: "Declare an http bearer (bearerFormat: JWT) or apiKey security scheme in components.securitySchemes and require it",
+			})
+		})
+	})
+	return findings
+}
+
+// hasType reports whether a schema allows the given type.
+func hasType(s *openapi.Schema, t string) bool {
+	for _, st := range s.Type {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// hasResponse reports whether an operation documents a status code, directly or with a range such as 4XX.
+func hasResponse(op *openapi.Operation, code string) bool {
+	if _, ok := op.Responses[code]; ok {
+		return true
+	}
+	_, ok := op.Responses[code[:1]+"XX"]
+	return ok
+}
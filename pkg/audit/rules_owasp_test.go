@@ -0,0 +1,199 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+const owaspSpec = `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+servers:
+  - url: http://api.example.com
+  - url: http://localhost:8080
+  - url: https://api.example.com
+security:
+  - bearer: []
+paths:
+  /users/{userId}:
+    parameters:
+      - name: userId
+        in: path
+        required: true
+        schema: {type: integer}
+    get:
+      parameters:
+        - name: Authorization
+          in: header
+          schema: {type: string, maxLength: 512}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        "401": {description: unauthorized}
+        "403": {description: forbidden}
+        "429":
+          description: slow down
+          headers:
+            Retry-After: {schema: {type: integer}}
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/UserInput"}
+      responses:
+        "200": {description: ok}
+        "401": {description: unauthorized}
+        "429": {description: slow down}
+  /health:
+    get:
+      security: []
+      responses:
+        "200": {description: ok}
+        "4XX": {description: client error}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+        password: {type: string}
+        api_key: {type: string, writeOnly: true}
+        nextPageToken: {type: string}
+    UserInput:
+      type: object
+      properties:
+        name: {type: string, maxLength: 100}
+        bio: {type: string}
+        birthday: {type: string, format: date}
+        role: {type: string, enum: [admin, user]}
+        tags:
+          type: array
+          items: {type: string, maxLength: 20}
+        groups:
+          type: array
+          maxItems: 10
+          items: {type: string, maxLength: 20}
+    Closed:
+      type: object
+      properties:
+        name: {type: string}
+      additionalProperties: false
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+    basic:
+      type: http
+      scheme: basic
+`
+
+// ruleFindings runs a single rule against a spec and returns "location: pointer" for each finding.
+func ruleFindings(t *testing.T, rule Rule, spec string) []string {
+	t.Helper()
+	var doc openapi.Document
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, f := range rule.Check(&doc) {
+		if f.RuleID != rule.ID() || f.Severity != rule.Severity() || f.Recommendation == "" {
+			t.Errorf("malformed finding %+v", f)
+		}
+		out = append(out, f.Location+": "+f.Pointer)
+	}
+	return out
+}
+
+func TestOWASPRules(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want []string
+	}{
+		{&UnboundedArrayRule{}, []string{"Schema 'UserInput': /components/schemas/UserInput/properties/tags"}},
+		{&UnboundedStringRule{}, []string{"Schema 'UserInput': /components/schemas/UserInput/properties/bio"}},
+		{&IntegerIDRule{}, []string{"GET /users/{userId}: /paths/~1users~1{userId}/parameters/0"}},
+		{&MissingAuthResponsesRule{}, []string{"PUT /users/{userId}: /paths/~1users~1{userId}/put/responses"}},
+		{&MissingRateLimitRule{}, []string{
+			"GET /health: /paths/~1health/get/responses",
+			"PUT /users/{userId}: /paths/~1users~1{userId}/put/responses",
+		}},
+		{&HTTPServerRule{}, []string{"Server 'http://api.example.com': /servers/0/url"}},
+		{&BasicAuthNoTLSRule{}, []string{"SecurityScheme 'basic': /components/securitySchemes/basic/scheme"}},
+		{&OpenAdditionalPropertiesRule{}, []string{"Schema 'UserInput': /components/schemas/UserInput"}},
+		{&SensitiveFieldRule{}, []string{"Schema 'User': /components/schemas/User/properties/password"}},
+		{&BearerFormatRule{}, []string{
+			"SecurityScheme 'bearer': /components/securitySchemes/bearer",
+		}},
+		{&AuthHeaderParamRule{}, []string{"GET /users/{userId}: /paths/~1users~1{userId}/get/parameters/0"}},
+	}
+	for _, tt := range tests {
+		if got := ruleFindings(t, tt.rule, owaspSpec); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %q\nwant %q", tt.rule.ID(), got, tt.want)
+		}
+	}
+}
+
+func TestOWASPRulesSecureSpec(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+servers:
+  - url: https://api.example.com
+security:
+  - bearer: []
+paths:
+  /items/{itemId}:
+    patch:
+      parameters:
+        - {name: itemId, in: path, required: true, schema: {type: string, format: uuid}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              additionalProperties: false
+              properties:
+                ids: {type: array, maxItems: 5, items: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  secret: {type: string, writeOnly: true}
+        "4XX":
+          description: client error
+          headers:
+            RateLimit-Remaining: {schema: {type: integer}}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer, bearerFormat: JWT}
+    basic: {type: http, scheme: basic}
+`
+	for _, rule := range DefaultRules()[5:] {
+		if got := ruleFindings(t, rule, spec); len(got) != 0 {
+			t.Errorf("%s: unexpected findings %q", rule.ID(), got)
+		}
+	}
+}
+
+func TestIsSensitiveField(t *testing.T) {
+	for name, want := range map[string]bool{
+		"password": true, "newPassword": true, "client_secret": true, "SSN": true, "access_token": true,
+		"apiKey": true, "private-key": true, "pin": true,
+		"name": false, "pinned": false, "nextPageToken": false, "tokenType": false, "idempotency_token": false,
+	} {
+		if got := isSensitiveField(name); got != want {
+			t.Errorf("isSensitiveField(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
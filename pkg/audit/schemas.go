@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// forEachOperation calls fn for every operation, in path order, so findings come out in a stable order.
+func forEachOperation(doc *openapi.Document, fn func(path string, entry operationEntry)) {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, entry := range getOperations(doc.Paths[path]) {
+			fn(path, entry)
+		}
+	}
+}
+
+// effectiveSecurity returns the security requirements that apply to an operation. An explicit
+// empty list on the operation makes it public.
+func effectiveSecurity(doc *openapi.Document, op *openapi.Operation) []openapi.SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return doc.Security
+}
+
+// requiresAuth reports whether an operation can only be called with credentials. An empty
+// requirement ({}) makes authentication optional.
+func requiresAuth(doc *openapi.Document, op *openapi.Operation) bool {
+	security := effectiveSecurity(doc, op)
+	for _, req := range security {
+		if len(req) == 0 {
+			return false
+		}
+	}
+	return len(security) > 0
+}
+
+// componentName returns the name a local reference to a component of the given kind points at.
+func componentName(ref, kind string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	return yamlnode.Unescape(name), ok && name != ""
+}
+
+// resolveParameter follows a reference to a component parameter.
+func resolveParameter(doc *openapi.Document, p *openapi.Parameter, pointer string) (*openapi.Parameter, string) {
+	if name, ok := componentName(p.Ref, "parameters"); ok && doc.Components != nil {
+		return doc.Components.Parameters[name], yamlnode.Join("/components/parameters", name)
+	}
+	return p, pointer
+}
+
+// resolveRequestBody follows a reference to a component request body.
+func resolveRequestBody(doc *openapi.Document, rb *openapi.RequestBody, pointer string) (*openapi.RequestBody, string) {
+	if name, ok := componentName(rb.Ref, "requestBodies"); ok && doc.Components != nil {
+		return doc.Components.RequestBodies[name], yamlnode.Join("/components/requestBodies", name)
+	}
+	return rb, pointer
+}
+
+// resolveResponse follows a reference to a component response.
+func resolveResponse(doc *openapi.Document, r *openapi.Response, pointer string) (*openapi.Response, string) {
+	if name, ok := componentName(r.Ref, "responses"); ok && doc.Components != nil {
+		return doc.Components.Responses[name], yamlnode.Join("/components/responses", name)
+	}
+	return r, pointer
+}
+
+// operationParameters calls fn for the parameters of an operation and of its path item.
+func operationParameters(doc *openapi.Document, path string, entry operationEntry, fn func(p *openapi.Parameter, pointer string)) {
+	visit := func(params []*openapi.Parameter, pointer string) {
+		for i, p := range params {
+			if p == nil {
+				continue
+			}
+			if resolved, at := resolveParameter(doc, p, yamlnode.Index(pointer, i)); resolved != nil {
+				fn(resolved, at)
+			}
+		}
+	}
+	visit(doc.Paths[path].Parameters, yamlnode.Join("/paths", path, "parameters"))
+	visit(entry.op.Parameters, yamlnode.Join(operationPointer(path, entry.method), "parameters"))
+}
+
+// schemaWalker visits schemas and their subschemas once each, following references to component
+// schemas, which are reported at their component pointer.
+type schemaWalker struct {
+	doc    *openapi.Document
+	seen   map[*openapi.Schema]bool
+	params map[string]string // Parameter names by schema pointer
+}
+
+func newSchemaWalker(doc *openapi.Document) *schemaWalker {
+	return &schemaWalker{doc: doc, seen: make(map[*openapi.Schema]bool), params: make(map[string]string)}
+}
+
+func (w *schemaWalker) walk(s *openapi.Schema, pointer string, fn func(s *openapi.Schema, pointer string)) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		if name, ok := componentName(s.Ref, "schemas"); ok && w.doc.Components != nil {
+			w.walk(w.doc.Components.Schemas[name], yamlnode.Join("/components/schemas", name), fn)
+		}
+		return
+	}
+	if w.seen[s] {
+		return
+	}
+	w.seen[s] = true
+	fn(s, pointer)
+
+	w.walk(s.Items, yamlnode.Join(pointer, "items"), fn)
+	for _, name := range sortedKeys(s.Properties) {
+		w.walk(s.Properties[name], yamlnode.Join(pointer, "properties", name), fn)
+	}
+	w.walk(s.AdditionalProperties, yamlnode.Join(pointer, "additionalProperties"), fn)
+	for _, c := range []struct {
+		keyword string
+		list    []*openapi.Schema
+	}{{"allOf", s.AllOf}, {"anyOf", s.AnyOf}, {"oneOf", s.OneOf}} {
+		for i, sub := range c.list {
+			w.walk(sub, yamlnode.Index(yamlnode.Join(pointer, c.keyword), i), fn)
+		}
+	}
+}
+
+// walkContent walks the schemas of the media types of a request body or response.
+func (w *schemaWalker) walkContent(content map[string]openapi.MediaType, pointer string, fn func(s *openapi.Schema, pointer string)) {
+	for _, mediaType := range sortedKeys(content) {
+		w.walk(content[mediaType].Schema, yamlnode.Join(pointer, "content", mediaType, "schema"), fn)
+	}
+}
+
+// walkInputs walks the request body and parameter schemas of every operation.
+func (w *schemaWalker) walkInputs(fn func(location string, s *openapi.Schema, pointer string)) {
+	forEachOperation(w.doc, func(path string, entry operationEntry) {
+		location := fmt.Sprintf("%s %s", entry.method, path)
+		visit := func(s *openapi.Schema, pointer string) { fn(location, s, pointer) }
+		if rb := entry.op.RequestBody; rb != nil {
+			if body, at := resolveRequestBody(w.doc, rb, yamlnode.Join(operationPointer(path, entry.method), "requestBody")); body != nil {
+				w.walkContent(body.Content, at, visit)
+			}
+		}
+		operationParameters(w.doc, path, entry, func(p *openapi.Parameter, pointer string) {
+			w.params[yamlnode.Join(pointer, "schema")] = p.Name
+			w.walk(p.Schema, yamlnode.Join(pointer, "schema"), visit)
+		})
+	})
+}
+
+// walkResponses walks the response body schemas of every operation.
+func (w *schemaWalker) walkResponses(fn func(location string, s *openapi.Schema, pointer string)) {
+	forEachOperation(w.doc, func(path string, entry operationEntry) {
+		location := fmt.Sprintf("%s %s", entry.method, path)
+		visit := func(s *openapi.Schema, pointer string) { fn(location, s, pointer) }
+		for _, code := range sortedKeys(entry.op.Responses) {
+			r := entry.op.Responses[code]
+			if r == nil {
+				continue
+			}
+			if resolved, at := resolveResponse(w.doc, r, yamlnode.Join(operationPointer(path, entry.method), "responses", code)); resolved != nil {
+				w.walkContent(resolved.Content, at, visit)
+			}
+		}
+	})
+}
+
+// schemaLocation describes where a schema is: the component it belongs to, or the operation it was reached from.
+func schemaLocation(operation, pointer string) string {
+	if rest, ok := strings.CutPrefix(pointer, "/components/schemas/"); ok {
+		name, _, _ := strings.Cut(rest, "/")
+		return fmt.Sprintf("Schema '%s'", yamlnode.Unescape(name))
+	}
+	return operation
+}
+
+// subject names a schema in a message: its property or parameter name, or "schema".
+func (w *schemaWalker) subject(pointer string) string {
+	if name, ok := w.params[pointer]; ok {
+		return fmt.Sprintf("parameter '%s'", name)
+	}
+	parent, last := splitPointer(pointer)
+	if _, field := splitPointer(parent); field == "properties" {
+		return fmt.Sprintf("property '%s'", last)
+	}
+	if last == "items" {
+		if _, field := splitPointer(parent); field != "" {
+			return fmt.Sprintf("items of '%s'", field)
+		}
+	}
+	return "schema"
+}
+
+// splitPointer splits the last token off a JSON pointer.
+func splitPointer(pointer string) (parent, last string) {
+	i := strings.LastIndex(pointer, "/")
+	if i < 0 {
+		return "", ""
+	}
+	return pointer[:i], yamlnode.Unescape(pointer[i+1:])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,512 @@
+// Package bundle resolves the external $refs of an OpenAPI document split across several files or URLs.
+// Referenced values are copied into components and the $refs point at the copies, or, when dereferencing,
+// every $ref is replaced by the value it refers to.
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// Option configures bundling
+type Option func(*bundler)
+
+// WithDereference replaces every $ref, internal ones included, with a copy of the value it refers to.
+// Circular references cannot be inlined and make bundling fail.
+func WithDereference() Option {
+	return func(b *bundler) {
+		b.dereference = true
+	}
+}
+
+// WithFetcher sets how referenced documents are read. The default reads files and fetches http(s) URLs.
+func WithFetcher(fetch func(location string) ([]byte, error)) Option {
+	return func(b *bundler) {
+		b.fetch = fetch
+	}
+}
+
+// File bundles the document in a file, resolving relative $refs against its directory.
+func File(path string, opts ...Option) (*yaml.Node, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	b := newBundler(opts)
+	data, err := b.fetch(abs)
+	if err != nil {
+		return nil, err
+	}
+	return b.bundle(data, abs)
+}
+
+// URL bundles the document at a URL, resolving relative $refs against it.
+func URL(url string, opts ...Option) (*yaml.Node, error) {
+	b := newBundler(opts)
+	data, err := b.fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	return b.bundle(data, url)
+}
+
+// Data bundles a document read from base, the file path or URL relative $refs are resolved against.
+// An empty base resolves them against the working directory.
+func Data(data []byte, base string, opts ...Option) (*yaml.Node, error) {
+	if !isURL(base) {
+		if base == "" {
+			base = "-"
+		}
+		abs, err := filepath.Abs(base)
+		if err != nil {
+			return nil, err
+		}
+		base = abs
+	}
+	return newBundler(opts).bundle(data, base)
+}
+
+// componentSections are the sections of components that $refs can be bundled into
+var componentSections = []string{
+	"schemas", "responses", "parameters", "examples", "requestBodies",
+	"headers", "securitySchemes", "links", "callbacks", "pathItems",
+}
+
+// bundler holds the state of a single bundling run
+type bundler struct {
+	dereference bool
+	fetch       func(location string) ([]byte, error)
+
+	root     *yaml.Node
+	rootLoc  string
+	docs     map[string]*yaml.Node // Parsed documents by location
+	bundled  map[string]string     // $refs of copies by the location#fragment they were copied from
+	inlining []string              // Targets being inlined, to detect cycles
+}
+
+func newBundler(opts []Option) *bundler {
+	b := &bundler{
+		fetch:   fetch,
+		docs:    make(map[string]*yaml.Node),
+		bundled: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+func (b *bundler) bundle(data []byte, location string) (*yaml.Node, error) {
+	root, err := yamlnode.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", location, err)
+	}
+	b.root, b.rootLoc = root, location
+	b.docs[location] = root
+
+	if !b.dereference {
+		if err := b.registerRoot(); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.walk(root, yamlnode.Scope{}, location, ""); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// registerRoot keeps the names of root components that are only a $ref to another file, such as
+// User: {$ref: ./user.yaml}, so the file is copied under that name. Path items that are a $ref
+// are registered too, so other $refs into their files point into the copied path item.
+func (b *bundler) registerRoot() error {
+	var err error
+	register := func(pointer string, value *yaml.Node) {
+		ref := yamlnode.Ref(value)
+		if ref == "" || err != nil {
+			return
+		}
+		var location, fragment string
+		location, fragment, err = b.locate(ref, b.rootLoc)
+		if err == nil && location != b.rootLoc {
+			b.bundled[location+"#"+fragment] = "#" + pointer
+		}
+	}
+
+	components := yamlnode.Get(b.root, "components")
+	for _, section := range componentSections {
+		yamlnode.Pairs(yamlnode.Get(components, section), func(key, value *yaml.Node) {
+			register(yamlnode.Join("/components", section, key.Value), value)
+		})
+	}
+	yamlnode.Pairs(yamlnode.Get(b.root, "paths"), func(key, value *yaml.Node) {
+		register(yamlnode.Join("/paths", key.Value), value)
+	})
+	return err
+}
+
+// bundledRef returns the $ref of a copy of the value at location#fragment, or of a copy of a value
+// that contains it, or "".
+func (b *bundler) bundledRef(location, fragment string) string {
+	ref, longest := "", -1
+	for key, copyRef := range b.bundled {
+		keyLoc, prefix, _ := strings.Cut(key, "#")
+		if keyLoc != location || len(prefix) <= longest {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(fragment, prefix); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+			ref, longest = copyRef+rest, len(prefix)
+		}
+	}
+	return ref
+}
+
+// walk replaces the $refs below node, which was read from location and is placed at pointer in the
+// result. The scope tells the keys of node apart from property and component names.
+func (b *bundler) walk(node *yaml.Node, scope yamlnode.Scope, location, pointer string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if ref := yamlnode.Ref(node); ref != "" && !scope.Names {
+			return b.replace(node, scope, ref, location, pointer)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if scope.IsExample(key) {
+				// Examples are data, where $ref has no meaning
+				continue
+			}
+			if key == "discriminator" && !scope.Names {
+				if err := b.mapping(node.Content[i+1], location); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := b.walk(node.Content[i+1], scope.Child(key), location, yamlnode.Join(pointer, key)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := b.walk(child, scope.Item(), location, yamlnode.Index(pointer, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replace resolves the $ref of node.
+func (b *bundler) replace(node *yaml.Node, scope yamlnode.Scope, ref, location, pointer string) error {
+	target, fragment, err := b.locate(ref, location)
+	if err != nil {
+		return err
+	}
+	key := target + "#" + fragment
+
+	if b.dereference {
+		return b.inline(node, scope, key, target, fragment, pointer)
+	}
+	if target == b.rootLoc {
+		setRef(node, "#"+fragment)
+		return nil
+	}
+	if b.bundled[key] == "#"+pointer {
+		// The root component that refers to the file becomes the copy
+		return b.inline(node, scope, key, target, fragment, pointer)
+	}
+	section := sectionFor(pointer)
+	if section == "" {
+		// Path items have no place in OpenAPI 3.0 components, so they are copied in place
+		return b.inline(node, scope, key, target, fragment, pointer)
+	}
+	if bundledRef := b.bundledRef(target, fragment); bundledRef != "" {
+		setRef(node, bundledRef)
+		return nil
+	}
+	componentRef, err := b.component(section, key, target, fragment)
+	if err != nil {
+		return err
+	}
+	setRef(node, componentRef)
+	return nil
+}
+
+// mapping bundles the schemas a discriminator mapping refers to by reference rather than by name.
+// The mapping keeps references to them even when dereferencing.
+func (b *bundler) mapping(discriminator *yaml.Node, location string) error {
+	var err error
+	yamlnode.Pairs(yamlnode.Get(discriminator, "mapping"), func(_, value *yaml.Node) {
+		if err != nil || !isReference(value.Value) {
+			return
+		}
+		var target, fragment string
+		target, fragment, err = b.locate(value.Value, location)
+		if err != nil {
+			return
+		}
+		ref := "#" + fragment
+		if target != b.rootLoc {
+			if ref = b.bundledRef(target, fragment); ref == "" {
+				ref, err = b.component("schemas", target+"#"+fragment, target, fragment)
+			}
+		}
+		value.Value = ref
+	})
+	return err
+}
+
+// isReference reports whether a discriminator mapping value is a reference rather than a schema name.
+func isReference(value string) bool {
+	return strings.Contains(value, "#") || strings.Contains(value, "/") || isURL(value)
+}
+
+// component copies the value of a $ref into a section of the root components, once, and returns
+// the $ref of the copy.
+func (b *bundler) component(section, key, location, fragment string) (string, error) {
+	value, err := b.resolve(location, fragment)
+	if err != nil {
+		return "", err
+	}
+	name := b.uniqueName(section, componentName(location, fragment))
+	pointer := yamlnode.Join("/components", section, name)
+	// Registered before walking the copy, so references back to it end here
+	b.bundled[key] = "#" + pointer
+
+	value = yamlnode.Clone(value)
+	yamlnode.SetPair(b.section(section), name, value)
+	if err := b.walk(value, yamlnode.Scope{Field: section}, location, pointer); err != nil {
+		return "", err
+	}
+	return "#" + pointer, nil
+}
+
+// inline replaces node with a copy of the value its $ref refers to. Other keys next to the $ref,
+// such as a description, override those of the value.
+func (b *bundler) inline(node *yaml.Node, scope yamlnode.Scope, key, location, fragment, pointer string) error {
+	if i := slices.Index(b.inlining, key); i >= 0 {
+		cycle := append(slices.Clone(b.inlining[i:]), key)
+		return fmt.Errorf("circular $ref cannot be dereferenced: %s", strings.Join(cycle, " -> "))
+	}
+	value, err := b.resolve(location, fragment)
+	if err != nil {
+		return err
+	}
+	value = yamlnode.Clone(value)
+
+	b.inlining = append(b.inlining, key)
+	err = b.walk(value, scope, location, pointer)
+	b.inlining = b.inlining[:len(b.inlining)-1]
+	if err != nil {
+		return err
+	}
+
+	siblings := node.Content
+	*node = *value
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(siblings); i += 2 {
+		if siblings[i].Value != "$ref" {
+			yamlnode.SetPair(node, siblings[i].Value, siblings[i+1])
+		}
+	}
+	return nil
+}
+
+// locate returns the document location and JSON pointer a $ref found in the document at base refers to.
+func (b *bundler) locate(ref, base string) (location, fragment string, err error) {
+	target, fragment, _ := strings.Cut(ref, "#")
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", "", fmt.Errorf("unsupported $ref %q in %s: only JSON pointer fragments are supported", ref, base)
+	}
+	if target == "" {
+		return base, fragment, nil
+	}
+	if isURL(target) {
+		return target, fragment, nil
+	}
+	if isURL(base) {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", "", err
+		}
+		refURL, err := url.Parse(target)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid $ref %q in %s: %w", ref, base, err)
+		}
+		return baseURL.ResolveReference(refURL).String(), fragment, nil
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	target = filepath.FromSlash(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(base), target)
+	}
+	return filepath.Clean(target), fragment, nil
+}
+
+// resolve returns the node at fragment in the document at location.
+func (b *bundler) resolve(location, fragment string) (*yaml.Node, error) {
+	doc, err := b.load(location)
+	if err != nil {
+		return nil, err
+	}
+	node := yamlnode.Resolve(doc, fragment)
+	if node == nil {
+		return nil, fmt.Errorf("$ref target %s#%s not found", location, fragment)
+	}
+	return node, nil
+}
+
+func (b *bundler) load(location string) (*yaml.Node, error) {
+	if doc, ok := b.docs[location]; ok {
+		return doc, nil
+	}
+	data, err := b.fetch(location)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := yamlnode.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", location, err)
+	}
+	b.docs[location] = doc
+	return doc, nil
+}
+
+// section returns a section of the root components, creating it as needed.
+func (b *bundler) section(name string) *yaml.Node {
+	components := yamlnode.Get(b.root, "components")
+	if components == nil {
+		components = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		yamlnode.SetPair(b.root, "components", components)
+	}
+	section := yamlnode.Get(components, name)
+	if section == nil {
+		section = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		yamlnode.SetPair(components, name, section)
+	}
+	return section
+}
+
+// uniqueName returns name, or name followed by a number when the section already has it.
+func (b *bundler) uniqueName(section, name string) string {
+	existing := yamlnode.Get(yamlnode.Get(b.root, "components"), section)
+	unique := name
+	for i := 2; yamlnode.Get(existing, unique) != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// sectionFor returns the components section for a $ref at pointer, or "" for path items.
+func sectionFor(pointer string) string {
+	var tokens []string
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		tokens = append(tokens, yamlnode.Unescape(token))
+	}
+	n := len(tokens)
+	if n == 3 && tokens[0] == "components" && slices.Contains(componentSections, tokens[1]) {
+		return tokens[1]
+	}
+	if n >= 2 && slices.ContainsFunc(tokens[:n-2], isSchemaKeyword) {
+		return "schemas"
+	}
+	switch {
+	case n == 0:
+		return "schemas"
+	case tokens[n-1] == "requestBody":
+		return "requestBodies"
+	case n >= 2 && (tokens[n-2] == "paths" || tokens[n-2] == "webhooks"):
+		return ""
+	case n >= 3 && tokens[n-3] == "callbacks":
+		return ""
+	case n >= 2 && slices.Contains([]string{"parameters", "responses", "headers", "examples", "links", "callbacks"}, tokens[n-2]):
+		return tokens[n-2]
+	}
+	return "schemas"
+}
+
+// isSchemaKeyword reports whether a token leads into a subschema, below which every $ref is a schema.
+func isSchemaKeyword(token string) bool {
+	switch token {
+	case "schema", "properties", "items", "additionalProperties", "allOf", "anyOf", "oneOf", "not",
+		"patternProperties", "prefixItems", "$defs", "definitions", "contains", "if", "then", "else",
+		"dependentSchemas", "propertyNames", "unevaluatedItems", "unevaluatedProperties":
+		return true
+	}
+	return false
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// componentName names a copied value after the last token of its pointer, or its file name.
+func componentName(location, fragment string) string {
+	name := ""
+	if i := strings.LastIndex(fragment, "/"); i >= 0 {
+		name = yamlnode.Unescape(fragment[i+1:])
+	}
+	if name == "" {
+		base := location
+		if u, err := url.Parse(location); err == nil && isURL(location) {
+			base = path.Base(u.Path)
+		} else {
+			base = filepath.Base(location)
+		}
+		name = strings.TrimSuffix(base, path.Ext(base))
+	}
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "Bundled"
+	}
+	return name
+}
+
+func setRef(node *yaml.Node, ref string) {
+	yamlnode.SetPair(node, "$ref", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ref})
+}
+
+func fetch(location string) ([]byte, error) {
+	if !isURL(location) {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return data, nil
+	}
+
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", location, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
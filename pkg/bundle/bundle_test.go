@@ -0,0 +1,326 @@
+package bundle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+var files = map[string]string{
+	"openapi.yaml": `openapi: 3.0.3
+info: {title: Users, version: 1.0.0}
+paths:
+  /users:
+    $ref: ./paths/users.yaml
+components:
+  schemas:
+    Address:
+      type: string
+    Error:
+      $ref: ./schemas/error.yaml
+`,
+	"paths/users.yaml": `get:
+  parameters:
+    - $ref: ../common.yaml#/parameters/Limit
+  responses:
+    "200":
+      description: ok
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: ../schemas/user.yaml#/User
+    default:
+      $ref: ../common.yaml#/responses/Problem
+`,
+	"schemas/user.yaml": `User:
+  type: object
+  properties:
+    address:
+      $ref: "#/Address"
+    friends:
+      type: array
+      items:
+        $ref: "#/User"
+Address:
+  type: object
+  properties:
+    city: {type: string}
+`,
+	"schemas/error.yaml": `type: object
+properties:
+  message: {type: string}
+`,
+	"common.yaml": `parameters:
+  Limit:
+    name: limit
+    in: query
+    schema: {type: integer}
+responses:
+  Problem:
+    description: A problem
+    content:
+      application/json:
+        schema:
+          $ref: ./schemas/error.yaml
+`,
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func get(t *testing.T, root *yaml.Node, pointer string) *yaml.Node {
+	t.Helper()
+	node := yamlnode.Resolve(root, pointer)
+	if node == nil {
+		t.Fatalf("%s not found in\n%s", pointer, dump(root))
+	}
+	return node
+}
+
+func assertRef(t *testing.T, root *yaml.Node, pointer, want string) {
+	t.Helper()
+	if got := yamlnode.Ref(get(t, root, pointer)); got != want {
+		t.Errorf("%s: $ref = %q, want %q", pointer, got, want)
+	}
+}
+
+func dump(node *yaml.Node) string {
+	data, _ := yaml.Marshal(node)
+	return string(data)
+}
+
+func TestFile(t *testing.T) {
+	dir := writeFiles(t, files)
+	root, err := File(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := "/paths/~1users/get"
+	get(t, root, op)
+	assertRef(t, root, op+"/parameters/0", "#/components/parameters/Limit")
+	assertRef(t, root, op+"/responses/200/content/application~1json/schema/items", "#/components/schemas/User")
+	assertRef(t, root, op+"/responses/default", "#/components/responses/Problem")
+
+	// Refs inside copied files point at the copies, including circular ones
+	assertRef(t, root, "/components/schemas/User/properties/friends/items", "#/components/schemas/User")
+	assertRef(t, root, "/components/schemas/User/properties/address", "#/components/schemas/Address2")
+	get(t, root, "/components/schemas/Address2/properties/city")
+	if get(t, root, "/components/schemas/Address/type").Value != "string" {
+		t.Error("existing Address schema was overwritten")
+	}
+
+	// A root component that refers to a file becomes the copy and keeps its name
+	get(t, root, "/components/schemas/Error/properties/message")
+	assertRef(t, root, "/components/responses/Problem/content/application~1json/schema", "#/components/schemas/Error")
+	if out := dump(root); strings.Contains(out, ".yaml") {
+		t.Errorf("external $ref left in\n%s", out)
+	}
+}
+
+func TestDereference(t *testing.T) {
+	acyclic := make(map[string]string)
+	for name, content := range files {
+		acyclic[name] = content
+	}
+	acyclic["schemas/user.yaml"] = strings.Replace(files["schemas/user.yaml"], `$ref: "#/User"`, "type: string", 1)
+	dir := writeFiles(t, acyclic)
+
+	root, err := File(filepath.Join(dir, "openapi.yaml"), WithDereference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := dump(root); strings.Contains(out, "$ref") {
+		t.Errorf("$ref left after dereferencing:\n%s", out)
+	}
+	get(t, root, "/paths/~1users/get/responses/200/content/application~1json/schema/items/properties/address/properties/city")
+	get(t, root, "/paths/~1users/get/responses/default/content/application~1json/schema/properties/message")
+
+	dir = writeFiles(t, files)
+	_, err = File(filepath.Join(dir, "openapi.yaml"), WithDereference())
+	if err == nil || !strings.Contains(err.Error(), "circular $ref") || !strings.Contains(err.Error(), "user.yaml#/User -> ") {
+		t.Errorf("err = %v, want a circular $ref error", err)
+	}
+}
+
+func TestDereferenceSiblings(t *testing.T) {
+	root, err := Data([]byte(`openapi: 3.1.0
+paths:
+  /a:
+    get:
+      responses:
+        "200":
+          $ref: "#/components/responses/Ok"
+          description: Overridden
+components:
+  responses:
+    Ok:
+      description: ok
+      headers: {X-Id: {schema: {type: string}}}
+`), "", WithDereference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := get(t, root, "/paths/~1a/get/responses/200")
+	if get(t, resp, "/description").Value != "Overridden" || yamlnode.Get(resp, "headers") == nil {
+		t.Errorf("response = %s", dump(resp))
+	}
+}
+
+func TestURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/openapi.yaml":
+			_, _ = w.Write([]byte("openapi: 3.0.3\npaths:\n  /pets:\n    get:\n      responses:\n        \"200\":\n          $ref: ./responses.yaml#/PetList\n"))
+		case "/api/responses.yaml":
+			_, _ = w.Write([]byte("PetList:\n  description: Pets\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	root, err := URL(server.URL + "/api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRef(t, root, "/paths/~1pets/get/responses/200", "#/components/responses/PetList")
+	get(t, root, "/components/responses/PetList/description")
+
+	_, err = Data([]byte("openapi: 3.0.3\ncomponents:\n  schemas:\n    A: {$ref: missing.yaml}\n"), server.URL+"/api/openapi.yaml")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err = %v, want HTTP 404", err)
+	}
+}
+
+func TestMissingTarget(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"openapi.yaml": "openapi: 3.0.3\ncomponents:\n  schemas:\n    A: {$ref: './b.yaml#/Nope'}\n",
+		"b.yaml":       "B: {type: string}\n",
+	})
+	_, err := File(filepath.Join(dir, "openapi.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "b.yaml#/Nope not found") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSectionFor(t *testing.T) {
+	tests := map[string]string{
+		"/paths/~1a":                                              "",
+		"/paths/~1a/get/parameters/0":                             "parameters",
+		"/paths/~1a/get/parameters/0/schema":                      "schemas",
+		"/paths/~1a/get/parameters/0/examples/max":                "examples",
+		"/paths/~1a/post/requestBody":                             "requestBodies",
+		"/paths/~1a/get/responses/200":                            "responses",
+		"/paths/~1a/get/responses/200/headers/X-Rate":             "headers",
+		"/paths/~1a/post/callbacks/onEvent":                       "callbacks",
+		"/paths/~1a/post/callbacks/onEvent/{$request.body#~1url}": "",
+		"/components/schemas/A":                                   "schemas",
+		"/components/securitySchemes/Key":                         "securitySchemes",
+		"/components/schemas/A/properties/headers/items":          "schemas",
+		"/components/schemas/A/properties/parameters":             "schemas",
+		"/webhooks/newPet":                                        "",
+	}
+	for pointer, want := range tests {
+		if got := sectionFor(pointer); got != want {
+			t.Errorf("sectionFor(%q) = %q, want %q", pointer, got, want)
+		}
+	}
+}
+
+func TestComponentName(t *testing.T) {
+	tests := []struct{ location, fragment, want string }{
+		{"/specs/user.yaml", "/User", "User"},
+		{"/specs/user.yaml", "", "user"},
+		{"https://example.com/schemas/pet%20type.json", "", "pet_type"},
+		{"/specs/defs.yaml", "/definitions/Pet Owner", "Pet_Owner"},
+	}
+	for _, tt := range tests {
+		if got := componentName(tt.location, tt.fragment); got != tt.want {
+			t.Errorf("componentName(%q, %q) = %q, want %q", tt.location, tt.fragment, got, tt.want)
+		}
+	}
+}
+
+func TestDiscriminatorAndNestedRefs(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"openapi.yaml": `openapi: 3.0.3
+paths:
+  /pets:
+    $ref: ./pets.yaml
+  /pets/{id}:
+    get:
+      responses:
+        "200": {$ref: "./pets.yaml#/get/responses/200"}
+components:
+  schemas:
+    Pet:
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: ./cat.yaml
+          dog: Dog
+      properties:
+        name: {$ref: "./cat.yaml#/properties/name"}
+`,
+		"pets.yaml": "get:\n  responses:\n    \"200\": {description: ok}\n",
+		"cat.yaml":  "type: object\nproperties:\n  name: {type: string}\n",
+	})
+	root, err := File(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRef(t, root, "/paths/~1pets~1{id}/get/responses/200", "#/paths/~1pets/get/responses/200")
+	mapping := get(t, root, "/components/schemas/Pet/discriminator/mapping")
+	if cat, dog := yamlnode.Get(mapping, "cat").Value, yamlnode.Get(mapping, "dog").Value; cat != "#/components/schemas/cat" || dog != "Dog" {
+		t.Errorf("mapping = %s", dump(mapping))
+	}
+	assertRef(t, root, "/components/schemas/Pet/properties/name", "#/components/schemas/cat/properties/name")
+}
+
+func TestPropertiesNamedLikeFields(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"openapi.yaml": `openapi: 3.0.3
+paths: {}
+components:
+  schemas:
+    Wrapper:
+      type: object
+      properties:
+        example: {$ref: "./other.yaml#/Thing"}
+        discriminator: {$ref: "./other.yaml#/Thing"}
+      example:
+        example: {$ref: "./other.yaml#/Thing"}
+`,
+		"other.yaml": "Thing:\n  type: string\n",
+	})
+	root, err := File(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRef(t, root, "/components/schemas/Wrapper/properties/example", "#/components/schemas/Thing")
+	assertRef(t, root, "/components/schemas/Wrapper/properties/discriminator", "#/components/schemas/Thing")
+	// The example field is data, left as it is
+	assertRef(t, root, "/components/schemas/Wrapper/example/example", "./other.yaml#/Thing")
+}
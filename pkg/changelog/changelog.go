@@ -0,0 +1,213 @@
+// Package changelog turns the changes between two versions of an OpenAPI document into a
+// human-readable changelog grouped by tag.
+package changelog
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/diff"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// UntaggedGroup is the heading of operations without tags
+const UntaggedGroup = "Other"
+
+// Kind is the section of the changelog an entry belongs to
+type Kind string
+
+const (
+	KindNewEndpoints        Kind = "New endpoints"
+	KindDeprecatedEndpoints Kind = "Deprecated endpoints"
+	KindRemovedEndpoints    Kind = "Removed endpoints"
+	KindNewFields           Kind = "New fields"
+	KindRemovedFields       Kind = "Removed fields"
+	KindChangedSchemas      Kind = "Changed schemas"
+	KindOther               Kind = "Other changes"
+)
+
+// kinds lists the sections in the order they are written
+var kinds = []Kind{
+	KindNewEndpoints, KindDeprecatedEndpoints, KindRemovedEndpoints,
+	KindNewFields, KindRemovedFields, KindChangedSchemas, KindOther,
+}
+
+// Entry is a line of the changelog
+type Entry struct {
+	Kind     Kind   `json:"kind"`
+	Breaking bool   `json:"breaking,omitempty"`
+	Subject  string `json:"subject"` // The endpoint or component schema the entry is about
+	Text     string `json:"text"`
+}
+
+// Group holds the entries of a tag
+type Group struct {
+	Tag     string  `json:"tag"`
+	Entries []Entry `json:"entries"`
+}
+
+// Changelog describes a release of an API
+type Changelog struct {
+	Title  string  `json:"title"`
+	Date   string  `json:"date,omitempty"`
+	Groups []Group `json:"groups"`
+}
+
+// Option configures a changelog
+type Option func(*Changelog)
+
+// WithTitle sets the title of the release; the default is the version of the new document.
+func WithTitle(title string) Option {
+	return func(c *Changelog) {
+		if title != "" {
+			c.Title = title
+		}
+	}
+}
+
+// WithDate sets the release date shown next to the title.
+func WithDate(date string) Option {
+	return func(c *Changelog) {
+		c.Date = date
+	}
+}
+
+// Build compares two documents and groups the changes by the tags of their operations.
+func Build(from, to *openapi.Document, opts ...Option) *Changelog {
+	c := &Changelog{Title: to.Info.Version, Groups: []Group{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	ops := indexOperations(from)
+	for location, op := range indexOperations(to) {
+		ops[location] = op
+	}
+
+	groups := make(map[string]*Group)
+	seen := make(map[string]bool)
+	for _, change := range diff.Compare(from, to).Changes {
+		entry := newEntry(change, ops[change.Location])
+		tags := []string{UntaggedGroup}
+		if op := ops[change.Location]; op != nil && len(op.Tags) > 0 {
+			tags = op.Tags
+		}
+		for _, tag := range tags {
+			// Changes to shared schemas are reported once per tag rather than once per operation
+			key := tag + "\x00" + string(entry.Kind) + "\x00" + entry.Subject + "\x00" + entry.Text
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			g, ok := groups[tag]
+			if !ok {
+				g = &Group{Tag: tag}
+				groups[tag] = g
+			}
+			g.Entries = append(g.Entries, entry)
+		}
+	}
+
+	for _, tag := range tagOrder(to, groups) {
+		g := groups[tag]
+		sort.SliceStable(g.Entries, func(i, j int) bool {
+			return slices.Index(kinds, g.Entries[i].Kind) < slices.Index(kinds, g.Entries[j].Kind)
+		})
+		c.Groups = append(c.Groups, *g)
+	}
+	return c
+}
+
+// indexOperations returns the operations of a document by "METHOD /path", as diff locates them.
+func indexOperations(doc *openapi.Document) map[string]*openapi.Operation {
+	ops := make(map[string]*openapi.Operation)
+	for path, item := range doc.Paths {
+		if item == nil {
+			continue
+		}
+		for method, op := range map[string]*openapi.Operation{
+			"GET": item.Get, "PUT": item.Put, "POST": item.Post, "DELETE": item.Delete,
+			"OPTIONS": item.Options, "HEAD": item.Head, "PATCH": item.Patch, "TRACE": item.Trace,
+		} {
+			if op != nil {
+				ops[method+" "+path] = op
+			}
+		}
+	}
+	return ops
+}
+
+// tagOrder returns the tags with changes: those declared by the document first, in its order,
+// then the others alphabetically, and untagged operations last.
+func tagOrder(doc *openapi.Document, groups map[string]*Group) []string {
+	var order []string
+	for _, t := range doc.Tags {
+		if _, ok := groups[t.Name]; ok && !slices.Contains(order, t.Name) {
+			order = append(order, t.Name)
+		}
+	}
+	var rest []string
+	for tag := range groups {
+		if tag != UntaggedGroup && !slices.Contains(order, tag) {
+			rest = append(rest, tag)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+	if _, ok := groups[UntaggedGroup]; ok && !slices.Contains(order, UntaggedGroup) {
+		order = append(order, UntaggedGroup)
+	}
+	return order
+}
+
+func newEntry(change diff.Change, op *openapi.Operation) Entry {
+	entry := Entry{
+		Kind:     kindOf(change),
+		Breaking: change.Level == diff.LevelBreaking,
+		Subject:  change.Location,
+		Text:     change.Message,
+	}
+	switch entry.Kind {
+	case KindNewEndpoints, KindDeprecatedEndpoints, KindRemovedEndpoints:
+		entry.Text = ""
+		if op != nil {
+			entry.Text = op.Summary
+		}
+	default:
+		if name := componentSchema(change.Pointer); name != "" {
+			entry.Subject = name
+		}
+	}
+	return entry
+}
+
+func kindOf(change diff.Change) Kind {
+	id := change.ID
+	switch {
+	case id == "endpoint-added":
+		return KindNewEndpoints
+	case id == "endpoint-deprecated":
+		return KindDeprecatedEndpoints
+	case id == "endpoint-removed":
+		return KindRemovedEndpoints
+	case strings.HasSuffix(id, "-property-added"):
+		return KindNewFields
+	case strings.HasSuffix(id, "-property-removed"):
+		return KindRemovedFields
+	case componentSchema(change.Pointer) != "":
+		return KindChangedSchemas
+	}
+	return KindOther
+}
+
+// componentSchema returns the name of the component schema a pointer is in, if any.
+func componentSchema(pointer string) string {
+	rest, ok := strings.CutPrefix(pointer, "/components/schemas/")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "/")
+	return yamlnode.Unescape(name)
+}
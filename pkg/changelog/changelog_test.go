@@ -0,0 +1,177 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/diff"
+)
+
+const fromSpec = `openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}}
+  /pets/{id}:
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {$ref: "#/components/schemas/Pet"}}
+  /legacy:
+    get:
+      summary: Legacy listing
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        nickname: {type: string}
+`
+
+const toSpec = `openapi: 3.0.3
+info: {title: Pets, version: 2.0.0}
+tags:
+  - name: stores
+  - name: pets
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      deprecated: true
+      summary: List pets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}}
+  /pets/{id}:
+    get:
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {$ref: "#/components/schemas/Pet"}}
+  /stores:
+    post:
+      tags: [stores]
+      summary: Open a <store>
+      responses: {"201": {description: created}}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        age: {type: integer}
+`
+
+func build(t *testing.T, opts ...Option) *Changelog {
+	t.Helper()
+	from, err := diff.Parse([]byte(fromSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := diff.Parse([]byte(toSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Build(from, to, opts...)
+}
+
+func TestBuild(t *testing.T) {
+	c := build(t)
+	if c.Title != "2.0.0" {
+		t.Errorf("Title = %q", c.Title)
+	}
+	var tags []string
+	for _, g := range c.Groups {
+		tags = append(tags, g.Tag)
+	}
+	if strings.Join(tags, ",") != "stores,pets,Other" {
+		t.Fatalf("groups = %v", tags)
+	}
+
+	pets := c.Groups[1]
+	want := []Entry{
+		{Kind: KindDeprecatedEndpoints, Subject: "GET /pets", Text: "List pets"},
+		{Kind: KindNewFields, Subject: "Pet", Text: "Property '[].age' of response 200 was added"},
+		{Kind: KindNewFields, Subject: "Pet", Text: "Property 'age' of response 200 was added"},
+		{Kind: KindRemovedFields, Breaking: true, Subject: "Pet", Text: "Property '[].nickname' of response 200 was removed"},
+		{Kind: KindRemovedFields, Breaking: true, Subject: "Pet", Text: "Property 'nickname' of response 200 was removed"},
+	}
+	if len(pets.Entries) != len(want) {
+		t.Fatalf("pets entries = %+v", pets.Entries)
+	}
+	for i := range want {
+		if pets.Entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, pets.Entries[i], want[i])
+		}
+	}
+
+	other := c.Groups[2].Entries
+	if len(other) != 1 || other[0].Kind != KindRemovedEndpoints || other[0].Text != "Legacy listing" || !other[0].Breaking {
+		t.Errorf("Other entries = %+v", other)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	out := FormatMarkdown(build(t, WithTitle("v2"), WithDate("2026-01-02")))
+	for _, want := range []string{
+		"## v2 (2026-01-02)\n",
+		"### stores\n\n#### New endpoints\n\n- `POST /stores`: Open a &lt;store&gt;\n",
+		"#### Removed fields\n\n- **Breaking:** `Pet`: Property '[].nickname' of response 200 was removed\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	empty := &Changelog{Title: "v3"}
+	if got := FormatMarkdown(empty); got != "## v3\n\nNo API changes.\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatHTML(t *testing.T) {
+	out, err := FormatHTML(build(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<h3>stores</h3>", "Open a &lt;store&gt;", `<span class="breaking">Breaking:</span>`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q", want)
+		}
+	}
+}
+
+func TestAddToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	if err := AddToFile(path, "## 1.0.0\n\n- first\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToFile(path, "## 2.0.0\n\n- second\n"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n## 2.0.0\n\n- second\n\n## 1.0.0\n\n- first\n"
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,163 @@
+package changelog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// FormatMarkdown formats the changelog as a Markdown section headed by its title.
+func FormatMarkdown(c *Changelog) string {
+	var sb strings.Builder
+	sb.WriteString("## " + heading(c) + "\n")
+	if len(c.Groups) == 0 {
+		sb.WriteString("\nNo API changes.\n")
+		return sb.String()
+	}
+	for _, g := range c.Groups {
+		sb.WriteString(fmt.Sprintf("\n### %s\n", g.Tag))
+		for _, kind := range kinds {
+			entries := g.entries(kind)
+			if len(entries) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("\n#### %s\n\n", kind))
+			for _, e := range entries {
+				sb.WriteString("- ")
+				if e.Breaking {
+					sb.WriteString("**Breaking:** ")
+				}
+				sb.WriteString("`" + e.Subject + "`")
+				if e.Text != "" {
+					sb.WriteString(": " + htmlEscaper.Replace(e.Text))
+				}
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// htmlEscaper keeps Markdown renderers from taking text such as <id> for HTML tags.
+var htmlEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+var htmlTemplate = template.Must(template.New("changelog").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Heading}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; }
+code { background: #f3f4f6; padding: 0.1em 0.3em; border-radius: 4px; }
+.breaking { color: #b42318; font-weight: 600; }
+</style>
+</head>
+<body>
+<h2>{{.Heading}}</h2>
+{{- range .Groups}}
+<h3>{{.Tag}}</h3>
+{{- range .Sections}}
+<h4>{{.Kind}}</h4>
+<ul>
+{{- range .Entries}}
+<li>{{if .Breaking}}<span class="breaking">Breaking:</span> {{end}}<code>{{.Subject}}</code>{{if .Text}}: {{.Text}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- else}}
+<p>No API changes.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlSection struct {
+	Kind    Kind
+	Entries []Entry
+}
+
+type htmlGroup struct {
+	Tag      string
+	Sections []htmlSection
+}
+
+// FormatHTML formats the changelog as a standalone HTML page.
+func FormatHTML(c *Changelog) (string, error) {
+	data := struct {
+		Heading string
+		Groups  []htmlGroup
+	}{Heading: heading(c)}
+	for _, g := range c.Groups {
+		hg := htmlGroup{Tag: g.Tag}
+		for _, kind := range kinds {
+			if entries := g.entries(kind); len(entries) > 0 {
+				hg.Sections = append(hg.Sections, htmlSection{Kind: kind, Entries: entries})
+			}
+		}
+		data.Groups = append(data.Groups, hg)
+	}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// AddToFile adds a Markdown release section to a changelog file, above the previous releases
+// so the newest comes first. A missing file is created with a "# Changelog" title.
+func AddToFile(path, section string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		data = []byte("# Changelog\n")
+	} else if err != nil {
+		return fmt.Errorf("failed to read changelog: %w", err)
+	}
+
+	content := string(data)
+	section = strings.TrimRight(section, "\n") + "\n"
+	if i := firstRelease(content); i >= 0 {
+		content = content[:i] + section + "\n" + content[i:]
+	} else {
+		content = strings.TrimRight(content, "\n") + "\n\n" + section
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// firstRelease returns the offset of the first "## " heading, or -1.
+func firstRelease(content string) int {
+	if strings.HasPrefix(content, "## ") {
+		return 0
+	}
+	if i := strings.Index(content, "\n## "); i >= 0 {
+		return i + 1
+	}
+	return -1
+}
+
+func heading(c *Changelog) string {
+	title := c.Title
+	if title == "" {
+		title = "Unreleased"
+	}
+	if c.Date != "" {
+		title += " (" + c.Date + ")"
+	}
+	return title
+}
+
+func (g Group) entries(kind Kind) []Entry {
+	var out []Entry
+	for _, e := range g.Entries {
+		if e.Kind == kind {
+			out = append(out, e)
+		}
+	}
+	return out
+}
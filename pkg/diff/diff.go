@@ -0,0 +1,157 @@
+// Package diff compares two OpenAPI documents and classifies every change as breaking,
+// non-breaking or informational for the clients of the API.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Level classifies a change by its effect on existing clients
+type Level string
+
+const (
+	LevelBreaking    Level = "breaking"
+	LevelNonBreaking Level = "non-breaking"
+	LevelInfo        Level = "info"
+)
+
+// levelRanks orders levels from least to most disruptive
+var levelRanks = map[Level]int{LevelInfo: 0, LevelNonBreaking: 1, LevelBreaking: 2}
+
+// ParseLevel parses a level name, case-insensitively.
+func ParseLevel(s string) (Level, error) {
+	level := Level(strings.ToLower(s))
+	if _, ok := levelRanks[level]; !ok {
+		return "", fmt.Errorf("unknown change level %q (want breaking, non-breaking or info)", s)
+	}
+	return level, nil
+}
+
+// AtLeast reports whether l is at least as disruptive as other.
+func (l Level) AtLeast(other Level) bool {
+	return levelRanks[l] >= levelRanks[other]
+}
+
+// Change is a single difference between two documents
+type Change struct {
+	ID       string `json:"id"`
+	Level    Level  `json:"level"`
+	Location string `json:"location"`
+	// Pointer locates the change in the revision; removed endpoints, parameters, request bodies
+	// and responses are located in the base
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result holds the changes from a base document to a revision
+type Result struct {
+	Base     string   `json:"base,omitempty"`
+	Revision string   `json:"revision,omitempty"`
+	Changes  []Change `json:"changes"`
+}
+
+// Count returns the number of changes of a level.
+func (r *Result) Count(level Level) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges reports whether the result has changes at least as disruptive as level.
+func (r *Result) HasChanges(level Level) bool {
+	for _, c := range r.Changes {
+		if c.Level.AtLeast(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the changes from base to revision.
+func Compare(base, revision *openapi.Document) *Result {
+	d := &differ{
+		base:     base,
+		revision: revision,
+		result:   &Result{Changes: []Change{}},
+		visiting: make(map[[2]*openapi.Schema]bool),
+	}
+	d.comparePaths()
+	return d.result
+}
+
+// Parse decodes an OpenAPI document in JSON or YAML.
+func Parse(data []byte) (*openapi.Document, error) {
+	var doc openapi.Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	return &doc, nil
+}
+
+// LoadFile reads and parses an OpenAPI document from a file.
+func LoadFile(path string) (*openapi.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return Parse(data)
+}
+
+// LoadURL fetches and parses an OpenAPI document.
+func LoadURL(url string) (*openapi.Document, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return Parse(data)
+}
+
+// differ accumulates the changes between two documents
+type differ struct {
+	base     *openapi.Document
+	revision *openapi.Document
+	result   *Result
+	visiting map[[2]*openapi.Schema]bool // Schema pairs being compared, to stop at cycles
+}
+
+func (d *differ) add(level Level, id, location, pointer, format string, args ...any) {
+	d.result.Changes = append(d.result.Changes, Change{
+		ID:       id,
+		Level:    level,
+		Location: location,
+		Pointer:  pointer,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
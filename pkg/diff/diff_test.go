@@ -0,0 +1,183 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+// compare diffs two YAML documents and returns "level id: location: message" for each change.
+func compare(t *testing.T, base, revision string) []string {
+	t.Helper()
+	b, err := Parse([]byte(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Parse([]byte(revision))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, c := range Compare(b, r).Changes {
+		out = append(out, string(c.Level)+" "+c.ID+": "+c.Location+": "+c.Message)
+	}
+	return out
+}
+
+const header = "openapi: 3.0.3\ninfo: {title: T, version: '1'}\n"
+
+func TestCompareEndpoints(t *testing.T) {
+	base := header + `paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      responses: {"200": {description: ok}}
+    delete:
+      responses: {"204": {description: gone}}
+  /legacy:
+    get:
+      responses: {"200": {description: ok}}
+`
+	revision := header + `paths:
+  /users/{userId}:
+    get:
+      operationId: fetchUser
+      deprecated: true
+      responses: {"200": {description: ok}}
+    put:
+      responses: {"200": {description: ok}}
+`
+	want := []string{
+		"breaking endpoint-removed: GET /legacy: GET /legacy was removed",
+		"info endpoint-deprecated: GET /users/{userId}: GET /users/{userId} was deprecated",
+		"info operation-id-changed: GET /users/{userId}: operationId changed from 'getUser' to 'fetchUser'",
+		"breaking endpoint-removed: DELETE /users/{userId}: DELETE /users/{userId} was removed",
+		"non-breaking endpoint-added: PUT /users/{userId}: PUT /users/{userId} was added",
+	}
+	if got := compare(t, base, revision); !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCompareParameters(t *testing.T) {
+	base := header + `paths:
+  /items/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: q, in: query, schema: {type: string}}
+        - $ref: "#/components/parameters/Trace"
+      responses: {"200": {description: ok}}
+components:
+  parameters:
+    Trace: {name: X-Trace, in: header, required: true, schema: {type: string}}
+`
+	revision := header + `paths:
+  /items/{itemId}:
+    parameters:
+      - {name: itemId, in: path, required: true, schema: {type: string}}
+    get:
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer}}
+        - {name: page, in: query, schema: {type: integer}}
+        - {name: tenant, in: header, required: true, schema: {type: string}}
+        - {name: X-Trace, in: header, schema: {type: string}}
+      responses: {"200": {description: ok}}
+`
+	want := []string{
+		"non-breaking request-parameter-became-optional: GET /items/{itemId}: Header parameter 'X-Trace' became optional",
+		"breaking request-parameter-became-required: GET /items/{itemId}: Query parameter 'limit' became required",
+		"breaking request-parameter-removed: GET /items/{itemId}: Query parameter 'q' was removed",
+		"breaking request-parameter-added: GET /items/{itemId}: Required header parameter 'tenant' was added",
+		"non-breaking request-parameter-added: GET /items/{itemId}: Optional query parameter 'page' was added",
+	}
+	if got := compare(t, base, revision); !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCompareBodiesAndResponses(t *testing.T) {
+	base := header + `security: [{key: []}]
+paths:
+  /orders:
+    post:
+      security: []
+      requestBody:
+        content:
+          application/json: {schema: {type: object}}
+          application/xml: {schema: {type: object}}
+      responses:
+        "201":
+          description: created
+          headers: {Location: {schema: {type: string}}}
+        "409": {description: conflict}
+    get:
+      responses:
+        "200": {description: ok}
+`
+	revision := header + `security: [{key: []}]
+paths:
+  /orders:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json: {schema: {type: object}}
+      responses:
+        "201": {description: created}
+        "422": {description: invalid}
+    get:
+      security: [{}]
+      responses:
+        "202": {description: accepted}
+`
+	want := []string{
+		"non-breaking security-removed: GET /orders: Authentication is no longer required",
+		"breaking response-status-removed: GET /orders: Response 200 was removed",
+		"non-breaking response-status-added: GET /orders: Response 202 was added",
+		"breaking security-added: POST /orders: Authentication is now required (key)",
+		"breaking request-body-became-required: POST /orders: Request body became required",
+		"breaking request-media-type-removed: POST /orders: Media type 'application/xml' was removed from request body",
+		"breaking response-header-removed: POST /orders: Header 'Location' was removed from response 201",
+		"info response-status-removed: POST /orders: Response 409 was removed",
+		"non-breaking response-status-added: POST /orders: Response 422 was added",
+	}
+	if got := compare(t, base, revision); !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	spec := header + `paths:
+  /nodes:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {$ref: "#/components/schemas/Node"}}
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children: {type: array, items: {$ref: "#/components/schemas/Node"}}
+`
+	if got := compare(t, spec, spec); len(got) != 0 {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestResultLevels(t *testing.T) {
+	r := &Result{Changes: []Change{{Level: LevelInfo}, {Level: LevelNonBreaking}}}
+	if r.HasChanges(LevelBreaking) || !r.HasChanges(LevelNonBreaking) || r.Count(LevelInfo) != 1 {
+		t.Error("unexpected level counts")
+	}
+	if level, err := ParseLevel("Breaking"); err != nil || level != LevelBreaking {
+		t.Errorf("ParseLevel = %q, %v", level, err)
+	}
+	if _, err := ParseLevel("major"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
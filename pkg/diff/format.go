@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// levelTitles lists the levels in the order they are reported, with their headings
+var levelTitles = []struct {
+	level Level
+	title string
+}{
+	{LevelBreaking, "Breaking changes"},
+	{LevelNonBreaking, "Non-breaking changes"},
+	{LevelInfo, "Informational changes"},
+}
+
+// FormatText formats the diff result for display, grouped by level.
+func FormatText(result *Result) string {
+	var sb strings.Builder
+	if result.Base != "" || result.Revision != "" {
+		sb.WriteString(fmt.Sprintf("Comparing %s -> %s\n\n", result.Base, result.Revision))
+	}
+	if len(result.Changes) == 0 {
+		sb.WriteString("No changes found.\n")
+		return sb.String()
+	}
+	for _, lt := range levelTitles {
+		changes := result.byLevel(lt.level)
+		if len(changes) == 0 {
+			continue
+		}
+		heading := fmt.Sprintf("%s (%d)", lt.title, len(changes))
+		sb.WriteString(heading + "\n" + strings.Repeat("-", len(heading)) + "\n")
+		for _, c := range changes {
+			sb.WriteString(fmt.Sprintf("  %s: %s [%s]\n", c.Location, c.Message, c.ID))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(summary(result) + "\n")
+	return sb.String()
+}
+
+// FormatJSON formats the diff result as JSON.
+func FormatJSON(result *Result) ([]byte, error) {
+	out := *result
+	if out.Changes == nil {
+		out.Changes = []Change{}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// FormatMarkdown formats the diff result as Markdown, for pull request comments.
+func FormatMarkdown(result *Result) string {
+	var sb strings.Builder
+	sb.WriteString("## API changes\n\n")
+	if len(result.Changes) == 0 {
+		sb.WriteString("No changes found.\n")
+		return sb.String()
+	}
+	if result.Count(LevelBreaking) > 0 {
+		sb.WriteString(":warning: ")
+	}
+	sb.WriteString(summary(result) + "\n")
+	for _, lt := range levelTitles {
+		changes := result.byLevel(lt.level)
+		if len(changes) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n### %s\n\n", lt.title))
+		for _, c := range changes {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s (`%s`)\n", escapeMarkdown(c.Location), escapeMarkdown(c.Message), c.ID))
+		}
+	}
+	return sb.String()
+}
+
+func (r *Result) byLevel(level Level) []Change {
+	var out []Change
+	for _, c := range r.Changes {
+		if c.Level == level {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func summary(result *Result) string {
+	return fmt.Sprintf("%d breaking, %d non-breaking, %d informational change(s)",
+		result.Count(LevelBreaking), result.Count(LevelNonBreaking), result.Count(LevelInfo))
+}
+
+// markdownEscaper escapes the characters of path templates and messages that Markdown would
+// otherwise interpret.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", ">", "&gt;")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
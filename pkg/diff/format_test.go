@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var formatResult = &Result{
+	Base:     "old.yaml",
+	Revision: "new.yaml",
+	Changes: []Change{
+		{ID: "endpoint-removed", Level: LevelBreaking, Location: "GET /users/{id}", Message: "GET /users/{id} was removed"},
+		{ID: "endpoint-added", Level: LevelNonBreaking, Location: "GET /user_groups", Message: "GET /user_groups was added"},
+	},
+}
+
+func TestFormatText(t *testing.T) {
+	out := FormatText(formatResult)
+	for _, want := range []string{
+		"Comparing old.yaml -> new.yaml",
+		"Breaking changes (1)\n--------------------\n  GET /users/{id}: GET /users/{id} was removed [endpoint-removed]",
+		"1 breaking, 1 non-breaking, 0 informational change(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Informational") {
+		t.Error("empty levels should be left out")
+	}
+	if got := FormatText(&Result{}); got != "No changes found.\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	out := FormatMarkdown(formatResult)
+	for _, want := range []string{
+		":warning: 1 breaking",
+		"### Breaking changes\n\n- **GET /users/{id}**: GET /users/{id} was removed (`endpoint-removed`)",
+		`- **GET /user\_groups**`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(&Result{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if changes, ok := decoded["changes"].([]any); !ok || len(changes) != 0 {
+		t.Errorf("changes = %v, want an empty list", decoded["changes"])
+	}
+}
@@ -0,0 +1,408 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// pathParamPattern matches the parameters of a path template
+var pathParamPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizePath replaces path parameter names, so that renaming a path parameter is not
+// reported as a removed and an added endpoint.
+func normalizePath(path string) string {
+	return pathParamPattern.ReplaceAllString(path, "{}")
+}
+
+// operations returns the operations of a path item by lowercase method, in the order of the specification.
+func operations(item *openapi.PathItem) []methodOperation {
+	if item == nil {
+		return nil
+	}
+	var ops []methodOperation
+	for _, m := range []methodOperation{
+		{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete},
+		{"options", item.Options}, {"head", item.Head}, {"patch", item.Patch}, {"trace", item.Trace},
+	} {
+		if m.op != nil {
+			ops = append(ops, m)
+		}
+	}
+	return ops
+}
+
+type methodOperation struct {
+	method string
+	op     *openapi.Operation
+}
+
+func (d *differ) comparePaths() {
+	basePaths := make(map[string]string, len(d.base.Paths))
+	for path := range d.base.Paths {
+		basePaths[normalizePath(path)] = path
+	}
+	revisionPaths := make(map[string]string, len(d.revision.Paths))
+	for path := range d.revision.Paths {
+		revisionPaths[normalizePath(path)] = path
+	}
+
+	for _, key := range sortedKeys(basePaths) {
+		basePath := basePaths[key]
+		revisionPath, ok := revisionPaths[key]
+		if !ok {
+			for _, m := range operations(d.base.Paths[basePath]) {
+				location := operationLocation(m.method, basePath)
+				d.add(LevelBreaking, "endpoint-removed", location, yamlnode.Join("/paths", basePath, m.method), "%s was removed", location)
+			}
+			continue
+		}
+		d.comparePathItem(basePath, revisionPath)
+	}
+	for _, key := range sortedKeys(revisionPaths) {
+		if _, ok := basePaths[key]; ok {
+			continue
+		}
+		path := revisionPaths[key]
+		for _, m := range operations(d.revision.Paths[path]) {
+			location := operationLocation(m.method, path)
+			d.add(LevelNonBreaking, "endpoint-added", location, yamlnode.Join("/paths", path, m.method), "%s was added", location)
+		}
+	}
+}
+
+func operationLocation(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func (d *differ) comparePathItem(basePath, revisionPath string) {
+	baseItem, revisionItem := d.base.Paths[basePath], d.revision.Paths[revisionPath]
+	revisionOps := make(map[string]*openapi.Operation)
+	for _, m := range operations(revisionItem) {
+		revisionOps[m.method] = m.op
+	}
+	baseOps := make(map[string]bool)
+
+	for _, m := range operations(baseItem) {
+		baseOps[m.method] = true
+		location := operationLocation(m.method, revisionPath)
+		revisionOp, ok := revisionOps[m.method]
+		if !ok {
+			d.add(LevelBreaking, "endpoint-removed", location, yamlnode.Join("/paths", basePath, m.method), "%s was removed", location)
+			continue
+		}
+		d.compareOperation(operationPair{
+			location: location,
+			pointer:  yamlnode.Join("/paths", revisionPath, m.method),
+			base:     m.op, revision: revisionOp,
+			baseItem: baseItem, revisionItem: revisionItem,
+			basePath: basePath, revisionPath: revisionPath,
+			method: m.method,
+		})
+	}
+	for _, m := range operations(revisionItem) {
+		if !baseOps[m.method] {
+			location := operationLocation(m.method, revisionPath)
+			d.add(LevelNonBreaking, "endpoint-added", location, yamlnode.Join("/paths", revisionPath, m.method), "%s was added", location)
+		}
+	}
+}
+
+// operationPair is an operation in the base and the revision
+type operationPair struct {
+	location, pointer      string
+	base, revision         *openapi.Operation
+	baseItem, revisionItem *openapi.PathItem
+	basePath, revisionPath string
+	method                 string
+}
+
+func (d *differ) compareOperation(p operationPair) {
+	if !p.base.Deprecated && p.revision.Deprecated {
+		d.add(LevelInfo, "endpoint-deprecated", p.location, yamlnode.Join(p.pointer, "deprecated"), "%s was deprecated", p.location)
+	}
+	if p.base.OperationID != p.revision.OperationID && p.base.OperationID != "" {
+		d.add(LevelInfo, "operation-id-changed", p.location, yamlnode.Join(p.pointer, "operationId"),
+			"operationId changed from '%s' to '%s'", p.base.OperationID, p.revision.OperationID)
+	}
+	d.compareSecurity(p)
+	d.compareParameters(p)
+	d.compareRequestBody(p)
+	d.compareResponses(p)
+}
+
+// compareSecurity reports operations that start or stop requiring credentials, or that accept
+// different security schemes.
+func (d *differ) compareSecurity(p operationPair) {
+	base := securityNames(effectiveSecurity(d.base, p.base))
+	revision := securityNames(effectiveSecurity(d.revision, p.revision))
+	pointer := yamlnode.Join(p.pointer, "security")
+	switch {
+	case base == revision:
+	case base == "":
+		d.add(LevelBreaking, "security-added", p.location, pointer, "Authentication is now required (%s)", revision)
+	case revision == "":
+		d.add(LevelNonBreaking, "security-removed", p.location, pointer, "Authentication is no longer required")
+	default:
+		d.add(LevelBreaking, "security-changed", p.location, pointer, "Security changed from %s to %s", base, revision)
+	}
+}
+
+func effectiveSecurity(doc *openapi.Document, op *openapi.Operation) []openapi.SecurityRequirement {
+	if op.Security != nil {
+		return op.Security
+	}
+	return doc.Security
+}
+
+// securityNames describes the alternatives of a security requirement list, or returns "" when
+// no credentials are required.
+func securityNames(reqs []openapi.SecurityRequirement) string {
+	alternatives := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		if len(req) == 0 {
+			return ""
+		}
+		alternatives = append(alternatives, strings.Join(sortedKeys(req), " + "))
+	}
+	sort.Strings(alternatives)
+	return strings.Join(alternatives, " or ")
+}
+
+// parameterEntry is a resolved parameter and its pointer
+type parameterEntry struct {
+	param   *openapi.Parameter
+	pointer string
+}
+
+// operationParameters returns the resolved parameters of an operation, including those of its
+// path item that it does not override, keyed by location and name.
+func operationParameters(doc *openapi.Document, item *openapi.PathItem, op *openapi.Operation, path, method string) map[string]parameterEntry {
+	params := make(map[string]parameterEntry)
+	collect := func(list []*openapi.Parameter, pointer string) {
+		for i, p := range list {
+			if p == nil {
+				continue
+			}
+			at := yamlnode.Index(pointer, i)
+			if name, ok := componentName(p.Ref, "parameters"); ok && doc.Components != nil {
+				p, at = doc.Components.Parameters[name], yamlnode.Join("/components/parameters", name)
+			}
+			if p != nil {
+				params[parameterKey(p)] = parameterEntry{param: p, pointer: at}
+			}
+		}
+	}
+	collect(item.Parameters, yamlnode.Join("/paths", path, "parameters"))
+	collect(op.Parameters, yamlnode.Join("/paths", path, method, "parameters"))
+	return params
+}
+
+func parameterSubject(p *openapi.Parameter) string {
+	return fmt.Sprintf("%s parameter '%s'", p.In, p.Name)
+}
+
+// parameterKey identifies a parameter by its location and name.
+func parameterKey(p *openapi.Parameter) string {
+	return string(p.In) + ":" + p.Name
+}
+
+func (d *differ) compareParameters(p operationPair) {
+	base := operationParameters(d.base, p.baseItem, p.base, p.basePath, p.method)
+	revision := operationParameters(d.revision, p.revisionItem, p.revision, p.revisionPath, p.method)
+	renamePathParameters(base, p.basePath, revision, p.revisionPath)
+
+	for _, key := range sortedKeys(base) {
+		b := base[key]
+		r, ok := revision[key]
+		if !ok {
+			d.add(LevelBreaking, "request-parameter-removed", p.location, b.pointer,
+				"%s was removed", capitalize(parameterSubject(b.param)))
+			continue
+		}
+		d.compareParameter(p.location, b.param, r)
+	}
+	for _, key := range sortedKeys(revision) {
+		if _, ok := base[key]; ok {
+			continue
+		}
+		r := revision[key]
+		if r.param.Required {
+			d.add(LevelBreaking, "request-parameter-added", p.location, r.pointer,
+				"Required %s was added", parameterSubject(r.param))
+		} else {
+			d.add(LevelNonBreaking, "request-parameter-added", p.location, r.pointer,
+				"Optional %s was added", parameterSubject(r.param))
+		}
+	}
+}
+
+// renamePathParameters keys the path parameters of the base by the names the revision gives
+// them at the same position of the path template.
+func renamePathParameters(base map[string]parameterEntry, basePath string, revision map[string]parameterEntry, revisionPath string) {
+	baseNames := pathParamPattern.FindAllString(basePath, -1)
+	revisionNames := pathParamPattern.FindAllString(revisionPath, -1)
+	for i := range baseNames {
+		if i >= len(revisionNames) || baseNames[i] == revisionNames[i] {
+			continue
+		}
+		oldKey := "path:" + strings.Trim(baseNames[i], "{}")
+		newKey := "path:" + strings.Trim(revisionNames[i], "{}")
+		if entry, ok := base[oldKey]; ok {
+			if _, taken := revision[oldKey]; !taken {
+				delete(base, oldKey)
+				base[newKey] = entry
+			}
+		}
+	}
+}
+
+func (d *differ) compareParameter(location string, base *openapi.Parameter, r parameterEntry) {
+	revision := r.param
+	subject := parameterSubject(revision)
+	switch {
+	case !base.Required && revision.Required:
+		d.add(LevelBreaking, "request-parameter-became-required", location, yamlnode.Join(r.pointer, "required"), "%s became required", capitalize(subject))
+	case base.Required && !revision.Required:
+		d.add(LevelNonBreaking, "request-parameter-became-optional", location, yamlnode.Join(r.pointer, "required"), "%s became optional", capitalize(subject))
+	}
+	if !base.Deprecated && revision.Deprecated {
+		d.add(LevelInfo, "request-parameter-deprecated", location, yamlnode.Join(r.pointer, "deprecated"), "%s was deprecated", capitalize(subject))
+	}
+	d.compareSchema(schemaPair{
+		location:  location,
+		pointer:   yamlnode.Join(r.pointer, "schema"),
+		subject:   subject,
+		direction: request,
+		base:      base.Schema, revision: revision.Schema,
+	})
+}
+
+func (d *differ) compareRequestBody(p operationPair) {
+	base, _ := resolveRequestBody(d.base, p.base.RequestBody, "")
+	revision, pointer := resolveRequestBody(d.revision, p.revision.RequestBody, yamlnode.Join(p.pointer, "requestBody"))
+	switch {
+	case base == nil && revision == nil:
+		return
+	case base == nil:
+		if revision.Required {
+			d.add(LevelBreaking, "request-body-added", p.location, pointer, "Required request body was added")
+		} else {
+			d.add(LevelNonBreaking, "request-body-added", p.location, pointer, "Optional request body was added")
+		}
+		return
+	case revision == nil:
+		d.add(LevelBreaking, "request-body-removed", p.location, yamlnode.Join("/paths", p.basePath, p.method, "requestBody"), "Request body was removed")
+		return
+	}
+
+	switch {
+	case !base.Required && revision.Required:
+		d.add(LevelBreaking, "request-body-became-required", p.location, yamlnode.Join(pointer, "required"), "Request body became required")
+	case base.Required && !revision.Required:
+		d.add(LevelNonBreaking, "request-body-became-optional", p.location, yamlnode.Join(pointer, "required"), "Request body became optional")
+	}
+	d.compareContent(p.location, pointer, "request body", request, base.Content, revision.Content)
+}
+
+func (d *differ) compareResponses(p operationPair) {
+	for _, code := range sortedKeys(p.base.Responses) {
+		base, _ := resolveResponse(d.base, p.base.Responses[code], "")
+		if base == nil {
+			continue
+		}
+		revision, pointer := resolveResponse(d.revision, p.revision.Responses[code], yamlnode.Join(p.pointer, "responses", code))
+		if revision == nil {
+			level := LevelInfo
+			if strings.HasPrefix(code, "2") {
+				level = LevelBreaking
+			}
+			d.add(level, "response-status-removed", p.location, yamlnode.Join("/paths", p.basePath, p.method, "responses", code), "Response %s was removed", code)
+			continue
+		}
+		subject := "response " + code
+		for _, name := range sortedKeys(base.Headers) {
+			if !hasHeader(revision.Headers, name) {
+				d.add(LevelBreaking, "response-header-removed", p.location, yamlnode.Join(pointer, "headers"), "Header '%s' was removed from %s", name, subject)
+			}
+		}
+		d.compareContent(p.location, pointer, subject, response, base.Content, revision.Content)
+	}
+	for _, code := range sortedKeys(p.revision.Responses) {
+		if _, ok := p.base.Responses[code]; !ok {
+			d.add(LevelNonBreaking, "response-status-added", p.location, yamlnode.Join(p.pointer, "responses", code), "Response %s was added", code)
+		}
+	}
+}
+
+// hasHeader looks a header up case-insensitively, as HTTP does.
+func hasHeader(headers map[string]*openapi.Header, name string) bool {
+	for h := range headers {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareContent compares the media types of a request body or response.
+func (d *differ) compareContent(location, pointer, subject string, dir direction, base, revision map[string]openapi.MediaType) {
+	for _, mediaType := range sortedKeys(base) {
+		r, ok := revision[mediaType]
+		if !ok {
+			d.add(LevelBreaking, dir.prefix()+"media-type-removed", location, yamlnode.Join(pointer, "content"),
+				"Media type '%s' was removed from %s", mediaType, subject)
+			continue
+		}
+		d.compareSchema(schemaPair{
+			location:  location,
+			pointer:   yamlnode.Join(pointer, "content", mediaType, "schema"),
+			subject:   subject,
+			direction: dir,
+			base:      base[mediaType].Schema, revision: r.Schema,
+		})
+	}
+	for _, mediaType := range sortedKeys(revision) {
+		if _, ok := base[mediaType]; !ok {
+			d.add(LevelNonBreaking, dir.prefix()+"media-type-added", location, yamlnode.Join(pointer, "content", mediaType),
+				"Media type '%s' was added to %s", mediaType, subject)
+		}
+	}
+}
+
+// componentName returns the name a local reference to a component of the given kind points at.
+func componentName(ref, kind string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+	return yamlnode.Unescape(name), ok && name != ""
+}
+
+func resolveRequestBody(doc *openapi.Document, rb *openapi.RequestBody, pointer string) (*openapi.RequestBody, string) {
+	if rb == nil {
+		return nil, pointer
+	}
+	if name, ok := componentName(rb.Ref, "requestBodies"); ok && doc.Components != nil {
+		return doc.Components.RequestBodies[name], yamlnode.Join("/components/requestBodies", name)
+	}
+	return rb, pointer
+}
+
+func resolveResponse(doc *openapi.Document, r *openapi.Response, pointer string) (*openapi.Response, string) {
+	if r == nil {
+		return nil, pointer
+	}
+	if name, ok := componentName(r.Ref, "responses"); ok && doc.Components != nil {
+		return doc.Components.Responses[name], yamlnode.Join("/components/responses", name)
+	}
+	return r, pointer
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
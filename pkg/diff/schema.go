@@ -0,0 +1,337 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// direction tells whether clients send or receive the data a schema describes. Narrowing what
+// the API accepts breaks clients, as does widening what it returns.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+func (dir direction) prefix() string {
+	if dir == request {
+		return "request-"
+	}
+	return "response-"
+}
+
+// schemaPair is a schema in the base and the revision
+type schemaPair struct {
+	location  string
+	pointer   string // Of the revision schema
+	subject   string // What the schema describes, such as "request body"
+	path      string // Property path from the subject, such as "items[].name"
+	direction direction
+	base      *openapi.Schema
+	revision  *openapi.Schema
+}
+
+// describe names the schema in a message.
+func (p schemaPair) describe() string {
+	if p.path == "" {
+		return p.subject
+	}
+	return fmt.Sprintf("property '%s' of %s", p.path, p.subject)
+}
+
+// child returns the pair for a subschema.
+func (p schemaPair) child(path, pointer string, base, revision *openapi.Schema) schemaPair {
+	p.path, p.pointer, p.base, p.revision = path, pointer, base, revision
+	return p
+}
+
+func (d *differ) schemaChange(p schemaPair, level Level, id, pointer, format string, args ...any) {
+	d.add(level, p.direction.prefix()+id, p.location, pointer, format, args...)
+}
+
+// levelFor classifies a change that narrows or widens what a schema allows: narrowing a
+// request or widening a response is breaking.
+func levelFor(dir direction, narrows bool) Level {
+	if (dir == request) == narrows {
+		return LevelBreaking
+	}
+	return LevelNonBreaking
+}
+
+func (d *differ) compareSchema(p schemaPair) {
+	p.base = resolveSchema(d.base, p.base, nil)
+	var at string
+	p.revision = resolveSchema(d.revision, p.revision, &at)
+	if at != "" {
+		p.pointer = at
+	}
+	if p.base == nil || p.revision == nil {
+		return
+	}
+
+	// Recursive schemas are compared once per chain of references
+	key := [2]*openapi.Schema{p.base, p.revision}
+	if d.visiting[key] {
+		return
+	}
+	d.visiting[key] = true
+	defer delete(d.visiting, key)
+
+	d.compareTypes(p)
+	d.compareFormat(p)
+	d.compareEnum(p)
+	if p.direction == request {
+		d.compareBounds(p)
+	}
+	d.compareProperties(p)
+
+	itemsPath := p.path + "[]"
+	d.compareSchema(p.child(itemsPath, yamlnode.Join(p.pointer, "items"), p.base.Items, p.revision.Items))
+	for _, c := range []struct {
+		keyword        string
+		base, revision []*openapi.Schema
+	}{{"allOf", p.base.AllOf, p.revision.AllOf}, {"anyOf", p.base.AnyOf, p.revision.AnyOf}, {"oneOf", p.base.OneOf, p.revision.OneOf}} {
+		for i := 0; i < len(c.base) && i < len(c.revision); i++ {
+			d.compareSchema(p.child(p.path, yamlnode.Index(yamlnode.Join(p.pointer, c.keyword), i), c.base[i], c.revision[i]))
+		}
+	}
+}
+
+// resolveSchema follows references to component schemas, storing the pointer of the component in at.
+func resolveSchema(doc *openapi.Document, s *openapi.Schema, at *string) *openapi.Schema {
+	for range 32 {
+		if s == nil || s.Ref == "" {
+			return s
+		}
+		name, ok := componentName(s.Ref, "schemas")
+		if !ok || doc.Components == nil {
+			return nil
+		}
+		s = doc.Components.Schemas[name]
+		if at != nil {
+			*at = yamlnode.Join("/components/schemas", name)
+		}
+	}
+	return nil
+}
+
+// schemaTypes returns the types a schema allows, with "null" for nullable schemas. An empty
+// result allows any type.
+func schemaTypes(s *openapi.Schema) []string {
+	types := slices.Clone([]string(s.Type))
+	if s.Nullable && len(types) > 0 && !slices.Contains(types, openapi.TypeNull) {
+		types = append(types, openapi.TypeNull)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// typesMissing returns the types of from that to does not allow; integers are numbers.
+func typesMissing(from, to []string) []string {
+	if len(to) == 0 {
+		return nil
+	}
+	var missing []string
+	for _, t := range from {
+		if slices.Contains(to, t) || (t == openapi.TypeInteger && slices.Contains(to, openapi.TypeNumber)) {
+			continue
+		}
+		missing = append(missing, t)
+	}
+	return missing
+}
+
+func (d *differ) compareTypes(p schemaPair) {
+	base, revision := schemaTypes(p.base), schemaTypes(p.revision)
+	if slices.Equal(base, revision) {
+		return
+	}
+	narrowed := len(typesMissing(base, revision)) > 0 || (len(base) == 0 && len(revision) > 0)
+	widened := len(typesMissing(revision, base)) > 0 || (len(revision) == 0 && len(base) > 0)
+	if !narrowed && !widened {
+		return
+	}
+	level := levelFor(p.direction, narrowed)
+	if p.direction == response {
+		level = levelFor(p.direction, !widened)
+	}
+	d.schemaChange(p, level, "type-changed", yamlnode.Join(p.pointer, "type"),
+		"Type of %s changed from %s to %s", p.describe(), typeList(base), typeList(revision))
+}
+
+func typeList(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, "|")
+}
+
+func (d *differ) compareFormat(p schemaPair) {
+	base, revision := p.base.Format, p.revision.Format
+	if base == revision {
+		return
+	}
+	// A new format constrains what clients may send; a dropped one what they can rely on
+	narrows := revision != ""
+	if p.direction == response {
+		narrows = base == ""
+	}
+	d.schemaChange(p, levelFor(p.direction, narrows), "format-changed", yamlnode.Join(p.pointer, "format"),
+		"Format of %s changed from '%s' to '%s'", p.describe(), base, revision)
+}
+
+func (d *differ) compareEnum(p schemaPair) {
+	base, revision := enumValues(p.base.Enum), enumValues(p.revision.Enum)
+	pointer := yamlnode.Join(p.pointer, "enum")
+	switch {
+	case len(base) == 0 && len(revision) == 0:
+		return
+	case len(base) == 0:
+		d.schemaChange(p, levelFor(p.direction, true), "enum-added", pointer,
+			"%s was restricted to %s", capitalize(p.describe()), strings.Join(revision, ", "))
+		return
+	case len(revision) == 0:
+		d.schemaChange(p, levelFor(p.direction, false), "enum-removed", pointer,
+			"%s is no longer restricted to an enum", capitalize(p.describe()))
+		return
+	}
+	if removed := missingValues(base, revision); len(removed) > 0 {
+		d.schemaChange(p, levelFor(p.direction, true), "enum-value-removed", pointer,
+			"Enum value(s) %s removed from %s", strings.Join(removed, ", "), p.describe())
+	}
+	if added := missingValues(revision, base); len(added) > 0 {
+		level := LevelNonBreaking
+		if p.direction == response {
+			// Clients that switch over the values may not handle new ones
+			level = LevelInfo
+		}
+		d.schemaChange(p, level, "enum-value-added", pointer,
+			"Enum value(s) %s added to %s", strings.Join(added, ", "), p.describe())
+	}
+}
+
+func enumValues(values []any) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, strconv.Quote(s))
+		} else {
+			out = append(out, fmt.Sprint(v))
+		}
+	}
+	return out
+}
+
+func missingValues(from, to []string) []string {
+	var missing []string
+	for _, v := range from {
+		if !slices.Contains(to, v) {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// compareBounds reports length, size and range limits of request schemas that became tighter or looser.
+func (d *differ) compareBounds(p schemaPair) {
+	toFloat := func(v *int64) *float64 {
+		if v == nil {
+			return nil
+		}
+		f := float64(*v)
+		return &f
+	}
+	for _, b := range []struct {
+		keyword        string
+		base, revision *float64
+		upper          bool
+	}{
+		{"maxLength", toFloat(p.base.MaxLength), toFloat(p.revision.MaxLength), true},
+		{"minLength", toFloat(p.base.MinLength), toFloat(p.revision.MinLength), false},
+		{"maximum", p.base.Maximum, p.revision.Maximum, true},
+		{"minimum", p.base.Minimum, p.revision.Minimum, false},
+		{"maxItems", toFloat(p.base.MaxItems), toFloat(p.revision.MaxItems), true},
+		{"minItems", toFloat(p.base.MinItems), toFloat(p.revision.MinItems), false},
+	} {
+		d.compareBound(p, b.keyword, b.base, b.revision, b.upper)
+	}
+}
+
+func (d *differ) compareBound(p schemaPair, keyword string, base, revision *float64, upper bool) {
+	pointer := yamlnode.Join(p.pointer, keyword)
+	switch {
+	case base == nil && revision == nil:
+	case base == nil:
+		d.schemaChange(p, LevelBreaking, "constraint-narrowed", pointer,
+			"%s of %s was set to %s", keyword, p.describe(), formatNumber(*revision))
+	case revision == nil:
+		d.schemaChange(p, LevelNonBreaking, "constraint-relaxed", pointer,
+			"%s of %s was removed", keyword, p.describe())
+	case *base != *revision:
+		narrowed := *revision < *base
+		if !upper {
+			narrowed = !narrowed
+		}
+		level, id := LevelNonBreaking, "constraint-relaxed"
+		if narrowed {
+			level, id = LevelBreaking, "constraint-narrowed"
+		}
+		d.schemaChange(p, level, id, pointer, "%s of %s changed from %s to %s",
+			keyword, p.describe(), formatNumber(*base), formatNumber(*revision))
+	}
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (d *differ) compareProperties(p schemaPair) {
+	propertyPath := func(name string) string {
+		if p.path == "" {
+			return name
+		}
+		return p.path + "." + name
+	}
+	propertiesPointer := yamlnode.Join(p.pointer, "properties")
+
+	for _, name := range sortedKeys(p.base.Properties) {
+		child := p.child(propertyPath(name), yamlnode.Join(propertiesPointer, name), p.base.Properties[name], nil)
+		revision, ok := p.revision.Properties[name]
+		if !ok {
+			level := LevelBreaking
+			if p.direction == request {
+				level = LevelInfo
+			}
+			d.schemaChange(p, level, "property-removed", propertiesPointer, "%s was removed", capitalize(child.describe()))
+			continue
+		}
+		wasRequired, isRequired := slices.Contains(p.base.Required, name), slices.Contains(p.revision.Required, name)
+		switch {
+		case !wasRequired && isRequired:
+			d.schemaChange(p, levelFor(p.direction, true), "property-became-required", yamlnode.Join(p.pointer, "required"),
+				"%s became required", capitalize(child.describe()))
+		case wasRequired && !isRequired:
+			d.schemaChange(p, levelFor(p.direction, false), "property-became-optional", yamlnode.Join(p.pointer, "required"),
+				"%s became optional", capitalize(child.describe()))
+		}
+		child.revision = revision
+		d.compareSchema(child)
+	}
+	for _, name := range sortedKeys(p.revision.Properties) {
+		if _, ok := p.base.Properties[name]; ok {
+			continue
+		}
+		child := p.child(propertyPath(name), yamlnode.Join(propertiesPointer, name), nil, p.revision.Properties[name])
+		if p.direction == request && slices.Contains(p.revision.Required, name) {
+			d.schemaChange(p, LevelBreaking, "property-added", child.pointer, "Required %s was added", child.describe())
+		} else {
+			d.schemaChange(p, LevelNonBreaking, "property-added", child.pointer, "%s was added", capitalize(child.describe()))
+		}
+	}
+}
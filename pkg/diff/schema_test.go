@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// schemaSpec wraps a schema as both the request body and the 200 response of an operation.
+func schemaSpec(schema string) string {
+	indented := strings.ReplaceAll(strings.TrimSpace(schema), "\n", "\n      ")
+	return header + `paths:
+  /things:
+    put:
+      requestBody:
+        content:
+          application/json: {schema: {$ref: "#/components/schemas/Thing"}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json: {schema: {$ref: "#/components/schemas/Thing"}}
+components:
+  schemas:
+    Thing:
+      ` + indented + "\n"
+}
+
+func TestCompareSchemas(t *testing.T) {
+	tests := []struct {
+		name           string
+		base, revision string
+		want           []string
+	}{
+		{
+			"property removed",
+			"type: object\nproperties: {a: {type: string}, b: {type: string}}",
+			"type: object\nproperties: {a: {type: string}}",
+			[]string{
+				"info request-property-removed: PUT /things: Property 'b' of request body was removed",
+				"breaking response-property-removed: PUT /things: Property 'b' of response 200 was removed",
+			},
+		},
+		{
+			"property became required",
+			"type: object\nproperties: {a: {type: string}}",
+			"type: object\nrequired: [a]\nproperties: {a: {type: string}}",
+			[]string{
+				"breaking request-property-became-required: PUT /things: Property 'a' of request body became required",
+				"non-breaking response-property-became-required: PUT /things: Property 'a' of response 200 became required",
+			},
+		},
+		{
+			"required property added",
+			"type: object\nproperties: {a: {type: string}}",
+			"type: object\nrequired: [b]\nproperties: {a: {type: string}, b: {type: string}}",
+			[]string{
+				"breaking request-property-added: PUT /things: Required property 'b' of request body was added",
+				"non-breaking response-property-added: PUT /things: Property 'b' of response 200 was added",
+			},
+		},
+		{
+			"enum narrowed",
+			"type: string\nenum: [a, b]",
+			"type: string\nenum: [a, c]",
+			[]string{
+				`breaking request-enum-value-removed: PUT /things: Enum value(s) "b" removed from request body`,
+				`non-breaking request-enum-value-added: PUT /things: Enum value(s) "c" added to request body`,
+				`non-breaking response-enum-value-removed: PUT /things: Enum value(s) "b" removed from response 200`,
+				`info response-enum-value-added: PUT /things: Enum value(s) "c" added to response 200`,
+			},
+		},
+		{
+			"type changed",
+			"type: integer",
+			"type: string",
+			[]string{
+				"breaking request-type-changed: PUT /things: Type of request body changed from integer to string",
+				"breaking response-type-changed: PUT /things: Type of response 200 changed from integer to string",
+			},
+		},
+		{
+			"type widened",
+			"type: integer",
+			"type: number\nnullable: true",
+			[]string{
+				"non-breaking request-type-changed: PUT /things: Type of request body changed from integer to null|number",
+				"breaking response-type-changed: PUT /things: Type of response 200 changed from integer to null|number",
+			},
+		},
+		{
+			"nested array items",
+			"type: array\nitems: {type: object, properties: {tags: {type: array, items: {type: string, maxLength: 10}}}}",
+			"type: array\nitems: {type: object, properties: {tags: {type: array, maxItems: 5, items: {type: string, maxLength: 20, format: uuid}}}}",
+			[]string{
+				"breaking request-constraint-narrowed: PUT /things: maxItems of property '[].tags' of request body was set to 5",
+				"breaking request-format-changed: PUT /things: Format of property '[].tags[]' of request body changed from '' to 'uuid'",
+				"non-breaking request-constraint-relaxed: PUT /things: maxLength of property '[].tags[]' of request body changed from 10 to 20",
+				"non-breaking response-format-changed: PUT /things: Format of property '[].tags[]' of response 200 changed from '' to 'uuid'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compare(t, schemaSpec(tt.base), schemaSpec(tt.revision)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparePointers(t *testing.T) {
+	base, err := Parse([]byte(schemaSpec("type: object\nproperties: {a: {type: string}}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revision, err := Parse([]byte(schemaSpec("type: object\nproperties: {a: {type: integer}}")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := Compare(base, revision).Changes
+	if len(changes) != 2 || changes[0].Pointer != "/components/schemas/Thing/properties/a/type" {
+		t.Errorf("got %+v", changes)
+	}
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// curlSecret marks where the name of an environment variable goes in an argument, so the
+// argument can be quoted first and the variable put in after.
+const curlSecret = "\x00"
+
+// Curl writes the operations of a document as a shell script with a curl command per operation.
+// Credentials are read from environment variables named after the security schemes, such as
+// $API_KEY for a scheme named api_key.
+func Curl(doc *openapi.Document) ([]byte, error) {
+	reqs := requests(doc)
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	writeTitle(&b, doc, "# ")
+	if names := secretSchemes(doc, reqs); len(names) > 0 {
+		vars := make([]string, len(names))
+		for i, name := range names {
+			vars[i] = envName(name)
+		}
+		b.WriteString("#\n# Set " + strings.Join(vars, ", ") + " to the credentials before running the commands.\n")
+	}
+
+	for _, r := range reqs {
+		s := r.sample(doc, r.baseURL(doc), func(name string) string { return curlSecret + envName(name) + curlSecret }, false)
+
+		b.WriteString("\n")
+		writeComment(&b, r.name, "# ")
+		command := "curl "
+		switch {
+		case s.method == "HEAD":
+			command += "--head "
+		case s.method != "GET" || s.body != nil:
+			command += "-X " + s.method + " "
+		}
+		args := []string{command + shellQuote(s.url)}
+		for _, h := range s.headers {
+			if h.Name == "Content-Type" && isMultipart(h.Value) {
+				// curl sets the content type with the boundary of the parts itself
+				continue
+			}
+			args = append(args, "-H "+shellQuote(h.Name+": "+h.Value))
+		}
+		if s.body != nil {
+			if isMultipart(s.body.mediaType) {
+				for _, f := range formFields(s.body.example) {
+					args = append(args, "-F "+shellQuote(f.Name+"="+f.Value))
+				}
+			} else {
+				args = append(args, "--data-raw "+shellQuote(s.body.text("  ")))
+			}
+		}
+		b.WriteString(strings.Join(args, " \\\n  ") + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// shellQuote quotes an argument for the shell: in single quotes, or in double quotes when it holds
+// environment variables, which are marked by curlSecret.
+func shellQuote(arg string) string {
+	if !strings.Contains(arg, curlSecret) {
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	parts := strings.Split(arg, curlSecret)
+	var b strings.Builder
+	b.WriteString(`"`)
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString("${" + part + "}")
+			continue
+		}
+		for _, r := range part {
+			if strings.ContainsRune("\"\\$`", r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
+
+// envName returns the name of the environment variable holding the credentials of a scheme: its
+// name in upper snake case, with characters other than letters, digits and underscores replaced.
+func envName(scheme string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range scheme {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if prev >= 'a' && prev <= 'z' || prev >= '0' && prev <= '9' {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case r >= '0' && r <= '9' || r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+		prev = r
+	}
+	name := b.String()
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,78 @@
+package export
+
+import "testing"
+
+func TestCurl(t *testing.T) {
+	want := `#!/bin/sh
+# Pets 1.0.0
+#
+# Set API_KEY, BASIC, BEARER to the credentials before running the commands.
+
+# Get a pet
+curl 'https://api.example.com/v1/pets/1?fields=name&fields=tag' \
+  -H 'X-Trace: 550e8400-e29b-41d4-a716-446655440000' \
+  -H "Authorization: Bearer ${BEARER}" \
+  -H 'Accept: application/json' \
+  -H 'Cookie: session=abc'
+
+# listPets
+curl 'http://localhost/v2/pets?limit=20' \
+  -H "Authorization: Bearer ${BEARER}" \
+  -H 'Accept: application/json'
+
+# createPet
+curl -X POST 'https://api.example.com/v1/pets' \
+  -H "X-API-Key: ${API_KEY}" \
+  -H 'Content-Type: application/json' \
+  --data-raw '{
+  "id": 7,
+  "name": "Rex",
+  "parent": null
+}'
+
+# login
+curl -X POST 'https://api.example.com/v1/login' \
+  -H 'Content-Type: application/x-www-form-urlencoded' \
+  --data-raw 'password=string&user=alice'
+
+# listUsers
+curl 'https://api.example.com/v1/users' \
+  -H "Authorization: Basic ${BASIC}"
+`
+	if got := string(exportSpec(t, FormatCurl)); got != want {
+		t.Errorf("Curl() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"plain", `'plain'`},
+		{"it's", `'it'\''s'`},
+		{"$HOME", `'$HOME'`},
+		{"Bearer " + curlSecret + "TOKEN" + curlSecret, `"Bearer ${TOKEN}"`},
+		{`a="$b"` + curlSecret + "KEY" + curlSecret, `"a=\"\$b\"${KEY}"`},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.arg); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"api_key":     "API_KEY",
+		"apiKey":      "API_KEY",
+		"OAuth2":      "OAUTH2",
+		"petstore-v2": "PETSTORE_V2",
+		"2fa":         "_2FA",
+	}
+	for scheme, want := range tests {
+		if got := envName(scheme); got != want {
+			t.Errorf("envName(%q) = %q, want %q", scheme, got, want)
+		}
+	}
+}
@@ -0,0 +1,667 @@
+// Package export writes example requests for the operations of an OpenAPI document in the formats
+// of API clients, so they can be sent without typing them in: Postman collections, HAR archives,
+// .http files and curl commands.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// Format is a format requests are exported in
+type Format string
+
+const (
+	FormatPostman Format = "postman" // Postman collection v2.1
+	FormatHAR     Format = "har"     // HTTP Archive 1.2
+	FormatHTTP    Format = "http"    // .http file of the VS Code REST Client and JetBrains HTTP client
+	FormatCurl    Format = "curl"    // Shell script of curl commands
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatPostman, FormatHAR, FormatHTTP, FormatCurl}
+
+// ParseFormat returns the format of the given name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format: %s (want postman, har, http or curl)", name)
+}
+
+// Option configures an export
+type Option func(*exporter)
+
+// WithVersion sets the yaswag version recorded as the creator of HAR archives.
+func WithVersion(version string) Option {
+	return func(e *exporter) {
+		e.version = version
+	}
+}
+
+// Export writes the requests of a document in the given format.
+func Export(doc *openapi.Document, format Format, opts ...Option) ([]byte, error) {
+	switch format {
+	case FormatPostman:
+		return Postman(doc)
+	case FormatHAR:
+		return HAR(doc, opts...)
+	case FormatHTTP:
+		return HTTP(doc)
+	case FormatCurl:
+		return Curl(doc)
+	}
+	return nil, fmt.Errorf("unsupported export format: %s", format)
+}
+
+// Node writes the requests of a document held as a YAML node, such as one read from a file, keeping
+// its paths in source order. The node is not modified.
+func Node(root *yaml.Node, format Format, opts ...Option) ([]byte, error) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	if yamlnode.Get(root, "swagger") != nil {
+		return nil, fmt.Errorf("swagger 2.0 documents are not supported, upgrade the document to OpenAPI 3.x first")
+	}
+	var doc openapi.Document
+	if err := root.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	var paths []string
+	yamlnode.Pairs(yamlnode.Get(root, "paths"), func(key, _ *yaml.Node) {
+		paths = append(paths, key.Value)
+	})
+	doc.SetSourceOrder("/paths", paths)
+	return Export(&doc, format, opts...)
+}
+
+// exporter holds the options of an export
+type exporter struct {
+	version string
+}
+
+// methods are the operations of a path item, in the order they are exported.
+var methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// request is an example request of an operation
+type request struct {
+	name        string
+	id          string // Operation ID, empty when it has none
+	tag         string // First tag of the operation, empty when it has none
+	method      string
+	path        string // Path template, such as /pets/{id}
+	description string
+	servers     []openapi.Server // Servers of the operation or path item, nil when the document's apply
+	params      []param
+	body        *body
+	accept      string
+	security    []openapi.SecurityRequirement
+	response    *response
+}
+
+// param is a parameter with an example value
+type param struct {
+	name        string
+	in          openapi.ParameterLocation
+	description string
+	required    bool
+	values      []string // Several values for exploded query arrays
+}
+
+// body is an example request or response body
+type body struct {
+	mediaType string
+	example   any
+}
+
+// response is the first success response of an operation, with an example body
+type response struct {
+	status int
+	body   *body
+}
+
+// requests returns the example requests of a document, with paths in source order.
+func requests(doc *openapi.Document) []*request {
+	var reqs []*request
+	for _, path := range pathOrder(doc) {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		for _, method := range methods {
+			if op := operation(item, method); op != nil {
+				reqs = append(reqs, newRequest(doc, item, op, method, path))
+			}
+		}
+	}
+	return reqs
+}
+
+// pathOrder returns the paths of a document in source order, when it was recorded, followed by the others sorted.
+func pathOrder(doc *openapi.Document) []string {
+	var paths []string
+	for _, path := range doc.SourceOrder["/paths"] {
+		if _, ok := doc.Paths[path]; ok && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	var rest []string
+	for path := range doc.Paths {
+		if !slices.Contains(paths, path) {
+			rest = append(rest, path)
+		}
+	}
+	sort.Strings(rest)
+	return append(paths, rest...)
+}
+
+func operation(item *openapi.PathItem, method string) *openapi.Operation {
+	switch method {
+	case "GET":
+		return item.Get
+	case "PUT":
+		return item.Put
+	case "POST":
+		return item.Post
+	case "DELETE":
+		return item.Delete
+	case "OPTIONS":
+		return item.Options
+	case "HEAD":
+		return item.Head
+	case "PATCH":
+		return item.Patch
+	case "TRACE":
+		return item.Trace
+	}
+	return nil
+}
+
+func newRequest(doc *openapi.Document, item *openapi.PathItem, op *openapi.Operation, method, path string) *request {
+	r := &request{
+		name:        op.Summary,
+		id:          op.OperationID,
+		method:      method,
+		path:        path,
+		description: op.Description,
+		security:    op.Security,
+	}
+	if r.name == "" {
+		r.name = op.OperationID
+	}
+	if r.name == "" {
+		r.name = method + " " + path
+	}
+	if len(op.Tags) > 0 {
+		r.tag = op.Tags[0]
+	}
+	if r.security == nil {
+		r.security = doc.Security
+	}
+	switch {
+	case len(op.Servers) > 0:
+		r.servers = op.Servers
+	case len(item.Servers) > 0:
+		r.servers = item.Servers
+	}
+
+	for _, p := range parameters(doc, item, op) {
+		r.params = append(r.params, newParam(doc, p))
+	}
+	if rb := resolveRequestBody(doc, op.RequestBody); rb != nil {
+		r.body = contentBody(doc, rb.Content)
+	}
+	r.response = successResponse(doc, op)
+	if r.response != nil && r.response.body != nil {
+		r.accept = r.response.body.mediaType
+	}
+	return r
+}
+
+// parameters returns the parameters of an operation, including those of its path item that it does not override.
+func parameters(doc *openapi.Document, item *openapi.PathItem, op *openapi.Operation) []*openapi.Parameter {
+	var params []*openapi.Parameter
+	for _, p := range append(slices.Clone(item.Parameters), op.Parameters...) {
+		p = resolveParameter(doc, p)
+		if p == nil {
+			continue
+		}
+		i := slices.IndexFunc(params, func(q *openapi.Parameter) bool { return q.Name == p.Name && q.In == p.In })
+		if i >= 0 {
+			params[i] = p
+			continue
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func newParam(doc *openapi.Document, p *openapi.Parameter) param {
+	schema := p.Schema
+	if schema == nil {
+		for _, mediaType := range sortedKeys(p.Content) {
+			schema = p.Content[mediaType].Schema
+			break
+		}
+	}
+	value := p.Example
+	if value == nil {
+		value = firstExample(p.Examples)
+	}
+	if value == nil && schema != nil {
+		value = schema.Default
+	}
+	if value == nil {
+		value = doc.SchemaExample(schema)
+	}
+
+	result := param{name: p.Name, in: p.In, description: p.Description, required: p.Required || p.In == openapi.ParameterInPath}
+	items, isArray := value.([]any)
+	explode := p.Explode == nil || *p.Explode
+	if isArray && p.In == openapi.ParameterInQuery && explode && (p.Style == "" || p.Style == "form") {
+		for _, item := range items {
+			result.values = append(result.values, formatValue(item))
+		}
+		return result
+	}
+	result.values = []string{formatValue(value)}
+	return result
+}
+
+// contentBody returns an example body for the preferred media type of a content map: JSON when there is one.
+func contentBody(doc *openapi.Document, content map[string]openapi.MediaType) *body {
+	mediaTypes := sortedKeys(content)
+	if len(mediaTypes) == 0 {
+		return nil
+	}
+	mediaType := mediaTypes[0]
+	if i := slices.IndexFunc(mediaTypes, isJSON); i >= 0 {
+		mediaType = mediaTypes[i]
+	}
+	media := content[mediaType]
+	example := media.Example
+	if example == nil {
+		example = firstExample(media.Examples)
+	}
+	if example == nil {
+		example = doc.SchemaExample(media.Schema)
+	}
+	return &body{mediaType: mediaType, example: example}
+}
+
+// successResponse returns the first 2xx response of an operation, or its default response.
+func successResponse(doc *openapi.Document, op *openapi.Operation) *response {
+	for _, status := range sortedKeys(op.Responses) {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 200 || code > 299 {
+			continue
+		}
+		if resp := resolveResponse(doc, op.Responses[status]); resp != nil {
+			return &response{status: code, body: contentBody(doc, resp.Content)}
+		}
+	}
+	if resp := resolveResponse(doc, op.Responses["default"]); resp != nil {
+		return &response{status: 200, body: contentBody(doc, resp.Content)}
+	}
+	return nil
+}
+
+func firstExample(examples map[string]*openapi.Example) any {
+	for _, name := range sortedKeys(examples) {
+		if ex := examples[name]; ex != nil && ex.Value != nil {
+			return ex.Value
+		}
+	}
+	return nil
+}
+
+// componentName returns the name of a component that ref points to in the given section, such as parameters.
+func componentName(ref, section string) (string, bool) {
+	return strings.CutPrefix(ref, "#/components/"+section+"/")
+}
+
+func resolveParameter(doc *openapi.Document, p *openapi.Parameter) *openapi.Parameter {
+	for range maxRefHops {
+		if p == nil || p.Ref == "" {
+			return p
+		}
+		name, ok := componentName(p.Ref, "parameters")
+		if !ok || doc.Components == nil {
+			return nil
+		}
+		p = doc.Components.Parameters[name]
+	}
+	return nil
+}
+
+func resolveRequestBody(doc *openapi.Document, rb *openapi.RequestBody) *openapi.RequestBody {
+	for range maxRefHops {
+		if rb == nil || rb.Ref == "" {
+			return rb
+		}
+		name, ok := componentName(rb.Ref, "requestBodies")
+		if !ok || doc.Components == nil {
+			return nil
+		}
+		rb = doc.Components.RequestBodies[name]
+	}
+	return nil
+}
+
+func resolveResponse(doc *openapi.Document, r *openapi.Response) *openapi.Response {
+	for range maxRefHops {
+		if r == nil || r.Ref == "" {
+			return r
+		}
+		name, ok := componentName(r.Ref, "responses")
+		if !ok || doc.Components == nil {
+			return nil
+		}
+		r = doc.Components.Responses[name]
+	}
+	return nil
+}
+
+// maxRefHops bounds how many $refs are followed, so reference cycles cannot loop forever.
+const maxRefHops = 32
+
+// paramsIn returns the parameters of a request in the given location.
+func (r *request) paramsIn(in openapi.ParameterLocation) []param {
+	var params []param
+	for _, p := range r.params {
+		if p.in == in {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// pathWith returns the path of the request with each {name} replaced by value(name).
+func (r *request) pathWith(value func(p param) string) string {
+	path := r.path
+	for _, p := range r.paramsIn(openapi.ParameterInPath) {
+		path = strings.ReplaceAll(path, "{"+p.name+"}", value(p))
+	}
+	return path
+}
+
+// queryString returns the encoded query of the required query parameters of the request.
+func (r *request) queryString() string {
+	var parts []string
+	for _, p := range r.paramsIn(openapi.ParameterInQuery) {
+		if !p.required {
+			continue
+		}
+		for _, v := range p.values {
+			parts = append(parts, url.QueryEscape(p.name)+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// nameValue is a query parameter, header, cookie or form field
+type nameValue struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	secret bool   // Whether the value is a placeholder for a credential
+}
+
+// sample is a request written out as it is sent, with example values and placeholders for credentials
+type sample struct {
+	method  string
+	url     string // Absolute URL with the query
+	query   []nameValue
+	headers []nameValue // Including Accept, Content-Type and Cookie
+	cookies []nameValue
+	body    *body
+}
+
+// sample writes out the request against a base URL, with all its query parameters and secret(scheme)
+// standing in for the credentials of its security schemes. Placeholders are URL encoded in the query
+// only when escapeSecrets is set, so that variables such as {{token}} stay intact.
+func (r *request) sample(doc *openapi.Document, base string, secret func(string) string, escapeSecrets bool) *sample {
+	s := &sample{
+		method: r.method,
+		url:    base + r.pathWith(func(p param) string { return url.PathEscape(strings.Join(p.values, ",")) }),
+		body:   r.body,
+	}
+	for _, p := range r.params {
+		for _, v := range p.values {
+			switch p.in {
+			case openapi.ParameterInQuery:
+				s.query = append(s.query, nameValue{Name: p.name, Value: v})
+			case openapi.ParameterInHeader:
+				s.headers = append(s.headers, nameValue{Name: p.name, Value: v})
+			case openapi.ParameterInCookie:
+				s.cookies = append(s.cookies, nameValue{Name: p.name, Value: v})
+			}
+		}
+	}
+	for _, name := range schemeNames(r.security) {
+		for _, c := range credentials(name, scheme(doc, name), secret) {
+			nv := nameValue{Name: c.name, Value: c.value, secret: true}
+			switch c.in {
+			case "query":
+				s.query = append(s.query, nv)
+			case "header":
+				s.headers = append(s.headers, nv)
+			case "cookie":
+				s.cookies = append(s.cookies, nv)
+			}
+		}
+	}
+
+	var query []string
+	for _, q := range s.query {
+		value := q.Value
+		if !q.secret || escapeSecrets {
+			value = url.QueryEscape(value)
+		}
+		query = append(query, url.QueryEscape(q.Name)+"="+value)
+	}
+	if len(query) > 0 {
+		s.url += "?" + strings.Join(query, "&")
+	}
+
+	if r.accept != "" {
+		s.headers = append(s.headers, nameValue{Name: "Accept", Value: r.accept})
+	}
+	if r.body != nil {
+		s.headers = append(s.headers, nameValue{Name: "Content-Type", Value: r.body.mediaType})
+	}
+	if len(s.cookies) > 0 {
+		var cookies []string
+		for _, c := range s.cookies {
+			cookies = append(cookies, c.Name+"="+c.Value)
+		}
+		s.headers = append(s.headers, nameValue{Name: "Cookie", Value: strings.Join(cookies, "; "), secret: slices.ContainsFunc(s.cookies, func(c nameValue) bool { return c.secret })})
+	}
+	return s
+}
+
+// baseURL returns the URL of the first server of the request, or of the document, with variables set
+// to their defaults. Relative URLs are made absolute with defaultOrigin.
+func (r *request) baseURL(doc *openapi.Document) string {
+	servers := r.servers
+	if servers == nil {
+		servers = doc.Servers
+	}
+	base := serverURL(servers, func(_ string, v openapi.ServerVariable) string { return v.Default })
+	if !strings.Contains(base, "://") {
+		base = defaultOrigin + base
+	}
+	return base
+}
+
+// defaultOrigin is the origin of servers with a relative URL, as requests need absolute URLs.
+const defaultOrigin = "http://localhost"
+
+// scheme returns a security scheme of the document by name.
+func scheme(doc *openapi.Document, name string) *openapi.SecurityScheme {
+	if doc.Components == nil {
+		return nil
+	}
+	return doc.Components.SecuritySchemes[name]
+}
+
+// schemeNames returns the schemes of the first security requirement, which is the one exported, sorted.
+func schemeNames(requirements []openapi.SecurityRequirement) []string {
+	if len(requirements) == 0 {
+		return nil
+	}
+	return sortedKeys(requirements[0])
+}
+
+// credential is a header, query parameter or cookie that carries a credential
+type credential struct {
+	in    string // header, query or cookie
+	name  string
+	value string
+}
+
+// credentials returns how a request authenticates with a scheme, with placeholder(name) standing in for secrets.
+func credentials(name string, s *openapi.SecurityScheme, placeholder func(string) string) []credential {
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case "apiKey":
+		return []credential{{in: s.In, name: s.Name, value: placeholder(name)}}
+	case "http":
+		prefix := strings.ToUpper(s.Scheme[:min(1, len(s.Scheme))]) + strings.ToLower(s.Scheme[min(1, len(s.Scheme)):])
+		return []credential{{in: "header", name: "Authorization", value: prefix + " " + placeholder(name)}}
+	case "oauth2", "openIdConnect":
+		return []credential{{in: "header", name: "Authorization", value: "Bearer " + placeholder(name)}}
+	}
+	return nil
+}
+
+// variablePattern matches the {name} variables of server URLs.
+var variablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// serverURL returns the URL of the first server without a trailing slash, with each variable
+// replaced by value(name, server variable).
+func serverURL(servers []openapi.Server, value func(name string, v openapi.ServerVariable) string) string {
+	if len(servers) == 0 {
+		return ""
+	}
+	server := servers[0]
+	u := variablePattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		return value(name, server.Variables[name])
+	})
+	return strings.TrimSuffix(u, "/")
+}
+
+// isJSON reports whether a media type is JSON, such as application/json or application/problem+json.
+func isJSON(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isForm(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "application/x-www-form-urlencoded")
+}
+
+func isMultipart(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "multipart/")
+}
+
+// text returns the body as it is sent: JSON indented by indent (compact when empty), form fields URL encoded, and strings as they are.
+func (b *body) text(indent string) string {
+	switch {
+	case b.example == nil:
+		return ""
+	case isForm(b.mediaType):
+		values := url.Values{}
+		for _, f := range formFields(b.example) {
+			values.Add(f.Name, f.Value)
+		}
+		return values.Encode()
+	case isJSON(b.mediaType):
+		return jsonText(b.example, indent)
+	}
+	if s, ok := b.example.(string); ok {
+		return s
+	}
+	return jsonText(b.example, indent)
+}
+
+// formFields returns the fields of an object example, sorted by name.
+func formFields(example any) []nameValue {
+	obj, ok := example.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var fields []nameValue
+	for _, name := range sortedKeys(obj) {
+		fields = append(fields, nameValue{Name: name, Value: formatValue(obj[name])})
+	}
+	return fields
+}
+
+func jsonText(v any, indent string) string {
+	data, err := marshal(v, indent)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// marshal encodes v as JSON followed by a newline, without escaping the <, > and & of placeholders and URLs.
+func marshal(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatValue formats an example value as it appears in a URL or header: scalars as they are,
+// arrays comma-separated and objects as JSON.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		return jsonText(v, "")
+	}
+	return fmt.Sprint(v)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+const spec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+  description: A pet store.
+servers:
+  - url: https://{env}.example.com/v1/
+    variables:
+      env: {default: api, description: Environment}
+security:
+  - bearer: []
+tags:
+  - name: users
+  - name: pets
+    description: Everything about pets
+paths:
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+      - {name: X-Trace, in: header, schema: {type: string}}
+    get:
+      operationId: getPet
+      summary: Get a pet
+      tags: [pets]
+      parameters:
+        - {name: X-Trace, in: header, required: true, schema: {type: string, format: uuid}}
+        - {name: fields, in: query, schema: {type: array, items: {type: string}}, example: [name, tag]}
+        - {name: session, in: cookie, schema: {type: string, default: abc}}
+      responses:
+        "404": {description: Not found}
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets:
+    post:
+      operationId: createPet
+      tags: [pets]
+      security:
+        - api_key: []
+      requestBody:
+        $ref: "#/components/requestBodies/Pet"
+      responses:
+        "201": {description: Created}
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - $ref: "#/components/parameters/Limit"
+      servers:
+        - url: /v2
+      responses:
+        "200":
+          $ref: "#/components/responses/Pets"
+  /login:
+    post:
+      operationId: login
+      security: []
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                user: {type: string, example: alice}
+                password: {type: string, format: password}
+      responses:
+        "204": {description: Logged in}
+  /users:
+    get:
+      operationId: listUsers
+      tags: [users, admin]
+      security:
+        - basic: []
+      responses:
+        default: {description: Users}
+components:
+  parameters:
+    Limit: {name: limit, in: query, required: true, schema: {type: integer, default: 20}}
+  requestBodies:
+    Pet:
+      content:
+        application/xml:
+          schema: {$ref: "#/components/schemas/Pet"}
+        application/json:
+          schema: {$ref: "#/components/schemas/Pet"}
+  responses:
+    Pets:
+      description: Pets
+      content:
+        application/json:
+          schema:
+            type: array
+            items: {$ref: "#/components/schemas/Pet"}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id: {type: integer, example: 7}
+        name: {type: string, example: Rex}
+        parent: {$ref: "#/components/schemas/Pet"}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    basic: {type: http, scheme: basic}
+    api_key: {type: apiKey, in: header, name: X-API-Key}
+`
+
+func exportSpec(t *testing.T, format Format, opts ...Option) []byte {
+	t.Helper()
+	return exportString(t, spec, format, opts...)
+}
+
+func exportString(t *testing.T, input string, format Format, opts ...Option) []byte {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	data, err := Node(root, format, opts...)
+	if err != nil {
+		t.Fatalf("Node() error = %v", err)
+	}
+	return data
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"postman", "har", "http", "curl"} {
+		if f, err := ParseFormat(name); err != nil || string(f) != name {
+			t.Errorf("ParseFormat(%q) = %q, %v", name, f, err)
+		}
+	}
+	if _, err := ParseFormat("insomnia"); err == nil {
+		t.Error("ParseFormat(insomnia) should fail")
+	}
+}
+
+func TestNode_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"not a mapping", "- a\n- b\n", "not a mapping"},
+		{"swagger 2.0", "swagger: \"2.0\"\ninfo: {title: T, version: v}\n", "swagger 2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := yamlnode.Parse([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			_, err = Node(root, FormatPostman)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Node() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, ""},
+		{"a b", "a b"},
+		{7, "7"},
+		{1.5, "1.5"},
+		{true, "true"},
+		{[]any{"a", 1}, "a,1"},
+		{map[string]any{"a": "<b>"}, `{"a":"<b>"}`},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.value); got != tt.want {
+			t.Errorf("formatValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"net/http"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// harStarted is the start time of every entry: the requests are samples that were never sent.
+const harStarted = "1970-01-01T00:00:00.000Z"
+
+type harArchive struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int         `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []nameValue  `json:"cookies"`
+	Headers     []nameValue  `json:"headers"`
+	QueryString []nameValue  `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []nameValue `json:"cookies"`
+	Headers     []nameValue `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string      `json:"mimeType"`
+	Text     string      `json:"text"`
+	Params   []nameValue `json:"params,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    int `json:"send"`
+	Wait    int `json:"wait"`
+	Receive int `json:"receive"`
+}
+
+// HAR writes the operations of a document as an HTTP Archive 1.2 with an entry per operation.
+// Each entry holds an example request, with the parameters, credentials and body filled in, and
+// an example of its success response. Secrets are written as <scheme> placeholders.
+func HAR(doc *openapi.Document, opts ...Option) ([]byte, error) {
+	e := &exporter{}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	archive := harArchive{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "yaswag", Version: e.version},
+		Entries: []harEntry{},
+	}}
+	for _, r := range requests(doc) {
+		archive.Log.Entries = append(archive.Log.Entries, harEntryFor(doc, r))
+	}
+
+	return marshal(archive, "  ")
+}
+
+func harEntryFor(doc *openapi.Document, r *request) harEntry {
+	s := r.sample(doc, r.baseURL(doc), func(name string) string { return "<" + name + ">" }, true)
+	req := harRequest{
+		Method:      s.method,
+		URL:         s.url,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     append([]nameValue{}, s.cookies...),
+		Headers:     append([]nameValue{}, s.headers...),
+		QueryString: append([]nameValue{}, s.query...),
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	if b := s.body; b != nil {
+		req.PostData = &harPostData{MimeType: b.mediaType, Text: b.text("")}
+		if isForm(b.mediaType) || isMultipart(b.mediaType) {
+			req.PostData.Params = formFields(b.example)
+		}
+		if isMultipart(b.mediaType) {
+			req.PostData.Text = ""
+		}
+		req.BodySize = len(req.PostData.Text)
+	}
+
+	resp := harResponse{
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []nameValue{},
+		Headers:     []nameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if r.response != nil {
+		resp.Status = r.response.status
+		resp.StatusText = http.StatusText(r.response.status)
+		if b := r.response.body; b != nil {
+			resp.Headers = append(resp.Headers, nameValue{Name: "Content-Type", Value: b.mediaType})
+			resp.Content = harContent{MimeType: b.mediaType, Text: b.text("")}
+			resp.Content.Size = len(resp.Content.Text)
+		}
+	}
+
+	return harEntry{
+		StartedDateTime: harStarted,
+		Request:         req,
+		Response:        resp,
+		Comment:         r.name,
+	}
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHAR(t *testing.T) {
+	var archive harArchive
+	if err := json.Unmarshal(exportSpec(t, FormatHAR, WithVersion("1.2.3")), &archive); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	log := archive.Log
+	if log.Version != "1.2" || log.Creator != (harCreator{Name: "yaswag", Version: "1.2.3"}) {
+		t.Errorf("log = %+v %+v", log.Version, log.Creator)
+	}
+	var urls []string
+	for _, e := range log.Entries {
+		urls = append(urls, e.Request.Method+" "+e.Request.URL)
+	}
+	want := []string{
+		"GET https://api.example.com/v1/pets/1?fields=name&fields=tag",
+		"GET http://localhost/v2/pets?limit=20",
+		"POST https://api.example.com/v1/pets",
+		"POST https://api.example.com/v1/login",
+		"GET https://api.example.com/v1/users",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Fatalf("urls = %v, want %v", urls, want)
+	}
+
+	t.Run("parameters and credentials", func(t *testing.T) {
+		get := log.Entries[0]
+		wantHeaders := []nameValue{
+			{Name: "X-Trace", Value: "550e8400-e29b-41d4-a716-446655440000"},
+			{Name: "Authorization", Value: "Bearer <bearer>"},
+			{Name: "Accept", Value: "application/json"},
+			{Name: "Cookie", Value: "session=abc"},
+		}
+		if !reflect.DeepEqual(get.Request.Headers, wantHeaders) {
+			t.Errorf("headers = %+v, want %+v", get.Request.Headers, wantHeaders)
+		}
+		if want := []nameValue{{Name: "session", Value: "abc"}}; !reflect.DeepEqual(get.Request.Cookies, want) {
+			t.Errorf("cookies = %+v, want %+v", get.Request.Cookies, want)
+		}
+		if get.Comment != "Get a pet" {
+			t.Errorf("comment = %q", get.Comment)
+		}
+	})
+
+	t.Run("response", func(t *testing.T) {
+		resp := log.Entries[0].Response
+		if resp.Status != 200 || resp.StatusText != "OK" {
+			t.Errorf("status = %d %q", resp.Status, resp.StatusText)
+		}
+		if resp.Content.MimeType != "application/json" || resp.Content.Text != `{"id":7,"name":"Rex","parent":null}` {
+			t.Errorf("content = %+v", resp.Content)
+		}
+		if users := log.Entries[4].Response; users.Status != 200 || users.Content.Text != "" {
+			t.Errorf("default response = %+v", users)
+		}
+	})
+
+	t.Run("bodies", func(t *testing.T) {
+		create := log.Entries[2].Request
+		if create.PostData == nil || create.PostData.MimeType != "application/json" || create.BodySize != len(create.PostData.Text) {
+			t.Errorf("postData = %+v", create.PostData)
+		}
+		login := log.Entries[3].Request
+		if login.PostData == nil || login.PostData.Text != "password=string&user=alice" || len(login.PostData.Params) != 2 {
+			t.Errorf("postData = %+v", login.PostData)
+		}
+		for _, h := range login.Headers {
+			if h.Name == "Authorization" {
+				t.Errorf("login has credentials %q, want none", h.Value)
+			}
+		}
+		if want := (nameValue{Name: "Authorization", Value: "Basic <basic>"}); log.Entries[4].Request.Headers[0] != want {
+			t.Errorf("users headers = %+v, want %+v", log.Entries[4].Request.Headers, want)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// httpBoundary separates the parts of multipart bodies in .http files.
+const httpBoundary = "yaswag"
+
+// HTTP writes the operations of a document as a .http file, the format of the REST Client of VS Code
+// and the HTTP client of JetBrains IDEs, with requests separated by ### lines. The server URL and its
+// variables are file variables, with baseUrl for the whole URL, and so are the credentials of the
+// security schemes, which are left empty to be filled in.
+func HTTP(doc *openapi.Document) ([]byte, error) {
+	reqs := requests(doc)
+
+	var b strings.Builder
+	writeTitle(&b, doc, "# ")
+	b.WriteString("#\n# Fill in the credential variables below before sending requests.\n\n")
+
+	var base string
+	if len(doc.Servers) > 0 {
+		variables := doc.Servers[0].Variables
+		for _, name := range sortedKeys(variables) {
+			b.WriteString("@" + name + " = " + variables[name].Default + "\n")
+		}
+		base = serverURL(doc.Servers, func(name string, _ openapi.ServerVariable) string { return "{{" + name + "}}" })
+	}
+	if !strings.Contains(base, "://") {
+		base = defaultOrigin + base
+	}
+	b.WriteString("@baseUrl = " + base + "\n")
+	for _, name := range secretSchemes(doc, reqs) {
+		b.WriteString("@" + name + " =\n")
+	}
+
+	for _, r := range reqs {
+		base := "{{baseUrl}}"
+		if r.servers != nil {
+			base = r.baseURL(doc)
+		}
+		s := r.sample(doc, base, func(name string) string { return "{{" + name + "}}" }, false)
+
+		b.WriteString("\n### " + r.name + "\n")
+		if r.id != "" && r.id != r.name {
+			b.WriteString("# @name " + r.id + "\n")
+		}
+		writeComment(&b, r.description, "# ")
+		b.WriteString(s.method + " " + s.url + "\n")
+		for _, h := range s.headers {
+			if h.Name == "Content-Type" && isMultipart(h.Value) {
+				h.Value += "; boundary=" + httpBoundary
+			}
+			b.WriteString(h.Name + ": " + h.Value + "\n")
+		}
+		if s.body == nil {
+			continue
+		}
+		b.WriteString("\n")
+		if isMultipart(s.body.mediaType) {
+			for _, f := range formFields(s.body.example) {
+				b.WriteString("--" + httpBoundary + "\n")
+				b.WriteString("Content-Disposition: form-data; name=\"" + f.Name + "\"\n\n")
+				b.WriteString(f.Value + "\n")
+			}
+			b.WriteString("--" + httpBoundary + "--\n")
+			continue
+		}
+		b.WriteString(s.body.text("  ") + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// secretSchemes returns the schemes of the document whose credentials the requests need, sorted.
+func secretSchemes(doc *openapi.Document, reqs []*request) []string {
+	var names []string
+	for _, r := range reqs {
+		for _, name := range schemeNames(r.security) {
+			if scheme(doc, name) != nil && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// writeTitle writes the title and version of a document as a comment.
+func writeTitle(b *strings.Builder, doc *openapi.Document, prefix string) {
+	title := strings.TrimSpace(doc.Info.Title + " " + doc.Info.Version)
+	if title == "" {
+		title = "API"
+	}
+	b.WriteString(prefix + title + "\n")
+}
+
+// writeComment writes each line of text as a comment.
+func writeComment(b *strings.Builder, text, prefix string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+}
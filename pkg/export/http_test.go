@@ -0,0 +1,90 @@
+package export
+
+import "testing"
+
+func TestHTTP(t *testing.T) {
+	want := `# Pets 1.0.0
+#
+# Fill in the credential variables below before sending requests.
+
+@env = api
+@baseUrl = https://{{env}}.example.com/v1
+@api_key =
+@basic =
+@bearer =
+
+### Get a pet
+# @name getPet
+GET {{baseUrl}}/pets/1?fields=name&fields=tag
+X-Trace: 550e8400-e29b-41d4-a716-446655440000
+Authorization: Bearer {{bearer}}
+Accept: application/json
+Cookie: session=abc
+
+### listPets
+GET http://localhost/v2/pets?limit=20
+Authorization: Bearer {{bearer}}
+Accept: application/json
+
+### createPet
+POST {{baseUrl}}/pets
+X-API-Key: {{api_key}}
+Content-Type: application/json
+
+{
+  "id": 7,
+  "name": "Rex",
+  "parent": null
+}
+
+### login
+POST {{baseUrl}}/login
+Content-Type: application/x-www-form-urlencoded
+
+password=string&user=alice
+
+### listUsers
+GET {{baseUrl}}/users
+Authorization: Basic {{basic}}
+`
+	if got := string(exportSpec(t, FormatHTTP)); got != want {
+		t.Errorf("HTTP() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTTP_Multipart(t *testing.T) {
+	input := `openapi: 3.1.0
+info: {title: Files, version: "1"}
+paths:
+  /files:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                name: {type: string, example: a.txt}
+      responses:
+        "204": {description: Uploaded}
+`
+	want := `# Files 1
+#
+# Fill in the credential variables below before sending requests.
+
+@baseUrl = http://localhost
+
+### POST /files
+POST {{baseUrl}}/files
+Content-Type: multipart/form-data; boundary=yaswag
+
+--yaswag
+Content-Disposition: form-data; name="name"
+
+a.txt
+--yaswag--
+`
+	if got := string(exportString(t, input, FormatHTTP)); got != want {
+		t.Errorf("HTTP() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,353 @@
+package export
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// postmanSchema is the schema URL of Postman collections v2.1
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []*postmanItem    `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// postmanItem is a folder when it has items, and a request otherwise
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []*postmanItem  `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanKeyValue `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	Auth        *postmanAuth      `json:"auth,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string              `json:"mode"`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue   `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue   `json:"formdata,omitempty"`
+	Options    *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	OAuth2 []postmanKeyValue `json:"oauth2,omitempty"`
+}
+
+// Postman writes the operations of a document as a Postman v2.1 collection. Operations are put in
+// a folder per tag and get example bodies generated from their schemas. The server URL and its
+// variables become collection variables, with baseUrl for the whole URL, and each security scheme
+// becomes the auth of the requests that use it, with its secrets as variables named after it.
+func Postman(doc *openapi.Document) ([]byte, error) {
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:        doc.Info.Title,
+			Description: doc.Info.Description,
+			Schema:      postmanSchema,
+		},
+		Item: []*postmanItem{},
+	}
+	if collection.Info.Name == "" {
+		collection.Info.Name = "API"
+	}
+
+	collection.Variable = append(collection.Variable, postmanKeyValue{
+		Key:   "baseUrl",
+		Value: serverURL(doc.Servers, func(name string, _ openapi.ServerVariable) string { return "{{" + name + "}}" }),
+		Type:  "string",
+	})
+	if len(doc.Servers) > 0 {
+		variables := doc.Servers[0].Variables
+		for _, name := range sortedKeys(variables) {
+			collection.Variable = append(collection.Variable, postmanKeyValue{
+				Key:         name,
+				Value:       variables[name].Default,
+				Type:        "string",
+				Description: variables[name].Description,
+			})
+		}
+	}
+	collection.Auth = postmanAuthFor(doc, doc.Security)
+
+	folders := map[string]*postmanItem{}
+	for _, tag := range doc.Tags {
+		folders[tag.Name] = &postmanItem{Name: tag.Name, Description: tag.Description}
+	}
+	var order []string
+	var secrets []string
+	for _, r := range requests(doc) {
+		item := postmanRequestItem(doc, r)
+		if name := firstScheme(r.security); name != "" && !slices.Contains(secrets, name) {
+			secrets = append(secrets, name)
+		}
+		if r.tag == "" {
+			collection.Item = append(collection.Item, item)
+			continue
+		}
+		folder := folders[r.tag]
+		if folder == nil {
+			folder = &postmanItem{Name: r.tag}
+			folders[r.tag] = folder
+		}
+		if !slices.Contains(order, r.tag) {
+			order = append(order, r.tag)
+		}
+		folder.Item = append(folder.Item, item)
+	}
+
+	// Folders come first, in the order of the tags of the document, followed by untagged requests
+	var items []*postmanItem
+	for _, tag := range doc.Tags {
+		if slices.Contains(order, tag.Name) {
+			items = append(items, folders[tag.Name])
+		}
+	}
+	for _, tag := range order {
+		if !slices.ContainsFunc(doc.Tags, func(t openapi.Tag) bool { return t.Name == tag }) {
+			items = append(items, folders[tag])
+		}
+	}
+	collection.Item = append(items, collection.Item...)
+
+	if name := firstScheme(doc.Security); name != "" && !slices.Contains(secrets, name) {
+		secrets = append(secrets, name)
+	}
+	slices.Sort(secrets)
+	for _, name := range secrets {
+		for _, v := range postmanSecrets(name, scheme(doc, name)) {
+			collection.Variable = append(collection.Variable, postmanKeyValue{Key: v, Value: "", Type: "string"})
+		}
+	}
+
+	return marshal(collection, "  ")
+}
+
+func postmanRequestItem(doc *openapi.Document, r *request) *postmanItem {
+	base := "{{baseUrl}}"
+	if r.servers != nil {
+		base = serverURL(r.servers, func(_ string, v openapi.ServerVariable) string { return v.Default })
+	}
+
+	u := postmanURL{Host: []string{base}, Path: []string{}}
+	path := r.pathWith(func(p param) string { return ":" + p.name })
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment != "" {
+			u.Path = append(u.Path, segment)
+		}
+	}
+	u.Raw = base + path
+	for _, p := range r.paramsIn(openapi.ParameterInPath) {
+		u.Variable = append(u.Variable, postmanKeyValue{Key: p.name, Value: strings.Join(p.values, ","), Description: p.description})
+	}
+	for _, p := range r.paramsIn(openapi.ParameterInQuery) {
+		for _, v := range p.values {
+			u.Query = append(u.Query, postmanKeyValue{Key: p.name, Value: v, Description: p.description, Disabled: !p.required})
+		}
+	}
+	if query := r.queryString(); query != "" {
+		u.Raw += "?" + query
+	}
+
+	req := &postmanRequest{Method: r.method, Header: []postmanKeyValue{}, URL: u, Description: r.description}
+	for _, p := range r.paramsIn(openapi.ParameterInHeader) {
+		req.Header = append(req.Header, postmanKeyValue{Key: p.name, Value: strings.Join(p.values, ","), Description: p.description, Disabled: !p.required})
+	}
+	var cookies []string
+	for _, p := range r.paramsIn(openapi.ParameterInCookie) {
+		cookies = append(cookies, p.name+"="+strings.Join(p.values, ","))
+	}
+	if r.accept != "" {
+		req.Header = append(req.Header, postmanKeyValue{Key: "Accept", Value: r.accept})
+	}
+	if r.body != nil {
+		req.Header = append(req.Header, postmanKeyValue{Key: "Content-Type", Value: r.body.mediaType})
+		req.Body = postmanBodyFor(r.body)
+	}
+
+	// Requests inherit the auth of the collection unless their security differs from the document's
+	switch {
+	case r.security != nil && len(r.security) == 0:
+		req.Auth = &postmanAuth{Type: "noauth"}
+	case !slices.EqualFunc(r.security, doc.Security, sameRequirement):
+		req.Auth = postmanAuthFor(doc, r.security)
+	}
+	if name := firstScheme(r.security); name != "" {
+		// Postman API keys go in headers or queries only, so keys in cookies are sent in the Cookie header
+		if s := scheme(doc, name); s != nil && s.Type == "apiKey" && s.In == "cookie" {
+			cookies = append(cookies, s.Name+"={{"+name+"}}")
+		}
+	}
+	if len(cookies) > 0 {
+		req.Header = append(req.Header, postmanKeyValue{Key: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+	return &postmanItem{Name: r.name, Request: req}
+}
+
+func postmanBodyFor(b *body) *postmanBody {
+	switch {
+	case isForm(b.mediaType):
+		pb := &postmanBody{Mode: "urlencoded"}
+		for _, f := range formFields(b.example) {
+			pb.URLEncoded = append(pb.URLEncoded, postmanKeyValue{Key: f.Name, Value: f.Value, Type: "text"})
+		}
+		return pb
+	case isMultipart(b.mediaType):
+		pb := &postmanBody{Mode: "formdata"}
+		for _, f := range formFields(b.example) {
+			pb.FormData = append(pb.FormData, postmanKeyValue{Key: f.Name, Value: f.Value, Type: "text"})
+		}
+		return pb
+	}
+	pb := &postmanBody{Mode: "raw", Raw: b.text("  ")}
+	if isJSON(b.mediaType) {
+		pb.Options = &postmanBodyOptions{}
+		pb.Options.Raw.Language = "json"
+	}
+	return pb
+}
+
+// postmanAuthFor returns the Postman auth of the first scheme of the first security requirement, or nil when there is none.
+func postmanAuthFor(doc *openapi.Document, requirements []openapi.SecurityRequirement) *postmanAuth {
+	name := firstScheme(requirements)
+	s := scheme(doc, name)
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case "apiKey":
+		if s.In == "cookie" {
+			return nil
+		}
+		return &postmanAuth{Type: "apikey", APIKey: []postmanKeyValue{
+			{Key: "key", Value: s.Name, Type: "string"},
+			{Key: "value", Value: "{{" + name + "}}", Type: "string"},
+			{Key: "in", Value: s.In, Type: "string"},
+		}}
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			return &postmanAuth{Type: "basic", Basic: []postmanKeyValue{
+				{Key: "username", Value: "{{" + name + "Username}}", Type: "string"},
+				{Key: "password", Value: "{{" + name + "Password}}", Type: "string"},
+			}}
+		case "bearer":
+			return &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{{Key: "token", Value: "{{" + name + "}}", Type: "string"}}}
+		}
+	case "oauth2":
+		return &postmanAuth{Type: "oauth2", OAuth2: postmanOAuth2(name, s, requirements[0][name])}
+	case "openIdConnect":
+		return &postmanAuth{Type: "bearer", Bearer: []postmanKeyValue{{Key: "token", Value: "{{" + name + "}}", Type: "string"}}}
+	}
+	return nil
+}
+
+// postmanOAuth2 configures Postman to get a token with the first flow of a scheme, in the order
+// authorization code, client credentials, password and implicit.
+func postmanOAuth2(name string, s *openapi.SecurityScheme, scopes []string) []postmanKeyValue {
+	config := []postmanKeyValue{
+		{Key: "accessToken", Value: "{{" + name + "}}", Type: "string"},
+		{Key: "addTokenTo", Value: "header", Type: "string"},
+	}
+	if s.Flows == nil {
+		return config
+	}
+	flows := []struct {
+		grant string
+		flow  *openapi.OAuthFlow
+	}{
+		{"authorization_code", s.Flows.AuthorizationCode},
+		{"client_credentials", s.Flows.ClientCredentials},
+		{"password_credentials", s.Flows.Password},
+		{"implicit", s.Flows.Implicit},
+	}
+	for _, f := range flows {
+		if f.flow == nil {
+			continue
+		}
+		config = append(config, postmanKeyValue{Key: "grant_type", Value: f.grant, Type: "string"})
+		if f.flow.AuthorizationURL != "" {
+			config = append(config, postmanKeyValue{Key: "authUrl", Value: f.flow.AuthorizationURL, Type: "string"})
+		}
+		if f.flow.TokenURL != "" {
+			config = append(config, postmanKeyValue{Key: "accessTokenUrl", Value: f.flow.TokenURL, Type: "string"})
+		}
+		break
+	}
+	if len(scopes) > 0 {
+		config = append(config, postmanKeyValue{Key: "scope", Value: strings.Join(scopes, " "), Type: "string"})
+	}
+	return config
+}
+
+// postmanSecrets returns the names of the variables holding the secrets of a scheme.
+func postmanSecrets(name string, s *openapi.SecurityScheme) []string {
+	if s != nil && s.Type == "http" && strings.EqualFold(s.Scheme, "basic") {
+		return []string{name + "Username", name + "Password"}
+	}
+	return []string{name}
+}
+
+// firstScheme returns the first scheme of the first security requirement, the one Postman auth is made from.
+func firstScheme(requirements []openapi.SecurityRequirement) string {
+	if names := schemeNames(requirements); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+func sameRequirement(a, b openapi.SecurityRequirement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, scopes := range a {
+		other, ok := b[name]
+		if !ok || !slices.Equal(scopes, other) {
+			return false
+		}
+	}
+	return true
+}
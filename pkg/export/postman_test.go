@@ -0,0 +1,127 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPostman(t *testing.T) {
+	var collection postmanCollection
+	if err := json.Unmarshal(exportSpec(t, FormatPostman), &collection); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if collection.Info.Name != "Pets" || collection.Info.Schema != postmanSchema {
+		t.Errorf("Info = %+v", collection.Info)
+	}
+	if collection.Auth == nil || collection.Auth.Type != "bearer" || collection.Auth.Bearer[0].Value != "{{bearer}}" {
+		t.Errorf("Auth = %+v, want bearer {{bearer}}", collection.Auth)
+	}
+
+	variables := map[string]string{}
+	var names []string
+	for _, v := range collection.Variable {
+		variables[v.Key] = v.Value
+		names = append(names, v.Key)
+	}
+	wantNames := []string{"baseUrl", "env", "api_key", "basicUsername", "basicPassword", "bearer"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("variables = %v, want %v", names, wantNames)
+	}
+	if variables["baseUrl"] != "https://{{env}}.example.com/v1" || variables["env"] != "api" {
+		t.Errorf("variables = %v", variables)
+	}
+
+	// Folders follow the tags of the document, untagged requests come last
+	var items []string
+	for _, item := range collection.Item {
+		items = append(items, item.Name)
+	}
+	if want := []string{"users", "pets", "login"}; !reflect.DeepEqual(items, want) {
+		t.Fatalf("items = %v, want %v", items, want)
+	}
+	pets := collection.Item[1]
+	if pets.Description != "Everything about pets" {
+		t.Errorf("pets description = %q", pets.Description)
+	}
+	var requests []string
+	for _, item := range pets.Item {
+		requests = append(requests, item.Request.Method+" "+item.Name)
+	}
+	if want := []string{"GET Get a pet", "GET listPets", "POST createPet"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("pets requests = %v, want %v", requests, want)
+	}
+
+	t.Run("parameters", func(t *testing.T) {
+		get := pets.Item[0].Request
+		if get.URL.Raw != "{{baseUrl}}/pets/:id" {
+			t.Errorf("raw = %q", get.URL.Raw)
+		}
+		if want := []string{"pets", ":id"}; !reflect.DeepEqual(get.URL.Path, want) {
+			t.Errorf("path = %v, want %v", get.URL.Path, want)
+		}
+		if len(get.URL.Variable) != 1 || get.URL.Variable[0].Key != "id" || get.URL.Variable[0].Value != "1" {
+			t.Errorf("variable = %+v", get.URL.Variable)
+		}
+		if len(get.URL.Query) != 2 || get.URL.Query[1].Value != "tag" || !get.URL.Query[0].Disabled {
+			t.Errorf("query = %+v, want fields=name and fields=tag, disabled", get.URL.Query)
+		}
+		want := []postmanKeyValue{
+			{Key: "X-Trace", Value: "550e8400-e29b-41d4-a716-446655440000"},
+			{Key: "Accept", Value: "application/json"},
+			{Key: "Cookie", Value: "session=abc"},
+		}
+		if !reflect.DeepEqual(get.Header, want) {
+			t.Errorf("header = %+v, want %+v", get.Header, want)
+		}
+		if get.Auth != nil {
+			t.Errorf("auth = %+v, want inherited", get.Auth)
+		}
+	})
+
+	t.Run("servers and required query", func(t *testing.T) {
+		list := pets.Item[1].Request
+		if list.URL.Raw != "/v2/pets?limit=20" {
+			t.Errorf("raw = %q", list.URL.Raw)
+		}
+	})
+
+	t.Run("json body", func(t *testing.T) {
+		create := pets.Item[2].Request
+		if create.Body == nil || create.Body.Mode != "raw" || create.Body.Options.Raw.Language != "json" {
+			t.Fatalf("body = %+v", create.Body)
+		}
+		var body map[string]any
+		if err := json.Unmarshal([]byte(create.Body.Raw), &body); err != nil {
+			t.Fatalf("body is not JSON: %v", err)
+		}
+		if want := map[string]any{"id": 7.0, "name": "Rex", "parent": nil}; !reflect.DeepEqual(body, want) {
+			t.Errorf("body = %v, want %v", body, want)
+		}
+		if create.Auth == nil || create.Auth.Type != "apikey" || create.Auth.APIKey[0].Value != "X-API-Key" {
+			t.Errorf("auth = %+v", create.Auth)
+		}
+	})
+
+	t.Run("form body without auth", func(t *testing.T) {
+		login := collection.Item[2].Request
+		want := []postmanKeyValue{
+			{Key: "password", Value: "string", Type: "text"},
+			{Key: "user", Value: "alice", Type: "text"},
+		}
+		if login.Body == nil || login.Body.Mode != "urlencoded" || !reflect.DeepEqual(login.Body.URLEncoded, want) {
+			t.Errorf("body = %+v", login.Body)
+		}
+		if login.Auth == nil || login.Auth.Type != "noauth" {
+			t.Errorf("auth = %+v, want noauth", login.Auth)
+		}
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		users := collection.Item[0].Item[0].Request
+		if users.Auth == nil || users.Auth.Type != "basic" || users.Auth.Basic[0].Value != "{{basicUsername}}" {
+			t.Errorf("auth = %+v", users.Auth)
+		}
+	})
+}
@@ -0,0 +1,401 @@
+// Package filter reduces an OpenAPI document to a subset of its operations, such as the public ones,
+// and prunes the components that are no longer referenced.
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/glob"
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// Option configures filtering
+type Option func(*filter)
+
+// WithTags keeps only the operations that have at least one of the tags.
+func WithTags(tags ...string) Option {
+	return func(f *filter) {
+		f.tags = append(f.tags, tags...)
+	}
+}
+
+// WithExcludeTags removes the operations that have any of the tags, and the tags themselves.
+func WithExcludeTags(tags ...string) Option {
+	return func(f *filter) {
+		f.excludeTags = append(f.excludeTags, tags...)
+	}
+}
+
+// WithPaths keeps only the paths matching at least one of the glob patterns. "*" matches within a
+// segment and "**" any number of segments, so /v2/** matches /v2 and everything below it.
+func WithPaths(patterns ...string) Option {
+	return func(f *filter) {
+		f.paths = append(f.paths, patterns...)
+	}
+}
+
+// WithExcludeExtensions removes everything marked with one of the extensions, such as x-internal: true.
+// That includes operations, path items, parameters, schema properties, components and tags.
+func WithExcludeExtensions(names ...string) Option {
+	return func(f *filter) {
+		f.extensions = append(f.extensions, names...)
+	}
+}
+
+// WithKeepUnused keeps the components that are no longer referenced.
+func WithKeepUnused() Option {
+	return func(f *filter) {
+		f.keepUnused = true
+	}
+}
+
+// methods are the keys of a path item that hold operations
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// filter holds the settings of a single filtering run
+type filter struct {
+	tags        []string
+	excludeTags []string
+	paths       []string
+	extensions  []string
+	keepUnused  bool
+
+	root       *yaml.Node
+	operations map[*yaml.Node]bool // Whether each path item had operations before filtering
+}
+
+// Node returns a filtered copy of a document held as a YAML node. The node is not modified.
+func Node(root *yaml.Node, opts ...Option) (*yaml.Node, error) {
+	f := &filter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	for _, pattern := range f.paths {
+		if err := glob.Validate(pattern); err != nil {
+			return nil, fmt.Errorf("invalid path pattern %q: %w", pattern, err)
+		}
+	}
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	f.root = yamlnode.Clone(root)
+
+	usedBefore := usedTags(f.root)
+	reachableBefore := f.reachable()
+	f.operations = make(map[*yaml.Node]bool)
+	for _, section := range []string{"paths", "webhooks"} {
+		yamlnode.Pairs(yamlnode.Get(f.root, section), func(_, item *yaml.Node) {
+			f.operations[item] = hasOperations(item)
+		})
+	}
+
+	if len(f.extensions) > 0 {
+		f.removeMarked(f.root)
+	}
+	f.filterPaths("paths", true)
+	f.filterPaths("webhooks", false)
+	f.filterTags(usedBefore)
+	if !f.keepUnused {
+		f.prune(reachableBefore)
+	}
+	return f.root, nil
+}
+
+// removeMarked removes the mapping values and sequence elements below node that carry one of the
+// excluded extensions. Removed schema properties are dropped from required as well.
+func (f *filter) removeMarked(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if properties := yamlnode.Get(node, "properties"); properties != nil && properties.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(properties.Content); i += 2 {
+				if f.marked(properties.Content[i+1]) {
+					removeItem(yamlnode.Get(node, "required"), properties.Content[i].Value)
+				}
+			}
+		}
+		kept := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !f.marked(node.Content[i+1]) {
+				kept = append(kept, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = kept
+		for i := 1; i < len(node.Content); i += 2 {
+			f.removeMarked(node.Content[i])
+		}
+	case yaml.SequenceNode:
+		kept := node.Content[:0]
+		for _, child := range node.Content {
+			if !f.marked(child) {
+				kept = append(kept, child)
+			}
+		}
+		node.Content = kept
+		for _, child := range node.Content {
+			f.removeMarked(child)
+		}
+	}
+}
+
+// marked reports whether node carries one of the excluded extensions with a value other than false or null.
+func (f *filter) marked(node *yaml.Node) bool {
+	for _, name := range f.extensions {
+		value := yamlnode.Get(node, name)
+		if value == nil {
+			continue
+		}
+		if value.Kind != yaml.ScalarNode {
+			return true
+		}
+		switch strings.ToLower(value.Value) {
+		case "false", "null", "~", "":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// filterPaths removes the path items of section that do not match the path patterns and the operations
+// that do not match the tags. Path items left without operations are removed too.
+func (f *filter) filterPaths(section string, matchPaths bool) {
+	items := yamlnode.Get(f.root, section)
+	if items == nil || items.Kind != yaml.MappingNode {
+		return
+	}
+	kept := items.Content[:0]
+	for i := 0; i+1 < len(items.Content); i += 2 {
+		key, item := items.Content[i], items.Content[i+1]
+		if matchPaths && len(f.paths) > 0 && !glob.MatchAny(f.paths, key.Value) {
+			continue
+		}
+		if f.filterOperations(item) {
+			kept = append(kept, key, item)
+		}
+	}
+	items.Content = kept
+}
+
+// filterOperations removes the operations of a path item that do not match the tags, and reports
+// whether the path item is still needed. A path item that is a $ref is kept whole if any of its
+// operations matches.
+func (f *filter) filterOperations(item *yaml.Node) bool {
+	if yamlnode.Get(item, "$ref") != nil {
+		target := yamlnode.FollowRefs(f.root, item)
+		if !hasOperations(target) {
+			return true
+		}
+		for _, method := range methods {
+			if op := yamlnode.Get(target, method); op != nil && f.keep(op) {
+				return true
+			}
+		}
+		return false
+	}
+
+	kept := item.Content[:0]
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i], item.Content[i+1]
+		if slices.Contains(methods, key.Value) && !f.keep(value) {
+			continue
+		}
+		kept = append(kept, key, value)
+	}
+	item.Content = kept
+	return hasOperations(item) || !f.operations[item]
+}
+
+func hasOperations(item *yaml.Node) bool {
+	for _, method := range methods {
+		if yamlnode.Get(item, method) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// keep reports whether an operation matches the tags.
+func (f *filter) keep(op *yaml.Node) bool {
+	tags := operationTags(op)
+	for _, tag := range tags {
+		if slices.Contains(f.excludeTags, tag) {
+			return false
+		}
+	}
+	if len(f.tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(f.tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTags removes the tag definitions that were used by operations before filtering and no longer are,
+// and the excluded ones.
+func (f *filter) filterTags(usedBefore map[string]bool) {
+	tags := yamlnode.Get(f.root, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return
+	}
+	used := usedTags(f.root)
+	kept := tags.Content[:0]
+	for _, tag := range tags.Content {
+		name := yamlnode.Scalar(yamlnode.Get(tag, "name"))
+		if slices.Contains(f.excludeTags, name) || (usedBefore[name] && !used[name]) {
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	tags.Content = kept
+}
+
+// reachable returns the "section/name" of the components that something outside components refers to,
+// directly or through other components.
+func (f *filter) reachable() map[string]bool {
+	components := yamlnode.Get(f.root, "components")
+	reachable := make(map[string]bool)
+	type component struct {
+		section string
+		value   *yaml.Node
+	}
+	var queue []component
+	visit := func(ref string) {
+		tokens := strings.SplitN(strings.TrimPrefix(ref, "#/"), "/", 4)
+		if !strings.HasPrefix(ref, "#/") || len(tokens) < 3 || tokens[0] != "components" {
+			return
+		}
+		id := tokens[1] + "/" + yamlnode.Unescape(tokens[2])
+		if reachable[id] {
+			return
+		}
+		reachable[id] = true
+		if target := yamlnode.Get(yamlnode.Get(components, tokens[1]), yamlnode.Unescape(tokens[2])); target != nil {
+			queue = append(queue, component{tokens[1], target})
+		}
+	}
+
+	yamlnode.Pairs(f.root, func(key, value *yaml.Node) {
+		if key.Value != "components" {
+			references(value, yamlnode.Scope{}.Child(key.Value), visit)
+		}
+	})
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		references(c.value, yamlnode.Scope{Field: c.section}, visit)
+	}
+	return reachable
+}
+
+// prune removes the components that were reachable before filtering and no longer are. Components
+// that were never referenced are left alone. Sections left empty are removed.
+func (f *filter) prune(reachableBefore map[string]bool) {
+	components := yamlnode.Get(f.root, "components")
+	if components == nil {
+		return
+	}
+	reachable := f.reachable()
+
+	kept := components.Content[:0]
+	for i := 0; i+1 < len(components.Content); i += 2 {
+		section, entries := components.Content[i], components.Content[i+1]
+		if !strings.HasPrefix(section.Value, "x-") && entries.Kind == yaml.MappingNode {
+			used := entries.Content[:0]
+			for j := 0; j+1 < len(entries.Content); j += 2 {
+				id := section.Value + "/" + entries.Content[j].Value
+				if reachable[id] || !reachableBefore[id] {
+					used = append(used, entries.Content[j], entries.Content[j+1])
+				}
+			}
+			entries.Content = used
+			if len(entries.Content) == 0 {
+				continue
+			}
+		}
+		kept = append(kept, section, entries)
+	}
+	components.Content = kept
+	if len(components.Content) == 0 {
+		yamlnode.RemoveKey(f.root, "components")
+	}
+}
+
+// references calls fn with every internal reference below node: $refs, discriminator mappings and
+// security requirements, which name security schemes. The scope tells the keys of node apart from
+// property and component names.
+func references(node *yaml.Node, scope yamlnode.Scope, fn func(ref string)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case scope.Names:
+				references(value, scope.Child(key), fn)
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				fn(value.Value)
+			case scope.IsExample(key):
+				// Examples are data, where $ref has no meaning
+			case key == "discriminator":
+				yamlnode.Pairs(yamlnode.Get(value, "mapping"), func(_, target *yaml.Node) {
+					fn(target.Value)
+				})
+			case key == "security" && value.Kind == yaml.SequenceNode:
+				for _, requirement := range value.Content {
+					yamlnode.Pairs(requirement, func(name, _ *yaml.Node) {
+						fn("#/components/securitySchemes/" + yamlnode.Escape(name.Value))
+					})
+				}
+			default:
+				references(value, scope.Child(key), fn)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			references(child, scope.Item(), fn)
+		}
+	}
+}
+
+// usedTags returns the tags of the operations of the paths and webhooks.
+func usedTags(root *yaml.Node) map[string]bool {
+	used := make(map[string]bool)
+	for _, section := range []string{"paths", "webhooks"} {
+		yamlnode.Pairs(yamlnode.Get(root, section), func(_, item *yaml.Node) {
+			for _, method := range methods {
+				for _, tag := range operationTags(yamlnode.Get(item, method)) {
+					used[tag] = true
+				}
+			}
+		})
+	}
+	return used
+}
+
+func operationTags(op *yaml.Node) []string {
+	var tags []string
+	for _, tag := range yamlnode.Items(yamlnode.Get(op, "tags")) {
+		tags = append(tags, tag.Value)
+	}
+	return tags
+}
+
+// removeItem removes a scalar from a sequence node.
+func removeItem(seq *yaml.Node, value string) {
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return
+	}
+	seq.Content = slices.DeleteFunc(seq.Content, func(n *yaml.Node) bool {
+		return n.Kind == yaml.ScalarNode && n.Value == value
+	})
+}
@@ -0,0 +1,226 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+const spec = `openapi: 3.0.3
+info: {title: Shop, version: 1.0.0}
+security:
+  - apiKey: []
+tags:
+  - name: public
+  - name: admin
+  - name: unused
+paths:
+  /v1/items:
+    get:
+      tags: [public]
+      responses:
+        "200": {$ref: "#/components/responses/Items"}
+  /v2/items:
+    parameters:
+      - $ref: "#/components/parameters/Trace"
+    get:
+      tags: [public]
+      responses:
+        "200": {$ref: "#/components/responses/Items"}
+    post:
+      tags: [admin]
+      security: [{oauth: [write]}]
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewItem"}
+      responses:
+        "201": {description: created}
+  /v2/items/{id}:
+    get:
+      tags: [public]
+      x-internal: true
+      responses:
+        "200": {description: ok}
+  /v2/orders:
+    get:
+      tags: [public]
+      parameters:
+        - {name: debug, in: query, x-internal: true, schema: {type: boolean}}
+        - {name: page, in: query, x-internal: false, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+components:
+  parameters:
+    Trace: {name: X-Trace, in: header, schema: {type: string}}
+  responses:
+    Items:
+      description: ok
+      content:
+        application/json:
+          schema:
+            type: array
+            items: {$ref: "#/components/schemas/Item"}
+  schemas:
+    Item:
+      type: object
+      required: [id, cost]
+      properties:
+        id: {type: string}
+        cost: {type: number, x-internal: true}
+    NewItem:
+      type: object
+      properties:
+        item: {$ref: "#/components/schemas/Item"}
+        supplier: {$ref: "#/components/schemas/Supplier"}
+    Supplier: {type: object}
+    Order:
+      oneOf:
+        - $ref: "#/components/schemas/Item"
+      discriminator:
+        propertyName: kind
+        mapping:
+          item: "#/components/schemas/Item"
+    Library: {type: string}
+    Secret: {type: string, x-internal: true}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Key}
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials: {tokenUrl: /token, scopes: {write: Write}}
+`
+
+func parse(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func keys(node *yaml.Node) []string {
+	var out []string
+	yamlnode.Pairs(node, func(key, _ *yaml.Node) {
+		out = append(out, key.Value)
+	})
+	return out
+}
+
+func scalars(node *yaml.Node, key string) []string {
+	var out []string
+	for _, item := range yamlnode.Items(node) {
+		if key != "" {
+			item = yamlnode.Get(item, key)
+		}
+		out = append(out, item.Value)
+	}
+	return out
+}
+
+func TestNode(t *testing.T) {
+	root := parse(t, spec)
+	got, err := Node(root, WithTags("public"), WithPaths("/v2/**"), WithExcludeExtensions("x-internal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want []string
+	}{
+		{"paths", keys(yamlnode.Get(got, "paths")), []string{"/v2/items", "/v2/orders"}},
+		{"/v2/items", keys(yamlnode.Resolve(got, "/paths/~1v2~1items")), []string{"parameters", "get"}},
+		{"tags", scalars(yamlnode.Get(got, "tags"), "name"), []string{"public", "unused"}},
+		{"parameters", scalars(yamlnode.Resolve(got, "/paths/~1v2~1orders/get/parameters"), "name"), []string{"page"}},
+		{"schemas", keys(yamlnode.Resolve(got, "/components/schemas")), []string{"Item", "Order", "Library"}},
+		{"Item properties", keys(yamlnode.Resolve(got, "/components/schemas/Item/properties")), []string{"id"}},
+		{"Item required", scalars(yamlnode.Resolve(got, "/components/schemas/Item/required"), ""), []string{"id"}},
+		{"securitySchemes", keys(yamlnode.Resolve(got, "/components/securitySchemes")), []string{"apiKey"}},
+		{"components", keys(yamlnode.Get(got, "components")), []string{"parameters", "responses", "schemas", "securitySchemes"}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// The input is left as it was
+	if len(keys(yamlnode.Get(root, "paths"))) != 4 {
+		t.Error("Node() modified its input")
+	}
+}
+
+func TestNode_ExcludeTags(t *testing.T) {
+	got, err := Node(parse(t, spec), WithExcludeTags("public"), WithKeepUnused())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths := keys(yamlnode.Get(got, "paths")); !reflect.DeepEqual(paths, []string{"/v2/items"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	if tags := scalars(yamlnode.Get(got, "tags"), "name"); !reflect.DeepEqual(tags, []string{"admin", "unused"}) {
+		t.Errorf("tags = %v", tags)
+	}
+	if schemas := keys(yamlnode.Resolve(got, "/components/schemas")); len(schemas) != 6 {
+		t.Errorf("WithKeepUnused() pruned schemas: %v", schemas)
+	}
+}
+
+func TestNode_InvalidPattern(t *testing.T) {
+	if _, err := Node(parse(t, spec), WithPaths("/v2/[")); err == nil {
+		t.Error("Node() accepted a malformed pattern")
+	}
+}
+
+func TestNode_PropertyNamedExample(t *testing.T) {
+	src := `openapi: 3.0.3
+info: {title: Things, version: 1.0.0}
+paths:
+  /public:
+    get:
+      tags: [public]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Wrapper"}
+  /internal:
+    get:
+      tags: [internal]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                allOf: [{$ref: "#/components/schemas/Thing"}, {$ref: "#/components/schemas/Other"}]
+components:
+  schemas:
+    Wrapper:
+      type: object
+      properties:
+        example: {$ref: "#/components/schemas/Thing"}
+      example:
+        example: {$ref: "#/components/schemas/Other"}
+    Thing: {type: string}
+    Other: {type: string}
+`
+	got, err := Node(parse(t, src), WithTags("public"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Thing is still used by the property, Other is only named in an example
+	if schemas := keys(yamlnode.Resolve(got, "/components/schemas")); !reflect.DeepEqual(schemas, []string{"Wrapper", "Thing"}) {
+		t.Errorf("schemas = %v", schemas)
+	}
+}
@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// reportLevels maps lint severities to report levels.
+var reportLevels = map[Severity]report.Level{
+	SeverityError: report.LevelError,
+	SeverityWarn:  report.LevelWarning,
+	SeverityInfo:  report.LevelNote,
+	SeverityHint:  report.LevelNote,
+}
+
+// FormatText formats the lint result for display, one finding per line as file:line:col.
+func FormatText(result *Result) string {
+	var sb strings.Builder
+	for _, f := range result.Findings {
+		location := f.Pointer
+		if f.Line > 0 {
+			location = fmt.Sprintf("%d:%d", f.Line, f.Column)
+			if result.Source != "" {
+				location = result.Source + ":" + location
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s %s: %s (at %s)\n", location, f.Severity, f.RuleID, f.Message, f.Pointer))
+	}
+	if len(result.Findings) == 0 {
+		sb.WriteString("No lint problems found.\n")
+		return sb.String()
+	}
+	counts := make(map[Severity]int)
+	for _, f := range result.Findings {
+		counts[f.Severity]++
+	}
+	sb.WriteString(fmt.Sprintf("\n%d problems (%d errors, %d warnings, %d infos, %d hints)\n",
+		len(result.Findings), counts[SeverityError], counts[SeverityWarn], counts[SeverityInfo], counts[SeverityHint]))
+	return sb.String()
+}
+
+// FormatJSON formats the lint result as JSON.
+func FormatJSON(result *Result) ([]byte, error) {
+	out := *result
+	if out.Findings == nil {
+		out.Findings = []Finding{}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// ToReport converts a lint result for the machine-readable report formats. Rule descriptions
+// are taken from the ruleset, which may be nil.
+func ToReport(result *Result, ruleset *Ruleset) *report.Report {
+	r := &report.Report{Name: "lint"}
+	if ruleset != nil {
+		for _, rule := range ruleset.Rules {
+			if rule.Severity != SeverityOff {
+				r.Rules = append(r.Rules, report.Rule{ID: rule.ID, Name: rule.ID, Description: rule.Description})
+			}
+		}
+	}
+	for _, f := range result.Findings {
+		r.Issues = append(r.Issues, report.Issue{
+			RuleID:  f.RuleID,
+			Level:   reportLevels[f.Severity],
+			Message: f.Message,
+			File:    result.Source,
+			Line:    f.Line,
+			Column:  f.Column,
+			Pointer: f.Pointer,
+		})
+	}
+	return r
+}
@@ -0,0 +1,312 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/internal/yamlschema"
+)
+
+// target is the value a rule function checks. node is nil when the field is not defined.
+type target struct {
+	node     *yaml.Node
+	property string
+}
+
+// function checks a target and returns a message for every problem it finds.
+type function func(root *yaml.Node, t target) []string
+
+// functionFactory builds a function from the functionOptions of a rule, reporting invalid options.
+type functionFactory func(options *yaml.Node) (function, error)
+
+// functions lists the rule functions available to rulesets by name.
+var functions = map[string]functionFactory{
+	"truthy":      newTruthy,
+	"falsy":       newFalsy,
+	"defined":     newDefined,
+	"undefined":   newUndefined,
+	"pattern":     newPattern,
+	"casing":      newCasing,
+	"enumeration": newEnumeration,
+	"length":      newLength,
+	"schema":      newSchema,
+}
+
+// isTruthy reports whether a value is truthy in the JavaScript sense that Spectral rulesets assume.
+func isTruthy(node *yaml.Node) bool {
+	if node == nil {
+		return false
+	}
+	if node.Kind != yaml.ScalarNode {
+		return true
+	}
+	switch node.Tag {
+	case "!!null":
+		return false
+	case "!!bool":
+		return node.Value == "true"
+	case "!!int", "!!float":
+		f, err := strconv.ParseFloat(node.Value, 64)
+		return err != nil || f != 0
+	}
+	return node.Value != ""
+}
+
+func newTruthy(*yaml.Node) (function, error) {
+	return func(_ *yaml.Node, t target) []string {
+		if !isTruthy(t.node) {
+			return []string{fmt.Sprintf("%q property must be truthy", t.property)}
+		}
+		return nil
+	}, nil
+}
+
+func newFalsy(*yaml.Node) (function, error) {
+	return func(_ *yaml.Node, t target) []string {
+		if isTruthy(t.node) {
+			return []string{fmt.Sprintf("%q property must be falsy", t.property)}
+		}
+		return nil
+	}, nil
+}
+
+func newDefined(*yaml.Node) (function, error) {
+	return func(_ *yaml.Node, t target) []string {
+		if t.node == nil {
+			return []string{fmt.Sprintf("%q property must be defined", t.property)}
+		}
+		return nil
+	}, nil
+}
+
+func newUndefined(*yaml.Node) (function, error) {
+	return func(_ *yaml.Node, t target) []string {
+		if t.node != nil {
+			return []string{fmt.Sprintf("%q property must be undefined", t.property)}
+		}
+		return nil
+	}, nil
+}
+
+type patternOptions struct {
+	Match    string `yaml:"match"`
+	NotMatch string `yaml:"notMatch"`
+}
+
+func newPattern(options *yaml.Node) (function, error) {
+	var opts patternOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	if opts.Match == "" && opts.NotMatch == "" {
+		return nil, fmt.Errorf("pattern needs match or notMatch")
+	}
+	match, err := compilePattern(opts.Match)
+	if err != nil {
+		return nil, err
+	}
+	notMatch, err := compilePattern(opts.NotMatch)
+	if err != nil {
+		return nil, err
+	}
+	return func(_ *yaml.Node, t target) []string {
+		value, ok := scalarValue(t.node)
+		if !ok {
+			return nil
+		}
+		var problems []string
+		if match != nil && !match.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%q must match the pattern %q", value, opts.Match))
+		}
+		if notMatch != nil && notMatch.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%q must not match the pattern %q", value, opts.NotMatch))
+		}
+		return problems
+	}, nil
+}
+
+// compilePattern compiles a regular expression, accepting the /pattern/flags form of JavaScript.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if end := strings.LastIndex(pattern, "/"); strings.HasPrefix(pattern, "/") && end > 0 {
+		flags := strings.ReplaceAll(pattern[end+1:], "g", "")
+		pattern = pattern[1:end]
+		if flags != "" {
+			pattern = "(?" + flags + ")" + pattern
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// casingPatterns match the casing types, with and without digits.
+var casingPatterns = map[string][2]string{
+	"flat":   {`^[a-z][a-z0-9]*$`, `^[a-z]+$`},
+	"camel":  {`^[a-z][a-z0-9]*(?:[A-Z0-9][a-z0-9]*)*$`, `^[a-z]+(?:[A-Z][a-z]+)*[A-Z]?$`},
+	"pascal": {`^[A-Z][a-z0-9]*(?:[A-Z0-9][a-z0-9]*)*$`, `^[A-Z][a-z]+(?:[A-Z][a-z]+)*[A-Z]?$`},
+	"kebab":  {`^[a-z][a-z0-9]*(?:-[a-z0-9]+)*$`, `^[a-z]+(?:-[a-z]+)*$`},
+	"cobol":  {`^[A-Z][A-Z0-9]*(?:-[A-Z0-9]+)*$`, `^[A-Z]+(?:-[A-Z]+)*$`},
+	"snake":  {`^[a-z][a-z0-9]*(?:_[a-z0-9]+)*$`, `^[a-z]+(?:_[a-z]+)*$`},
+	"macro":  {`^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*$`, `^[A-Z]+(?:_[A-Z]+)*$`},
+}
+
+type casingOptions struct {
+	Type           string `yaml:"type"`
+	DisallowDigits bool   `yaml:"disallowDigits"`
+}
+
+func newCasing(options *yaml.Node) (function, error) {
+	var opts casingOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	patterns, ok := casingPatterns[opts.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown casing type %q", opts.Type)
+	}
+	re := regexp.MustCompile(patterns[0])
+	if opts.DisallowDigits {
+		re = regexp.MustCompile(patterns[1])
+	}
+	return func(_ *yaml.Node, t target) []string {
+		value, ok := scalarValue(t.node)
+		if !ok || value == "" || re.MatchString(value) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%q must be %s case", value, opts.Type)}
+	}, nil
+}
+
+type enumerationOptions struct {
+	Values     []string `yaml:"values"`
+	ValuesFrom string   `yaml:"valuesFrom"`
+}
+
+// newEnumeration checks that a value is one of a fixed list, or, with valuesFrom, one of the
+// scalars a JSONPath selects from the document.
+func newEnumeration(options *yaml.Node) (function, error) {
+	var opts enumerationOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	var from *path
+	if opts.ValuesFrom != "" {
+		var err error
+		if from, err = compilePath(opts.ValuesFrom); err != nil {
+			return nil, err
+		}
+	} else if len(opts.Values) == 0 {
+		return nil, fmt.Errorf("enumeration needs values or valuesFrom")
+	}
+	return func(root *yaml.Node, t target) []string {
+		value, ok := scalarValue(t.node)
+		if !ok {
+			return nil
+		}
+		values := opts.Values
+		if from != nil {
+			values = nil
+			for _, m := range from.find(root) {
+				values = append(values, yamlnode.Scalar(m.node))
+			}
+		}
+		if contains(values, value) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%q must be one of: %s", value, strings.Join(values, ", "))}
+	}, nil
+}
+
+type lengthOptions struct {
+	Min *int `yaml:"min"`
+	Max *int `yaml:"max"`
+}
+
+// newLength checks the length of strings, the number of items of sequences and the number of
+// properties of mappings.
+func newLength(options *yaml.Node) (function, error) {
+	var opts lengthOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	if opts.Min == nil && opts.Max == nil {
+		return nil, fmt.Errorf("length needs min or max")
+	}
+	return func(_ *yaml.Node, t target) []string {
+		if t.node == nil {
+			return nil
+		}
+		n := length(t.node)
+		switch {
+		case opts.Min != nil && n < *opts.Min:
+			return []string{fmt.Sprintf("%q must be at least %d long", t.property, *opts.Min)}
+		case opts.Max != nil && n > *opts.Max:
+			return []string{fmt.Sprintf("%q must be at most %d long", t.property, *opts.Max)}
+		}
+		return nil
+	}, nil
+}
+
+func length(node *yaml.Node) int {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return len(node.Content) / 2
+	case yaml.SequenceNode:
+		return len(node.Content)
+	}
+	return len([]rune(node.Value))
+}
+
+type schemaOptions struct {
+	Schema yaml.Node `yaml:"schema"`
+}
+
+func newSchema(options *yaml.Node) (function, error) {
+	var opts schemaOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	if opts.Schema.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("schema needs a schema object")
+	}
+	schema := &opts.Schema
+	return func(_ *yaml.Node, t target) []string {
+		if t.node == nil {
+			return nil
+		}
+		// The schema is its own root, so its $refs point into the schema rather than the document
+		if msg := yamlschema.Match(schema, schema, t.node); msg != "" {
+			return []string{msg}
+		}
+		return nil
+	}, nil
+}
+
+// decodeOptions decodes functionOptions into the options struct of a function.
+func decodeOptions(options *yaml.Node, out any) error {
+	if options == nil || options.Kind == 0 {
+		return nil
+	}
+	if err := options.Decode(out); err != nil {
+		return fmt.Errorf("invalid functionOptions: %w", err)
+	}
+	return nil
+}
+
+// scalarValue returns the value of a scalar node; functions that check strings skip other values.
+func scalarValue(node *yaml.Node) (string, bool) {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return "", false
+	}
+	return node.Value, true
+}
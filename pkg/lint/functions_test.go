@@ -0,0 +1,120 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// node parses a YAML value.
+func node(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	n, err := yamlnode.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// build compiles a function from YAML options.
+func build(t *testing.T, name, options string) function {
+	t.Helper()
+	var opts *yaml.Node
+	if options != "" {
+		opts = node(t, options)
+	}
+	fn, err := functions[name](opts)
+	if err != nil {
+		t.Fatalf("%s(%s): %v", name, options, err)
+	}
+	return fn
+}
+
+func TestFunctions(t *testing.T) {
+	root := node(t, "tags: [{name: pets}, {name: store}]")
+	tests := []struct {
+		function, options, value string
+		ok                       bool
+	}{
+		{"truthy", "", "yes", true},
+		{"truthy", "", `""`, false},
+		{"truthy", "", "0", false},
+		{"truthy", "", "false", false},
+		{"truthy", "", "null", false},
+		{"truthy", "", "[]", true},
+		{"falsy", "", "false", true},
+		{"falsy", "", "x", false},
+		{"defined", "", "null", true},
+		{"undefined", "", "x", false},
+		{"pattern", "match: ^/v[0-9]+", "/v1/pets", true},
+		{"pattern", "match: ^/v[0-9]+", "/pets", false},
+		{"pattern", "match: /^PETS$/i", "pets", true},
+		{"pattern", "notMatch: /$", "/pets/", false},
+		{"casing", "type: camel", "listPets", true},
+		{"casing", "type: camel", "ListPets", false},
+		{"casing", "type: pascal", "ListPets", true},
+		{"casing", "type: kebab", "list-pets", true},
+		{"casing", "type: kebab", "list_pets", false},
+		{"casing", "type: snake", "list_pets2", true},
+		{"casing", "{type: snake, disallowDigits: true}", "list_pets2", false},
+		{"casing", "type: macro", "LIST_PETS", true},
+		{"casing", "type: cobol", "LIST-PETS", true},
+		{"casing", "type: flat", "listpets", true},
+		{"enumeration", "values: [a, b]", "b", true},
+		{"enumeration", "values: [a, b]", "c", false},
+		{"enumeration", "valuesFrom: $.tags[*].name", "store", true},
+		{"enumeration", "valuesFrom: $.tags[*].name", "users", false},
+		{"length", "min: 2", "ab", true},
+		{"length", "min: 2", "[a]", false},
+		{"length", "max: 1", "{a: 1, b: 2}", false},
+		{"schema", "schema: {type: object, required: [id]}", "{id: 1}", true},
+		{"schema", "schema: {type: object, required: [id]}", "{name: x}", false},
+	}
+	for _, tt := range tests {
+		fn := build(t, tt.function, tt.options)
+		problems := fn(root, target{node: node(t, tt.value), property: "field"})
+		if (len(problems) == 0) != tt.ok {
+			t.Errorf("%s(%s) on %s: got %v, want ok=%v", tt.function, tt.options, tt.value, problems, tt.ok)
+		}
+	}
+}
+
+func TestFunctionsUndefinedTarget(t *testing.T) {
+	missing := target{property: "description"}
+	if problems := build(t, "truthy", "")(nil, missing); len(problems) != 1 || !strings.Contains(problems[0], `"description"`) {
+		t.Errorf("truthy on a missing field: got %v", problems)
+	}
+	if problems := build(t, "defined", "")(nil, missing); len(problems) != 1 {
+		t.Errorf("defined on a missing field: got %v", problems)
+	}
+	// Functions that check values ignore fields that are not there
+	for _, fn := range []function{build(t, "pattern", "match: x"), build(t, "casing", "type: camel"), build(t, "schema", "schema: {type: string}")} {
+		if problems := fn(nil, missing); problems != nil {
+			t.Errorf("got %v for a missing field", problems)
+		}
+	}
+}
+
+func TestFunctionOptionErrors(t *testing.T) {
+	tests := []struct{ function, options string }{
+		{"pattern", ""},
+		{"pattern", "match: '['"},
+		{"casing", "type: shouty"},
+		{"enumeration", ""},
+		{"enumeration", "valuesFrom: tags"},
+		{"length", ""},
+		{"schema", ""},
+	}
+	for _, tt := range tests {
+		var opts *yaml.Node
+		if tt.options != "" {
+			opts = node(t, tt.options)
+		}
+		if _, err := functions[tt.function](opts); err == nil {
+			t.Errorf("%s(%s) should fail", tt.function, tt.options)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// path is a compiled JSONPath expression. The supported subset is what rulesets need to select
+// parts of an OpenAPI document:
+//
+//	$                 the document root
+//	.name ['name']    a mapping key
+//	.* [*]            every value of a mapping or item of a sequence
+//	[0]               a sequence item
+//	[get,put] ['a','b'] a union of keys
+//	..name ..*        recursive descent
+type path struct {
+	expr     string
+	segments []segment
+}
+
+type segment struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+}
+
+// match is a node selected by a path, with the key it is stored under (nil for the root and
+// sequence items) and its JSON pointer.
+type match struct {
+	node    *yaml.Node
+	key     *yaml.Node
+	pointer string
+}
+
+func compilePath(expr string) (*path, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+	p := &path{expr: expr}
+	rest := expr[1:]
+	for rest != "" {
+		seg, remaining, err := parseSegment(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+		}
+		p.segments = append(p.segments, seg)
+		rest = remaining
+	}
+	return p, nil
+}
+
+func parseSegment(s string) (segment, string, error) {
+	var seg segment
+	switch {
+	case strings.HasPrefix(s, ".."):
+		seg.recursive = true
+		s = s[2:]
+	case strings.HasPrefix(s, "."):
+		s = s[1:]
+	case strings.HasPrefix(s, "["):
+	default:
+		return seg, "", fmt.Errorf("unexpected %q", s)
+	}
+	if strings.HasPrefix(s, "[") {
+		return parseBracket(seg, s)
+	}
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		end = len(s)
+	}
+	name := s[:end]
+	if name == "" {
+		return seg, "", fmt.Errorf("empty name")
+	}
+	if name == "*" {
+		seg.wildcard = true
+	} else {
+		seg.names = []string{name}
+	}
+	return seg, s[end:], nil
+}
+
+// parseBracket parses "[...]" at the start of s into seg.
+func parseBracket(seg segment, s string) (segment, string, error) {
+	end := closingBracket(s)
+	if end < 0 {
+		return seg, "", fmt.Errorf("unclosed [")
+	}
+	body := strings.TrimSpace(s[1:end])
+	if strings.HasPrefix(body, "?") || strings.HasPrefix(body, "(") {
+		return seg, "", fmt.Errorf("filter and script expressions are not supported")
+	}
+	if body == "*" {
+		seg.wildcard = true
+		return seg, s[end+1:], nil
+	}
+	for _, item := range splitUnion(body) {
+		name := strings.TrimSpace(item)
+		if len(name) >= 2 && (name[0] == '\'' || name[0] == '"') && name[len(name)-1] == name[0] {
+			name = name[1 : len(name)-1]
+		}
+		if name == "" {
+			return seg, "", fmt.Errorf("empty name in [%s]", body)
+		}
+		seg.names = append(seg.names, name)
+	}
+	return seg, s[end+1:], nil
+}
+
+// closingBracket returns the index of the "]" closing the "[" at s[0], skipping quoted names.
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == quote:
+			quote = 0
+		case quote != 0:
+		case s[i] == '\'' || s[i] == '"':
+			quote = s[i]
+		case s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnion splits a bracket body on commas outside quotes.
+func splitUnion(body string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(body); i++ {
+		switch {
+		case quote != 0 && body[i] == quote:
+			quote = 0
+		case quote != 0:
+		case body[i] == '\'' || body[i] == '"':
+			quote = body[i]
+		case body[i] == ',':
+			items = append(items, body[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, body[start:])
+}
+
+// find returns the nodes selected by the path, in document order.
+func (p *path) find(root *yaml.Node) []match {
+	current := []match{{node: root}}
+	for _, seg := range p.segments {
+		var next []match
+		for _, m := range current {
+			if seg.recursive {
+				for _, d := range descendants(m) {
+					next = append(next, seg.children(d)...)
+				}
+				continue
+			}
+			next = append(next, seg.children(m)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// children returns the children of m selected by the segment.
+func (seg segment) children(m match) []match {
+	var out []match
+	node := m.node
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		yamlnode.Pairs(node, func(key, value *yaml.Node) {
+			if seg.wildcard || contains(seg.names, key.Value) {
+				out = append(out, match{node: value, key: key, pointer: yamlnode.Join(m.pointer, key.Value)})
+			}
+		})
+	case yaml.SequenceNode:
+		for i, item := range yamlnode.Items(node) {
+			if seg.wildcard || contains(seg.names, strconv.Itoa(i)) {
+				out = append(out, match{node: item, pointer: yamlnode.Index(m.pointer, i)})
+			}
+		}
+	}
+	return out
+}
+
+// descendants returns m and every node below it, visiting anchored nodes once.
+func descendants(m match) []match {
+	var out []match
+	seen := make(map[*yaml.Node]bool)
+	var visit func(m match)
+	visit = func(m match) {
+		if seen[m.node] {
+			return
+		}
+		seen[m.node] = true
+		out = append(out, m)
+		for _, child := range (segment{wildcard: true}).children(m) {
+			visit(child)
+		}
+	}
+	visit(m)
+	return out
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+const pathDoc = `openapi: 3.1.0
+tags:
+  - name: pets
+  - name: store
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: createPet
+    parameters: []
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+components:
+  schemas:
+    Pet:
+      properties:
+        name: {type: string}
+`
+
+func pointers(t *testing.T, expr string) []string {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(pathDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := compilePath(expr)
+	if err != nil {
+		t.Fatalf("compilePath(%q): %v", expr, err)
+	}
+	var out []string
+	for _, m := range p.find(root) {
+		out = append(out, m.pointer)
+	}
+	return out
+}
+
+func TestPathFind(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"$", []string{""}},
+		{"$.openapi", []string{"/openapi"}},
+		{"$['openapi']", []string{"/openapi"}},
+		{"$.tags[*].name", []string{"/tags/0/name", "/tags/1/name"}},
+		{"$.tags[1]", []string{"/tags/1"}},
+		{"$.paths.*", []string{"/paths/~1pets", "/paths/~1pets~1{id}"}},
+		{"$.paths[*][get,delete]", []string{"/paths/~1pets/get", "/paths/~1pets~1{id}/delete"}},
+		{"$.paths['/pets'][\"post\"].operationId", []string{"/paths/~1pets/post/operationId"}},
+		{"$..operationId", []string{"/paths/~1pets/get/operationId", "/paths/~1pets/post/operationId", "/paths/~1pets~1{id}/delete/operationId"}},
+		{"$.components..type", []string{"/components/schemas/Pet/properties/name/type"}},
+		{"$.missing.*", nil},
+	}
+	for _, tt := range tests {
+		if got := pointers(t, tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompilePathErrors(t *testing.T) {
+	for _, expr := range []string{"paths", "$.paths[?(@.get)]", "$.paths[", "$.", "$x"} {
+		if _, err := compilePath(expr); err == nil {
+			t.Errorf("compilePath(%q) should fail", expr)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package lint checks OpenAPI documents against configurable, Spectral-style rulesets.
+//
+// A rule selects parts of the document with JSONPath "given" expressions and checks them, or one
+// of their fields, with a built-in function such as truthy, pattern, casing, enumeration or schema.
+package lint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// Finding is a rule violation.
+type Finding struct {
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Pointer  string   `json:"pointer"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+}
+
+// Result holds the findings of a lint run, ordered by position.
+type Result struct {
+	Source   string    `json:"source,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings at least as severe as sev.
+func (r *Result) Count(sev Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(sev) {
+			n++
+		}
+	}
+	return n
+}
+
+// Linter lints OpenAPI documents against a ruleset.
+type Linter struct {
+	ruleset *Ruleset
+}
+
+// New creates a linter for the ruleset, or for the recommended ruleset if it is nil.
+func New(ruleset *Ruleset) *Linter {
+	if ruleset == nil {
+		ruleset = Recommended()
+	}
+	return &Linter{ruleset: ruleset}
+}
+
+// LintFile lints an OpenAPI document file.
+func (l *Linter) LintFile(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return l.lintSource(data, path)
+}
+
+// LintURL lints an OpenAPI document fetched from a URL.
+func (l *Linter) LintURL(url string) (*Result, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return l.lintSource(data, url)
+}
+
+func (l *Linter) lintSource(data []byte, source string) (*Result, error) {
+	result, err := l.LintData(data)
+	if result != nil {
+		result.Source = source
+	}
+	return result, err
+}
+
+// LintData lints OpenAPI document bytes (JSON or YAML).
+func (l *Linter) LintData(data []byte) (*Result, error) {
+	root, err := yamlnode.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	result := &Result{Findings: []Finding{}}
+	for _, rule := range l.ruleset.Rules {
+		if rule.Severity != SeverityOff {
+			result.Findings = append(result.Findings, rule.run(root)...)
+		}
+	}
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return result, nil
+}
+
+// run applies the rule to every node its given paths select.
+func (r *Rule) run(root *yaml.Node) []Finding {
+	var findings []Finding
+	for _, p := range r.paths {
+		for _, m := range p.find(root) {
+			for _, c := range r.checks {
+				for _, t := range fieldTargets(m, c.field) {
+					for _, problem := range c.function(root, t.target) {
+						findings = append(findings, r.finding(root, t, problem))
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// fieldTarget is a target with the JSON pointer of its field.
+type fieldTarget struct {
+	target
+	pointer string
+}
+
+// fieldTargets returns what a then clause checks on a match: the match itself, a dotted field
+// path below it, or with "@key" the keys of a mapping (or the key the match is stored under).
+func fieldTargets(m match, field string) []fieldTarget {
+	switch field {
+	case "":
+		return []fieldTarget{{target{node: m.node, property: propertyName(m)}, m.pointer}}
+	case "@key":
+		return keyTargets(m)
+	}
+	node, pointer := m.node, m.pointer
+	parts := strings.Split(field, ".")
+	for _, part := range parts {
+		pointer = yamlnode.Join(pointer, part)
+		node = yamlnode.Get(node, part)
+	}
+	return []fieldTarget{{target{node: node, property: parts[len(parts)-1]}, pointer}}
+}
+
+func keyTargets(m match) []fieldTarget {
+	var targets []fieldTarget
+	yamlnode.Pairs(m.node, func(key, _ *yaml.Node) {
+		targets = append(targets, fieldTarget{target{node: key, property: key.Value}, yamlnode.Join(m.pointer, key.Value)})
+	})
+	if targets == nil && m.key != nil {
+		targets = append(targets, fieldTarget{target{node: m.key, property: m.key.Value}, m.pointer})
+	}
+	return targets
+}
+
+// propertyName returns the key or index a match is stored under.
+func propertyName(m match) string {
+	if m.key != nil {
+		return m.key.Value
+	}
+	if i := strings.LastIndex(m.pointer, "/"); i >= 0 {
+		return yamlnode.Unescape(m.pointer[i+1:])
+	}
+	return "$"
+}
+
+func (r *Rule) finding(root *yaml.Node, t fieldTarget, problem string) Finding {
+	f := Finding{RuleID: r.ID, Severity: r.Severity, Message: r.message(t, problem), Pointer: t.pointer}
+	if f.Pointer == "" {
+		f.Pointer = "/"
+	}
+	// A missing field is reported at the closest node that exists
+	for pointer := t.pointer; f.Line == 0; pointer = parentPointer(pointer) {
+		f.Line, f.Column = yamlnode.Position(root, pointer)
+		if pointer == "" {
+			break
+		}
+	}
+	return f
+}
+
+func parentPointer(pointer string) string {
+	if i := strings.LastIndex(pointer, "/"); i >= 0 {
+		return pointer[:i]
+	}
+	return ""
+}
+
+// message renders the rule's message template. The placeholders are {{error}}, {{value}},
+// {{path}}, {{property}} and {{description}}.
+func (r *Rule) message(t fieldTarget, problem string) string {
+	template := r.Message
+	if template == "" {
+		template = "{{error}}"
+	}
+	value := ""
+	if t.node != nil && t.node.Kind == yaml.ScalarNode {
+		value = t.node.Value
+	}
+	return strings.NewReplacer(
+		"{{error}}", problem,
+		"{{value}}", value,
+		"{{path}}", t.pointer,
+		"{{property}}", t.property,
+		"{{description}}", r.Description,
+	).Replace(template)
+}
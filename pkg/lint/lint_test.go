@@ -0,0 +1,221 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+const lintSpec = `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+tags:
+  - name: users
+paths:
+  /users/:
+    get:
+      operationId: List_Users
+      tags: [users, admin]
+      responses:
+        "200": {description: ok}
+  /userGroups:
+    post:
+      operationId: createGroup
+      summary: Create a group
+      tags: [users]
+      responses:
+        "200": {description: ok}
+`
+
+// findRule returns the findings of a rule.
+func findRule(result *Result, id string) []Finding {
+	var out []Finding
+	for _, f := range result.Findings {
+		if f.RuleID == id {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestRecommended(t *testing.T) {
+	result, err := New(nil).LintData([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rule    string
+		pointer string
+		line    int
+		message string
+	}{
+		{"info-description", "/info/description", 2, `"description" property must be truthy`},
+		{"path-no-trailing-slash", "/paths/~1users~1", 8, "Path /users/ must not end with a slash"},
+		{"operation-description", "/paths/~1users~1/get", 9, "Operation must have a description or a summary."},
+		{"operation-operationId-casing", "/paths/~1users~1/get/operationId", 10, `operationId "List_Users" must be camel case`},
+		{"operation-tag-defined", "/paths/~1users~1/get/tags/1", 11, `Operation tag "admin" must be one of: users`},
+		{"paths-kebab-case", "/paths/~1userGroups", 14, "Path /userGroups should be kebab-case"},
+	}
+	for _, tt := range tests {
+		findings := findRule(result, tt.rule)
+		if len(findings) != 1 {
+			t.Errorf("%s: got %d findings %v, want 1", tt.rule, len(findings), findings)
+			continue
+		}
+		f := findings[0]
+		if f.Pointer != tt.pointer || f.Line != tt.line || f.Message != tt.message || f.Severity != SeverityWarn {
+			t.Errorf("%s: got %+v", tt.rule, f)
+		}
+	}
+	if len(result.Findings) != len(tests) {
+		t.Errorf("got %d findings, want %d: %+v", len(result.Findings), len(tests), result.Findings)
+	}
+	for i := 1; i < len(result.Findings); i++ {
+		if result.Findings[i].Line < result.Findings[i-1].Line {
+			t.Errorf("findings are not ordered by line: %+v", result.Findings)
+		}
+	}
+}
+
+func TestRecommended_OperationTags(t *testing.T) {
+	spec := `openapi: 3.0.3
+info: {title: Test, version: "1.0", description: Tags}
+tags:
+  - name: users
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      summary: List users
+      responses:
+        "200": {description: ok}
+    post:
+      operationId: createUser
+      summary: Create a user
+      tags: []
+      responses:
+        "200": {description: ok}
+`
+	result, err := New(nil).LintData([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := findRule(result, "operation-tags")
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings %+v, want 2", len(findings), findings)
+	}
+	if f := findings[0]; f.Pointer != "/paths/~1users/get/tags" || f.Message != `"tags" property must be defined` {
+		t.Errorf("untagged operation: got %+v", f)
+	}
+	if f := findings[1]; f.Pointer != "/paths/~1users/post/tags" || f.Message != `"tags" must be at least 1 long` {
+		t.Errorf("empty tags: got %+v", f)
+	}
+}
+
+func TestRulesetExtendsAndOverrides(t *testing.T) {
+	dir := t.TempDir()
+	base := `rules:
+  contact-email:
+    description: The API must list a contact email
+    message: "{{description}} ({{property}} at {{path}})"
+    severity: error
+    given: $.info
+    then:
+      field: contact.email
+      function: truthy
+`
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	custom := `extends: [yaswag:recommended, base.yaml]
+rules:
+  paths-kebab-case: off
+  operation-tag-defined: error
+`
+	path := filepath.Join(dir, "ruleset.yaml")
+	if err := os.WriteFile(path, []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := LoadRuleset(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := New(rs).LintData([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := findRule(result, "paths-kebab-case"); len(got) != 0 {
+		t.Errorf("disabled rule reported %+v", got)
+	}
+	if got := findRule(result, "operation-tag-defined"); len(got) != 1 || got[0].Severity != SeverityError {
+		t.Errorf("overridden severity: got %+v", got)
+	}
+	got := findRule(result, "contact-email")
+	if len(got) != 1 || got[0].Message != "The API must list a contact email (email at /info/contact/email)" || got[0].Line != 2 {
+		t.Errorf("contact-email: got %+v", got)
+	}
+	if result.Count(SeverityError) != 2 {
+		t.Errorf("Count(error) = %d, want 2", result.Count(SeverityError))
+	}
+	// Overrides must not leak into the recommended ruleset
+	if Recommended().Rule("operation-tag-defined").Severity != SeverityWarn {
+		t.Error("override changed the recommended ruleset")
+	}
+}
+
+func TestRulesetErrors(t *testing.T) {
+	tests := []struct {
+		name, ruleset, want string
+	}{
+		{"unknown function", "rules:\n  r:\n    given: $\n    then: {function: nope}\n", `rule "r": unknown function "nope"`},
+		{"bad path", "rules:\n  r:\n    given: paths\n    then: {function: truthy}\n", "must start with $"},
+		{"bad options", "rules:\n  r:\n    given: $\n    then: {function: casing, functionOptions: {type: x}}\n", "unknown casing type"},
+		{"bad severity", "rules:\n  r:\n    severity: fatal\n    given: $\n    then: {function: truthy}\n", "unknown severity"},
+		{"missing then", "rules:\n  r:\n    given: $\n", "then is required"},
+		{"unknown override", "rules:\n  r: off\n", "no inherited rule"},
+		{"unknown extends", "extends: spectral:oas\n", "unknown ruleset"},
+	}
+	for _, tt := range tests {
+		_, err := ParseRuleset([]byte(tt.ruleset), ".")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRulesetExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.yaml")
+	if err := os.WriteFile(path, []byte("extends: a.yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRuleset(path); err == nil || !strings.Contains(err.Error(), "extends itself") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestToReport(t *testing.T) {
+	rs := Recommended()
+	result, err := New(rs).LintData([]byte(lintSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Source = "api.yaml"
+	r := ToReport(result, rs)
+	if r.Name != "lint" || len(r.Rules) != len(rs.Rules) || len(r.Issues) != len(result.Findings) {
+		t.Fatalf("got %+v", r)
+	}
+	issue := r.Issues[0]
+	if issue.File != "api.yaml" || issue.Level != report.LevelWarning || issue.Line != 2 {
+		t.Errorf("got %+v", issue)
+	}
+	text := FormatText(result)
+	if !strings.Contains(text, "api.yaml:2:1: warn info-description:") || !strings.Contains(text, "6 problems") {
+		t.Errorf("unexpected text output:\n%s", text)
+	}
+}
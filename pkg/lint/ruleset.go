@@ -0,0 +1,299 @@
+package lint
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RecommendedRuleset is the name extends uses for the built-in ruleset.
+const RecommendedRuleset = "yaswag:recommended"
+
+// DefaultRulesetFile is the ruleset the lint command picks up from the working directory.
+const DefaultRulesetFile = ".yaswag-lint.yaml"
+
+//go:embed rulesets/recommended.yaml
+var recommendedYAML []byte
+
+// Severity is the severity of a rule and of its findings.
+type Severity string
+
+const (
+	SeverityError Severity = "error"
+	SeverityWarn  Severity = "warn"
+	SeverityInfo  Severity = "info"
+	SeverityHint  Severity = "hint"
+	SeverityOff   Severity = "off"
+)
+
+// severityRanks orders severities from most to least severe.
+var severityRanks = map[Severity]int{
+	SeverityError: 4,
+	SeverityWarn:  3,
+	SeverityInfo:  2,
+	SeverityHint:  1,
+	SeverityOff:   0,
+}
+
+// ParseSeverity parses a severity name. "warning" is accepted for "warn".
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToLower(s))
+	if sev == "warning" {
+		sev = SeverityWarn
+	}
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("unknown severity %q (use error, warn, info, hint or off)", s)
+	}
+	return sev, nil
+}
+
+// AtLeast reports whether s is as severe as other or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRanks[s] >= severityRanks[other]
+}
+
+// Rule is a lint rule: the nodes selected by its given paths are checked by each of its then clauses.
+type Rule struct {
+	ID          string
+	Description string
+	Message     string
+	Severity    Severity
+	Given       []string
+
+	paths  []*path
+	checks []check
+}
+
+// check is a compiled then clause.
+type check struct {
+	field    string
+	function function
+}
+
+// Ruleset is an ordered set of lint rules.
+type Ruleset struct {
+	Rules []*Rule
+}
+
+// Recommended returns the built-in ruleset.
+func Recommended() *Ruleset {
+	rs, err := parseRuleset(recommendedYAML, "", nil)
+	if err != nil {
+		panic(fmt.Sprintf("lint: invalid recommended ruleset: %v", err))
+	}
+	return rs
+}
+
+// LoadRuleset reads a ruleset file. Relative extends paths are resolved against the file's directory.
+func LoadRuleset(path string) (*Ruleset, error) {
+	return loadRuleset(path, map[string]bool{})
+}
+
+func loadRuleset(path string, loading map[string]bool) (*Ruleset, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if loading[abs] {
+		return nil, fmt.Errorf("ruleset %s extends itself", path)
+	}
+	loading[abs] = true
+	defer delete(loading, abs)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ruleset: %w", err)
+	}
+	rs, err := parseRuleset(data, filepath.Dir(abs), loading)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// ParseRuleset parses a ruleset document. Relative extends paths are resolved against dir.
+func ParseRuleset(data []byte, dir string) (*Ruleset, error) {
+	return parseRuleset(data, dir, map[string]bool{})
+}
+
+type rawRuleset struct {
+	Extends stringList `yaml:"extends"`
+	Rules   yaml.Node  `yaml:"rules"`
+}
+
+type rawRule struct {
+	Description string     `yaml:"description"`
+	Message     string     `yaml:"message"`
+	Severity    string     `yaml:"severity"`
+	Given       stringList `yaml:"given"`
+	Then        thenList   `yaml:"then"`
+}
+
+type rawThen struct {
+	Field           string    `yaml:"field"`
+	Function        string    `yaml:"function"`
+	FunctionOptions yaml.Node `yaml:"functionOptions"`
+}
+
+// stringList decodes a string or a list of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = []string{node.Value}
+		return nil
+	}
+	return node.Decode((*[]string)(l))
+}
+
+// thenList decodes a then clause or a list of them.
+type thenList []rawThen
+
+func (l *thenList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var t rawThen
+		if err := node.Decode(&t); err != nil {
+			return err
+		}
+		*l = []rawThen{t}
+		return nil
+	}
+	return node.Decode((*[]rawThen)(l))
+}
+
+func parseRuleset(data []byte, dir string, loading map[string]bool) (*Ruleset, error) {
+	var raw rawRuleset
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid ruleset: %w", err)
+	}
+	rs := &Ruleset{}
+	for _, ext := range raw.Extends {
+		base, err := loadExtends(ext, dir, loading)
+		if err != nil {
+			return nil, err
+		}
+		rs.Rules = append(rs.Rules, base.Rules...)
+	}
+	if raw.Rules.Kind != 0 && raw.Rules.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("rules must be a mapping of rule names to rules")
+	}
+	for i := 0; i+1 < len(raw.Rules.Content); i += 2 {
+		if err := rs.addRule(raw.Rules.Content[i].Value, raw.Rules.Content[i+1]); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", raw.Rules.Content[i].Value, err)
+		}
+	}
+	return rs, nil
+}
+
+func loadExtends(ext, dir string, loading map[string]bool) (*Ruleset, error) {
+	if ext == RecommendedRuleset {
+		return Recommended(), nil
+	}
+	if strings.Contains(ext, ":") && !filepath.IsAbs(ext) {
+		return nil, fmt.Errorf("unknown ruleset %q", ext)
+	}
+	if !filepath.IsAbs(ext) {
+		ext = filepath.Join(dir, ext)
+	}
+	return loadRuleset(ext, loading)
+}
+
+// addRule adds a rule definition, or applies a severity override such as "rule-id: off" to an
+// inherited rule.
+func (rs *Ruleset) addRule(id string, node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return rs.override(id, node.Value)
+	}
+	var raw rawRule
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	rule, err := compileRule(id, raw)
+	if err != nil {
+		return err
+	}
+	for i, existing := range rs.Rules {
+		if existing.ID == id {
+			rs.Rules[i] = rule
+			return nil
+		}
+	}
+	rs.Rules = append(rs.Rules, rule)
+	return nil
+}
+
+func (rs *Ruleset) override(id, value string) error {
+	rule := rs.Rule(id)
+	if rule == nil {
+		return fmt.Errorf("no inherited rule to override")
+	}
+	switch value {
+	case "true":
+		return nil
+	case "false":
+		value = string(SeverityOff)
+	}
+	sev, err := ParseSeverity(value)
+	if err != nil {
+		return err
+	}
+	// Copy the rule so the ruleset it was inherited from is left unchanged
+	copied := *rule
+	copied.Severity = sev
+	for i, existing := range rs.Rules {
+		if existing == rule {
+			rs.Rules[i] = &copied
+		}
+	}
+	return nil
+}
+
+// Rule returns the rule with the given ID, or nil.
+func (rs *Ruleset) Rule(id string) *Rule {
+	for _, rule := range rs.Rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+func compileRule(id string, raw rawRule) (*Rule, error) {
+	rule := &Rule{ID: id, Description: raw.Description, Message: raw.Message, Severity: SeverityWarn, Given: raw.Given}
+	if raw.Severity != "" {
+		sev, err := ParseSeverity(raw.Severity)
+		if err != nil {
+			return nil, err
+		}
+		rule.Severity = sev
+	}
+	if len(raw.Given) == 0 {
+		return nil, fmt.Errorf("given is required")
+	}
+	if len(raw.Then) == 0 {
+		return nil, fmt.Errorf("then is required")
+	}
+	for _, expr := range raw.Given {
+		p, err := compilePath(expr)
+		if err != nil {
+			return nil, err
+		}
+		rule.paths = append(rule.paths, p)
+	}
+	for _, then := range raw.Then {
+		factory, ok := functions[then.Function]
+		if !ok {
+			return nil, fmt.Errorf("unknown function %q", then.Function)
+		}
+		fn, err := factory(&then.FunctionOptions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", then.Function, err)
+		}
+		rule.checks = append(rule.checks, check{field: then.Field, function: fn})
+	}
+	return rule, nil
+}
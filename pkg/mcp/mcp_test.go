@@ -337,30 +337,6 @@ func TestGetEndpointHandler(t *testing.T) {
 	}
 }
 
-func TestGenerateStringExample(t *testing.T) {
-	tests := []struct {
-		format   string
-		expected string
-	}{
-		{"date", "2024-01-15"},
-		{"date-time", "2024-01-15T10:30:00Z"},
-		{"email", "user@example.com"},
-		{"uri", "https://example.com"},
-		{"uuid", "550e8400-e29b-41d4-a716-446655440000"},
-		{"", "string"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.format, func(t *testing.T) {
-			schema := &openapi.Schema{Format: tt.format}
-			result := generateStringExample(schema)
-			if result != tt.expected {
-				t.Errorf("generateStringExample with format %s = %s, want %s", tt.format, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestGetOperation(t *testing.T) {
 	pathItem := &openapi.PathItem{
 		Get:     &openapi.Operation{Summary: "get"},
@@ -898,97 +898,7 @@ func extractExampleFromContent(content map[string]openapi.MediaType, doc *openap
 
 // generateSchemaExample generates example data from a schema
 func generateSchemaExample(schema *openapi.Schema, doc *openapi.Document) any {
-	if schema == nil {
-		return nil
-	}
-
-	if resolved := resolveSchemaRef(schema, doc); resolved != nil {
-		return generateSchemaExample(resolved, doc)
-	}
-
-	if schema.Example != nil {
-		return schema.Example
-	}
-
-	if len(schema.Type) == 0 {
-		return nil
-	}
-
-	return generateExampleByType(schema.Type[0], schema, doc)
-}
-
-// resolveSchemaRef resolves a $ref to the actual schema, returns nil if not a ref
-func resolveSchemaRef(schema *openapi.Schema, doc *openapi.Document) *openapi.Schema {
-	if schema.Ref == "" {
-		return nil
-	}
-	refName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
-	if doc.Components != nil && doc.Components.Schemas != nil {
-		if refSchema, ok := doc.Components.Schemas[refName]; ok {
-			return refSchema
-		}
-	}
-	return nil
-}
-
-// generateExampleByType generates an example value based on schema type
-func generateExampleByType(schemaType string, schema *openapi.Schema, doc *openapi.Document) any {
-	switch schemaType {
-	case "string":
-		return generateStringExample(schema)
-	case "integer":
-		return 1
-	case "number":
-		return 1.0
-	case "boolean":
-		return true
-	case "array":
-		return generateArrayExample(schema, doc)
-	case "object":
-		return generateObjectExample(schema, doc)
-	default:
-		return nil
-	}
-}
-
-// generateArrayExample generates an example array
-func generateArrayExample(schema *openapi.Schema, doc *openapi.Document) []any {
-	if schema.Items != nil {
-		return []any{generateSchemaExample(schema.Items, doc)}
-	}
-	return []any{}
-}
-
-// generateObjectExample generates an example object
-func generateObjectExample(schema *openapi.Schema, doc *openapi.Document) map[string]any {
-	obj := make(map[string]any)
-	for propName, propSchema := range schema.Properties {
-		obj[propName] = generateSchemaExample(propSchema, doc)
-	}
-	return obj
-}
-
-// generateStringExample generates example string based on format
-func generateStringExample(schema *openapi.Schema) string {
-	if len(schema.Enum) > 0 {
-		if s, ok := schema.Enum[0].(string); ok {
-			return s
-		}
-	}
-	switch schema.Format {
-	case "date":
-		return "2024-01-15"
-	case "date-time":
-		return "2024-01-15T10:30:00Z"
-	case "email":
-		return "user@example.com"
-	case "uri":
-		return "https://example.com"
-	case "uuid":
-		return "550e8400-e29b-41d4-a716-446655440000"
-	default:
-		return "string"
-	}
+	return doc.SchemaExample(schema)
 }
 
 // relatedEndpoint represents a related endpoint
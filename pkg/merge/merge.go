@@ -0,0 +1,571 @@
+// Package merge combines several OpenAPI documents, such as one per service, into one. Paths can be
+// prefixed per document, components that clash by name are namespaced, and paths, operations and
+// operationIds defined by more than one document are reported as conflicts.
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// Input is one of the documents to merge
+type Input struct {
+	Source     string     // Names the document in errors and conflicts, such as its file name; defaults to Name
+	Name       string     // Namespaces its renamed components; defaults to info.title
+	Root       *yaml.Node // The document, with external $refs already bundled; it is not modified
+	PathPrefix string     // Prepended to each of its paths, such as /billing
+}
+
+// Option configures merging
+type Option func(*merger)
+
+// WithInfo sets the title and version of the merged document. Empty values keep those of the first document.
+func WithInfo(title, version string) Option {
+	return func(m *merger) {
+		m.title, m.version = title, version
+	}
+}
+
+// WithServers sets the servers of the merged document, such as the URL of a gateway. The servers of
+// the inputs are then dropped.
+func WithServers(urls ...string) Option {
+	return func(m *merger) {
+		m.servers = urls
+	}
+}
+
+// Conflict is something more than one document defines that merging cannot reconcile
+type Conflict struct {
+	Kind      string   // "path", "operation", "operationId" or "webhook"
+	Name      string   // What is defined twice, such as "/users", "GET /users" or "listUsers"
+	Documents []string // Names of the documents that define it
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s is defined by %s", c.Kind, c.Name, strings.Join(c.Documents, " and "))
+}
+
+// ConflictError is returned by Merge when the documents conflict
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Conflicts) == 1 {
+		return "merge conflict: " + e.Conflicts[0].String()
+	}
+	msgs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		msgs[i] = c.String()
+	}
+	return fmt.Sprintf("%d merge conflicts:\n  %s", len(e.Conflicts), strings.Join(msgs, "\n  "))
+}
+
+// methods are the keys of a path item that hold operations
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Merge combines the documents in order. Info, externalDocs and extensions come from the first document
+// that has them. Components with the same name and content are kept once; when the content differs,
+// the later one is renamed with its document's name as a prefix (Error becomes BillingError) and the
+// $refs to it follow. Tags are merged by name. Servers and security requirements shared by every
+// document stay at the top level; otherwise each document's are copied to its path items and operations.
+func Merge(inputs []Input, opts ...Option) (*yaml.Node, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no documents to merge")
+	}
+	m := &merger{
+		paths:        yamlnode.Mapping(),
+		webhooks:     yamlnode.Mapping(),
+		components:   yamlnode.Mapping(),
+		tags:         &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"},
+		extensions:   yamlnode.Mapping(),
+		tagNames:     make(map[string]bool),
+		namespaces:   make(map[string]bool),
+		pathOwners:   make(map[string]string),
+		opOwners:     make(map[string]string),
+		operationIDs: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	docs := make([]*document, len(inputs))
+	for i, input := range inputs {
+		d, err := m.document(input, i)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && minorVersion(d.version) != minorVersion(docs[0].version) {
+			return nil, fmt.Errorf("cannot merge OpenAPI %s (%s) with OpenAPI %s (%s)", docs[0].version, docs[0].source, d.version, d.source)
+		}
+		docs[i] = d
+	}
+
+	for _, d := range docs {
+		m.rename(d)
+		m.rewrite(d.root, yamlnode.Scope{}, d)
+		m.mergeComponents(d)
+	}
+
+	security, sharedSecurity := shared(docs, "security")
+	servers, sharedServers := shared(docs, "servers")
+	if m.servers != nil {
+		servers, sharedServers = nil, true
+	}
+	for _, d := range docs {
+		if !sharedSecurity {
+			pushSecurity(d)
+		}
+		if !sharedServers {
+			pushServers(d)
+		}
+		m.mergePaths(d)
+		m.mergeTags(d)
+	}
+
+	if len(m.conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: m.conflicts}
+	}
+	return m.assemble(docs, security, servers), nil
+}
+
+// document holds one input while it is merged
+type document struct {
+	source    string // Names the document in errors and conflicts
+	namespace string
+	prefix    string
+	version   string
+	root      *yaml.Node
+	renames   map[string]string // New names of components by "section/name"
+}
+
+// merger holds the state of a single merge
+type merger struct {
+	title, version string
+	servers        []string
+
+	paths, webhooks, components, tags, extensions *yaml.Node
+	tagNames                                      map[string]bool
+	namespaces                                    map[string]bool
+	pathOwners                                    map[string]string // Names of the documents defining paths
+	opOwners                                      map[string]string // Names of the documents defining operations, by "METHOD path"
+	operationIDs                                  map[string]string // Names of the documents using operationIds
+	conflicts                                     []Conflict
+}
+
+func (m *merger) document(input Input, i int) (*document, error) {
+	root := input.Root
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	name := input.Name
+	if name == "" {
+		name = yamlnode.Scalar(yamlnode.Get(yamlnode.Get(root, "info"), "title"))
+	}
+	if name == "" {
+		name = "document " + strconv.Itoa(i+1)
+	}
+	source := input.Source
+	if source == "" {
+		source = name
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: document is not a mapping", source)
+	}
+	version := yamlnode.Scalar(yamlnode.Get(root, "openapi"))
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s: not an OpenAPI 3 document", source)
+	}
+
+	namespace := identifier(name)
+	if namespace == "" {
+		namespace = "Doc" + strconv.Itoa(i+1)
+	}
+	base := namespace
+	for n := 2; m.namespaces[namespace]; n++ {
+		namespace = base + strconv.Itoa(n)
+	}
+	m.namespaces[namespace] = true
+
+	prefix := strings.TrimRight(input.PathPrefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return &document{
+		source:    source,
+		namespace: namespace,
+		prefix:    prefix,
+		version:   version,
+		root:      yamlnode.Clone(root),
+		renames:   make(map[string]string),
+	}, nil
+}
+
+// rename picks new names for the components of d that clash with merged ones. Renaming one component
+// changes the content of those referring to it, so this repeats until nothing else clashes.
+func (m *merger) rename(d *document) {
+	components := yamlnode.Get(d.root, "components")
+	for changed := true; changed; {
+		changed = false
+		eachComponent(components, func(section, name string, value *yaml.Node) {
+			id := section + "/" + name
+			if _, ok := d.renames[id]; ok {
+				return
+			}
+			existing := yamlnode.Get(yamlnode.Get(m.components, section), name)
+			if existing == nil {
+				return
+			}
+			candidate := yamlnode.Clone(value)
+			m.rewrite(candidate, yamlnode.Scope{Field: section}, d)
+			if !equal(existing, candidate) {
+				d.renames[id] = m.uniqueName(d, section, d.namespace+identifier(name[:1])+name[1:])
+				changed = true
+			}
+		})
+	}
+}
+
+// uniqueName returns name, or name with a number suffix when it is taken in section.
+func (m *merger) uniqueName(d *document, section, name string) string {
+	taken := func(n string) bool {
+		if yamlnode.Get(yamlnode.Get(m.components, section), n) != nil ||
+			yamlnode.Get(yamlnode.Get(yamlnode.Get(d.root, "components"), section), n) != nil {
+			return true
+		}
+		for id, renamed := range d.renames {
+			if renamed == n && strings.HasPrefix(id, section+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	unique := name
+	for i := 2; taken(unique); i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// rewrite points the $refs and security requirements below node, a value of the given scope, at the merged names.
+func (m *merger) rewrite(node *yaml.Node, scope yamlnode.Scope, d *document) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case scope.Names:
+				m.rewrite(value, scope.Child(key), d)
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				value.Value = d.ref(value.Value)
+			case scope.IsExample(key):
+				// Examples are data, where $ref has no meaning
+			case key == "discriminator":
+				yamlnode.Pairs(yamlnode.Get(value, "mapping"), func(_, target *yaml.Node) {
+					target.Value = d.ref(target.Value)
+				})
+			case key == "security" && value.Kind == yaml.SequenceNode:
+				for _, requirement := range value.Content {
+					for j := 0; requirement.Kind == yaml.MappingNode && j+1 < len(requirement.Content); j += 2 {
+						if renamed, ok := d.renames["securitySchemes/"+requirement.Content[j].Value]; ok {
+							requirement.Content[j].Value = renamed
+						}
+					}
+				}
+			default:
+				m.rewrite(value, scope.Child(key), d)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			m.rewrite(child, scope.Item(), d)
+		}
+	}
+}
+
+// ref returns an internal $ref of d as it reads in the merged document.
+func (d *document) ref(ref string) string {
+	if !strings.HasPrefix(ref, "#/") {
+		return ref
+	}
+	tokens := strings.Split(ref[2:], "/")
+	switch {
+	case len(tokens) >= 3 && tokens[0] == "components":
+		if renamed, ok := d.renames[tokens[1]+"/"+yamlnode.Unescape(tokens[2])]; ok {
+			tokens[2] = yamlnode.Escape(renamed)
+		}
+	case len(tokens) >= 2 && tokens[0] == "paths" && d.prefix != "":
+		tokens[1] = yamlnode.Escape(d.prefix + yamlnode.Unescape(tokens[1]))
+	}
+	return "#/" + strings.Join(tokens, "/")
+}
+
+func (m *merger) mergeComponents(d *document) {
+	yamlnode.Pairs(yamlnode.Get(d.root, "components"), func(section, entries *yaml.Node) {
+		if strings.HasPrefix(section.Value, "x-") {
+			if yamlnode.Get(m.components, section.Value) == nil {
+				yamlnode.SetPair(m.components, section.Value, entries)
+			}
+		}
+	})
+	eachComponent(yamlnode.Get(d.root, "components"), func(section, name string, value *yaml.Node) {
+		if renamed, ok := d.renames[section+"/"+name]; ok {
+			name = renamed
+		}
+		entries := yamlnode.Get(m.components, section)
+		if entries == nil {
+			entries = yamlnode.Mapping()
+			yamlnode.SetPair(m.components, section, entries)
+		}
+		// Identical components are kept once; the others were renamed
+		if yamlnode.Get(entries, name) == nil {
+			yamlnode.SetPair(entries, name, value)
+		}
+	})
+}
+
+func (m *merger) mergePaths(d *document) {
+	yamlnode.Pairs(yamlnode.Get(d.root, "paths"), func(key, item *yaml.Node) {
+		m.mergePath(d, d.prefix+key.Value, item)
+	})
+	yamlnode.Pairs(yamlnode.Get(d.root, "webhooks"), func(key, item *yaml.Node) {
+		existing := yamlnode.Get(m.webhooks, key.Value)
+		switch {
+		case existing == nil:
+			yamlnode.SetPair(m.webhooks, key.Value, item)
+			m.pathOwners["webhook "+key.Value] = d.source
+		case !equal(existing, item):
+			m.conflict("webhook", key.Value, m.pathOwners["webhook "+key.Value], d.source)
+		}
+	})
+}
+
+// mergePath adds a path item, or its operations when another document defines the path too.
+func (m *merger) mergePath(d *document, path string, item *yaml.Node) {
+	existing := yamlnode.Get(m.paths, path)
+	if existing == nil {
+		existing = yamlnode.Mapping()
+		yamlnode.SetPair(m.paths, path, existing)
+		m.pathOwners[path] = d.source
+	} else if yamlnode.Ref(existing) != "" || yamlnode.Ref(item) != "" {
+		if !equal(existing, item) {
+			m.conflict("path", path, m.pathOwners[path], d.source)
+		}
+		return
+	}
+
+	yamlnode.Pairs(item, func(key, value *yaml.Node) {
+		current := yamlnode.Get(existing, key.Value)
+		if !isMethod(key.Value) {
+			if current == nil {
+				yamlnode.SetPair(existing, key.Value, value)
+			} else if !equal(current, value) {
+				m.conflict("path", path, m.pathOwners[path], d.source)
+			}
+			return
+		}
+
+		operation := strings.ToUpper(key.Value) + " " + path
+		if current != nil {
+			m.conflict("operation", operation, m.opOwners[operation], d.source)
+			return
+		}
+		yamlnode.SetPair(existing, key.Value, value)
+		m.opOwners[operation] = d.source
+		if id := yamlnode.Scalar(yamlnode.Get(value, "operationId")); id != "" {
+			if owner, ok := m.operationIDs[id]; ok {
+				m.conflict("operationId", id, owner, d.source)
+			} else {
+				m.operationIDs[id] = d.source
+			}
+		}
+	})
+}
+
+func (m *merger) mergeTags(d *document) {
+	for _, tag := range yamlnode.Items(yamlnode.Get(d.root, "tags")) {
+		name := yamlnode.Scalar(yamlnode.Get(tag, "name"))
+		if !m.tagNames[name] {
+			m.tagNames[name] = true
+			m.tags.Content = append(m.tags.Content, tag)
+		}
+	}
+	yamlnode.Pairs(d.root, func(key, value *yaml.Node) {
+		if strings.HasPrefix(key.Value, "x-") && yamlnode.Get(m.extensions, key.Value) == nil {
+			yamlnode.SetPair(m.extensions, key.Value, value)
+		}
+	})
+}
+
+func (m *merger) conflict(kind, name string, documents ...string) {
+	for _, c := range m.conflicts {
+		if c.Kind == kind && c.Name == name && reflect.DeepEqual(c.Documents, documents) {
+			return
+		}
+	}
+	m.conflicts = append(m.conflicts, Conflict{Kind: kind, Name: name, Documents: documents})
+}
+
+// assemble builds the merged document.
+func (m *merger) assemble(docs []*document, security, servers *yaml.Node) *yaml.Node {
+	root := yamlnode.Mapping()
+	yamlnode.SetPair(root, "openapi", yamlnode.Get(docs[0].root, "openapi"))
+
+	info := first(docs, "info")
+	if info == nil {
+		info = yamlnode.Mapping()
+	}
+	if m.title != "" {
+		yamlnode.SetPair(info, "title", yamlnode.String(m.title))
+	}
+	if m.version != "" {
+		yamlnode.SetPair(info, "version", yamlnode.String(m.version))
+	}
+	yamlnode.SetPair(root, "info", info)
+	if dialect := first(docs, "jsonSchemaDialect"); dialect != nil {
+		yamlnode.SetPair(root, "jsonSchemaDialect", dialect)
+	}
+
+	if m.servers != nil {
+		servers = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, url := range m.servers {
+			servers.Content = append(servers.Content, yamlnode.Mapping(yamlnode.String("url"), yamlnode.String(url)))
+		}
+	}
+	if servers != nil {
+		yamlnode.SetPair(root, "servers", servers)
+	}
+	if security != nil {
+		yamlnode.SetPair(root, "security", security)
+	}
+	if len(m.tags.Content) > 0 {
+		yamlnode.SetPair(root, "tags", m.tags)
+	}
+	yamlnode.SetPair(root, "paths", m.paths)
+	if len(m.webhooks.Content) > 0 {
+		yamlnode.SetPair(root, "webhooks", m.webhooks)
+	}
+	if len(m.components.Content) > 0 {
+		yamlnode.SetPair(root, "components", m.components)
+	}
+	if externalDocs := first(docs, "externalDocs"); externalDocs != nil {
+		yamlnode.SetPair(root, "externalDocs", externalDocs)
+	}
+	root.Content = append(root.Content, m.extensions.Content...)
+	return root
+}
+
+// shared returns the value of key if every document has the same one, nil included.
+func shared(docs []*document, key string) (*yaml.Node, bool) {
+	value := yamlnode.Get(docs[0].root, key)
+	for _, d := range docs[1:] {
+		other := yamlnode.Get(d.root, key)
+		if (value == nil) != (other == nil) || (value != nil && !equal(value, other)) {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// pushSecurity copies the top-level security of d to each of its operations that has none.
+func pushSecurity(d *document) {
+	security := yamlnode.Get(d.root, "security")
+	if security == nil {
+		return
+	}
+	for _, section := range []string{"paths", "webhooks"} {
+		yamlnode.Pairs(yamlnode.Get(d.root, section), func(_, item *yaml.Node) {
+			for _, method := range methods {
+				if op := yamlnode.Get(item, method); op != nil && yamlnode.Get(op, "security") == nil {
+					yamlnode.SetPair(op, "security", yamlnode.Clone(security))
+				}
+			}
+		})
+	}
+}
+
+// pushServers copies the top-level servers of d to each of its path items that has none.
+func pushServers(d *document) {
+	servers := yamlnode.Get(d.root, "servers")
+	if servers == nil {
+		return
+	}
+	yamlnode.Pairs(yamlnode.Get(d.root, "paths"), func(_, item *yaml.Node) {
+		if yamlnode.Ref(item) == "" && yamlnode.Get(item, "servers") == nil {
+			yamlnode.SetPair(item, "servers", yamlnode.Clone(servers))
+		}
+	})
+}
+
+// first returns the value of key in the first document that has it.
+func first(docs []*document, key string) *yaml.Node {
+	for _, d := range docs {
+		if value := yamlnode.Get(d.root, key); value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// eachComponent calls fn for every component, skipping extension sections.
+func eachComponent(components *yaml.Node, fn func(section, name string, value *yaml.Node)) {
+	yamlnode.Pairs(components, func(section, entries *yaml.Node) {
+		if strings.HasPrefix(section.Value, "x-") {
+			return
+		}
+		yamlnode.Pairs(entries, func(key, value *yaml.Node) {
+			fn(section.Value, key.Value, value)
+		})
+	})
+}
+
+// identifier turns a document name into a component name prefix: "billing-service" becomes BillingService.
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// minorVersion returns the major and minor part of an OpenAPI version: 3.1.0 becomes 3.1.
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func isMethod(key string) bool {
+	for _, method := range methods {
+		if key == method {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b *yaml.Node) bool {
+	var x, y any
+	if a.Decode(&x) != nil || b.Decode(&y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
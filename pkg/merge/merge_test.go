@@ -0,0 +1,313 @@
+package merge
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+const users = `openapi: 3.0.3
+info: {title: Users, version: 1.0.0}
+servers:
+  - url: https://users.internal
+security:
+  - bearer: []
+tags:
+  - name: users
+    description: User accounts
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      tags: [users]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/User"}
+        default:
+          $ref: "#/components/responses/Problem"
+components:
+  responses:
+    Problem:
+      description: A problem
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  schemas:
+    User:
+      type: object
+      properties:
+        id: {type: string}
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+`
+
+const billing = `openapi: 3.0.1
+info: {title: billing-service, version: 2.0.0}
+servers:
+  - url: https://billing.internal
+security:
+  - bearer: []
+tags:
+  - name: users
+    description: Ignored, users is already defined
+  - name: invoices
+paths:
+  /invoices/{id}:
+    get:
+      operationId: getInvoice
+      tags: [invoices]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Invoice"}
+        default:
+          $ref: "#/components/responses/Problem"
+    put:
+      operationId: putInvoice
+      security: []
+      responses:
+        "200": {$ref: "#/paths/~1invoices~1{id}/get/responses/200"}
+components:
+  responses:
+    Problem:
+      description: A problem
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  schemas:
+    Invoice:
+      type: object
+      properties:
+        owner: {$ref: "#/components/schemas/User"}
+        example: {$ref: "#/components/schemas/Error"}
+      example: {owner: {$ref: "#/components/schemas/Error"}}
+    User:
+      type: object
+      properties:
+        id: {type: string}
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer, bearerFormat: JWT}
+`
+
+func parse(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func keys(node *yaml.Node) []string {
+	var out []string
+	yamlnode.Pairs(node, func(key, _ *yaml.Node) {
+		out = append(out, key.Value)
+	})
+	return out
+}
+
+func scalarAt(t *testing.T, root *yaml.Node, pointer string) string {
+	t.Helper()
+	node := yamlnode.Resolve(root, pointer)
+	if node == nil {
+		t.Fatalf("%s not found", pointer)
+	}
+	return node.Value
+}
+
+func TestMerge(t *testing.T) {
+	billingRoot := parse(t, billing)
+	merged, err := Merge([]Input{
+		{Root: parse(t, users)},
+		{Root: billingRoot, PathPrefix: "billing/"},
+	}, WithInfo("Gateway", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := keys(yamlnode.Get(merged, "paths")), []string{"/users", "/billing/invoices/{id}"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("paths = %v, want %v", got, want)
+	}
+	components := yamlnode.Get(merged, "components")
+	if got, want := keys(yamlnode.Get(components, "schemas")), []string{"User", "Error", "Invoice", "BillingServiceError"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("schemas = %v, want %v", got, want)
+	}
+	if got, want := keys(yamlnode.Get(components, "responses")), []string{"Problem", "BillingServiceProblem"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("responses = %v, want %v", got, want)
+	}
+	if got, want := keys(yamlnode.Get(components, "securitySchemes")), []string{"bearer", "BillingServiceBearer"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("securitySchemes = %v, want %v", got, want)
+	}
+	if n := len(yamlnode.Items(yamlnode.Get(merged, "tags"))); n != 2 {
+		t.Errorf("got %d tags, want 2", n)
+	}
+
+	values := []struct{ pointer, want string }{
+		{"/info/title", "Gateway"},
+		{"/info/version", "1.0.0"},
+		{"/openapi", "3.0.3"},
+		{"/tags/0/description", "User accounts"},
+		{"/paths/~1billing~1invoices~1{id}/get/responses/default/$ref", "#/components/responses/BillingServiceProblem"},
+		{"/paths/~1billing~1invoices~1{id}/put/responses/200/$ref", "#/paths/~1billing~1invoices~1{id}/get/responses/200"},
+		{"/components/responses/BillingServiceProblem/content/application~1json/schema/$ref", "#/components/schemas/BillingServiceError"},
+		{"/components/schemas/Invoice/properties/owner/$ref", "#/components/schemas/User"},
+		// A property named example is a schema, an example field is data
+		{"/components/schemas/Invoice/properties/example/$ref", "#/components/schemas/BillingServiceError"},
+		{"/components/schemas/Invoice/example/owner/$ref", "#/components/schemas/Error"},
+		// Servers differ, so they move to the path items
+		{"/paths/~1users/servers/0/url", "https://users.internal"},
+		{"/paths/~1billing~1invoices~1{id}/servers/0/url", "https://billing.internal"},
+	}
+	for _, v := range values {
+		if got := scalarAt(t, merged, v.pointer); got != v.want {
+			t.Errorf("%s = %q, want %q", v.pointer, got, v.want)
+		}
+	}
+
+	// So does security, with the renamed scheme
+	if got := keys(yamlnode.Resolve(merged, "/paths/~1users/get/security/0")); !reflect.DeepEqual(got, []string{"bearer"}) {
+		t.Errorf("GET /users security = %v", got)
+	}
+	if got := keys(yamlnode.Resolve(merged, "/paths/~1billing~1invoices~1{id}/get/security/0")); !reflect.DeepEqual(got, []string{"BillingServiceBearer"}) {
+		t.Errorf("GET /billing/invoices/{id} security = %v", got)
+	}
+	if n := len(yamlnode.Items(yamlnode.Resolve(merged, "/paths/~1billing~1invoices~1{id}/put/security"))); n != 0 {
+		t.Errorf("explicit empty security was replaced: %d requirements", n)
+	}
+	if yamlnode.Get(merged, "security") != nil || yamlnode.Get(merged, "servers") != nil {
+		t.Error("differing security and servers were kept at the top level")
+	}
+
+	// The inputs are left as they were
+	if yamlnode.Get(yamlnode.Get(billingRoot, "paths"), "/invoices/{id}") == nil {
+		t.Error("Merge() modified its input")
+	}
+}
+
+func TestMerge_SharedAndServers(t *testing.T) {
+	a := `openapi: 3.1.0
+info: {title: A, version: "1"}
+security: [{key: []}]
+paths:
+  /a: {get: {responses: {"200": {description: ok}}}}
+components:
+  schemas:
+    Error: {type: string}
+`
+	b := strings.NewReplacer("title: A", "title: B", "/a:", "/b:").Replace(a)
+	merged, err := Merge([]Input{{Root: parse(t, a)}, {Root: parse(t, b)}}, WithServers("https://api.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scalarAt(t, merged, "/servers/0/url"); got != "https://api.example.com" {
+		t.Errorf("servers/0/url = %q", got)
+	}
+	if yamlnode.Get(merged, "security") == nil {
+		t.Error("shared security was not kept at the top level")
+	}
+	if yamlnode.Resolve(merged, "/paths/~1a/get/security") != nil {
+		t.Error("shared security was copied to the operations")
+	}
+	if got := keys(yamlnode.Resolve(merged, "/components/schemas")); !reflect.DeepEqual(got, []string{"Error"}) {
+		t.Errorf("identical schemas were not merged: %v", got)
+	}
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	a := `openapi: 3.0.3
+info: {title: A, version: "1"}
+paths:
+  /items:
+    parameters: [{name: q, in: query, schema: {type: string}}]
+    get: {operationId: list, responses: {"200": {description: ok}}}
+  /other:
+    post: {operationId: create, responses: {"200": {description: ok}}}
+`
+	b := `openapi: 3.0.3
+info: {title: B, version: "1"}
+paths:
+  /items:
+    parameters: [{name: q, in: query, schema: {type: integer}}]
+    get: {operationId: list, responses: {"200": {description: ok}}}
+    post: {operationId: create, responses: {"200": {description: ok}}}
+`
+	_, err := Merge([]Input{{Root: parse(t, a)}, {Root: parse(t, b)}})
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Merge() error = %v, want a ConflictError", err)
+	}
+	want := []Conflict{
+		{Kind: "path", Name: "/items", Documents: []string{"A", "B"}},
+		{Kind: "operation", Name: "GET /items", Documents: []string{"A", "B"}},
+		{Kind: "operationId", Name: "create", Documents: []string{"A", "B"}},
+	}
+	if !reflect.DeepEqual(conflictErr.Conflicts, want) {
+		t.Errorf("conflicts = %+v, want %+v", conflictErr.Conflicts, want)
+	}
+	if !strings.Contains(err.Error(), "operation GET /items is defined by A and B") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	// Documents are named by their source when it is given, as titles need not differ
+	_, err = Merge([]Input{{Source: "users.yaml", Root: parse(t, a)}, {Source: "billing.yaml", Root: parse(t, b)}})
+	if !errors.As(err, &conflictErr) || !reflect.DeepEqual(conflictErr.Conflicts[0].Documents, []string{"users.yaml", "billing.yaml"}) {
+		t.Errorf("with sources: error = %v", err)
+	}
+
+	// With a prefix the paths no longer clash
+	_, err = Merge([]Input{{Root: parse(t, a)}, {Root: parse(t, b), PathPrefix: "/b"}})
+	if !errors.As(err, &conflictErr) || len(conflictErr.Conflicts) != 2 {
+		t.Errorf("with prefix: error = %v, want the two operationId conflicts", err)
+	}
+}
+
+func TestMerge_Versions(t *testing.T) {
+	a := "openapi: 3.0.3\ninfo: {title: A, version: '1'}\npaths: {}\n"
+	tests := []struct {
+		name, other, want string
+	}{
+		{"minor", "openapi: 3.1.0\ninfo: {title: B, version: '1'}\npaths: {}\n", "cannot merge OpenAPI 3.0.3 (A) with OpenAPI 3.1.0 (B)"},
+		{"swagger", "swagger: '2.0'\ninfo: {title: B, version: '1'}\npaths: {}\n", "B: not an OpenAPI 3 document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Merge([]Input{{Root: parse(t, a)}, {Root: parse(t, tt.other)}})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Merge() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"billing-service": "BillingService",
+		"Pet Store API":   "PetStoreAPI",
+		"users_v2":        "UsersV2",
+	}
+	for in, want := range tests {
+		if got := identifier(in); got != want {
+			t.Errorf("identifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,521 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// ConversionWarning is something that cannot be represented in the version a document is converted
+// to, and was dropped or approximated.
+type ConversionWarning struct {
+	Pointer string // JSON pointer of the value in the converted document
+	Message string
+}
+
+func (w ConversionWarning) String() string {
+	return w.Pointer + ": " + w.Message
+}
+
+// Convert converts the document to another OpenAPI version; see ConvertNode.
+func (d *Document) Convert(version string) ([]ConversionWarning, error) {
+	var root yaml.Node
+	if err := root.Encode(d); err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	warnings, err := ConvertNode(&root, version)
+	if err != nil {
+		return nil, err
+	}
+	var converted Document
+	if err := root.Decode(&converted); err != nil {
+		return nil, fmt.Errorf("failed to decode converted document: %w", err)
+	}
+	converted.SourceOrder = d.SourceOrder
+	*d = converted
+	return warnings, nil
+}
+
+var versionPattern = regexp.MustCompile(`^3\.([0-2])(\.\d+)?$`)
+
+// latestPatch is the version a document gets when converted to a minor version
+var latestPatch = map[string]string{"3.0": "3.0.3", "3.1": "3.1.0", "3.2": "3.2.0"}
+
+// ConvertNode converts a document held as a YAML node, in place, to an OpenAPI version: "3.0", "3.1"
+// or "3.2", or a full version such as "3.0.3".
+//
+// From 3.0 to 3.1, nullable becomes a "null" type, a schema example becomes examples and boolean
+// exclusiveMinimum and exclusiveMaximum become the bound itself. From 3.1 to 3.0 that is reversed:
+// type arrays become nullable or anyOf, const becomes enum, $ref siblings move to allOf and
+// components.pathItems are inlined. Keywords and objects 3.0 lacks, such as webhooks or prefixItems,
+// are removed with a warning, as are those 3.2 adds when converting from it.
+func ConvertNode(root *yaml.Node, version string) ([]ConversionWarning, error) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported target version %q (want 3.0, 3.1 or 3.2)", version)
+	}
+	to := "3." + match[1]
+	if match[2] == "" {
+		version = latestPatch[to]
+	}
+
+	current := yamlnode.Get(root, "openapi")
+	if current == nil {
+		if yamlnode.Get(root, "swagger") != nil {
+			return nil, fmt.Errorf("swagger 2.0 documents must be upgraded first")
+		}
+		return nil, fmt.Errorf("document has no openapi version")
+	}
+	match = versionPattern.FindStringSubmatch(current.Value)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", current.Value)
+	}
+	from := "3." + match[1]
+
+	c := &converter{root: root}
+	if from == "3.0" && to != "3.0" {
+		c.eachSchema(c.upSchema)
+	}
+	if from == "3.2" && to != "3.2" {
+		c.down32()
+	}
+	if from != "3.0" && to == "3.0" {
+		c.down31()
+		c.eachSchema(c.downSchema)
+	}
+	current.Value, current.Tag = version, "!!str"
+	return c.warnings, nil
+}
+
+// converter holds the state of a single conversion
+type converter struct {
+	root     *yaml.Node
+	warnings []ConversionWarning
+}
+
+func (c *converter) warn(pointer, format string, args ...any) {
+	c.warnings = append(c.warnings, ConversionWarning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// upSchema converts a schema from OpenAPI 3.0 to 3.1.
+func (c *converter) upSchema(schema *yaml.Node, _ string) {
+	if nullable := yamlnode.Get(schema, "nullable"); nullable != nil {
+		yamlnode.RemoveKey(schema, "nullable")
+		if isTrue(nullable) {
+			addNull(schema)
+		}
+	}
+
+	for _, keys := range [][2]string{{"minimum", "exclusiveMinimum"}, {"maximum", "exclusiveMaximum"}} {
+		exclusive := yamlnode.Get(schema, keys[1])
+		if exclusive == nil || exclusive.Tag != "!!bool" {
+			continue
+		}
+		yamlnode.RemoveKey(schema, keys[1])
+		if bound := yamlnode.Get(schema, keys[0]); bound != nil && isTrue(exclusive) {
+			yamlnode.RemoveKey(schema, keys[0])
+			yamlnode.SetPair(schema, keys[1], bound)
+		}
+	}
+
+	if example := yamlnode.Get(schema, "example"); example != nil {
+		yamlnode.RemoveKey(schema, "example")
+		if yamlnode.Get(schema, "examples") == nil {
+			yamlnode.SetPair(schema, "examples", sequence(example))
+		}
+	}
+}
+
+// addNull makes a schema accept null as well.
+func addNull(schema *yaml.Node) {
+	typ := yamlnode.Get(schema, "type")
+	switch {
+	case typ != nil && typ.Kind == yaml.ScalarNode:
+		types := sequence(typ, yamlnode.String(TypeNull))
+		types.Style = yaml.FlowStyle
+		yamlnode.SetPair(schema, "type", types)
+	case typ != nil && typ.Kind == yaml.SequenceNode:
+		if !slices.ContainsFunc(typ.Content, func(n *yaml.Node) bool { return n.Value == TypeNull }) {
+			typ.Content = append(typ.Content, yamlnode.String(TypeNull))
+		}
+	case yamlnode.Get(schema, "oneOf") != nil || yamlnode.Get(schema, "anyOf") != nil:
+		alternatives := yamlnode.Get(schema, "oneOf")
+		if alternatives == nil {
+			alternatives = yamlnode.Get(schema, "anyOf")
+		}
+		alternatives.Content = append(alternatives.Content, pair("type", yamlnode.String(TypeNull)))
+		return
+	default:
+		// Without a type, such as allOf: [{$ref: ...}], null is an alternative to the whole schema
+		inner := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: schema.Content}
+		schema.Content = nil
+		yamlnode.SetPair(schema, "anyOf", sequence(inner, pair("type", yamlnode.String(TypeNull))))
+		return
+	}
+
+	if enum := yamlnode.Get(schema, "enum"); enum != nil && enum.Kind == yaml.SequenceNode {
+		if !slices.ContainsFunc(enum.Content, func(n *yaml.Node) bool { return n.Tag == "!!null" }) {
+			enum.Content = append(enum.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		}
+	}
+}
+
+// dropNullAlternative turns a {type: "null"} alternative of anyOf or oneOf into nullable, the
+// reverse of addNull. A single remaining alternative replaces the schema when nothing else is in it.
+func dropNullAlternative(schema *yaml.Node) {
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		alternatives := yamlnode.Get(schema, keyword)
+		if alternatives == nil || alternatives.Kind != yaml.SequenceNode {
+			continue
+		}
+		index := slices.IndexFunc(alternatives.Content, func(n *yaml.Node) bool {
+			return len(n.Content) == 2 && n.Content[0].Value == "type" && n.Content[1].Value == TypeNull
+		})
+		if index < 0 {
+			continue
+		}
+		alternatives.Content = slices.Delete(alternatives.Content, index, index+1)
+		if len(alternatives.Content) == 1 && len(schema.Content) == 2 && alternatives.Content[0].Kind == yaml.MappingNode {
+			schema.Content = alternatives.Content[0].Content
+		}
+		yamlnode.SetPair(schema, "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		return
+	}
+}
+
+// unsupported30 are the JSON Schema keywords of OpenAPI 3.1 that OpenAPI 3.0 schemas do not have
+var unsupported30 = []string{
+	"$id", "$schema", "$anchor", "$dynamicAnchor", "$dynamicRef", "$defs", "$comment", "$vocabulary",
+	"prefixItems", "contains", "minContains", "maxContains", "if", "then", "else",
+	"dependentSchemas", "dependentRequired", "patternProperties", "propertyNames",
+	"unevaluatedItems", "unevaluatedProperties", "contentEncoding", "contentMediaType", "contentSchema",
+}
+
+// downSchema converts a schema from OpenAPI 3.1 to 3.0.
+func (c *converter) downSchema(schema *yaml.Node, pointer string) {
+	dropNullAlternative(schema)
+
+	// $ref replaces its siblings in 3.0, so it moves into allOf next to them
+	if ref := yamlnode.Get(schema, "$ref"); ref != nil && len(schema.Content) > 2 {
+		yamlnode.RemoveKey(schema, "$ref")
+		allOf := yamlnode.Get(schema, "allOf")
+		if allOf == nil {
+			allOf = sequence()
+			yamlnode.SetPair(schema, "allOf", allOf)
+		}
+		allOf.Content = append([]*yaml.Node{pair("$ref", ref)}, allOf.Content...)
+	}
+
+	if typ := yamlnode.Get(schema, "type"); typ != nil && typ.Kind == yaml.ScalarNode && typ.Value == TypeNull {
+		yamlnode.SetPair(schema, "type", sequence(typ))
+	}
+	if typ := yamlnode.Get(schema, "type"); typ != nil && typ.Kind == yaml.SequenceNode {
+		var types []*yaml.Node
+		nullable := false
+		for _, t := range typ.Content {
+			if t.Value == TypeNull {
+				nullable = true
+			} else {
+				types = append(types, t)
+			}
+		}
+		switch {
+		case len(types) == 1:
+			yamlnode.SetPair(schema, "type", types[0])
+		case len(types) == 0:
+			yamlnode.RemoveKey(schema, "type")
+			if yamlnode.Get(schema, "enum") == nil {
+				yamlnode.SetPair(schema, "enum", sequence(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}))
+			}
+		default:
+			yamlnode.RemoveKey(schema, "type")
+			alternatives := sequence()
+			for _, t := range types {
+				alternatives.Content = append(alternatives.Content, pair("type", t))
+			}
+			if yamlnode.Get(schema, "anyOf") == nil {
+				yamlnode.SetPair(schema, "anyOf", alternatives)
+			} else {
+				allOf := yamlnode.Get(schema, "allOf")
+				if allOf == nil {
+					allOf = sequence()
+					yamlnode.SetPair(schema, "allOf", allOf)
+				}
+				allOf.Content = append(allOf.Content, pair("anyOf", alternatives))
+			}
+		}
+		if nullable {
+			yamlnode.SetPair(schema, "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+	}
+
+	if constant := yamlnode.Get(schema, "const"); constant != nil {
+		yamlnode.RemoveKey(schema, "const")
+		if yamlnode.Get(schema, "enum") == nil {
+			yamlnode.SetPair(schema, "enum", sequence(constant))
+		}
+	}
+
+	for _, keys := range [][2]string{{"minimum", "exclusiveMinimum"}, {"maximum", "exclusiveMaximum"}} {
+		exclusive := yamlnode.Get(schema, keys[1])
+		if exclusive == nil || exclusive.Kind != yaml.ScalarNode || exclusive.Tag == "!!bool" {
+			continue
+		}
+		yamlnode.RemoveKey(schema, keys[1])
+		value, err := strconv.ParseFloat(exclusive.Value, 64)
+		if err != nil {
+			continue
+		}
+		// Keep whichever of the inclusive and the exclusive bound is stricter
+		if bound := yamlnode.Get(schema, keys[0]); bound != nil {
+			inclusive, err := strconv.ParseFloat(bound.Value, 64)
+			if err == nil && (keys[0] == "minimum" && inclusive > value || keys[0] == "maximum" && inclusive < value) {
+				continue
+			}
+		}
+		yamlnode.SetPair(schema, keys[0], exclusive)
+		yamlnode.SetPair(schema, keys[1], &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+	}
+
+	if examples := yamlnode.Get(schema, "examples"); examples != nil && examples.Kind == yaml.SequenceNode {
+		yamlnode.RemoveKey(schema, "examples")
+		if len(examples.Content) > 0 && yamlnode.Get(schema, "example") == nil {
+			yamlnode.SetPair(schema, "example", examples.Content[0])
+		}
+		if len(examples.Content) > 1 {
+			c.warn(pointer, "only the first of %d examples is kept", len(examples.Content))
+		}
+	}
+
+	if yamlnode.Get(schema, "format") == nil {
+		if yamlnode.Scalar(yamlnode.Get(schema, "contentEncoding")) == "base64" {
+			yamlnode.RemoveKey(schema, "contentEncoding")
+			yamlnode.SetPair(schema, "format", yamlnode.String("byte"))
+		} else if yamlnode.Scalar(yamlnode.Get(schema, "contentMediaType")) == "application/octet-stream" {
+			yamlnode.RemoveKey(schema, "contentMediaType")
+			yamlnode.SetPair(schema, "format", yamlnode.String("binary"))
+		}
+	}
+
+	var removed []string
+	for _, keyword := range unsupported30 {
+		if yamlnode.Get(schema, keyword) != nil {
+			yamlnode.RemoveKey(schema, keyword)
+			removed = append(removed, keyword)
+		}
+	}
+	if len(removed) > 0 {
+		c.warn(pointer, "removed %s, which OpenAPI 3.0 schemas do not support", strings.Join(removed, ", "))
+	}
+}
+
+// down31 converts the parts of a document outside schemas from OpenAPI 3.1 to 3.0.
+func (c *converter) down31() {
+	root := c.root
+	c.inlinePathItems()
+	if yamlnode.Get(root, "webhooks") != nil {
+		yamlnode.RemoveKey(root, "webhooks")
+		c.warn("/webhooks", "removed webhooks, which OpenAPI 3.0 does not support")
+	}
+	if yamlnode.Get(root, "jsonSchemaDialect") != nil {
+		yamlnode.RemoveKey(root, "jsonSchemaDialect")
+		c.warn("/jsonSchemaDialect", "removed jsonSchemaDialect, which OpenAPI 3.0 does not support")
+	}
+	info := yamlnode.Get(root, "info")
+	if yamlnode.Get(info, "summary") != nil {
+		yamlnode.RemoveKey(info, "summary")
+		c.warn("/info/summary", "removed info summary, which OpenAPI 3.0 does not support")
+	}
+	if license := yamlnode.Get(info, "license"); yamlnode.Get(license, "identifier") != nil {
+		yamlnode.RemoveKey(license, "identifier")
+		c.warn("/info/license/identifier", "removed license identifier, which OpenAPI 3.0 does not support")
+	}
+	if yamlnode.Get(root, "paths") == nil {
+		yamlnode.SetPair(root, "paths", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	schemes := yamlnode.Get(yamlnode.Get(root, "components"), "securitySchemes")
+	for i := 0; schemes != nil && i+1 < len(schemes.Content); i += 2 {
+		if yamlnode.Scalar(yamlnode.Get(schemes.Content[i+1], "type")) == "mutualTLS" {
+			c.warn(yamlnode.Join("/components/securitySchemes", schemes.Content[i].Value), "removed mutualTLS security scheme, which OpenAPI 3.0 does not support")
+			schemes.Content = append(schemes.Content[:i], schemes.Content[i+2:]...)
+			i -= 2
+		}
+	}
+}
+
+// inlinePathItems replaces the $refs to components.pathItems, which OpenAPI 3.0 lacks, with copies.
+func (c *converter) inlinePathItems() {
+	components := yamlnode.Get(c.root, "components")
+	pathItems := yamlnode.Get(components, "pathItems")
+	if pathItems == nil {
+		return
+	}
+	yamlnode.Walk(c.root, "", func(node *yaml.Node, pointer string) bool {
+		ref := yamlnode.Scalar(yamlnode.Get(node, "$ref"))
+		if !strings.HasPrefix(ref, "#/components/pathItems/") || strings.HasPrefix(pointer, "/components/pathItems") {
+			return true
+		}
+		if target := yamlnode.Resolve(c.root, ref); target != nil {
+			node.Content = yamlnode.Clone(target).Content
+		}
+		return true
+	})
+	yamlnode.RemoveKey(components, "pathItems")
+}
+
+// down32 converts the parts of a document that OpenAPI 3.2 added to their 3.1 form, or removes them.
+func (c *converter) down32() {
+	if yamlnode.Get(c.root, "$self") != nil {
+		yamlnode.RemoveKey(c.root, "$self")
+		c.warn("/$self", "removed $self, which OpenAPI 3.1 does not support")
+	}
+	for i, tag := range yamlnode.Items(yamlnode.Get(c.root, "tags")) {
+		c.removeFields(tag, yamlnode.Index("/tags", i), "tag", "summary", "parent", "kind")
+	}
+	c.walkObjects(c.root, yamlnode.Scope{}, "", func(key string, value *yaml.Node, pointer string) {
+		switch key {
+		case "paths", "webhooks", "pathItems":
+			yamlnode.Pairs(value, func(name, item *yaml.Node) {
+				c.removeFields(item, yamlnode.Join(pointer, name.Value), "path item", "query", "additionalOperations")
+			})
+		case "callbacks":
+			yamlnode.Pairs(value, func(name, callback *yaml.Node) {
+				yamlnode.Pairs(callback, func(expression, item *yaml.Node) {
+					at := yamlnode.Join(pointer, name.Value, expression.Value)
+					c.removeFields(item, at, "path item", "query", "additionalOperations")
+				})
+			})
+		case "content":
+			yamlnode.Pairs(value, func(mediaType, media *yaml.Node) {
+				c.removeFields(media, yamlnode.Join(pointer, mediaType.Value), "media type", "itemSchema", "itemEncoding", "prefixEncoding")
+			})
+		case "servers":
+			for i, server := range yamlnode.Items(value) {
+				c.removeFields(server, yamlnode.Index(pointer, i), "server", "name")
+			}
+		case "securitySchemes":
+			yamlnode.Pairs(value, func(name, scheme *yaml.Node) {
+				at := yamlnode.Join(pointer, name.Value)
+				c.removeFields(scheme, at, "security scheme", "deprecated", "oauth2MetadataUrl")
+				c.removeFields(yamlnode.Get(scheme, "flows"), at+"/flows", "OAuth flows", "deviceAuthorization")
+			})
+		case "examples":
+			yamlnode.Pairs(value, func(name, example *yaml.Node) {
+				if data := yamlnode.Get(example, "dataValue"); data != nil && yamlnode.Get(example, "value") == nil {
+					yamlnode.RemoveKey(example, "dataValue")
+					yamlnode.SetPair(example, "value", data)
+				}
+				c.removeFields(example, yamlnode.Join(pointer, name.Value), "example", "dataValue", "serializedValue")
+			})
+		}
+	})
+}
+
+// removeFields removes fields of an object and warns about each one present.
+func (c *converter) removeFields(object *yaml.Node, pointer, kind string, fields ...string) {
+	for _, field := range fields {
+		if yamlnode.Get(object, field) != nil {
+			yamlnode.RemoveKey(object, field)
+			c.warn(yamlnode.Join(pointer, field), "removed %s %s, which OpenAPI 3.1 does not support", kind, field)
+		}
+	}
+}
+
+// walkObjects calls fn for each field and value of the objects of the document outside schemas,
+// extensions and example data, with the pointer of the value. Components, properties and other
+// names are not fields, so a parameter named value is still walked.
+func (c *converter) walkObjects(node *yaml.Node, scope yamlnode.Scope, pointer string, fn func(key string, value *yaml.Node, pointer string)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			at := yamlnode.Join(pointer, key)
+			if !scope.Names {
+				if scope.IsExample(key) || strings.HasPrefix(key, "x-") {
+					continue
+				}
+				fn(key, value, at)
+				if key == "schema" || key == "itemSchema" || key == "schemas" && scope.Field == "components" {
+					continue
+				}
+			}
+			c.walkObjects(value, scope.Child(key), at, fn)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			c.walkObjects(child, scope.Item(), yamlnode.Index(pointer, i), fn)
+		}
+	}
+}
+
+// eachSchema calls fn for every schema of the document, outer schemas before the ones they contain.
+func (c *converter) eachSchema(fn func(schema *yaml.Node, pointer string)) {
+	c.walkObjects(c.root, yamlnode.Scope{}, "", func(key string, value *yaml.Node, pointer string) {
+		switch {
+		case key == "schema" || key == "itemSchema":
+			walkSchema(value, pointer, fn)
+		case key == "schemas" && pointer == "/components/schemas":
+			yamlnode.Pairs(value, func(name, schema *yaml.Node) {
+				walkSchema(schema, yamlnode.Join(pointer, name.Value), fn)
+			})
+		}
+	})
+}
+
+// Keywords whose values are schemas, maps of schemas or lists of schemas
+var (
+	schemaKeywords = []string{
+		"items", "additionalProperties", "not", "contains", "if", "then", "else", "propertyNames",
+		"unevaluatedItems", "unevaluatedProperties", "additionalItems", "contentSchema",
+	}
+	schemaMapKeywords  = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+)
+
+func walkSchema(schema *yaml.Node, pointer string, fn func(*yaml.Node, string)) {
+	if schema.Kind != yaml.MappingNode {
+		return
+	}
+	fn(schema, pointer)
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i].Value, schema.Content[i+1]
+		at := yamlnode.Join(pointer, key)
+		switch {
+		case slices.Contains(schemaKeywords, key):
+			walkSchema(value, at, fn)
+		case slices.Contains(schemaMapKeywords, key):
+			yamlnode.Pairs(value, func(name, child *yaml.Node) {
+				walkSchema(child, yamlnode.Join(at, name.Value), fn)
+			})
+		case slices.Contains(schemaListKeywords, key):
+			for j, child := range yamlnode.Items(value) {
+				walkSchema(child, yamlnode.Index(at, j), fn)
+			}
+		}
+	}
+}
+
+func isTrue(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && strings.EqualFold(node.Value, "true")
+}
+
+func sequence(items ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: items}
+}
+
+// pair returns a mapping node holding a single key and value.
+func pair(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{yamlnode.String(key), value}}
+}
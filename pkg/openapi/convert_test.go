@@ -0,0 +1,379 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func convert(t *testing.T, src, version string) (any, []string) {
+	t.Helper()
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &root); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := ConvertNode(&root, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got any
+	if err := root.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]string, len(warnings))
+	for i, w := range warnings {
+		messages[i] = w.String()
+	}
+	return got, messages
+}
+
+func decode(t *testing.T, src string) any {
+	t.Helper()
+	var v any
+	if err := yaml.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestConvertNode_Up(t *testing.T) {
+	src := `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /items:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          example: 10
+          schema: {type: integer, minimum: 0, exclusiveMinimum: true, maximum: 100, exclusiveMaximum: false, nullable: false}
+      responses:
+        "200":
+          description: ok
+        default:
+          description: error
+          content:
+            application/json:
+              schema: {type: string, nullable: true}
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        name: {type: string, nullable: true, example: box}
+        size: {type: string, enum: [S, M], nullable: true}
+        owner:
+          nullable: true
+          allOf: [{$ref: "#/components/schemas/Owner"}]
+        tag:
+          nullable: true
+          oneOf: [{type: string}, {type: integer}]
+    Owner: {type: object}
+`
+	want := `openapi: 3.1.0
+info: {title: T, version: "1"}
+paths:
+  /items:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          example: 10
+          schema: {type: integer, exclusiveMinimum: 0, maximum: 100}
+      responses:
+        "200":
+          description: ok
+        default:
+          description: error
+          content:
+            application/json:
+              schema: {type: [string, "null"]}
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        name: {type: [string, "null"], examples: [box]}
+        size: {type: [string, "null"], enum: [S, M, null]}
+        owner:
+          anyOf:
+            - allOf: [{$ref: "#/components/schemas/Owner"}]
+            - type: "null"
+        tag:
+          oneOf: [{type: string}, {type: integer}, {type: "null"}]
+    Owner: {type: object}
+`
+	got, warnings := convert(t, src, "3.1")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("ConvertNode() =\n%s", out)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestConvertNode_Down(t *testing.T) {
+	src := `openapi: 3.1.0
+info: {title: T, summary: S, version: "1", license: {name: MIT, identifier: MIT}}
+jsonSchemaDialect: https://spec.openapis.org/oas/3.1/dialect/base
+paths:
+  /items:
+    $ref: "#/components/pathItems/Items"
+webhooks:
+  created: {post: {responses: {"200": {description: ok}}}}
+components:
+  pathItems:
+    Items:
+      get: {responses: {"200": {description: ok}}}
+  schemas:
+    Item:
+      type: object
+      properties:
+        name: {type: [string, "null"], examples: [a, b]}
+        id: {type: [string, integer]}
+        kind: {const: item}
+        size: {type: number, minimum: 5, exclusiveMinimum: 1, exclusiveMaximum: 10}
+        owner: {$ref: "#/components/schemas/Owner", description: The owner}
+        data: {type: string, contentEncoding: base64}
+        pair: {type: array, prefixItems: [{type: string}]}
+        parent:
+          anyOf:
+            - {$ref: "#/components/schemas/Owner", description: The parent}
+            - type: "null"
+        choice:
+          oneOf: [{type: string}, {type: integer}, {type: "null"}]
+        nothing: {type: "null"}
+    Owner: {type: object}
+`
+	want := `openapi: 3.0.3
+info: {title: T, version: "1", license: {name: MIT}}
+paths:
+  /items:
+    get: {responses: {"200": {description: ok}}}
+components:
+  schemas:
+    Item:
+      type: object
+      properties:
+        name: {type: string, nullable: true, example: a}
+        id: {anyOf: [{type: string}, {type: integer}]}
+        kind: {enum: [item]}
+        size: {type: number, minimum: 5, maximum: 10, exclusiveMaximum: true}
+        owner: {description: The owner, allOf: [{$ref: "#/components/schemas/Owner"}]}
+        data: {type: string, format: byte}
+        pair: {type: array}
+        parent: {description: The parent, nullable: true, allOf: [{$ref: "#/components/schemas/Owner"}]}
+        choice: {oneOf: [{type: string}, {type: integer}], nullable: true}
+        nothing: {enum: [null], nullable: true}
+    Owner: {type: object}
+`
+	got, warnings := convert(t, src, "3.0")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("ConvertNode() =\n%s", out)
+	}
+	wantWarnings := []string{
+		"/webhooks: removed webhooks, which OpenAPI 3.0 does not support",
+		"/jsonSchemaDialect: removed jsonSchemaDialect, which OpenAPI 3.0 does not support",
+		"/info/summary: removed info summary, which OpenAPI 3.0 does not support",
+		"/info/license/identifier: removed license identifier, which OpenAPI 3.0 does not support",
+		"/components/schemas/Item/properties/name: only the first of 2 examples is kept",
+		"/components/schemas/Item/properties/pair: removed prefixItems, which OpenAPI 3.0 schemas do not support",
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings =\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestConvertNode_From32(t *testing.T) {
+	src := `openapi: 3.2.0
+$self: https://example.com/openapi.yaml
+info: {title: T, version: "1"}
+tags:
+  - {name: pets, summary: Pets, kind: nav}
+paths:
+  /items:
+    query:
+      responses: {"200": {description: ok}}
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/jsonl:
+              itemSchema: {type: string}
+`
+	want := `openapi: 3.1.0
+info: {title: T, version: "1"}
+tags:
+  - {name: pets}
+paths:
+  /items:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/jsonl: {}
+`
+	got, warnings := convert(t, src, "3.1")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("ConvertNode() =\n%s", out)
+	}
+	if len(warnings) != 5 {
+		t.Errorf("warnings =\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestConvertNode_NamedLikeData(t *testing.T) {
+	src := `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths: {}
+components:
+  parameters:
+    value:
+      name: value
+      in: query
+      schema: {type: string, nullable: true}
+  responses:
+    example:
+      description: ok
+      content:
+        application/json:
+          schema: {type: string, nullable: true}
+          examples:
+            value:
+              value: {type: string, nullable: true}
+`
+	want := `openapi: 3.1.0
+info: {title: T, version: "1"}
+paths: {}
+components:
+  parameters:
+    value:
+      name: value
+      in: query
+      schema: {type: [string, "null"]}
+  responses:
+    example:
+      description: ok
+      content:
+        application/json:
+          schema: {type: [string, "null"]}
+          examples:
+            value:
+              value: {type: string, nullable: true}
+`
+	got, warnings := convert(t, src, "3.1")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("ConvertNode() =\n%s", out)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestConvertNode_Errors(t *testing.T) {
+	tests := []struct {
+		name, src, version, want string
+	}{
+		{"target", "openapi: 3.0.3", "4.0", `unsupported target version "4.0" (want 3.0, 3.1 or 3.2)`},
+		{"swagger", "swagger: '2.0'", "3.1", "swagger 2.0 documents must be upgraded first"},
+		{"source", "openapi: 2.5.0", "3.1", `unsupported OpenAPI version "2.5.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.src), &root); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ConvertNode(&root, tt.version); err == nil || err.Error() != tt.want {
+				t.Errorf("ConvertNode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_Convert(t *testing.T) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "T", Version: "1"},
+		Components: &Components{Schemas: map[string]*Schema{
+			"Age": {
+				Type:                 NewSchemaType(TypeInteger),
+				Nullable:             true,
+				Minimum:              ptrFloat(0),
+				ExclusiveMinimumFlag: ptrBool(true),
+				Example:              3,
+			},
+		}},
+	}
+	if _, err := doc.Convert("3.1.1"); err != nil {
+		t.Fatal(err)
+	}
+	age := doc.Components.Schemas["Age"]
+	if doc.OpenAPI != "3.1.1" {
+		t.Errorf("OpenAPI = %q", doc.OpenAPI)
+	}
+	if !reflect.DeepEqual(age.Type, SchemaType{TypeInteger, TypeNull}) || age.Nullable {
+		t.Errorf("Type = %v, Nullable = %v", age.Type, age.Nullable)
+	}
+	if age.Minimum != nil || age.ExclusiveMinimum == nil || *age.ExclusiveMinimum != 0 || age.ExclusiveMinimumFlag != nil {
+		t.Errorf("Minimum = %v, ExclusiveMinimum = %+v", age.Minimum, age.ExclusiveMinimum)
+	}
+	if age.Example != nil || !reflect.DeepEqual(age.Examples, []any{3}) {
+		t.Errorf("Example = %v, Examples = %v", age.Example, age.Examples)
+	}
+
+	if _, err := doc.Convert("3.0"); err != nil {
+		t.Fatal(err)
+	}
+	age = doc.Components.Schemas["Age"]
+	if !age.Nullable || age.ExclusiveMinimum != nil || age.ExclusiveMinimumFlag == nil || !*age.ExclusiveMinimumFlag || age.Minimum == nil || *age.Minimum != 0 {
+		t.Errorf("round trip: %+v", age)
+	}
+}
+
+func TestSchema_ExclusiveFlags(t *testing.T) {
+	var s Schema
+	if err := yaml.Unmarshal([]byte("exclusiveMinimum: true\nexclusiveMaximum: 9.5"), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.ExclusiveMinimum != nil || s.ExclusiveMinimumFlag == nil || !*s.ExclusiveMinimumFlag ||
+		s.ExclusiveMaximumFlag != nil || s.ExclusiveMaximum == nil || *s.ExclusiveMaximum != 9.5 {
+		t.Errorf("UnmarshalYAML() = %+v", s)
+	}
+	data, err := yaml.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "exclusiveMinimum: true\nexclusiveMaximum: 9.5\n" {
+		t.Errorf("MarshalYAML() = %q", got)
+	}
+
+	var fromJSON Schema
+	if err := json.Unmarshal([]byte(`{"exclusiveMinimum":false,"exclusiveMaximum":3}`), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(&fromJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"exclusiveMinimum":false,"exclusiveMaximum":3}` {
+		t.Errorf("MarshalJSON() = %s", got)
+	}
+}
+
+func ptrFloat(f float64) *float64 {
+	return &f
+}
+
+func ptrBool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import "strings"
+
+// SchemaExample generates example data from a schema: its example when it has one, otherwise a
+// placeholder for its type, built up from the properties and items of objects and arrays.
+// References to component schemas are followed; a schema that refers back to itself ends in nil.
+func (d *Document) SchemaExample(schema *Schema) any {
+	return d.schemaExample(schema, make(map[string]bool))
+}
+
+func (d *Document) schemaExample(schema *Schema, seen map[string]bool) any {
+	if schema == nil {
+		return nil
+	}
+
+	if resolved := d.resolveSchemaRef(schema); resolved != nil {
+		if seen[schema.Ref] {
+			return nil
+		}
+		seen[schema.Ref] = true
+		defer delete(seen, schema.Ref)
+		return d.schemaExample(resolved, seen)
+	}
+
+	if schema.Example != nil {
+		return schema.Example
+	}
+
+	for _, t := range schema.Type {
+		if t != TypeNull {
+			return d.exampleByType(t, schema, seen)
+		}
+	}
+	return nil
+}
+
+// resolveSchemaRef resolves a $ref to the actual schema, returns nil if not a ref
+func (d *Document) resolveSchemaRef(schema *Schema) *Schema {
+	if schema.Ref == "" {
+		return nil
+	}
+	refName := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+	if d.Components != nil && d.Components.Schemas != nil {
+		if refSchema, ok := d.Components.Schemas[refName]; ok {
+			return refSchema
+		}
+	}
+	return nil
+}
+
+// exampleByType generates an example value based on schema type
+func (d *Document) exampleByType(schemaType string, schema *Schema, seen map[string]bool) any {
+	switch schemaType {
+	case TypeString:
+		return stringExample(schema)
+	case TypeInteger:
+		return 1
+	case TypeNumber:
+		return 1.0
+	case TypeBoolean:
+		return true
+	case TypeArray:
+		if schema.Items != nil {
+			return []any{d.schemaExample(schema.Items, seen)}
+		}
+		return []any{}
+	case TypeObject:
+		obj := make(map[string]any)
+		for propName, propSchema := range schema.Properties {
+			obj[propName] = d.schemaExample(propSchema, seen)
+		}
+		return obj
+	default:
+		return nil
+	}
+}
+
+// stringExample generates example string based on format
+func stringExample(schema *Schema) string {
+	if len(schema.Enum) > 0 {
+		if s, ok := schema.Enum[0].(string); ok {
+			return s
+		}
+	}
+	switch schema.Format {
+	case "date":
+		return "2024-01-15"
+	case "date-time":
+		return "2024-01-15T10:30:00Z"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	case "uuid":
+		return "550e8400-e29b-41d4-a716-446655440000"
+	default:
+		return "string"
+	}
+}
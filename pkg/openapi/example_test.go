@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringExample(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"date", "2024-01-15"},
+		{"date-time", "2024-01-15T10:30:00Z"},
+		{"email", "user@example.com"},
+		{"uri", "https://example.com"},
+		{"uuid", "550e8400-e29b-41d4-a716-446655440000"},
+		{"", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			schema := &Schema{Format: tt.format}
+			result := stringExample(schema)
+			if result != tt.expected {
+				t.Errorf("stringExample with format %s = %s, want %s", tt.format, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDocument_SchemaExample(t *testing.T) {
+	doc := &Document{Components: &Components{Schemas: map[string]*Schema{
+		"Pet": {
+			Type: NewSchemaType(TypeObject),
+			Properties: map[string]*Schema{
+				"id":     {Type: NewSchemaType(TypeInteger), Example: 10},
+				"name":   {Type: SchemaType{TypeNull, TypeString}},
+				"status": {Type: NewSchemaType(TypeString), Enum: []any{"available", "sold"}},
+				"tags":   ArraySchema(StringSchema()),
+				"parent": RefTo("Pet"),
+			},
+		},
+	}}}
+
+	got := doc.SchemaExample(RefTo("Pet"))
+	want := map[string]any{
+		"id":     10,
+		"name":   "string",
+		"status": "available",
+		"tags":   []any{"string"},
+		"parent": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaExample() = %#v, want %#v", got, want)
+	}
+	if got := doc.SchemaExample(RefTo("Missing")); got != nil {
+		t.Errorf("SchemaExample() of an unresolved $ref = %#v, want nil", got)
+	}
+}
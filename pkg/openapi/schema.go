@@ -1,6 +1,11 @@
 package openapi
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
 
 // Schema represents a JSON Schema object that describes the structure of data.
 // https://spec.openapis.org/oas/v3.1.0#schema-object
@@ -31,6 +36,12 @@ type Schema struct {
 	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
 	MultipleOf       *float64 `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
 
+	// OpenAPI 3.0 writes exclusiveMinimum and exclusiveMaximum as booleans that make minimum and
+	// maximum exclusive. They are read into these fields, which are written in place of
+	// ExclusiveMinimum and ExclusiveMaximum when set.
+	ExclusiveMinimumFlag *bool `json:"-" yaml:"-"`
+	ExclusiveMaximumFlag *bool `json:"-" yaml:"-"`
+
 	// Array validation
 	Items       *Schema `json:"items,omitempty" yaml:"items,omitempty"`
 	MinItems    *int64  `json:"minItems,omitempty" yaml:"minItems,omitempty"`
@@ -58,6 +69,11 @@ type Schema struct {
 
 	// XML
 	XML *XML `json:"xml,omitempty" yaml:"xml,omitempty"`
+
+	// Bool is set when the schema was read as a boolean, such as "additionalProperties: false".
+	// The schema also holds the object form of the boolean, and is written back as the boolean
+	// as long as it still equals that form.
+	Bool *bool `json:"-" yaml:"-"`
 }
 
 // SchemaType represents the type field which can be a single type or array of types.
@@ -132,6 +148,207 @@ func (s SchemaType) MarshalYAML() (interface{}, error) {
 	return []string(s), nil
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler.
+// Handles both string (OpenAPI 3.0) and sequence (OpenAPI 3.1+) formats.
+func (s *SchemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = SchemaType{node.Value}
+		return nil
+	}
+	var arr []string
+	if err := node.Decode(&arr); err != nil {
+		return err
+	}
+	*s = arr
+	return nil
+}
+
+// boolSchema returns the schema equivalent to a boolean schema: true accepts any value and is
+// the empty schema, false accepts none and is {"not": {}}.
+func boolSchema(b bool) Schema {
+	if b {
+		return Schema{Bool: &b}
+	}
+	return Schema{Not: &Schema{}, Bool: &b}
+}
+
+// boolForm returns the boolean a schema was read as, when it still equals its object form.
+func (s *Schema) boolForm() (value, ok bool) {
+	if s.Bool == nil {
+		return false, false
+	}
+	if *s.Bool {
+		return true, s.IsEmpty()
+	}
+	return false, s.IsFalse()
+}
+
+// IsFalse reports whether the schema accepts no value, as "additionalProperties: false" does.
+func (s *Schema) IsFalse() bool {
+	return s != nil && s.Not != nil && s.Not.IsEmpty() && s.withoutNot().IsEmpty()
+}
+
+// IsEmpty reports whether the schema has no keywords and so accepts any value, as
+// "additionalProperties: true" does.
+func (s *Schema) IsEmpty() bool {
+	if s == nil {
+		return false
+	}
+	type plain Schema
+	c := *s
+	c.Bool = nil
+	data, err := json.Marshal((*plain)(&c))
+	return err == nil && string(data) == "{}" && s.ExclusiveMinimumFlag == nil && s.ExclusiveMaximumFlag == nil
+}
+
+func (s *Schema) withoutNot() *Schema {
+	c := *s
+	c.Not = nil
+	return &c
+}
+
+// MarshalJSON implements json.Marshaler.
+// Schemas read as booleans are written back as booleans.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if b, ok := s.boolForm(); ok {
+		return json.Marshal(b)
+	}
+	type plain Schema
+	if s.ExclusiveMinimumFlag == nil && s.ExclusiveMaximumFlag == nil {
+		return json.Marshal(plain(s))
+	}
+	return json.Marshal(struct {
+		plain
+		ExclusiveMinimum any `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum any `json:"exclusiveMaximum,omitempty"`
+	}{plain(s), exclusiveBound(s.ExclusiveMinimum, s.ExclusiveMinimumFlag), exclusiveBound(s.ExclusiveMaximum, s.ExclusiveMaximumFlag)})
+}
+
+// MarshalYAML implements yaml.Marshaler.
+// Schemas read as booleans are written back as booleans.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	if b, ok := s.boolForm(); ok {
+		return b, nil
+	}
+	type plain Schema
+	if s.ExclusiveMinimumFlag == nil && s.ExclusiveMaximumFlag == nil {
+		return plain(s), nil
+	}
+	// Encoded with a placeholder bound in place of each flag, so the keys keep their place
+	flags := map[string]*bool{"exclusiveMinimum": s.ExclusiveMinimumFlag, "exclusiveMaximum": s.ExclusiveMaximumFlag}
+	var placeholder float64
+	if s.ExclusiveMinimumFlag != nil {
+		s.ExclusiveMinimum = &placeholder
+	}
+	if s.ExclusiveMaximumFlag != nil {
+		s.ExclusiveMaximum = &placeholder
+	}
+	var node yaml.Node
+	if err := node.Encode(plain(s)); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if flag := flags[node.Content[i].Value]; flag != nil {
+			node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(*flag)}
+		}
+	}
+	return &node, nil
+}
+
+// exclusiveBound returns the value exclusiveMinimum or exclusiveMaximum is written as: the
+// OpenAPI 3.0 flag when set, or else the bound.
+func exclusiveBound(bound *float64, flag *bool) any {
+	if flag != nil {
+		return *flag
+	}
+	if bound != nil {
+		return *bound
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Boolean schemas, such as "additionalProperties": false, are decoded to their object form.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*s = boolSchema(b)
+		return nil
+	}
+	type plain Schema
+	aux := struct {
+		*plain
+		ExclusiveMinimum json.RawMessage `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum json.RawMessage `json:"exclusiveMaximum,omitempty"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if s.ExclusiveMinimum, s.ExclusiveMinimumFlag, err = decodeExclusive(aux.ExclusiveMinimum); err != nil {
+		return err
+	}
+	s.ExclusiveMaximum, s.ExclusiveMaximumFlag, err = decodeExclusive(aux.ExclusiveMaximum)
+	return err
+}
+
+// decodeExclusive decodes exclusiveMinimum or exclusiveMaximum, a number in OpenAPI 3.1+ and a
+// boolean in OpenAPI 3.0.
+func decodeExclusive(data json.RawMessage) (*float64, *bool, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil, nil
+	}
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		return nil, &b, nil
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, nil, err
+	}
+	return &v, nil, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+// Boolean schemas, such as "additionalProperties: false", are decoded to their object form.
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		*s = boolSchema(b)
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		// The OpenAPI 3.0 boolean form of exclusiveMinimum and exclusiveMaximum goes to the flags
+		c := *node
+		c.Content = nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			var flag **bool
+			switch key.Value {
+			case "exclusiveMinimum":
+				flag = &s.ExclusiveMinimumFlag
+			case "exclusiveMaximum":
+				flag = &s.ExclusiveMaximumFlag
+			}
+			if flag != nil && value.Kind == yaml.ScalarNode && value.Tag == "!!bool" {
+				var b bool
+				if err := value.Decode(&b); err != nil {
+					return err
+				}
+				*flag = &b
+				continue
+			}
+			c.Content = append(c.Content, key, value)
+		}
+		node = &c
+	}
+	type plain Schema
+	return node.Decode((*plain)(s))
+}
+
 // Discriminator is used when request bodies or response payloads may be one of a number of different schemas.
 // https://spec.openapis.org/oas/v3.1.0#discriminator-object
 type Discriminator struct {
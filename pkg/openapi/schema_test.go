@@ -364,3 +364,84 @@ func TestSchema_Discriminator(t *testing.T) {
 		t.Errorf("Discriminator.Mapping length = %d, want 2", len(schema.Discriminator.Mapping))
 	}
 }
+
+func TestSchemaType_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		input string
+		want  SchemaType
+	}{
+		{"type: string", SchemaType{"string"}},
+		{"type: [string, \"null\"]", SchemaType{"string", "null"}},
+	}
+
+	for _, tt := range tests {
+		var s Schema
+		if err := yaml.Unmarshal([]byte(tt.input), &s); err != nil {
+			t.Fatalf("yaml.Unmarshal(%q) error = %v", tt.input, err)
+		}
+		if len(s.Type) != len(tt.want) || s.Type[0] != tt.want[0] {
+			t.Errorf("yaml.Unmarshal(%q) Type = %v, want %v", tt.input, s.Type, tt.want)
+		}
+	}
+}
+
+func TestSchema_UnmarshalBoolean(t *testing.T) {
+	const doc = `{"type": "object", "properties": {"a": true}, "additionalProperties": false}`
+
+	var fromJSON, fromYAML Schema
+	if err := json.Unmarshal([]byte(doc), &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(doc), &fromYAML); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	for name, s := range map[string]Schema{"JSON": fromJSON, "YAML": fromYAML} {
+		if !s.AdditionalProperties.IsFalse() {
+			t.Errorf("%s: additionalProperties false should decode to a false schema, got %+v", name, s.AdditionalProperties)
+		}
+		if !s.Properties["a"].IsEmpty() || s.Properties["a"].IsFalse() {
+			t.Errorf("%s: true should decode to the empty schema, got %+v", name, s.Properties["a"])
+		}
+		if s.IsEmpty() || s.IsFalse() {
+			t.Errorf("%s: object schema reported as empty or false", name)
+		}
+	}
+}
+
+func TestSchema_BooleanRoundTrip(t *testing.T) {
+	const doc = `{"type":"object","properties":{"tags":{"type":"array","items":true}},"additionalProperties":false}`
+
+	var fromJSON Schema
+	if err := json.Unmarshal([]byte(doc), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&fromJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != doc {
+		t.Errorf("JSON round trip = %s, want %s", data, doc)
+	}
+
+	var fromYAML Schema
+	if err := yaml.Unmarshal([]byte(doc), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	out, err := yaml.Marshal(&fromYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"additionalProperties: false\n", "items: true\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("YAML round trip lost %q:\n%s", want, out)
+		}
+	}
+
+	// A boolean schema that gained keywords is written as an object
+	fromJSON.AdditionalProperties.Not = nil
+	fromJSON.AdditionalProperties.Type = NewSchemaType(TypeString)
+	if data, _ := json.Marshal(&fromJSON); !strings.Contains(string(data), `"additionalProperties":{"type":"string"}`) {
+		t.Errorf("changed boolean schema = %s", data)
+	}
+}
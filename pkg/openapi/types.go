@@ -13,6 +13,18 @@ type Document struct {
 	Security     []SecurityRequirement  `json:"security,omitempty" yaml:"security,omitempty"`
 	Tags         []Tag                  `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+
+	// SourceOrder records the order the keys of maps such as Paths were declared in,
+	// by the JSON pointer of the map. It is not part of the document.
+	SourceOrder map[string][]string `json:"-" yaml:"-"`
+}
+
+// SetSourceOrder records the order the keys of the map at pointer were declared in.
+func (d *Document) SetSourceOrder(pointer string, keys []string) {
+	if d.SourceOrder == nil {
+		d.SourceOrder = make(map[string][]string)
+	}
+	d.SourceOrder[pointer] = keys
 }
 
 // Info provides metadata about the API.
@@ -0,0 +1,614 @@
+package openapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// UpgradeNode converts a Swagger 2.0 document held as a YAML node, in place, to an OpenAPI version:
+// "3.0", "3.1" or "3.2", or a full version such as "3.0.3". Documents that already are OpenAPI 3.x
+// are converted with ConvertNode.
+//
+// definitions, parameters, responses and securityDefinitions move to components. Body and formData
+// parameters become request bodies and response schemas become content, both with the media types of
+// consumes and produces (application/json when there are none). host, basePath and schemes become
+// servers, with https when schemes is missing. What OpenAPI 3 cannot express, such as a tsv
+// collectionFormat, is dropped with a warning.
+func UpgradeNode(root *yaml.Node, version string) ([]ConversionWarning, error) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	if !versionPattern.MatchString(version) {
+		return nil, fmt.Errorf("unsupported target version %q (want 3.0, 3.1 or 3.2)", version)
+	}
+	swagger := yamlnode.Get(root, "swagger")
+	if swagger == nil {
+		return ConvertNode(root, version)
+	}
+	if !strings.HasPrefix(swagger.Value, "2.") {
+		return nil, fmt.Errorf("unsupported Swagger version %q", swagger.Value)
+	}
+
+	u := &upgrader{
+		converter: converter{root: root},
+		source:    &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: root.Content},
+	}
+	u.upgrade()
+	warnings, err := ConvertNode(root, version)
+	if err != nil {
+		return nil, err
+	}
+	return append(u.warnings, warnings...), nil
+}
+
+// upgrader holds the state of a single Swagger 2.0 upgrade
+type upgrader struct {
+	converter
+	source   *yaml.Node // the Swagger 2.0 document
+	consumes []string   // media types of request bodies, unless an operation has its own
+	produces []string   // media types of responses, unless an operation has its own
+}
+
+// swaggerSchemaFields are the fields with which Swagger 2.0 describes the value of a parameter, header
+// or items object, and which move to a schema in OpenAPI 3
+var swaggerSchemaFields = []string{
+	"type", "format", "items", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+var swaggerMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// upgrade replaces the content of the root with its OpenAPI 3.0 form.
+func (u *upgrader) upgrade() {
+	u.consumes = scalars(yamlnode.Get(u.source, "consumes"))
+	u.produces = scalars(yamlnode.Get(u.source, "produces"))
+
+	out := yamlnode.Mapping()
+	out.Content = append(out.Content, yamlnode.String("openapi"), yamlnode.String(latestPatch["3.0"]))
+	if info := yamlnode.Get(u.source, "info"); info != nil {
+		out.Content = append(out.Content, yamlnode.String("info"), info)
+	}
+	if servers := u.servers(scalars(yamlnode.Get(u.source, "schemes"))); servers != nil {
+		out.Content = append(out.Content, yamlnode.String("servers"), servers)
+	}
+	yamlnode.Pairs(u.source, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "swagger", "info", "host", "basePath", "schemes", "consumes", "produces",
+			"definitions", "parameters", "responses", "securityDefinitions":
+		case "paths":
+			out.Content = append(out.Content, key, u.paths(value))
+		default:
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	if components := u.components(); len(components.Content) > 0 {
+		out.Content = append(out.Content, yamlnode.String("components"), components)
+	}
+
+	u.root.Content = out.Content
+	rewriteSwaggerRefs(u.root, yamlnode.Scope{})
+	u.eachSchema(upgradeSchema)
+}
+
+// servers returns the servers for host and basePath with each of schemes, or nil if the document has
+// neither host nor basePath.
+func (u *upgrader) servers(schemes []string) *yaml.Node {
+	host := yamlnode.Scalar(yamlnode.Get(u.source, "host"))
+	basePath := strings.TrimSuffix(yamlnode.Scalar(yamlnode.Get(u.source, "basePath")), "/")
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return sequence(pair("url", yamlnode.String(basePath)))
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+	servers := sequence()
+	for _, scheme := range schemes {
+		servers.Content = append(servers.Content, pair("url", yamlnode.String(scheme+"://"+host+basePath)))
+	}
+	return servers
+}
+
+func (u *upgrader) components() *yaml.Node {
+	components := yamlnode.Mapping()
+	if definitions := yamlnode.Get(u.source, "definitions"); definitions != nil {
+		components.Content = append(components.Content, yamlnode.String("schemas"), definitions)
+	}
+
+	responses := yamlnode.Mapping()
+	yamlnode.Pairs(yamlnode.Get(u.source, "responses"), func(name, response *yaml.Node) {
+		responses.Content = append(responses.Content, name, u.response(response, u.produces))
+	})
+
+	parameters, bodies := yamlnode.Mapping(), yamlnode.Mapping()
+	yamlnode.Pairs(yamlnode.Get(u.source, "parameters"), func(name, param *yaml.Node) {
+		switch yamlnode.Scalar(yamlnode.Get(param, "in")) {
+		case "body":
+			bodies.Content = append(bodies.Content, name, u.bodyParameter(param, u.consumes))
+		case "formData":
+			// Inlined into the request bodies of the operations that refer to it
+		default:
+			parameters.Content = append(parameters.Content, name, u.parameter(param, yamlnode.Join("/parameters", name.Value)))
+		}
+	})
+
+	schemes := yamlnode.Mapping()
+	yamlnode.Pairs(yamlnode.Get(u.source, "securityDefinitions"), func(name, scheme *yaml.Node) {
+		schemes.Content = append(schemes.Content, name, u.securityScheme(scheme, yamlnode.Join("/securityDefinitions", name.Value)))
+	})
+
+	for _, c := range []struct {
+		key   string
+		value *yaml.Node
+	}{{"responses", responses}, {"parameters", parameters}, {"requestBodies", bodies}, {"securitySchemes", schemes}} {
+		if len(c.value.Content) > 0 {
+			components.Content = append(components.Content, yamlnode.String(c.key), c.value)
+		}
+	}
+	return components
+}
+
+func (u *upgrader) paths(paths *yaml.Node) *yaml.Node {
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(paths, func(path, item *yaml.Node) {
+		if strings.HasPrefix(path.Value, "x-") || yamlnode.Get(item, "$ref") != nil {
+			out.Content = append(out.Content, path, item)
+			return
+		}
+		out.Content = append(out.Content, path, u.pathItem(item, yamlnode.Join("/paths", path.Value)))
+	})
+	return out
+}
+
+func (u *upgrader) pathItem(item *yaml.Node, pointer string) *yaml.Node {
+	// Body and formData parameters of a path item apply to each of its operations
+	var params, inherited []swaggerParam
+	for _, p := range u.parameterList(yamlnode.Get(item, "parameters"), pointer+"/parameters") {
+		if in := p.in(); in == "body" || in == "formData" {
+			inherited = append(inherited, p)
+		} else {
+			params = append(params, p)
+		}
+	}
+
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(item, func(key, value *yaml.Node) {
+		switch {
+		case key.Value == "parameters":
+			if len(params) > 0 {
+				out.Content = append(out.Content, key, u.parameters(params))
+			}
+		case slices.Contains(swaggerMethods, key.Value):
+			out.Content = append(out.Content, key, u.operation(value, inherited, yamlnode.Join(pointer, key.Value)))
+		default:
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	return out
+}
+
+func (u *upgrader) operation(op *yaml.Node, inherited []swaggerParam, pointer string) *yaml.Node {
+	params := u.parameterList(yamlnode.Get(op, "parameters"), pointer+"/parameters")
+	for _, p := range inherited {
+		// Parameters of the operation override those of the path item with the same name and location
+		if !slices.ContainsFunc(params, func(q swaggerParam) bool { return q.in() == p.in() && q.name() == p.name() }) {
+			params = append(params, p)
+		}
+	}
+	consumes, produces := u.consumes, u.produces
+	if list := yamlnode.Get(op, "consumes"); list != nil {
+		consumes = scalars(list)
+	}
+	if list := yamlnode.Get(op, "produces"); list != nil {
+		produces = scalars(list)
+	}
+	params, body := u.requestBody(params, consumes)
+
+	out := yamlnode.Mapping()
+	addBody := func() {
+		if body != nil {
+			out.Content = append(out.Content, yamlnode.String("requestBody"), body)
+			body = nil
+		}
+	}
+	yamlnode.Pairs(op, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "consumes", "produces":
+		case "schemes":
+			if servers := u.servers(scalars(value)); servers != nil {
+				out.Content = append(out.Content, yamlnode.String("servers"), servers)
+			}
+		case "parameters":
+			if len(params) > 0 {
+				out.Content = append(out.Content, key, u.parameters(params))
+			}
+			addBody()
+		case "responses":
+			addBody()
+			out.Content = append(out.Content, key, u.responses(value, produces))
+		default:
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	addBody()
+	return out
+}
+
+// swaggerParam is a parameter of a path item or an operation
+type swaggerParam struct {
+	node    *yaml.Node // the parameter, or the one in #/parameters it refers to
+	ref     string     // the name of the parameter in #/parameters it refers to, if any
+	pointer string
+}
+
+func (p swaggerParam) in() string   { return yamlnode.Scalar(yamlnode.Get(p.node, "in")) }
+func (p swaggerParam) name() string { return yamlnode.Scalar(yamlnode.Get(p.node, "name")) }
+
+func (u *upgrader) parameterList(list *yaml.Node, pointer string) []swaggerParam {
+	var params []swaggerParam
+	for i, node := range yamlnode.Items(list) {
+		p := swaggerParam{node: node, pointer: yamlnode.Index(pointer, i)}
+		if ref := yamlnode.Scalar(yamlnode.Get(node, "$ref")); strings.HasPrefix(ref, "#/parameters/") {
+			if target := yamlnode.Resolve(u.source, ref); target != nil {
+				p.node, p.ref = target, yamlnode.Unescape(strings.TrimPrefix(ref, "#/parameters/"))
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// requestBody takes the body and formData parameters out of params and returns the request body they
+// make, if any.
+func (u *upgrader) requestBody(params []swaggerParam, consumes []string) ([]swaggerParam, *yaml.Node) {
+	var rest, form []swaggerParam
+	var body *yaml.Node
+	for _, p := range params {
+		switch p.in() {
+		case "body":
+			// The request body in components has the media types of the document
+			if p.ref != "" && slices.Equal(consumes, u.consumes) {
+				body = pair("$ref", yamlnode.String("#/components/requestBodies/"+yamlnode.Escape(p.ref)))
+			} else {
+				body = u.bodyParameter(p.node, consumes)
+			}
+		case "formData":
+			form = append(form, p)
+		default:
+			rest = append(rest, p)
+		}
+	}
+	if body == nil && len(form) > 0 {
+		body = u.formBody(form, consumes)
+	}
+	return rest, body
+}
+
+func (u *upgrader) bodyParameter(param *yaml.Node, consumes []string) *yaml.Node {
+	body := yamlnode.Mapping()
+	if description := yamlnode.Get(param, "description"); description != nil {
+		body.Content = append(body.Content, yamlnode.String("description"), description)
+	}
+	content := yamlnode.Mapping()
+	schema := yamlnode.Get(param, "schema")
+	for _, mediaType := range orJSON(consumes) {
+		media := yamlnode.Mapping()
+		if schema != nil {
+			media.Content = append(media.Content, yamlnode.String("schema"), yamlnode.Clone(schema))
+		}
+		content.Content = append(content.Content, yamlnode.String(mediaType), media)
+	}
+	body.Content = append(body.Content, yamlnode.String("content"), content)
+	if isSet(param, "required") {
+		body.Content = append(body.Content, yamlnode.String("required"), boolean(true))
+	}
+	yamlnode.Pairs(param, func(key, value *yaml.Node) {
+		if strings.HasPrefix(key.Value, "x-") {
+			body.Content = append(body.Content, key, value)
+		}
+	})
+	return body
+}
+
+// formBody returns a request body with an object schema that has a property for each formData
+// parameter. It is multipart/form-data when consumes lists it or a parameter is a file, and
+// application/x-www-form-urlencoded otherwise.
+func (u *upgrader) formBody(params []swaggerParam, consumes []string) *yaml.Node {
+	properties, required := yamlnode.Mapping(), sequence()
+	multipart := slices.Contains(consumes, "multipart/form-data")
+	for _, p := range params {
+		property := valueSchema(p.node)
+		if description := yamlnode.Get(p.node, "description"); description != nil {
+			property.Content = append(property.Content, yamlnode.String("description"), description)
+		}
+		properties.Content = append(properties.Content, yamlnode.String(p.name()), property)
+		if isSet(p.node, "required") {
+			required.Content = append(required.Content, yamlnode.String(p.name()))
+		}
+		if yamlnode.Scalar(yamlnode.Get(p.node, "type")) == "file" {
+			multipart = true
+		}
+	}
+
+	schema := pair("type", yamlnode.String(TypeObject))
+	schema.Content = append(schema.Content, yamlnode.String("properties"), properties)
+	if len(required.Content) > 0 {
+		schema.Content = append(schema.Content, yamlnode.String("required"), required)
+	}
+	mediaType := "application/x-www-form-urlencoded"
+	if multipart {
+		mediaType = "multipart/form-data"
+	}
+	return pair("content", pair(mediaType, pair("schema", schema)))
+}
+
+func (u *upgrader) parameters(params []swaggerParam) *yaml.Node {
+	list := sequence()
+	for _, p := range params {
+		if p.ref != "" {
+			list.Content = append(list.Content, pair("$ref", yamlnode.String("#/components/parameters/"+yamlnode.Escape(p.ref))))
+		} else {
+			list.Content = append(list.Content, u.parameter(p.node, p.pointer))
+		}
+	}
+	return list
+}
+
+func (u *upgrader) parameter(param *yaml.Node, pointer string) *yaml.Node {
+	if yamlnode.Get(param, "$ref") != nil {
+		return param
+	}
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(param, func(key, value *yaml.Node) {
+		switch {
+		case slices.Contains(swaggerSchemaFields, key.Value) || key.Value == "collectionFormat":
+		case key.Value == "x-example":
+			out.Content = append(out.Content, yamlnode.String("example"), value)
+		default:
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	u.collectionFormat(out, param, pointer)
+	out.Content = append(out.Content, yamlnode.String("schema"), valueSchema(param))
+	return out
+}
+
+// collectionFormat sets the style and explode of an array parameter from its collectionFormat.
+func (u *upgrader) collectionFormat(out, param *yaml.Node, pointer string) {
+	if yamlnode.Scalar(yamlnode.Get(param, "type")) != TypeArray {
+		return
+	}
+	in := yamlnode.Scalar(yamlnode.Get(param, "in"))
+	format := yamlnode.Scalar(yamlnode.Get(param, "collectionFormat"))
+	if format == "" {
+		format = "csv"
+	}
+	var style string
+	switch {
+	case format == "csv" && in == "query":
+		style = "form"
+	case format == "csv", format == "multi" && in == "query":
+		// simple for path and header parameters and exploded form for query ones are the defaults
+		return
+	case format == "ssv" && in == "query":
+		style = "spaceDelimited"
+	case format == "pipes" && in == "query":
+		style = "pipeDelimited"
+	default:
+		u.warn(pointer+"/collectionFormat", "collectionFormat %s of a %s parameter has no OpenAPI 3 equivalent", format, in)
+		return
+	}
+	out.Content = append(out.Content, yamlnode.String("style"), yamlnode.String(style), yamlnode.String("explode"), boolean(false))
+}
+
+func (u *upgrader) responses(responses *yaml.Node, produces []string) *yaml.Node {
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(responses, func(code, response *yaml.Node) {
+		if strings.HasPrefix(code.Value, "x-") {
+			out.Content = append(out.Content, code, response)
+			return
+		}
+		out.Content = append(out.Content, code, u.response(response, produces))
+	})
+	return out
+}
+
+func (u *upgrader) response(response *yaml.Node, produces []string) *yaml.Node {
+	if yamlnode.Get(response, "$ref") != nil {
+		return response
+	}
+	schema := yamlnode.Get(response, "schema")
+	content := yamlnode.Mapping()
+	if schema != nil {
+		for _, mediaType := range orJSON(produces) {
+			content.Content = append(content.Content, yamlnode.String(mediaType), pair("schema", yamlnode.Clone(schema)))
+		}
+	}
+	// Examples are keyed by media type, which may be one produces does not list
+	yamlnode.Pairs(yamlnode.Get(response, "examples"), func(mediaType, example *yaml.Node) {
+		media := yamlnode.Get(content, mediaType.Value)
+		if media == nil {
+			media = yamlnode.Mapping()
+			if schema != nil {
+				media.Content = append(media.Content, yamlnode.String("schema"), yamlnode.Clone(schema))
+			}
+			content.Content = append(content.Content, mediaType, media)
+		}
+		yamlnode.SetPair(media, "example", example)
+	})
+
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(response, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "schema", "examples":
+		case "headers":
+			out.Content = append(out.Content, key, headers(value))
+		default:
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	if len(content.Content) > 0 {
+		out.Content = append(out.Content, yamlnode.String("content"), content)
+	}
+	return out
+}
+
+func headers(headers *yaml.Node) *yaml.Node {
+	out := yamlnode.Mapping()
+	yamlnode.Pairs(headers, func(name, header *yaml.Node) {
+		h := yamlnode.Mapping()
+		yamlnode.Pairs(header, func(key, value *yaml.Node) {
+			if !slices.Contains(swaggerSchemaFields, key.Value) && key.Value != "collectionFormat" {
+				h.Content = append(h.Content, key, value)
+			}
+		})
+		h.Content = append(h.Content, yamlnode.String("schema"), valueSchema(header))
+		out.Content = append(out.Content, name, h)
+	})
+	return out
+}
+
+// swaggerFlows maps the oauth2 flows of Swagger 2.0 to their OpenAPI 3 names
+var swaggerFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+func (u *upgrader) securityScheme(scheme *yaml.Node, pointer string) *yaml.Node {
+	out := yamlnode.Mapping()
+	switch yamlnode.Scalar(yamlnode.Get(scheme, "type")) {
+	case "basic":
+		out.Content = append(out.Content, yamlnode.String("type"), yamlnode.String("http"), yamlnode.String("scheme"), yamlnode.String("basic"))
+	case "oauth2":
+		out.Content = append(out.Content, yamlnode.String("type"), yamlnode.String("oauth2"))
+		flow := yamlnode.Mapping()
+		for _, field := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+			if value := yamlnode.Get(scheme, field); value != nil {
+				flow.Content = append(flow.Content, yamlnode.String(field), value)
+			}
+		}
+		if yamlnode.Get(flow, "scopes") == nil {
+			flow.Content = append(flow.Content, yamlnode.String("scopes"), yamlnode.Mapping())
+		}
+		name := yamlnode.Scalar(yamlnode.Get(scheme, "flow"))
+		if swaggerFlows[name] == "" {
+			u.warn(pointer+"/flow", "unknown oauth2 flow %q", name)
+			break
+		}
+		out.Content = append(out.Content, yamlnode.String("flows"), pair(swaggerFlows[name], flow))
+	default:
+		return scheme
+	}
+	yamlnode.Pairs(scheme, func(key, value *yaml.Node) {
+		if key.Value == "description" || strings.HasPrefix(key.Value, "x-") {
+			out.Content = append(out.Content, key, value)
+		}
+	})
+	return out
+}
+
+// valueSchema returns the schema of a parameter, header or items object, whose value Swagger 2.0
+// describes with fields of the object itself.
+func valueSchema(object *yaml.Node) *yaml.Node {
+	schema := yamlnode.Mapping()
+	yamlnode.Pairs(object, func(key, value *yaml.Node) {
+		if !slices.Contains(swaggerSchemaFields, key.Value) {
+			return
+		}
+		if key.Value == "items" {
+			value = valueSchema(value)
+		}
+		schema.Content = append(schema.Content, key, value)
+	})
+	return schema
+}
+
+// upgradeSchema converts what differs between Swagger 2.0 and OpenAPI 3.0 schemas.
+func upgradeSchema(schema *yaml.Node, _ string) {
+	if yamlnode.Scalar(yamlnode.Get(schema, "type")) == "file" {
+		yamlnode.SetPair(schema, "type", yamlnode.String(TypeString))
+		yamlnode.SetPair(schema, "format", yamlnode.String("binary"))
+	}
+	if nullable := yamlnode.Get(schema, "x-nullable"); nullable != nil {
+		yamlnode.RemoveKey(schema, "x-nullable")
+		if isTrue(nullable) {
+			yamlnode.SetPair(schema, "nullable", boolean(true))
+		}
+	}
+	if discriminator := yamlnode.Get(schema, "discriminator"); discriminator != nil && discriminator.Kind == yaml.ScalarNode {
+		yamlnode.SetPair(schema, "discriminator", pair("propertyName", discriminator))
+	}
+}
+
+// swaggerRefPrefixes map the places $refs point to in Swagger 2.0 to where they are in OpenAPI 3
+var swaggerRefPrefixes = [][2]string{
+	{"#/definitions/", "#/components/schemas/"},
+	{"#/parameters/", "#/components/parameters/"},
+	{"#/responses/", "#/components/responses/"},
+}
+
+// rewriteSwaggerRefs points the $refs of an upgraded document, including those to other files, to
+// where what they refer to moved. The scope tells the keys of node apart from property and
+// component names.
+func rewriteSwaggerRefs(node *yaml.Node, scope yamlnode.Scope) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case scope.Names:
+				rewriteSwaggerRefs(value, scope.Child(key))
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				file, fragment, _ := strings.Cut(value.Value, "#")
+				for _, prefix := range swaggerRefPrefixes {
+					if rest, ok := strings.CutPrefix("#"+fragment, prefix[0]); ok {
+						value.Value = file + prefix[1] + rest
+						break
+					}
+				}
+			case !scope.IsExample(key) && !strings.HasPrefix(key, "x-"):
+				rewriteSwaggerRefs(value, scope.Child(key))
+			}
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			rewriteSwaggerRefs(child, scope.Item())
+		}
+	}
+}
+
+func scalars(node *yaml.Node) []string {
+	var values []string
+	for _, item := range yamlnode.Items(node) {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+func orJSON(mediaTypes []string) []string {
+	if len(mediaTypes) == 0 {
+		return []string{"application/json"}
+	}
+	return mediaTypes
+}
+
+func isSet(node *yaml.Node, key string) bool {
+	value := yamlnode.Get(node, key)
+	return value != nil && isTrue(value)
+}
+
+func boolean(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}
+}
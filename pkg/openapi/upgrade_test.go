@@ -0,0 +1,331 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func upgrade(t *testing.T, src, version string) (any, []string) {
+	t.Helper()
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &root); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := UpgradeNode(&root, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got any
+	if err := root.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]string, len(warnings))
+	for i, w := range warnings {
+		messages[i] = w.String()
+	}
+	return got, messages
+}
+
+const swaggerPetstore = `swagger: "2.0"
+info: {title: Petstore, version: "1"}
+host: api.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json]
+produces: [application/json, application/xml]
+tags: [{name: pets}]
+paths:
+  /pets:
+    parameters:
+      - $ref: "#/parameters/Trace"
+    get:
+      tags: [pets]
+      parameters:
+        - name: tags
+          in: query
+          type: array
+          items: {type: string}
+          collectionFormat: csv
+        - name: ids
+          in: query
+          type: array
+          items: {type: integer}
+          collectionFormat: tsv
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit: {type: integer, description: Calls left}
+          schema:
+            type: array
+            items: {$ref: "#/definitions/Pet"}
+          examples:
+            application/json: [{name: Rex}]
+        default: {$ref: "#/responses/Error"}
+    post:
+      parameters:
+        - $ref: "#/parameters/NewPet"
+      responses:
+        "201": {description: created}
+      security: [{oauth: [write]}]
+  /pets/{id}/photo:
+    post:
+      consumes: [multipart/form-data]
+      schemes: [https]
+      parameters:
+        - {name: id, in: path, required: true, type: integer, format: int64}
+        - {name: photo, in: formData, required: true, type: file}
+        - {name: caption, in: formData, type: string, description: Caption}
+      responses:
+        "204": {description: uploaded}
+parameters:
+  Trace: {name: X-Trace, in: header, type: string}
+  NewPet:
+    name: pet
+    in: body
+    required: true
+    schema: {$ref: "#/definitions/Pet"}
+responses:
+  Error:
+    description: error
+    schema: {$ref: "#/definitions/Error"}
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    required: [name, kind]
+    properties:
+      name: {type: string}
+      kind: {type: string}
+      tag: {type: string, x-nullable: true}
+  Error: {type: object}
+securityDefinitions:
+  basic: {type: basic}
+  key: {type: apiKey, in: header, name: X-Key}
+  oauth:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://example.com/auth
+    tokenUrl: https://example.com/token
+    scopes: {write: Write}
+    description: OAuth
+`
+
+func TestUpgradeNode(t *testing.T) {
+	want := `openapi: 3.0.3
+info: {title: Petstore, version: "1"}
+servers:
+  - url: https://api.example.com/v1
+  - url: http://api.example.com/v1
+tags: [{name: pets}]
+paths:
+  /pets:
+    parameters:
+      - $ref: "#/components/parameters/Trace"
+    get:
+      tags: [pets]
+      parameters:
+        - name: tags
+          in: query
+          style: form
+          explode: false
+          schema: {type: array, items: {type: string}}
+        - name: ids
+          in: query
+          schema: {type: array, items: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Rate-Limit: {description: Calls left, schema: {type: integer}}
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+              example: [{name: Rex}]
+            application/xml:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+        default: {$ref: "#/components/responses/Error"}
+    post:
+      requestBody: {$ref: "#/components/requestBodies/NewPet"}
+      responses:
+        "201": {description: created}
+      security: [{oauth: [write]}]
+  /pets/{id}/photo:
+    post:
+      servers:
+        - url: https://api.example.com/v1
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer, format: int64}}
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                photo: {type: string, format: binary}
+                caption: {type: string, description: Caption}
+              required: [photo]
+      responses:
+        "204": {description: uploaded}
+components:
+  schemas:
+    Pet:
+      type: object
+      discriminator: {propertyName: kind}
+      required: [name, kind]
+      properties:
+        name: {type: string}
+        kind: {type: string}
+        tag: {type: string, nullable: true}
+    Error: {type: object}
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+        application/xml:
+          schema: {$ref: "#/components/schemas/Error"}
+  parameters:
+    Trace: {name: X-Trace, in: header, schema: {type: string}}
+  requestBodies:
+    NewPet:
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Pet"}
+      required: true
+  securitySchemes:
+    basic: {type: http, scheme: basic}
+    key: {type: apiKey, in: header, name: X-Key}
+    oauth:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: https://example.com/auth
+          tokenUrl: https://example.com/token
+          scopes: {write: Write}
+      description: OAuth
+`
+	got, warnings := upgrade(t, swaggerPetstore, "3.0")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("UpgradeNode() =\n%s", out)
+	}
+	wantWarnings := []string{"/paths/~1pets/get/parameters/1/collectionFormat: collectionFormat tsv of a query parameter has no OpenAPI 3 equivalent"}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings =\n%s", strings.Join(warnings, "\n"))
+	}
+}
+
+func TestUpgradeNode_To31(t *testing.T) {
+	got, _ := upgrade(t, swaggerPetstore, "3.1")
+	doc := got.(map[string]any)
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v", doc["openapi"])
+	}
+	tag := doc["components"].(map[string]any)["schemas"].(map[string]any)["Pet"].(map[string]any)["properties"].(map[string]any)["tag"]
+	if !reflect.DeepEqual(tag, map[string]any{"type": []any{"string", "null"}}) {
+		t.Errorf("tag = %v", tag)
+	}
+}
+
+func TestUpgradeNode_Defaults(t *testing.T) {
+	src := `swagger: "2.0"
+info: {title: T, version: "1"}
+basePath: /api
+paths:
+  /items:
+    post:
+      parameters:
+        - {name: note, in: formData, type: string}
+        - {name: page, in: path, required: true, type: array, items: {type: string}, collectionFormat: pipes}
+      responses:
+        "200":
+          description: ok
+          schema: {type: string}
+`
+	want := `openapi: 3.0.3
+info: {title: T, version: "1"}
+servers:
+  - url: /api
+paths:
+  /items:
+    post:
+      parameters:
+        - {name: page, in: path, required: true, schema: {type: array, items: {type: string}}}
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema: {type: object, properties: {note: {type: string}}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: string}
+`
+	got, warnings := upgrade(t, src, "3.0.1")
+	if !reflect.DeepEqual(got, decode(t, strings.Replace(want, "3.0.3", "3.0.1", 1))) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("UpgradeNode() =\n%s", out)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestUpgradeNode_NamedLikeData(t *testing.T) {
+	src := `swagger: "2.0"
+info: {title: T, version: "1"}
+paths: {}
+definitions:
+  examples:
+    type: object
+    properties:
+      value: {$ref: "#/definitions/Thing"}
+      example: {$ref: "#/definitions/Thing"}
+    example: {value: {$ref: "#/definitions/Thing"}}
+  Thing: {type: string}
+`
+	want := `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths: {}
+components:
+  schemas:
+    examples:
+      type: object
+      properties:
+        value: {$ref: "#/components/schemas/Thing"}
+        example: {$ref: "#/components/schemas/Thing"}
+      example: {value: {$ref: "#/definitions/Thing"}}
+    Thing: {type: string}
+`
+	got, _ := upgrade(t, src, "3.0")
+	if !reflect.DeepEqual(got, decode(t, want)) {
+		out, _ := yaml.Marshal(got)
+		t.Errorf("UpgradeNode() =\n%s", out)
+	}
+}
+
+func TestUpgradeNode_Errors(t *testing.T) {
+	tests := []struct {
+		name, src, version, want string
+	}{
+		{"target", `swagger: "2.0"`, "2.0", `unsupported target version "2.0" (want 3.0, 3.1 or 3.2)`},
+		{"source", `swagger: "1.2"`, "3.0", `unsupported Swagger version "1.2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.src), &root); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := UpgradeNode(&root, tt.version); err == nil || err.Error() != tt.want {
+				t.Errorf("UpgradeNode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+// KeyOrder controls the order map keys such as paths, response codes and schema properties are written in.
+// The fixed fields of OpenAPI objects always follow the order of the specification.
+type KeyOrder string
+
+const (
+	// KeyOrderAlphabetical sorts map keys alphabetically. It is the zero value.
+	KeyOrderAlphabetical KeyOrder = "alphabetical"
+	// KeyOrderCanonical writes the fields of OpenAPI objects in the order of the specification, groups
+	// paths by resource and sorts response codes by status class, with default last. Other map keys are
+	// sorted alphabetically.
+	KeyOrderCanonical KeyOrder = "canonical"
+	// KeyOrderSource keeps the order recorded in Document.SourceOrder, such as the order annotations were
+	// written in. Maps without a recorded order are written canonically.
+	KeyOrderSource KeyOrder = "source"
+)
+
+// ParseKeyOrder parses a key order string into a KeyOrder type.
+func ParseKeyOrder(s string) (KeyOrder, error) {
+	switch KeyOrder(strings.ToLower(s)) {
+	case KeyOrderAlphabetical:
+		return KeyOrderAlphabetical, nil
+	case KeyOrderCanonical:
+		return KeyOrderCanonical, nil
+	case KeyOrderSource:
+		return KeyOrderSource, nil
+	default:
+		return "", fmt.Errorf("unknown key order: %s (supported: source, alphabetical, canonical)", s)
+	}
+}
+
+// ordered reports whether keys are written in an order other than the encoders' own.
+func (f *Formatter) ordered() bool {
+	return f.opts.KeyOrder == KeyOrderCanonical || f.opts.KeyOrder == KeyOrderSource
+}
+
+// orderedNode returns the document as a YAML node with its map keys reordered.
+func (f *Formatter) orderedNode(doc *openapi.Document, format Format) (*yaml.Node, error) {
+	var node yaml.Node
+	if format == FormatJSON {
+		// Start from the JSON encoding so keys and values match what encoding/json writes
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+	} else if err := node.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	var source map[string][]string
+	if f.opts.KeyOrder == KeyOrderSource {
+		source = doc.SourceOrder
+	}
+	reorder(&node, "", "document", source)
+	return &node, nil
+}
+
+// reorder sorts the keys of the maps in node, recursively. object names what node holds, as a key of
+// objects or a map or list of one.
+func reorder(node *yaml.Node, pointer, object string, source map[string][]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			reorder(child, pointer, object, source)
+		}
+	case yaml.SequenceNode:
+		item, _ := strings.CutPrefix(object, "list:")
+		for i, child := range node.Content {
+			reorder(child, yamlnode.Index(pointer, i), item, source)
+		}
+	case yaml.MappingNode:
+		if cmp := keyCompare(node, pointer, object, source); cmp != nil {
+			sortPairs(node, cmp)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			reorder(node.Content[i+1], yamlnode.Join(pointer, key), childObject(object, key), source)
+		}
+	}
+}
+
+// keyCompare returns the order of the keys of the mapping at pointer, or nil to leave them as encoded.
+func keyCompare(node *yaml.Node, pointer, object string, source map[string][]string) func(a, b string) int {
+	canonical := canonicalCompare(node, object)
+	recorded, ok := source[pointer]
+	if !ok {
+		return canonical
+	}
+	return func(a, b string) int {
+		i, j := slices.Index(recorded, a), slices.Index(recorded, b)
+		switch {
+		case i >= 0 && j >= 0:
+			return i - j
+		case i >= 0:
+			return -1
+		case j >= 0:
+			return 1
+		case canonical != nil:
+			return canonical(a, b)
+		}
+		return 0
+	}
+}
+
+// canonicalCompare returns the canonical order of the mappings that have one: the fields of OpenAPI
+// objects, paths and response codes.
+func canonicalCompare(node *yaml.Node, object string) func(a, b string) int {
+	switch object {
+	case "paths":
+		return comparePaths
+	case "responses":
+		for i := 0; i < len(node.Content); i += 2 {
+			if _, ok := statusRank(node.Content[i].Value); !ok {
+				return nil
+			}
+		}
+		return compareStatus
+	}
+	if o, ok := objects[object]; ok {
+		return func(a, b string) int {
+			return fieldRank(o.fields, a) - fieldRank(o.fields, b)
+		}
+	}
+	return nil
+}
+
+// fieldRank returns the position of a field in the field order of an object. Extensions and
+// unknown fields come after the fixed fields.
+func fieldRank(fields []string, key string) int {
+	if i := slices.Index(fields, key); i >= 0 {
+		return i
+	}
+	return len(fields)
+}
+
+// childObject returns what the value of key holds in a mapping that holds object.
+func childObject(object, key string) string {
+	if item, ok := strings.CutPrefix(object, "map:"); ok {
+		return item
+	}
+	switch object {
+	case "paths", "callback":
+		return "pathItem"
+	case "responses":
+		return "response"
+	}
+	return objects[object].children[key]
+}
+
+// objectOrder is the field order of an OpenAPI object and what its fields hold.
+type objectOrder struct {
+	fields   []string
+	children map[string]string
+}
+
+// objects holds the field order of the OpenAPI objects, as listed in the specification. Schemas have
+// no order in the specification and follow the fields of openapi.Schema.
+var objects = map[string]objectOrder{
+	"document": {
+		fields: []string{"openapi", "info", "jsonSchemaDialect", "servers", "paths", "webhooks", "components", "security", "tags", "externalDocs"},
+		children: map[string]string{
+			"info": "info", "servers": "list:server", "paths": "paths", "webhooks": "map:pathItem",
+			"components": "components", "tags": "list:tag", "externalDocs": "externalDocs",
+		},
+	},
+	"info": {
+		fields:   []string{"title", "summary", "description", "termsOfService", "contact", "license", "version"},
+		children: map[string]string{"contact": "contact", "license": "license"},
+	},
+	"contact": {fields: []string{"name", "url", "email"}},
+	"license": {fields: []string{"name", "identifier", "url"}},
+	"server": {
+		fields:   []string{"url", "description", "variables"},
+		children: map[string]string{"variables": "map:serverVariable"},
+	},
+	"serverVariable": {fields: []string{"enum", "default", "description"}},
+	"components": {
+		fields: []string{"schemas", "responses", "parameters", "examples", "requestBodies", "headers", "securitySchemes", "links", "callbacks", "pathItems"},
+		children: map[string]string{
+			"schemas": "map:schema", "responses": "map:response", "parameters": "map:parameter", "examples": "map:example",
+			"requestBodies": "map:requestBody", "headers": "map:header", "securitySchemes": "map:securityScheme",
+			"links": "map:link", "callbacks": "map:callback", "pathItems": "map:pathItem",
+		},
+	},
+	"pathItem": {
+		fields: []string{"$ref", "summary", "description", "get", "put", "post", "delete", "options", "head", "patch", "trace", "servers", "parameters"},
+		children: map[string]string{
+			"get": "operation", "put": "operation", "post": "operation", "delete": "operation", "options": "operation",
+			"head": "operation", "patch": "operation", "trace": "operation", "servers": "list:server", "parameters": "list:parameter",
+		},
+	},
+	"operation": {
+		fields: []string{"tags", "summary", "description", "externalDocs", "operationId", "parameters", "requestBody", "responses", "callbacks", "deprecated", "security", "servers"},
+		children: map[string]string{
+			"externalDocs": "externalDocs", "parameters": "list:parameter", "requestBody": "requestBody",
+			"responses": "responses", "callbacks": "map:callback", "servers": "list:server",
+		},
+	},
+	"externalDocs": {fields: []string{"description", "url"}},
+	"parameter": {
+		fields:   []string{"$ref", "name", "in", "description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"},
+		children: map[string]string{"schema": "schema", "examples": "map:example", "content": "map:mediaType"},
+	},
+	"requestBody": {
+		fields:   []string{"$ref", "description", "content", "required"},
+		children: map[string]string{"content": "map:mediaType"},
+	},
+	"mediaType": {
+		fields:   []string{"schema", "example", "examples", "encoding"},
+		children: map[string]string{"schema": "schema", "examples": "map:example", "encoding": "map:encoding"},
+	},
+	"encoding": {
+		fields:   []string{"contentType", "headers", "style", "explode", "allowReserved"},
+		children: map[string]string{"headers": "map:header"},
+	},
+	"response": {
+		fields:   []string{"$ref", "description", "headers", "content", "links"},
+		children: map[string]string{"headers": "map:header", "content": "map:mediaType", "links": "map:link"},
+	},
+	"example": {fields: []string{"$ref", "summary", "description", "value", "externalValue"}},
+	"link": {
+		fields:   []string{"$ref", "operationRef", "operationId", "parameters", "requestBody", "description", "server"},
+		children: map[string]string{"server": "server"},
+	},
+	"header": {
+		fields:   []string{"$ref", "description", "required", "deprecated", "allowEmptyValue", "style", "explode", "allowReserved", "schema", "example", "examples", "content"},
+		children: map[string]string{"schema": "schema", "examples": "map:example", "content": "map:mediaType"},
+	},
+	"tag": {
+		fields:   []string{"name", "description", "externalDocs"},
+		children: map[string]string{"externalDocs": "externalDocs"},
+	},
+	"schema": {
+		fields: []string{
+			"$ref", "type", "format", "title", "description", "default", "nullable", "deprecated", "readOnly", "writeOnly",
+			"example", "examples", "externalDocs", "minLength", "maxLength", "pattern", "minimum", "maximum",
+			"exclusiveMinimum", "exclusiveMaximum", "multipleOf", "items", "minItems", "maxItems", "uniqueItems",
+			"properties", "additionalProperties", "required", "minProperties", "maxProperties",
+			"allOf", "anyOf", "oneOf", "not", "enum", "discriminator", "xml",
+		},
+		children: map[string]string{
+			"externalDocs": "externalDocs", "items": "schema", "properties": "map:schema", "additionalProperties": "schema",
+			"allOf": "list:schema", "anyOf": "list:schema", "oneOf": "list:schema", "not": "schema",
+			"discriminator": "discriminator", "xml": "xml",
+		},
+	},
+	"discriminator": {fields: []string{"propertyName", "mapping"}},
+	"xml":           {fields: []string{"name", "namespace", "prefix", "attribute", "wrapped"}},
+	"securityScheme": {
+		fields:   []string{"$ref", "type", "description", "name", "in", "scheme", "bearerFormat", "flows", "openIdConnectUrl"},
+		children: map[string]string{"flows": "oauthFlows"},
+	},
+	"oauthFlows": {
+		fields: []string{"implicit", "password", "clientCredentials", "authorizationCode"},
+		children: map[string]string{
+			"implicit": "oauthFlow", "password": "oauthFlow", "clientCredentials": "oauthFlow", "authorizationCode": "oauthFlow",
+		},
+	},
+	"oauthFlow": {fields: []string{"authorizationUrl", "tokenUrl", "refreshUrl", "scopes"}},
+}
+
+// sortPairs stably sorts the key/value pairs of a mapping node by key.
+func sortPairs(node *yaml.Node, cmp func(a, b string) int) {
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		return cmp(a[0].Value, b[0].Value)
+	})
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
+
+// comparePaths sorts paths segment by segment, so /pets/{id} stays next to /pets.
+func comparePaths(a, b string) int {
+	return slices.Compare(strings.Split(a, "/"), strings.Split(b, "/"))
+}
+
+// compareStatus sorts response codes by status class, exact codes before ranges such as 4XX, and default last.
+func compareStatus(a, b string) int {
+	ra, _ := statusRank(a)
+	rb, _ := statusRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+	return strings.Compare(a, b)
+}
+
+// statusRank returns the sort rank of a response code, and whether key is one.
+func statusRank(key string) (int, bool) {
+	if key == "default" {
+		return 1000, true
+	}
+	if len(key) != 3 || key[0] < '1' || key[0] > '5' {
+		return 0, false
+	}
+	class := int(key[0]-'0') * 100
+	if strings.EqualFold(key[1:], "XX") {
+		return class + 99, true
+	}
+	code, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// writeJSON writes a node as compact JSON, keeping its key order.
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0])
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONString(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!str", "!!timestamp", "!!binary":
+			return writeJSONString(buf, node.Value)
+		}
+		// Numbers, booleans and null may be spelled in YAML-only ways such as 0x1F or ~
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	default:
+		return fmt.Errorf("unexpected YAML node kind %d", node.Kind)
+	}
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
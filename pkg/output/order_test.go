@@ -0,0 +1,242 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+)
+
+func TestParseKeyOrder(t *testing.T) {
+	for in, want := range map[string]KeyOrder{
+		"source":       KeyOrderSource,
+		"Alphabetical": KeyOrderAlphabetical,
+		"canonical":    KeyOrderCanonical,
+	} {
+		got, err := ParseKeyOrder(in)
+		if err != nil || got != want {
+			t.Errorf("ParseKeyOrder(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseKeyOrder("random"); err == nil {
+		t.Error("ParseKeyOrder(random) should fail")
+	}
+}
+
+func orderTestDoc() *openapi.Document {
+	responses := openapi.Responses{}
+	for _, code := range []string{"default", "404", "4XX", "201", "200"} {
+		responses[code] = &openapi.Response{Description: "r" + code}
+	}
+	item := &openapi.PathItem{Get: &openapi.Operation{Responses: responses}}
+	doc := &openapi.Document{
+		OpenAPI: "3.1.0",
+		Info:    openapi.Info{Title: "Order", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/pets/{id}":   item,
+			"/pets-export": item,
+			"/pets":        item,
+			"/pets/search": item,
+		},
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+			"Pet": {Properties: map[string]*openapi.Schema{"zeta": {}, "alpha": {}, "mid": {}}},
+		}},
+	}
+	doc.SetSourceOrder("/paths", []string{"/pets/search", "/pets"})
+	doc.SetSourceOrder("/paths/~1pets/get/responses", []string{"404", "200"})
+	doc.SetSourceOrder("/components/schemas/Pet/properties", []string{"zeta", "alpha", "mid"})
+	return doc
+}
+
+func TestFormatter_KeyOrder(t *testing.T) {
+	tests := []struct {
+		order      KeyOrder
+		paths      []string // Alphabetical paths and response codes are sorted by each encoder's own rules
+		responses  []string // Of GET /pets
+		properties []string
+	}{
+		{
+			order:      KeyOrderAlphabetical,
+			properties: []string{"alpha", "mid", "zeta"},
+		},
+		{
+			order:      KeyOrderCanonical,
+			paths:      []string{"/pets", "/pets/search", "/pets/{id}", "/pets-export"},
+			responses:  []string{"200", "201", "404", "4XX", "default"},
+			properties: []string{"alpha", "mid", "zeta"},
+		},
+		{
+			order:      KeyOrderSource,
+			paths:      []string{"/pets/search", "/pets", "/pets/{id}", "/pets-export"},
+			responses:  []string{"404", "200", "201", "4XX", "default"},
+			properties: []string{"zeta", "alpha", "mid"},
+		},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{FormatJSON, FormatYAML} {
+			t.Run(string(tt.order)+"/"+string(format), func(t *testing.T) {
+				f := NewFormatter(Options{Format: format, Indent: 2, Pretty: true, KeyOrder: tt.order})
+				data, err := f.Format(orderTestDoc())
+				if err != nil {
+					t.Fatalf("Format() error = %v", err)
+				}
+				out := string(data)
+				paths := out[strings.Index(out, "paths"):strings.Index(out, "components")]
+				assertOrder(t, paths, tt.paths, format)
+
+				if tt.responses != nil {
+					pets := out[strings.Index(out, quoteKey("/pets", format)):]
+					assertOrder(t, pets[:strings.Index(pets, "rdefault")], tt.responses, format)
+				}
+
+				assertOrder(t, out[strings.Index(out, "components"):], tt.properties, format)
+			})
+		}
+	}
+}
+
+func TestFormatter_KeyOrderJSON(t *testing.T) {
+	doc := orderTestDoc()
+	want, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pretty := range []bool{true, false} {
+		f := NewFormatter(Options{Format: FormatJSON, Indent: 4, Pretty: pretty, KeyOrder: KeyOrderCanonical})
+		data, err := f.Format(doc)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if pretty != strings.Contains(string(data), "\n    \"info\"") {
+			t.Errorf("pretty = %v, got\n%s", pretty, data)
+		}
+		var got, expected any
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		_ = json.Unmarshal(want, &expected)
+		gotJSON, _ := json.Marshal(got)
+		expectedJSON, _ := json.Marshal(expected)
+		if string(gotJSON) != string(expectedJSON) {
+			t.Errorf("reordered JSON differs from the document:\n%s\n%s", gotJSON, expectedJSON)
+		}
+	}
+}
+
+func TestCompareStatus(t *testing.T) {
+	codes := []string{"default", "5XX", "201", "2XX", "101", "404", "400"}
+	want := []string{"101", "201", "2XX", "400", "404", "5XX", "default"}
+	for i := range codes {
+		for j := range codes {
+			if (compareStatus(codes[i], codes[j]) < 0) != (indexOf(want, codes[i]) < indexOf(want, codes[j])) {
+				t.Errorf("compareStatus(%q, %q) = %d", codes[i], codes[j], compareStatus(codes[i], codes[j]))
+			}
+		}
+	}
+}
+
+func quoteKey(key string, format Format) string {
+	if format == FormatJSON {
+		return `"` + key + `":`
+	}
+	return key + ":"
+}
+
+// assertOrder checks that the keys appear in out in the given order.
+func assertOrder(t *testing.T, out string, keys []string, format Format) {
+	t.Helper()
+	last := -1
+	for _, key := range keys {
+		quoted := quoteKey(key, format)
+		if format == FormatYAML && strings.Trim(key, "0123456789") == "" {
+			quoted = `"` + key + `":`
+		}
+		i := strings.Index(out, quoted)
+		if i < 0 {
+			t.Fatalf("%q not found in\n%s", quoted, out)
+		}
+		if i < last {
+			t.Errorf("%q is out of order, want %v in\n%s", key, keys, out)
+		}
+		last = i
+	}
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestFormatter_CanonicalFields(t *testing.T) {
+	doc := orderTestDoc()
+	doc.Servers = []openapi.Server{{URL: "https://api.example.com"}}
+	doc.Webhooks = map[string]*openapi.PathItem{"newPet": {Post: &openapi.Operation{Summary: "New pet"}}}
+	doc.Security = []openapi.SecurityRequirement{{"key": {}}}
+	doc.Tags = []openapi.Tag{{Name: "pets"}}
+	doc.ExternalDocs = &openapi.ExternalDocumentation{URL: "https://example.com"}
+	want := []string{"openapi", "info", "servers", "paths", "webhooks", "components", "security", "tags", "externalDocs"}
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		f := NewFormatter(Options{Format: format, Indent: 2, Pretty: true, KeyOrder: KeyOrderCanonical})
+		data, err := f.Format(doc)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		indent := ""
+		if format == FormatJSON {
+			indent = "  "
+		}
+		var keys []string
+		for _, line := range strings.Split(string(data), "\n") {
+			key, _, ok := strings.Cut(strings.TrimPrefix(line, indent), ":")
+			if ok && strings.HasPrefix(line, indent) && !strings.HasPrefix(key, " ") {
+				keys = append(keys, strings.Trim(key, `"`))
+			}
+		}
+		if strings.Join(keys, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: top-level fields = %v, want %v", format, keys, want)
+		}
+	}
+}
+
+func TestReorder_Fields(t *testing.T) {
+	node, err := yamlnode.Parse([]byte(`x-internal: true
+paths:
+  /pets:
+    get:
+      responses: {"200": {content: {application/json: {schema: {$ref: "#/components/schemas/Pet"}}}, description: ok}}
+      operationId: listPets
+      parameters: [{in: query, name: limit, schema: {type: integer}}]
+      tags: [pets]
+components:
+  schemas:
+    Pet:
+      required: [type]
+      properties: {type: {type: string}, name: {type: string}, id: {type: integer}}
+      type: object
+info: {version: "1", title: Pets}
+openapi: 3.1.0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reorder(node, "", "document", nil)
+	data, err := NewFormatter(Options{Format: FormatJSON}).FormatNode(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"openapi":"3.1.0","info":{"title":"Pets","version":"1"},` +
+		`"paths":{"/pets":{"get":{"tags":["pets"],"operationId":"listPets","parameters":[{"name":"limit","in":"query","schema":{"type":"integer"}}],` +
+		`"responses":{"200":{"description":"ok","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}}},` +
+		// Property names are map keys, not fields
+		`"components":{"schemas":{"Pet":{"type":"object","properties":{"type":{"type":"string"},"name":{"type":"string"},"id":{"type":"integer"}},"required":["type"]}}},` +
+		`"x-internal":true}`
+	if string(data) != want {
+		t.Errorf("reorder() =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -24,9 +24,14 @@ const (
 
 // Options configures the output formatting.
 type Options struct {
-	Format Format
-	Indent int
-	Pretty bool
+	Format   Format
+	Indent   int
+	Pretty   bool
+	KeyOrder KeyOrder // Order of map keys; the zero value sorts them alphabetically
+
+	// BlockStyle makes FormatNode write a flow-style document, such as one read from JSON, as
+	// block-style YAML. Otherwise the document is written in the style it was read in.
+	BlockStyle bool
 }
 
 // DefaultOptions returns default output options.
@@ -60,6 +65,59 @@ func (f *Formatter) Format(doc *openapi.Document) ([]byte, error) {
 	}
 }
 
+// Node returns an OpenAPI document as a YAML node, with its map keys in the configured order.
+func (f *Formatter) Node(doc *openapi.Document) (*yaml.Node, error) {
+	if f.ordered() {
+		return f.orderedNode(doc, FormatYAML)
+	}
+	var node yaml.Node
+	if err := node.Encode(doc); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// FormatNode formats a document held as a YAML node, such as one read from a file, keeping its key order.
+func (f *Formatter) FormatNode(node *yaml.Node) ([]byte, error) {
+	switch f.opts.Format {
+	case FormatJSON:
+		return f.nodeToJSON(node)
+	case FormatYAML:
+		if f.opts.BlockStyle && isFlow(node) {
+			// Read from JSON: write it as block-style YAML rather than JSON that is also YAML
+			node = blockStyle(node)
+		}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(f.opts.Indent)
+		if err := encoder.Encode(node); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", f.opts.Format)
+	}
+}
+
+// isFlow reports whether the top-level mapping of a document is in flow style, as a parsed JSON document is.
+func isFlow(node *yaml.Node) bool {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	return node.Kind == yaml.MappingNode && node.Style&yaml.FlowStyle != 0
+}
+
+// blockStyle returns a copy of a node without flow style and with quotes only where a value needs them.
+func blockStyle(node *yaml.Node) *yaml.Node {
+	c := *node
+	c.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		c.Content[i] = blockStyle(child)
+	}
+	return &c
+}
+
 // FormatTo formats an OpenAPI document and writes to the given writer.
 func (f *Formatter) FormatTo(doc *openapi.Document, w io.Writer) error {
 	data, err := f.Format(doc)
@@ -80,6 +138,9 @@ func (f *Formatter) FormatToFile(doc *openapi.Document, filename string) error {
 }
 
 func (f *Formatter) toJSON(doc *openapi.Document) ([]byte, error) {
+	if f.ordered() {
+		return f.toOrderedJSON(doc)
+	}
 	if f.opts.Pretty {
 		indent := strings.Repeat(" ", f.opts.Indent)
 		return json.MarshalIndent(doc, "", indent)
@@ -92,13 +153,44 @@ func (f *Formatter) toYAML(doc *openapi.Document) ([]byte, error) {
 	encoder := yaml.NewEncoder(&buf)
 	encoder.SetIndent(f.opts.Indent)
 
-	if err := encoder.Encode(doc); err != nil {
+	var value any = doc
+	if f.ordered() {
+		node, err := f.orderedNode(doc, FormatYAML)
+		if err != nil {
+			return nil, err
+		}
+		value = node
+	}
+	if err := encoder.Encode(value); err != nil {
 		return nil, err
 	}
 
 	return buf.Bytes(), nil
 }
 
+func (f *Formatter) toOrderedJSON(doc *openapi.Document) ([]byte, error) {
+	node, err := f.orderedNode(doc, FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+	return f.nodeToJSON(node)
+}
+
+func (f *Formatter) nodeToJSON(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, node); err != nil {
+		return nil, err
+	}
+	if !f.opts.Pretty {
+		return buf.Bytes(), nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", strings.Repeat(" ", f.opts.Indent)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // ParseFormat parses a format string into a Format type.
 func ParseFormat(s string) (Format, error) {
 	switch strings.ToLower(s) {
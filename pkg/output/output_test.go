@@ -478,3 +478,66 @@ func assertContainsStr(t *testing.T, s, substr string) {
 		t.Errorf("Expected %q in output", substr)
 	}
 }
+
+func TestFormatter_FormatNode(t *testing.T) {
+	var node yaml.Node
+	src := "openapi: 3.1.0\nx-max: 0x1F\nx-none: ~\nx-date: 2026-01-02\nbase: &base {type: string}\nzeta: *base\nalpha: {$ref: '#/base'}\n"
+	if err := yaml.Unmarshal([]byte(src), &node); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := NewFormatter(Options{Format: FormatJSON}).FormatNode(&node)
+	if err != nil {
+		t.Fatalf("FormatNode() error = %v", err)
+	}
+	want := `{"openapi":"3.1.0","x-max":31,"x-none":null,"x-date":"2026-01-02","base":{"type":"string"},"zeta":{"type":"string"},"alpha":{"$ref":"#/base"}}`
+	if string(data) != want {
+		t.Errorf("FormatNode(json) = %s, want %s", data, want)
+	}
+
+	data, err = NewFormatter(Options{Format: FormatYAML, Indent: 2}).FormatNode(&node)
+	if err != nil {
+		t.Fatalf("FormatNode() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "openapi: 3.1.0\nx-max: 0x1F\n") {
+		t.Errorf("FormatNode(yaml) did not keep the document as written:\n%s", data)
+	}
+}
+
+func TestFormatter_FormatNode_FromJSON(t *testing.T) {
+	var node yaml.Node
+	src := `{"openapi": "3.1.0", "paths": {"/a": {"get": {"responses": {"200": {"description": "ok"}}}}}, "tags": ["a", "true"]}`
+	if err := yaml.Unmarshal([]byte(src), &node); err != nil {
+		t.Fatal(err)
+	}
+	// Without BlockStyle the document keeps the style it was read in
+	data, err := NewFormatter(Options{Format: FormatYAML, Indent: 2}).FormatNode(&node)
+	if err != nil {
+		t.Fatalf("FormatNode() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"openapi": "3.1.0", `) {
+		t.Errorf("FormatNode(yaml) changed the style of the document:\n%s", data)
+	}
+
+	data, err = NewFormatter(Options{Format: FormatYAML, Indent: 2, BlockStyle: true}).FormatNode(&node)
+	if err != nil {
+		t.Fatalf("FormatNode() error = %v", err)
+	}
+	want := `openapi: 3.1.0
+paths:
+  /a:
+    get:
+      responses:
+        "200":
+          description: ok
+tags:
+  - a
+  - "true"
+`
+	if string(data) != want {
+		t.Errorf("FormatNode(yaml) =\n%s\nwant\n%s", data, want)
+	}
+	if node.Content[0].Style != yaml.FlowStyle {
+		t.Error("FormatNode() modified its input")
+	}
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// githubCommands maps issue levels to GitHub Actions workflow commands.
+var githubCommands = map[Level]string{
+	LevelError:   "error",
+	LevelWarning: "warning",
+	LevelNote:    "notice",
+}
+
+// GitHub renders the report as GitHub Actions workflow commands, which show up as inline
+// annotations on pull requests, one line per issue.
+func GitHub(r *Report) []byte {
+	var sb strings.Builder
+	for _, issue := range r.Issues {
+		command, ok := githubCommands[issue.Level]
+		if !ok {
+			command = "notice"
+		}
+		var props []string
+		if issue.File != "" {
+			props = append(props, "file="+escapeProperty(issue.File))
+		}
+		if issue.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", issue.Line), fmt.Sprintf("col=%d", issue.Column))
+		}
+		props = append(props, "title="+escapeProperty(issue.RuleID))
+		sb.WriteString(fmt.Sprintf("::%s %s::%s\n", command, strings.Join(props, ","), escapeData(issue.text())))
+	}
+	return []byte(sb.String())
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit renders the report as JUnit XML. Every error is a failed test case and every warning or
+// note a passing one; a report without issues has a single passing case, so each run is recorded.
+func JUnit(r *Report) ([]byte, error) {
+	name := fmt.Sprintf("%s %s", toolName, r.Name)
+	suite := junitTestSuite{Name: name}
+	for _, issue := range r.Issues {
+		suite.Cases = append(suite.Cases, newJUnitCase(issue))
+		if issue.Level == LevelError {
+			suite.Failures++
+		}
+	}
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: "no issues", ClassName: name})
+	}
+	suite.Tests = len(suite.Cases)
+
+	suites := junitTestSuites{Name: name, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func newJUnitCase(issue Issue) junitTestCase {
+	tc := junitTestCase{ClassName: issue.File, Name: issue.RuleID}
+	if issue.Pointer != "" {
+		tc.Name = fmt.Sprintf("%s %s", issue.RuleID, issue.Pointer)
+	}
+	if tc.ClassName == "" {
+		tc.ClassName = toolName
+	}
+	detail := fmt.Sprintf("%s: %s", issue.location(), issue.text())
+	if issue.location() == "" {
+		detail = issue.text()
+	}
+	if issue.Level == LevelError {
+		tc.Failure = &junitFailure{Message: issue.Message, Type: string(issue.Level), Text: detail}
+	} else {
+		tc.SystemOut = fmt.Sprintf("%s: %s", issue.Level, detail)
+	}
+	return tc
+}
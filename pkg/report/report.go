@@ -0,0 +1,95 @@
+// Package report renders validation and audit findings in machine-readable formats:
+// SARIF for code scanning, JUnit XML for test dashboards and GitHub Actions workflow commands.
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of an issue.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Format is a report output format.
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatSARIF  Format = "sarif"
+	FormatJUnit  Format = "junit"
+	FormatGitHub Format = "github"
+)
+
+// Formats lists the supported output formats.
+var Formats = []Format{FormatText, FormatJSON, FormatSARIF, FormatJUnit, FormatGitHub}
+
+// ParseFormat parses an output format name, case-insensitively.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unsupported output format %q, must be one of %s", name, strings.Join(names, ", "))
+}
+
+// Rule describes a check that produces issues.
+type Rule struct {
+	ID          string
+	Name        string
+	Description string
+	Help        string
+}
+
+// Issue is a single finding.
+type Issue struct {
+	RuleID  string
+	Level   Level
+	Message string
+	// File is the path or URL of the checked document; empty when it was read from stdin.
+	File   string
+	Line   int
+	Column int
+	// Pointer is the JSON pointer of the offending node.
+	Pointer string
+}
+
+// Report is the set of issues produced by one run of a tool.
+type Report struct {
+	// Name identifies the run, for example "validate" or "audit".
+	Name    string
+	Version string
+	Rules   []Rule
+	Issues  []Issue
+}
+
+// toolName is the name reported as the producing tool.
+const toolName = "yaswag"
+
+// location returns "file:line:col", as much of it as is known.
+func (i Issue) location() string {
+	loc := i.File
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s:%d:%d", loc, i.Line, i.Column)
+	}
+	return strings.TrimPrefix(loc, ":")
+}
+
+// text returns the message with its pointer, if any.
+func (i Issue) text() string {
+	if i.Pointer == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s (at %s)", i.Message, i.Pointer)
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func testReport() *Report {
+	return &Report{
+		Name:    "validate",
+		Version: "1.2.3",
+		Rules:   []Rule{{ID: "openapi-error", Name: "OpenAPIError", Description: "Invalid document", Help: "Fix it"}},
+		Issues: []Issue{
+			{RuleID: "openapi-error", Level: LevelError, Message: "unresolved reference", File: "api/openapi.yaml", Line: 12, Column: 7, Pointer: "/paths/~1users/get"},
+			{RuleID: "unknown-rule", Level: LevelWarning, Message: "line one\nline two, 100%"},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"text", "JSON", "sarif", "junit", "GitHub"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", name, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil || !strings.Contains(err.Error(), "sarif") {
+		t.Errorf("expected error listing formats, got %v", err)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := SARIF(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "yaswag" || run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("driver = %+v", run.Tool.Driver)
+	}
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[1].ID != "unknown-rule" {
+		t.Errorf("rules = %+v, want declared rule plus unknown-rule", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("results = %+v", run.Results)
+	}
+
+	first := run.Results[0]
+	loc := first.Locations[0]
+	if first.Level != "error" || loc.PhysicalLocation.ArtifactLocation.URI != "api/openapi.yaml" ||
+		loc.PhysicalLocation.Region.StartLine != 12 || loc.PhysicalLocation.Region.StartColumn != 7 ||
+		loc.LogicalLocations[0].FullyQualifiedName != "/paths/~1users/get" {
+		t.Errorf("first result = %+v", first)
+	}
+	if second := run.Results[1]; second.RuleIndex != 1 || second.Locations != nil {
+		t.Errorf("second result = %+v", second)
+	}
+}
+
+func TestSARIF_NoIssues(t *testing.T) {
+	data, err := SARIF(&Report{Name: "audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"results": []`) || !strings.Contains(string(data), `"rules": []`) {
+		t.Errorf("expected empty arrays, got %s", data)
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	data, err := JUnit(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<?xml") {
+		t.Error("missing XML header")
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || suites.Name != "yaswag validate" {
+		t.Errorf("suites = %+v", suites)
+	}
+	cases := suites.Suites[0].Cases
+	if cases[0].Failure == nil || cases[0].Failure.Text != "api/openapi.yaml:12:7: unresolved reference (at /paths/~1users/get)" {
+		t.Errorf("failure = %+v", cases[0].Failure)
+	}
+	if cases[0].ClassName != "api/openapi.yaml" || cases[0].Name != "openapi-error /paths/~1users/get" {
+		t.Errorf("case = %+v", cases[0])
+	}
+	if cases[1].Failure != nil || !strings.HasPrefix(cases[1].SystemOut, "warning: ") {
+		t.Errorf("warning case = %+v", cases[1])
+	}
+}
+
+func TestJUnit_NoIssues(t *testing.T) {
+	data, err := JUnit(&Report{Name: "audit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Tests != 1 || suites.Failures != 0 {
+		t.Errorf("expected a single passing case, got %+v", suites)
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	got := string(GitHub(testReport()))
+	want := "::error file=api/openapi.yaml,line=12,col=7,title=openapi-error::unresolved reference (at /paths/~1users/get)\n" +
+		"::warning title=unknown-rule::line one%0Aline two, 100%25\n"
+	if got != want {
+		t.Errorf("GitHub() =\n%s\nwant\n%s", got, want)
+	}
+	if got := escapeProperty("a:b,c"); got != "a%3Ab%2Cc" {
+		t.Errorf("escapeProperty() = %q", got)
+	}
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"encoding/json"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolURI      = "https://github.com/fathurrohman26/yaswag"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name,omitempty"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SARIF renders the report as a SARIF 2.1.0 log, as consumed by GitHub code scanning.
+func SARIF(r *Report) ([]byte, error) {
+	driver := sarifDriver{Name: toolName, Version: r.Version, InformationURI: toolURI, Rules: []sarifRule{}}
+	index := make(map[string]int)
+	for _, rule := range r.Rules {
+		index[rule.ID] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, newSARIFRule(rule))
+	}
+
+	results := []sarifResult{}
+	for _, issue := range r.Issues {
+		// Issues from rules not declared up front still need a rule entry
+		if _, ok := index[issue.RuleID]; !ok {
+			index[issue.RuleID] = len(driver.Rules)
+			driver.Rules = append(driver.Rules, sarifRule{ID: issue.RuleID})
+		}
+		results = append(results, sarifResult{
+			RuleID:    issue.RuleID,
+			RuleIndex: index[issue.RuleID],
+			Level:     string(issue.Level),
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: sarifLocations(issue),
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func newSARIFRule(rule Rule) sarifRule {
+	sr := sarifRule{ID: rule.ID, Name: rule.Name}
+	if rule.Description != "" {
+		sr.ShortDescription = &sarifMessage{Text: rule.Description}
+	}
+	if rule.Help != "" {
+		sr.Help = &sarifMessage{Text: rule.Help}
+	}
+	return sr
+}
+
+func sarifLocations(issue Issue) []sarifLocation {
+	var loc sarifLocation
+	if issue.File != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: issue.File}}
+		if issue.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line, StartColumn: issue.Column}
+		}
+	}
+	if issue.Pointer != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: issue.Pointer}}
+	}
+	if loc.PhysicalLocation == nil && loc.LogicalLocations == nil {
+		return nil
+	}
+	return []sarifLocation{loc}
+}
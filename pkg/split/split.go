@@ -0,0 +1,297 @@
+// Package split writes an OpenAPI document as a directory tree: one file per path, one file per
+// component and a root file that $refs them. It is the reverse of package bundle.
+package split
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+// Option configures splitting
+type Option func(*splitter)
+
+// WithFormat sets the format of the files, and so their extension. The default is YAML.
+func WithFormat(format output.Format) Option {
+	return func(s *splitter) {
+		s.format = format
+	}
+}
+
+// Layout is a document split into files
+type Layout struct {
+	Format output.Format
+	Root   string                // The root file
+	Files  map[string]*yaml.Node // File contents by slash-separated path relative to the output directory
+}
+
+// Document splits an OpenAPI document, keeping paths, responses and schemas in source order.
+func Document(doc *openapi.Document, opts ...Option) (*Layout, error) {
+	formatter := output.NewFormatter(output.Options{Format: output.FormatYAML, KeyOrder: output.KeyOrderSource})
+	root, err := formatter.Node(doc)
+	if err != nil {
+		return nil, err
+	}
+	return Node(root, opts...)
+}
+
+// Node splits a document held as a YAML node, such as one read from a file. The node is not modified.
+func Node(root *yaml.Node, opts ...Option) (*Layout, error) {
+	s := &splitter{
+		format:     output.FormatYAML,
+		pathFiles:  make(map[string]string),
+		compFiles:  make(map[string]string),
+		fileExists: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.format != output.FormatYAML && s.format != output.FormatJSON {
+		return nil, fmt.Errorf("unsupported format: %s", s.format)
+	}
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("document is not a mapping")
+	}
+	return s.split(yamlnode.Clone(root)), nil
+}
+
+// Paths returns the files of the layout in lexical order.
+func (l *Layout) Paths() []string {
+	paths := make([]string, 0, len(l.Files))
+	for p := range l.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Write writes the files below dir, creating directories as needed.
+func (l *Layout) Write(dir string, indent int) error {
+	formatter := output.NewFormatter(output.Options{Format: l.Format, Indent: indent, Pretty: indent > 0})
+	for _, name := range l.Paths() {
+		data, err := formatter.FormatNode(l.Files[name])
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", name, err)
+		}
+		if !strings.HasSuffix(string(data), "\n") {
+			data = append(data, '\n')
+		}
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// splitter holds the state of a single split
+type splitter struct {
+	format     output.Format
+	rootFile   string
+	pathFiles  map[string]string // Files by path
+	compFiles  map[string]string // Files by "section/name" of the component
+	fileExists map[string]bool
+}
+
+func (s *splitter) split(root *yaml.Node) *Layout {
+	ext := "." + string(s.format)
+	s.rootFile = "openapi" + ext
+	layout := &Layout{Format: s.format, Root: s.rootFile, Files: make(map[string]*yaml.Node)}
+
+	// Name every file first, so references between them can be rewritten
+	type extracted struct {
+		file  string
+		scope yamlnode.Scope
+		value *yaml.Node
+	}
+	var files []extracted
+	paths := yamlnode.Get(root, "paths")
+	yamlnode.Pairs(paths, func(key, value *yaml.Node) {
+		if yamlnode.Ref(value) != "" {
+			return
+		}
+		file := s.uniqueFile("paths/"+pathFileName(key.Value), ext)
+		s.pathFiles[key.Value] = file
+		files = append(files, extracted{file, yamlnode.Scope{Field: "paths"}, value})
+	})
+	components := yamlnode.Get(root, "components")
+	yamlnode.Pairs(components, func(section, entries *yaml.Node) {
+		if entries.Kind != yaml.MappingNode || strings.HasPrefix(section.Value, "x-") {
+			return
+		}
+		yamlnode.Pairs(entries, func(key, value *yaml.Node) {
+			if yamlnode.Ref(value) != "" {
+				return
+			}
+			name := invalidNameChars.ReplaceAllString(key.Value, "_")
+			file := s.uniqueFile("components/"+section.Value+"/"+name, ext)
+			s.compFiles[section.Value+"/"+key.Value] = file
+			files = append(files, extracted{file, yamlnode.Scope{Field: section.Value}, value})
+		})
+	})
+
+	for _, f := range files {
+		s.rewrite(f.value, f.scope, f.file)
+		layout.Files[f.file] = f.value
+	}
+
+	// Replace the extracted values with references to their files
+	for i := 0; paths != nil && i+1 < len(paths.Content); i += 2 {
+		if file, ok := s.pathFiles[paths.Content[i].Value]; ok {
+			paths.Content[i+1] = refNode(relative(s.rootFile, file))
+		}
+	}
+	yamlnode.Pairs(components, func(section, entries *yaml.Node) {
+		for i := 0; entries.Kind == yaml.MappingNode && i+1 < len(entries.Content); i += 2 {
+			if file, ok := s.compFiles[section.Value+"/"+entries.Content[i].Value]; ok {
+				entries.Content[i+1] = refNode(relative(s.rootFile, file))
+			}
+		}
+	})
+	s.rewrite(root, yamlnode.Scope{}, s.rootFile)
+	layout.Files[s.rootFile] = root
+	return layout
+}
+
+// uniqueFile returns base+ext, or base with a number suffix when another file has that name.
+func (s *splitter) uniqueFile(base, ext string) string {
+	file := base + ext
+	for i := 2; s.fileExists[strings.ToLower(file)]; i++ {
+		file = base + "_" + strconv.Itoa(i) + ext
+	}
+	s.fileExists[strings.ToLower(file)] = true
+	return file
+}
+
+// rewrite makes the $refs below node, which is written to file, relative to that file. The scope
+// tells the keys of node apart from property and component names.
+func (s *splitter) rewrite(node *yaml.Node, scope yamlnode.Scope, file string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			switch {
+			case scope.Names:
+				s.rewrite(value, scope.Child(key), file)
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				value.Value = s.ref(value.Value, file)
+			case scope.IsExample(key):
+				// Examples are data, where $ref has no meaning
+			case key == "discriminator":
+				// Discriminator mappings name schemas by reference too
+				yamlnode.Pairs(yamlnode.Get(value, "mapping"), func(_, target *yaml.Node) {
+					if strings.HasPrefix(target.Value, "#") {
+						target.Value = s.ref(target.Value, file)
+					}
+				})
+			default:
+				s.rewrite(value, scope.Child(key), file)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			s.rewrite(child, scope.Item(), file)
+		}
+	}
+}
+
+// ref returns a $ref of the root document as seen from file.
+func (s *splitter) ref(ref, file string) string {
+	if !strings.HasPrefix(ref, "#") {
+		if file == s.rootFile || isURL(ref) || path.IsAbs(ref) {
+			return ref
+		}
+		// Relative to the directory of the root file
+		target, fragment, found := strings.Cut(ref, "#")
+		out := relative(file, path.Clean(target))
+		if found {
+			out += "#" + fragment
+		}
+		return out
+	}
+
+	pointer := ref[1:]
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+	if rest, ok := strings.CutPrefix(pointer, "/components/"); ok {
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) >= 2 {
+			if target, ok := s.compFiles[parts[0]+"/"+yamlnode.Unescape(parts[1])]; ok {
+				return relative(file, target) + fragmentOf(parts[2:])
+			}
+		}
+	}
+	if rest, ok := strings.CutPrefix(pointer, "/paths/"); ok {
+		parts := strings.SplitN(rest, "/", 2)
+		if target, ok := s.pathFiles[yamlnode.Unescape(parts[0])]; ok {
+			return relative(file, target) + fragmentOf(parts[1:])
+		}
+	}
+	if file == s.rootFile {
+		return ref
+	}
+	return relative(file, s.rootFile) + ref
+}
+
+func fragmentOf(rest []string) string {
+	if len(rest) == 0 || rest[0] == "" {
+		return ""
+	}
+	return "#/" + rest[0]
+}
+
+// relative returns the path of target relative to the directory of from, starting with ./ or ../.
+func relative(from, target string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._{}-]+`)
+
+// pathFileName names the file of a path: /users/{id} becomes users_{id}.
+func pathFileName(p string) string {
+	name := strings.ReplaceAll(strings.Trim(p, "/"), "/", "_")
+	name = invalidNameChars.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "root"
+	}
+	return name
+}
+
+func refNode(ref string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "$ref"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: ref},
+	}}
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,233 @@
+package split
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/pkg/bundle"
+	"github.com/fathurrohman26/yaswag/pkg/openapi"
+	"github.com/fathurrohman26/yaswag/pkg/output"
+)
+
+const spec = `openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: "#/components/schemas/Pet"}
+        default:
+          $ref: "#/components/responses/Problem"
+  /pets/{id}:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/Id"
+      responses:
+        "200":
+          $ref: "#/paths/~1pets/get/responses/200"
+components:
+  parameters:
+    Id: {name: id, in: path, required: true, schema: {type: string}}
+  responses:
+    Problem:
+      description: A problem
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+  schemas:
+    Pet:
+      type: object
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: "#/components/schemas/Cat"
+      properties:
+        owner: {$ref: "#/components/schemas/Owner/properties/name"}
+        parent: {$ref: "#/components/schemas/Pet"}
+      example: {$ref: "#/not/a/ref"}
+    Cat: {type: object}
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+    Error:
+      $ref: "./errors.yaml#/Error"
+`
+
+func parse(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	root, err := yamlnode.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func ref(t *testing.T, l *Layout, file, pointer string) string {
+	t.Helper()
+	node := yamlnode.Resolve(l.Files[file], pointer)
+	if node == nil {
+		t.Fatalf("%s#%s not found", file, pointer)
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	return yamlnode.Get(node, "$ref").Value
+}
+
+func TestNode(t *testing.T) {
+	root := parse(t, spec)
+	l, err := Node(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"components/parameters/Id.yaml",
+		"components/responses/Problem.yaml",
+		"components/schemas/Cat.yaml",
+		"components/schemas/Owner.yaml",
+		"components/schemas/Pet.yaml",
+		"openapi.yaml",
+		"paths/pets.yaml",
+		"paths/pets_{id}.yaml",
+	}
+	if got := l.Paths(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Paths() = %v, want %v", got, want)
+	}
+
+	refs := []struct{ file, pointer, want string }{
+		{"openapi.yaml", "/paths/~1pets", "./paths/pets.yaml"},
+		{"openapi.yaml", "/components/schemas/Pet", "./components/schemas/Pet.yaml"},
+		{"openapi.yaml", "/components/schemas/Error", "./errors.yaml#/Error"},
+		{"paths/pets.yaml", "/get/responses/200/content/application~1json/schema/items", "../components/schemas/Pet.yaml"},
+		{"paths/pets.yaml", "/get/responses/default", "../components/responses/Problem.yaml"},
+		{"paths/pets_{id}.yaml", "/get/parameters/0", "../components/parameters/Id.yaml"},
+		{"paths/pets_{id}.yaml", "/get/responses/200", "./pets.yaml#/get/responses/200"},
+		{"components/schemas/Pet.yaml", "/properties/owner", "./Owner.yaml#/properties/name"},
+		{"components/schemas/Pet.yaml", "/properties/parent", "./Pet.yaml"},
+		{"components/schemas/Pet.yaml", "/discriminator/mapping/cat", "./Cat.yaml"},
+		{"components/schemas/Pet.yaml", "/example", "#/not/a/ref"},
+		{"components/responses/Problem.yaml", "/content/application~1json/schema", "../../openapi.yaml#/components/schemas/Error"},
+	}
+	for _, r := range refs {
+		if got := ref(t, l, r.file, r.pointer); got != r.want {
+			t.Errorf("%s#%s: $ref = %q, want %q", r.file, r.pointer, got, r.want)
+		}
+	}
+
+	// The input is left as it was
+	if yamlnode.Get(yamlnode.Get(yamlnode.Get(root, "paths"), "/pets"), "get") == nil {
+		t.Error("Node() modified its input")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := strings.Replace(spec, `$ref: "./errors.yaml#/Error"`, "type: object", 1)
+	for _, format := range []output.Format{output.FormatYAML, output.FormatJSON} {
+		l, err := Node(parse(t, src), WithFormat(format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := filepath.Join(dir, string(format))
+		if err := l.Write(out, 2); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(out, "paths", "pets_{id}."+string(format))); err != nil {
+			t.Fatal(err)
+		}
+
+		bundled, err := bundle.File(filepath.Join(out, l.Root))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want any
+		if err := bundled.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if err := parse(t, src).Decode(&want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			gotYAML, _ := yaml.Marshal(got)
+			t.Errorf("%s: bundling the split files gave\n%s", format, gotYAML)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := &openapi.Document{
+		OpenAPI: "3.1.0",
+		Info:    openapi.Info{Title: "Pets", Version: "1.0.0"},
+		Paths: openapi.Paths{
+			"/": {Get: &openapi.Operation{Responses: openapi.Responses{"200": {Description: "ok"}}}},
+		},
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+			"Pet Owner": {Type: openapi.NewSchemaType(openapi.TypeString)},
+		}},
+	}
+	l, err := Document(doc, WithFormat(output.FormatJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"components/schemas/Pet_Owner.json", "openapi.json", "paths/root.json"}
+	if got := l.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestRoundTrip_PropertyNamedExample(t *testing.T) {
+	src := `openapi: 3.0.3
+info: {title: Things, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Wrapper:
+      type: object
+      properties:
+        example: {$ref: "#/components/schemas/Thing"}
+      example:
+        example: {$ref: "#/not/a/ref"}
+    Thing: {type: string}
+`
+	l, err := Node(parse(t, src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ref(t, l, "components/schemas/Wrapper.yaml", "/properties/example"); got != "./Thing.yaml" {
+		t.Errorf("Wrapper.yaml#/properties/example: $ref = %q", got)
+	}
+
+	out := t.TempDir()
+	if err := l.Write(out, 2); err != nil {
+		t.Fatal(err)
+	}
+	bundled, err := bundle.File(filepath.Join(out, l.Root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want any
+	if err := bundled.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if err := parse(t, src).Decode(&want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		gotYAML, _ := yaml.Marshal(got)
+		t.Errorf("bundling the split files gave\n%s", gotYAML)
+	}
+}
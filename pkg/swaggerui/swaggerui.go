@@ -11,6 +11,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/fathurrohman26/yaswag/pkg/validator"
 )
@@ -20,10 +21,15 @@ var templates embed.FS
 
 // Server serves OpenAPI specifications with Swagger UI.
 type Server struct {
+	mu          sync.RWMutex
 	specData    []byte
 	specURL     string
 	isRemoteURL bool
 	port        int
+
+	// Live reload: open pages listen on /events and refetch the spec on UpdateSpec
+	liveReload bool
+	clients    map[chan struct{}]struct{}
 }
 
 // NewServer creates a new Swagger UI server.
@@ -50,10 +56,34 @@ func (s *Server) SetSpecFromURL(url string) {
 
 // SetSpecFromData sets the OpenAPI specification from raw data.
 func (s *Server) SetSpecFromData(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.specData = data
 	s.isRemoteURL = false
 }
 
+// EnableLiveReload makes served pages reload the spec whenever UpdateSpec is called.
+func (s *Server) EnableLiveReload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.liveReload = true
+}
+
+// UpdateSpec replaces the specification data while the server is running and notifies open pages.
+// It is meant for servers whose spec was set with SetSpecFromData or SetSpecFromFile.
+func (s *Server) UpdateSpec(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specData = data
+	for ch := range s.clients {
+		// Clients only need to know that something changed, so a pending notification is enough
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // Serve starts the HTTP server and serves the Swagger UI.
 func (s *Server) Serve() error {
 	mux := http.NewServeMux()
@@ -64,6 +94,9 @@ func (s *Server) Serve() error {
 	// Serve validation endpoint
 	mux.HandleFunc("/validate", s.handleValidate)
 
+	// Serve live reload events
+	mux.HandleFunc("/events", s.handleEvents)
+
 	// Serve the Swagger UI HTML
 	mux.HandleFunc("/", s.handleUI)
 
@@ -92,7 +125,9 @@ func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
+		s.mu.RLock()
 		specData = s.specData
+		s.mu.RUnlock()
 	}
 
 	// Patch OpenAPI 3.2.x to 3.1.x for Swagger UI compatibility
@@ -107,9 +142,63 @@ func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if s.isLiveReload() {
+		w.Header().Set("Cache-Control", "no-store")
+	}
 	_, _ = w.Write(specData)
 }
 
+func (s *Server) isLiveReload() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.liveReload
+}
+
+// handleEvents streams a server-sent "reload" event every time the spec is updated.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok || !s.isLiveReload() {
+		http.NotFound(w, r)
+		return
+	}
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			_, _ = fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) subscribe() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients == nil {
+		s.clients = make(map[chan struct{}]struct{})
+	}
+	ch := make(chan struct{}, 1)
+	s.clients[ch] = struct{}{}
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, ch)
+}
+
 // ValidationResponse represents the JSON response for validation endpoint.
 type ValidationResponse struct {
 	Valid    bool             `json:"valid"`
@@ -147,8 +236,11 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) getSpecData() ([]byte, error) {
-	if !s.isRemoteURL {
-		return s.specData, nil
+	s.mu.RLock()
+	isRemoteURL, specData := s.isRemoteURL, s.specData
+	s.mu.RUnlock()
+	if !isRemoteURL {
+		return specData, nil
 	}
 	resp, err := http.Get(s.specURL)
 	if err != nil {
@@ -215,11 +307,11 @@ func mergeLocalResults(response *ValidationResponse, localResult *validator.Vali
 	if response.Valid && !localResult.Valid {
 		response.Valid = false
 		for _, e := range localResult.Errors {
-			response.Errors = append(response.Errors, ValidationItem{Message: e.Message, Path: e.Path})
+			response.Errors = append(response.Errors, ValidationItem{Message: e.Message, Path: e.Path, Line: e.Line, Column: e.Column})
 		}
 	}
 	for _, warn := range localResult.Warnings {
-		response.Warnings = append(response.Warnings, ValidationItem{Message: warn.Message, Path: warn.Path})
+		response.Warnings = append(response.Warnings, ValidationItem{Message: warn.Message, Path: warn.Path, Line: warn.Line, Column: warn.Column})
 	}
 }
 
@@ -319,9 +411,11 @@ func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := struct {
-		SpecURL string
+		SpecURL    string
+		LiveReload bool
 	}{
-		SpecURL: specURL,
+		SpecURL:    specURL,
+		LiveReload: s.isLiveReload(),
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -474,3 +474,81 @@ paths: {}`
 		}
 	})
 }
+
+func TestServer_HandleUI_LiveReload(t *testing.T) {
+	server := NewServer(8080)
+	server.SetSpecFromData([]byte(`{"openapi": "3.0.3"}`))
+
+	render := func() string {
+		w := httptest.NewRecorder()
+		server.handleUI(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Body.String()
+	}
+
+	if strings.Contains(render(), "EventSource") {
+		t.Error("expected no live reload script by default")
+	}
+	server.EnableLiveReload()
+	if !strings.Contains(render(), `new EventSource("/events")`) {
+		t.Error("expected live reload script when enabled")
+	}
+}
+
+func TestServer_HandleEvents_Disabled(t *testing.T) {
+	server := NewServer(8080)
+
+	w := httptest.NewRecorder()
+	server.handleEvents(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestServer_UpdateSpec_LiveReload(t *testing.T) {
+	server := NewServer(8080)
+	server.SetSpecFromData([]byte(`{"openapi": "3.0.3", "info": {"title": "v1"}}`))
+	server.EnableLiveReload()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/spec", server.handleSpec)
+	mux.HandleFunc("/events", server.handleEvents)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// Wait for the connection comment so the client is subscribed before updating.
+	buf := make([]byte, 256)
+	n, err := resp.Body.Read(buf)
+	if err != nil || !strings.Contains(string(buf[:n]), "connected") {
+		t.Fatalf("expected connection comment, got %q (%v)", buf[:n], err)
+	}
+
+	server.UpdateSpec([]byte(`{"openapi": "3.0.3", "info": {"title": "v2"}}`))
+
+	n, err = resp.Body.Read(buf)
+	if err != nil || !strings.Contains(string(buf[:n]), "event: reload") {
+		t.Fatalf("expected reload event, got %q (%v)", buf[:n], err)
+	}
+
+	specResp, err := http.Get(ts.URL + "/spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = specResp.Body.Close() }()
+	body, _ := io.ReadAll(specResp.Body)
+	if !strings.Contains(string(body), `"v2"`) {
+		t.Errorf("spec = %s, want updated spec", body)
+	}
+	if cc := specResp.Header.Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", cc)
+	}
+}
@@ -0,0 +1,218 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// httpMethods lists the operation fields of a Path Item object, in the order they are checked.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace", "query"}
+
+// checker runs structural and semantic checks on the yaml.Node tree of a document,
+// so that every problem is reported with a JSON pointer and a line and column.
+type checker struct {
+	root   *yaml.Node
+	minor  int // Minor version of the declared OpenAPI 3.x version
+	result *ValidationResult
+}
+
+func newChecker(root *yaml.Node, version string, result *ValidationResult) *checker {
+	c := &checker{root: root, result: result}
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) > 1 {
+		c.minor, _ = strconv.Atoi(parts[1])
+	}
+	return c
+}
+
+// run performs all checks and orders the findings by position.
+func (c *checker) run() {
+	c.checkStructure()
+	c.checkRefs()
+	c.checkOperationIDs()
+	c.checkPathParameters()
+	c.checkSecurityRequirements()
+	c.checkExamples()
+
+	c.result.Errors = unique(c.result.Errors)
+	c.result.Warnings = unique(c.result.Warnings)
+	sortByPosition(c.result.Errors)
+	sortByPosition(c.result.Warnings)
+}
+
+// unique returns errs without repeated findings.
+func unique(errs []ValidationError) []ValidationError {
+	seen := make(map[ValidationError]bool)
+	kept := errs[:0]
+	for _, e := range errs {
+		if !seen[e] {
+			seen[e] = true
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func sortByPosition(errs []ValidationError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+}
+
+func (c *checker) errorAt(node *yaml.Node, pointer, format string, args ...any) {
+	c.result.Valid = false
+	c.result.Errors = append(c.result.Errors, newPositionedError(node, pointer, fmt.Sprintf(format, args...)))
+}
+
+func (c *checker) warnAt(node *yaml.Node, pointer, format string, args ...any) {
+	c.result.Warnings = append(c.result.Warnings, newPositionedError(node, pointer, fmt.Sprintf(format, args...)))
+}
+
+func newPositionedError(node *yaml.Node, pointer, message string) ValidationError {
+	e := ValidationError{Message: message, Path: pointer}
+	if node != nil {
+		e.Line, e.Column = node.Line, node.Column
+	}
+	if e.Path == "" {
+		e.Path = "/"
+	}
+	return e
+}
+
+// resolve follows internal $ref chains and returns the referenced node, node itself when it is not a
+// reference, or nil when a reference cannot be resolved.
+func (c *checker) resolve(node *yaml.Node) *yaml.Node {
+	return yamlnode.FollowRefs(c.root, node)
+}
+
+// forEachOperation calls fn for every operation of every path, in document order.
+func (c *checker) forEachOperation(fn func(op *yaml.Node, pointer string)) {
+	c.forEachPathItem(func(_ string, item *yaml.Node, pointer string) {
+		forEachMethod(item, pointer, fn)
+	})
+}
+
+// forEachPathItem calls fn for every Path Item object, with its path template.
+func (c *checker) forEachPathItem(fn func(path string, item *yaml.Node, pointer string)) {
+	yamlnode.Pairs(yamlnode.Get(c.root, "paths"), func(key, value *yaml.Node) {
+		if value.Kind == yaml.MappingNode && !strings.HasPrefix(key.Value, "x-") {
+			fn(key.Value, value, yamlnode.Join("/paths", key.Value))
+		}
+	})
+}
+
+func forEachMethod(item *yaml.Node, pointer string, fn func(op *yaml.Node, pointer string)) {
+	for _, method := range httpMethods {
+		if op := yamlnode.Get(item, method); op != nil && op.Kind == yaml.MappingNode {
+			fn(op, yamlnode.Join(pointer, method))
+		}
+	}
+}
+
+// forEachSchema calls fn once for every Schema object in the document: component schemas,
+// every "schema" field and all of their subschemas.
+func (c *checker) forEachSchema(fn func(schema *yaml.Node, pointer string)) {
+	seen := make(map[*yaml.Node]bool)
+	var visit func(schema *yaml.Node, pointer string)
+	visit = func(schema *yaml.Node, pointer string) {
+		if schema == nil || schema.Kind != yaml.MappingNode || seen[schema] {
+			return
+		}
+		seen[schema] = true
+		fn(schema, pointer)
+		forEachSubschema(schema, pointer, visit)
+	}
+
+	yamlnode.Pairs(yamlnode.Resolve(c.root, "/components/schemas"), func(key, value *yaml.Node) {
+		visit(value, yamlnode.Join("/components/schemas", key.Value))
+	})
+	c.walkObjects(func(node *yaml.Node, pointer string) {
+		if schema := yamlnode.Get(node, "schema"); schema != nil {
+			visit(schema, yamlnode.Join(pointer, "schema"))
+		}
+	})
+}
+
+// walkObjects calls fn for every object of the document outside example and literal data, with its
+// pointer. Maps of names such as properties are not objects themselves, so a property named default
+// or example is still walked and a property named schema is not taken for a field.
+func (c *checker) walkObjects(fn func(node *yaml.Node, pointer string)) {
+	walkObjects(c.root, yamlnode.Scope{}, "", fn, make(map[*yaml.Node]bool))
+}
+
+func walkObjects(node *yaml.Node, scope yamlnode.Scope, pointer string, fn func(*yaml.Node, string), seen map[*yaml.Node]bool) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node == nil || seen[node] {
+		return
+	}
+	seen[node] = true
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkObjects(child, scope, pointer, fn, seen)
+		}
+	case yaml.MappingNode:
+		if !scope.Names {
+			fn(node, pointer)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if !scope.Names && isData(scope, key, value) {
+				continue
+			}
+			walkObjects(value, scope.Child(key), yamlnode.Join(pointer, key), fn, seen)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			walkObjects(child, scope.Item(), yamlnode.Index(pointer, i), fn, seen)
+		}
+	}
+}
+
+// isData reports whether the value of a field holds example or literal data rather than OpenAPI objects.
+func isData(scope yamlnode.Scope, key string, value *yaml.Node) bool {
+	switch key {
+	case "default", "enum", "const":
+		return true
+	case "examples":
+		// The examples of a schema are a list of values; elsewhere examples map names to Example objects
+		return value.Kind == yaml.SequenceNode
+	}
+	return scope.IsExample(key)
+}
+
+// Subschema keywords, grouped by how they hold their subschemas.
+var (
+	singleSubschemas = []string{"items", "additionalProperties", "not", "contains", "if", "then", "else", "propertyNames"}
+	mapSubschemas    = []string{"properties", "patternProperties", "$defs", "definitions"}
+	listSubschemas   = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+)
+
+func forEachSubschema(schema *yaml.Node, pointer string, fn func(schema *yaml.Node, pointer string)) {
+	for _, key := range singleSubschemas {
+		if sub := yamlnode.Get(schema, key); sub != nil {
+			fn(sub, yamlnode.Join(pointer, key))
+		}
+	}
+	for _, key := range mapSubschemas {
+		yamlnode.Pairs(yamlnode.Get(schema, key), func(name, sub *yaml.Node) {
+			fn(sub, yamlnode.Join(pointer, key, name.Value))
+		})
+	}
+	for _, key := range listSubschemas {
+		for i, sub := range yamlnode.Items(yamlnode.Get(schema, key)) {
+			fn(sub, yamlnode.Index(yamlnode.Join(pointer, key), i))
+		}
+	}
+}
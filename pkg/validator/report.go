@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"encoding/json"
+
+	"github.com/fathurrohman26/yaswag/pkg/report"
+)
+
+// StdinSource is the Source of documents read from standard input.
+const StdinSource = "<stdin>"
+
+// Rule IDs of validation findings in reports.
+const (
+	RuleError   = "openapi-error"
+	RuleWarning = "openapi-warning"
+)
+
+// FormatJSON formats the validation result as JSON. Empty lists are written as [] rather than null.
+func FormatJSON(result *ValidationResult) ([]byte, error) {
+	out := *result
+	if out.Errors == nil {
+		out.Errors = []ValidationError{}
+	}
+	if out.Warnings == nil {
+		out.Warnings = []ValidationError{}
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+// ToReport converts a validation result for the machine-readable report formats.
+func ToReport(result *ValidationResult) *report.Report {
+	r := &report.Report{
+		Name: "validate",
+		Rules: []report.Rule{
+			{ID: RuleError, Name: "OpenAPIError", Description: "The document violates the OpenAPI specification"},
+			{ID: RuleWarning, Name: "OpenAPIWarning", Description: "The document is valid but likely not what was intended"},
+		},
+	}
+	file := result.Source
+	if file == StdinSource {
+		file = ""
+	}
+	addIssues := func(errs []ValidationError, rule string, level report.Level) {
+		for _, e := range errs {
+			r.Issues = append(r.Issues, report.Issue{
+				RuleID: rule, Level: level, Message: e.Message,
+				File: file, Line: e.Line, Column: e.Column, Pointer: e.Path,
+			})
+		}
+	}
+	addIssues(result.Errors, RuleError, report.LevelError)
+	addIssues(result.Warnings, RuleWarning, report.LevelWarning)
+	return r
+}
@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+	"github.com/fathurrohman26/yaswag/internal/yamlschema"
+)
+
+// placeholderPattern matches the {name} placeholders of a path template.
+var placeholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// checkRefs reports internal references that do not resolve. External references cannot be
+// followed from a single document and are reported as warnings.
+func (c *checker) checkRefs() {
+	c.walkObjects(func(node *yaml.Node, pointer string) {
+		ref := yamlnode.Get(node, "$ref")
+		if ref == nil || ref.Kind != yaml.ScalarNode {
+			return
+		}
+		refPointer := yamlnode.Join(pointer, "$ref")
+		switch {
+		case !strings.HasPrefix(ref.Value, "#"):
+			c.warnAt(ref, refPointer, "external reference %q is not resolved", ref.Value)
+		case yamlnode.Resolve(c.root, ref.Value) == nil:
+			c.errorAt(ref, refPointer, "unresolved reference %q", ref.Value)
+		}
+	})
+}
+
+// checkOperationIDs reports operationIds used by more than one operation.
+func (c *checker) checkOperationIDs() {
+	seen := make(map[string]string)
+	c.forEachOperation(func(op *yaml.Node, pointer string) {
+		id := yamlnode.Get(op, "operationId")
+		if id == nil || id.Value == "" {
+			return
+		}
+		idPointer := yamlnode.Join(pointer, "operationId")
+		if first, ok := seen[id.Value]; ok {
+			c.errorAt(id, idPointer, "duplicate operationId %q, first used at %s", id.Value, first)
+			return
+		}
+		seen[id.Value] = idPointer
+	})
+}
+
+// pathParam is a path parameter declared by a Path Item or Operation object.
+type pathParam struct {
+	node    *yaml.Node
+	pointer string
+}
+
+// checkPathParameters reports path parameters without a matching placeholder in the path
+// template, and placeholders that no path parameter declares.
+func (c *checker) checkPathParameters() {
+	c.forEachPathItem(func(path string, item *yaml.Node, pointer string) {
+		placeholders := make(map[string]bool)
+		for _, m := range placeholderPattern.FindAllStringSubmatch(path, -1) {
+			placeholders[m[1]] = true
+		}
+
+		common := c.pathParams(item, pointer)
+		c.reportUnusedParams(common, placeholders, path)
+		forEachMethod(item, pointer, func(op *yaml.Node, opPointer string) {
+			own := c.pathParams(op, opPointer)
+			c.reportUnusedParams(own, placeholders, path)
+			c.reportUndeclaredPlaceholders(op, opPointer, path, common, own)
+		})
+	})
+}
+
+// pathParams returns the path parameters of a Path Item or Operation object by name.
+func (c *checker) pathParams(container *yaml.Node, pointer string) map[string]pathParam {
+	params := make(map[string]pathParam)
+	listPointer := yamlnode.Join(pointer, "parameters")
+	for i, item := range yamlnode.Items(yamlnode.Get(container, "parameters")) {
+		param := c.resolve(item)
+		if yamlnode.Scalar(yamlnode.Get(param, "in")) != "path" {
+			continue
+		}
+		params[yamlnode.Scalar(yamlnode.Get(param, "name"))] = pathParam{node: item, pointer: yamlnode.Index(listPointer, i)}
+	}
+	return params
+}
+
+func (c *checker) reportUnusedParams(params map[string]pathParam, placeholders map[string]bool, path string) {
+	for name, param := range params {
+		if !placeholders[name] {
+			c.errorAt(param.node, param.pointer, "path parameter %q does not appear in path %q", name, path)
+		}
+	}
+}
+
+func (c *checker) reportUndeclaredPlaceholders(op *yaml.Node, pointer, path string, common, own map[string]pathParam) {
+	for _, m := range placeholderPattern.FindAllStringSubmatch(path, -1) {
+		name := m[1]
+		_, inCommon := common[name]
+		_, inOwn := own[name]
+		if !inCommon && !inOwn {
+			c.errorAt(op, pointer, "path %q has placeholder {%s} but no path parameter %q is declared", path, name, name)
+		}
+	}
+}
+
+// checkSecurityRequirements reports security requirements naming undefined security schemes.
+func (c *checker) checkSecurityRequirements() {
+	schemes := yamlnode.Resolve(c.root, "/components/securitySchemes")
+	c.checkRequirements(yamlnode.Get(c.root, "security"), "/security", schemes)
+	c.forEachOperation(func(op *yaml.Node, pointer string) {
+		c.checkRequirements(yamlnode.Get(op, "security"), yamlnode.Join(pointer, "security"), schemes)
+	})
+}
+
+func (c *checker) checkRequirements(list *yaml.Node, pointer string, schemes *yaml.Node) {
+	for i, requirement := range yamlnode.Items(list) {
+		yamlnode.Pairs(requirement, func(key, _ *yaml.Node) {
+			if yamlnode.Get(schemes, key.Value) == nil {
+				c.errorAt(key, yamlnode.Join(yamlnode.Index(pointer, i), key.Value),
+					"security requirement references undefined security scheme %q", key.Value)
+			}
+		})
+	}
+}
+
+// checkExamples reports examples that do not satisfy their schema. Examples are not
+// normative, so mismatches are warnings.
+func (c *checker) checkExamples() {
+	c.forEachSchema(func(schema *yaml.Node, pointer string) {
+		if example := yamlnode.Get(schema, "example"); example != nil {
+			c.checkExample(schema, example, yamlnode.Join(pointer, "example"))
+		}
+		for i, example := range yamlnode.Items(yamlnode.Get(schema, "examples")) {
+			c.checkExample(schema, example, yamlnode.Index(yamlnode.Join(pointer, "examples"), i))
+		}
+	})
+
+	// Parameters, headers and media types hold examples next to their schema
+	c.walkObjects(func(node *yaml.Node, pointer string) {
+		schema := yamlnode.Get(node, "schema")
+		if schema == nil {
+			return
+		}
+		if example := yamlnode.Get(node, "example"); example != nil {
+			c.checkExample(schema, example, yamlnode.Join(pointer, "example"))
+		}
+		yamlnode.Pairs(yamlnode.Get(node, "examples"), func(key, value *yaml.Node) {
+			if example := yamlnode.Get(c.resolve(value), "value"); example != nil {
+				c.checkExample(schema, example, yamlnode.Join(pointer, "examples", key.Value, "value"))
+			}
+		})
+	})
+}
+
+func (c *checker) checkExample(schema, example *yaml.Node, pointer string) {
+	if msg := yamlschema.Match(c.root, schema, example); msg != "" {
+		c.warnAt(example, pointer, "example does not match its schema: %s", msg)
+	}
+}
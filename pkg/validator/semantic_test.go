@@ -0,0 +1,360 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// findError returns the first finding whose message contains substr.
+func findError(errs []ValidationError, substr string) (ValidationError, bool) {
+	for _, e := range errs {
+		if strings.Contains(e.Message, substr) {
+			return e, true
+		}
+	}
+	return ValidationError{}, false
+}
+
+func validate(t *testing.T, spec string) *ValidationResult {
+	t.Helper()
+	result, err := New().Validate([]byte(spec))
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return result
+}
+
+func TestValidator_UnresolvedRef(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Missing"
+        "404":
+          $ref: "common.yaml#/responses/NotFound"
+`)
+	if result.Valid {
+		t.Error("expected invalid result")
+	}
+	e, ok := findError(result.Errors, `unresolved reference "#/components/schemas/Missing"`)
+	if !ok {
+		t.Fatalf("missing unresolved reference error, got %v", result.Errors)
+	}
+	if e.Line != 14 || e.Column != 23 {
+		t.Errorf("position = %d:%d, want 14:23", e.Line, e.Column)
+	}
+	if want := "/paths/~1users/get/responses/200/content/application~1json/schema/$ref"; e.Path != want {
+		t.Errorf("Path = %q, want %q", e.Path, want)
+	}
+	if _, ok := findError(result.Warnings, "external reference"); !ok {
+		t.Errorf("expected warning for external reference, got %v", result.Warnings)
+	}
+}
+
+func TestValidator_DuplicateOperationID(t *testing.T) {
+	result := validate(t, `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+  /people:
+    get:
+      operationId: listUsers
+      responses:
+        "200":
+          description: OK
+`)
+	e, ok := findError(result.Errors, `duplicate operationId "listUsers"`)
+	if !ok {
+		t.Fatalf("missing duplicate operationId error, got %v", result.Errors)
+	}
+	if e.Line != 14 || e.Path != "/paths/~1people/get/operationId" {
+		t.Errorf("got line %d at %s", e.Line, e.Path)
+	}
+	if !strings.Contains(e.Message, "/paths/~1users/get/operationId") {
+		t.Errorf("message should point at the first use: %s", e.Message)
+	}
+}
+
+func TestValidator_PathParameters(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users/{id}:
+    parameters:
+      - $ref: "#/components/parameters/Version"
+    get:
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+components:
+  parameters:
+    Version:
+      name: version
+      in: path
+      required: true
+      schema:
+        type: string
+`)
+	if len(result.Errors) != 3 {
+		t.Fatalf("expected 3 errors, got %v", result.Errors)
+	}
+	for _, want := range []string{
+		`path parameter "version" does not appear in path "/users/{id}"`,
+		`path parameter "userId" does not appear`,
+		`placeholder {id} but no path parameter "id" is declared`,
+	} {
+		if _, ok := findError(result.Errors, want); !ok {
+			t.Errorf("missing error %q", want)
+		}
+	}
+	if e, _ := findError(result.Errors, "placeholder {id}"); e.Path != "/paths/~1users~1{id}/get" || e.Line != 10 {
+		t.Errorf("placeholder error at line %d %s", e.Line, e.Path)
+	}
+}
+
+func TestValidator_PathParametersInherited(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      responses:
+        "200":
+          description: OK
+`)
+	if !result.Valid {
+		t.Errorf("expected valid result, got %v", result.Errors)
+	}
+}
+
+func TestValidator_UndefinedSecurityScheme(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+security:
+  - apiKey: []
+paths:
+  /users:
+    get:
+      security:
+        - oauth: [read]
+      responses:
+        "200":
+          description: OK
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`)
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", result.Errors)
+	}
+	e := result.Errors[0]
+	if !strings.Contains(e.Message, `undefined security scheme "oauth"`) || e.Path != "/paths/~1users/get/security/0/oauth" || e.Line != 11 {
+		t.Errorf("unexpected error %v", e)
+	}
+}
+
+func TestValidator_UnresolvedRefReportedOnce(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+paths:
+  /users:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+    post:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        pet: {$ref: "#/components/schemas/Missing"}
+`)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "/components/schemas/User/properties/pet/$ref" {
+		t.Errorf("expected one unresolved reference error, got %v", result.Errors)
+	}
+}
+
+func TestValidator_PropertiesNamedLikeDataFields(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+paths: {}
+components:
+  schemas:
+    Setting:
+      type: object
+      properties:
+        default:
+          $ref: "#/components/schemas/Missing"
+        example:
+          type: integer
+          example: ten
+        enum:
+          type: object
+          properties:
+            schema: {type: string, example: 1}
+`)
+	if _, ok := findError(result.Errors, `unresolved reference "#/components/schemas/Missing"`); !ok {
+		t.Errorf("missing the unresolved reference of property default, got %v", result.Errors)
+	}
+	for _, want := range []struct{ message, path string }{
+		{"expected integer, got string", "/components/schemas/Setting/properties/example/example"},
+		{"expected string, got integer", "/components/schemas/Setting/properties/enum/properties/schema/example"},
+	} {
+		if e, ok := findError(result.Warnings, want.message); !ok || e.Path != want.path {
+			t.Errorf("missing warning %q at %s, got %v", want.message, want.path, result.Warnings)
+		}
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("expected 1 error, got %v", result.Errors)
+	}
+}
+
+func TestValidator_Examples(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+          example: 500
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+              examples:
+                bad:
+                  value:
+                    id: 1
+                    status: unknown
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          example: Ada
+        status:
+          type: string
+          enum: [active, disabled]
+        email:
+          type: string
+          nullable: true
+          example: null
+      example:
+        id: "1"
+        name: Ada
+`)
+	if !result.Valid {
+		t.Errorf("example mismatches should be warnings, got errors %v", result.Errors)
+	}
+	for _, want := range []struct{ message, path string }{
+		{"value: 500 is above the maximum 100", "/paths/~1users/get/parameters/0/example"},
+		{`value: missing required property "name"`, "/paths/~1users/get/responses/200/content/application~1json/examples/bad/value"},
+		{"/id: expected integer, got string", "/components/schemas/User/example"},
+	} {
+		e, ok := findError(result.Warnings, want.message)
+		if !ok {
+			t.Errorf("missing warning %q in %v", want.message, result.Warnings)
+			continue
+		}
+		if e.Path != want.path {
+			t.Errorf("warning %q at %s, want %s", want.message, e.Path, want.path)
+		}
+	}
+	if len(result.Warnings) != 3 {
+		t.Errorf("expected 3 warnings, got %v", result.Warnings)
+	}
+}
+
+func TestValidator_FindingsSortedByPosition(t *testing.T) {
+	result := validate(t, `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+security:
+  - missing: []
+paths:
+  /a:
+    get:
+      operationId: same
+      responses:
+        "200":
+          description: OK
+  /b:
+    get:
+      operationId: same
+      responses:
+        "200":
+          $ref: "#/components/responses/Missing"
+`)
+	// The missing response is reported once, at its $ref, rather than again by libopenapi
+	if len(result.Errors) != 3 {
+		t.Fatalf("expected 3 errors, got %v", result.Errors)
+	}
+	for i := 1; i < len(result.Errors); i++ {
+		if result.Errors[i].Line < result.Errors[i-1].Line {
+			t.Errorf("errors not sorted by line: %v", result.Errors)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package validator
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
+)
+
+// objectRules describes the fixed fields of an OpenAPI object, as given by the meta-schema.
+// fields maps each field to the first 3.x minor version defining it.
+type objectRules struct {
+	name     string
+	fields   map[string]int
+	required []string
+}
+
+var (
+	rootRules = objectRules{
+		name: "OpenAPI object",
+		fields: map[string]int{
+			"openapi": 0, "info": 0, "servers": 0, "paths": 0, "components": 0, "security": 0, "tags": 0,
+			"externalDocs": 0, "webhooks": 1, "jsonSchemaDialect": 1, "$self": 2,
+		},
+		required: []string{"openapi", "info"},
+	}
+	infoRules = objectRules{
+		name: "Info object",
+		fields: map[string]int{
+			"title": 0, "summary": 1, "description": 0, "termsOfService": 0, "contact": 0, "license": 0, "version": 0,
+		},
+		required: []string{"title", "version"},
+	}
+	serverRules = objectRules{
+		name:     "Server object",
+		fields:   map[string]int{"url": 0, "description": 0, "variables": 0, "name": 2},
+		required: []string{"url"},
+	}
+	tagRules = objectRules{
+		name:     "Tag object",
+		fields:   map[string]int{"name": 0, "description": 0, "externalDocs": 0, "summary": 2, "parent": 2, "kind": 2},
+		required: []string{"name"},
+	}
+	pathItemRules = objectRules{
+		name: "Path Item object",
+		fields: map[string]int{
+			"$ref": 0, "summary": 0, "description": 0, "servers": 0, "parameters": 0,
+			"get": 0, "put": 0, "post": 0, "delete": 0, "options": 0, "head": 0, "patch": 0, "trace": 0,
+			"query": 2, "additionalOperations": 2,
+		},
+	}
+	operationRules = objectRules{
+		name: "Operation object",
+		fields: map[string]int{
+			"tags": 0, "summary": 0, "description": 0, "externalDocs": 0, "operationId": 0, "parameters": 0,
+			"requestBody": 0, "responses": 0, "callbacks": 0, "deprecated": 0, "security": 0, "servers": 0,
+		},
+	}
+	parameterRules = objectRules{
+		name: "Parameter object",
+		fields: map[string]int{
+			"name": 0, "in": 0, "description": 0, "required": 0, "deprecated": 0, "allowEmptyValue": 0,
+			"style": 0, "explode": 0, "allowReserved": 0, "schema": 0, "example": 0, "examples": 0, "content": 0,
+		},
+		required: []string{"name", "in"},
+	}
+	responseRules = objectRules{
+		name:   "Response object",
+		fields: map[string]int{"description": 0, "headers": 0, "content": 0, "links": 0, "summary": 2},
+	}
+	securitySchemeRules = objectRules{
+		name: "Security Scheme object",
+		fields: map[string]int{
+			"type": 0, "description": 0, "name": 0, "in": 0, "scheme": 0, "bearerFormat": 0, "flows": 0,
+			"openIdConnectUrl": 0, "oauth2MetadataUrl": 2, "deprecated": 2,
+		},
+		required: []string{"type"},
+	}
+)
+
+// statusCodePattern matches the keys allowed in a Responses object besides "default".
+var statusCodePattern = regexp.MustCompile(`^[1-5](?:[0-9]{2}|XX)$`)
+
+// Allowed values of enumerated fields.
+var (
+	parameterLocations = []string{"query", "header", "path", "cookie"}
+	apiKeyLocations    = []string{"query", "header", "cookie"}
+	schemaTypeNames    = []string{"string", "number", "integer", "boolean", "array", "object"}
+)
+
+// securitySchemeFields lists the fields each security scheme type requires.
+var securitySchemeFields = map[string][]string{
+	"apiKey":        {"name", "in"},
+	"http":          {"scheme"},
+	"oauth2":        {"flows"},
+	"openIdConnect": {"openIdConnectUrl"},
+	"mutualTLS":     nil,
+}
+
+// checkStructure checks the document against the OpenAPI meta-schema of its declared version.
+func (c *checker) checkStructure() {
+	if !c.checkObject(c.root, "", rootRules) {
+		return
+	}
+	c.checkTopLevel()
+	c.checkObject(yamlnode.Get(c.root, "info"), "/info", infoRules)
+	c.checkList(yamlnode.Get(c.root, "servers"), "/servers", serverRules)
+	c.checkList(yamlnode.Get(c.root, "tags"), "/tags", tagRules)
+	c.checkPaths()
+	c.checkComponents()
+	c.forEachSchema(c.checkSchema)
+}
+
+// checkTopLevel warns about documents that describe no API at all. The meta-schema makes these
+// errors, but they are common while a specification is being written.
+func (c *checker) checkTopLevel() {
+	if c.minor == 0 {
+		if yamlnode.Get(c.root, "paths") == nil {
+			c.warnAt(c.root, "", "missing paths field; an OpenAPI 3.0 document requires paths")
+		}
+		return
+	}
+	for _, field := range []string{"paths", "components", "webhooks"} {
+		if yamlnode.Get(c.root, field) != nil {
+			return
+		}
+	}
+	c.warnAt(c.root, "", "document defines none of paths, components or webhooks")
+}
+
+// checkObject reports unknown, too new and missing fields. It returns false when node is not an object.
+func (c *checker) checkObject(node *yaml.Node, pointer string, rules objectRules) bool {
+	if node == nil {
+		return false
+	}
+	if node.Kind != yaml.MappingNode {
+		c.errorAt(node, pointer, "%s must be an object", rules.name)
+		return false
+	}
+	yamlnode.Pairs(node, func(key, _ *yaml.Node) {
+		c.checkField(key, yamlnode.Join(pointer, key.Value), rules)
+	})
+	for _, field := range rules.required {
+		c.requireField(node, pointer, field, rules.name)
+	}
+	return true
+}
+
+func (c *checker) checkField(key *yaml.Node, pointer string, rules objectRules) {
+	if strings.HasPrefix(key.Value, "x-") {
+		return
+	}
+	since, ok := rules.fields[key.Value]
+	if !ok {
+		c.errorAt(key, pointer, "unknown field %q in %s", key.Value, rules.name)
+		return
+	}
+	if since > c.minor {
+		c.errorAt(key, pointer, "field %q in %s requires OpenAPI 3.%d or later", key.Value, rules.name, since)
+	}
+}
+
+func (c *checker) requireField(node *yaml.Node, pointer, field, name string) {
+	if yamlnode.Get(node, field) == nil {
+		c.errorAt(node, pointer, "missing required field %q in %s", field, name)
+	}
+}
+
+// checkList checks every element of a list of objects.
+func (c *checker) checkList(list *yaml.Node, pointer string, rules objectRules) {
+	for i, item := range yamlnode.Items(list) {
+		c.checkObject(item, yamlnode.Index(pointer, i), rules)
+	}
+}
+
+func (c *checker) checkPaths() {
+	yamlnode.Pairs(yamlnode.Get(c.root, "paths"), func(key, _ *yaml.Node) {
+		if !strings.HasPrefix(key.Value, "/") && !strings.HasPrefix(key.Value, "x-") {
+			c.errorAt(key, yamlnode.Join("/paths", key.Value), "path %q must begin with /", key.Value)
+		}
+	})
+	c.forEachPathItem(func(_ string, item *yaml.Node, pointer string) {
+		c.checkObject(item, pointer, pathItemRules)
+		c.checkParameters(item, pointer)
+		forEachMethod(item, pointer, c.checkOperation)
+	})
+}
+
+func (c *checker) checkOperation(op *yaml.Node, pointer string) {
+	c.checkObject(op, pointer, operationRules)
+	if c.minor == 0 {
+		c.requireField(op, pointer, "responses", operationRules.name)
+	}
+	c.checkParameters(op, pointer)
+	yamlnode.Pairs(yamlnode.Get(op, "responses"), func(key, response *yaml.Node) {
+		responsePointer := yamlnode.Join(pointer, "responses", key.Value)
+		if key.Value != "default" && !strings.HasPrefix(key.Value, "x-") && !statusCodePattern.MatchString(key.Value) {
+			c.errorAt(key, responsePointer, "invalid response status code %q", key.Value)
+		}
+		c.checkResponse(response, responsePointer)
+	})
+}
+
+func (c *checker) checkParameters(container *yaml.Node, pointer string) {
+	listPointer := yamlnode.Join(pointer, "parameters")
+	for i, param := range yamlnode.Items(yamlnode.Get(container, "parameters")) {
+		c.checkParameter(param, yamlnode.Index(listPointer, i))
+	}
+}
+
+func (c *checker) checkParameter(param *yaml.Node, pointer string) {
+	if yamlnode.Get(param, "$ref") != nil || !c.checkObject(param, pointer, parameterRules) {
+		return
+	}
+	in := yamlnode.Get(param, "in")
+	locations := parameterLocations
+	if c.minor >= 2 {
+		locations = append(slices.Clone(locations), "querystring")
+	}
+	if in != nil && !slices.Contains(locations, in.Value) {
+		c.errorAt(in, yamlnode.Join(pointer, "in"), "invalid parameter location %q, must be one of %s", in.Value, strings.Join(locations, ", "))
+	}
+	if yamlnode.Scalar(in) == "path" && yamlnode.Scalar(yamlnode.Get(param, "required")) != "true" {
+		c.errorAt(param, pointer, "path parameter %q must be required", yamlnode.Scalar(yamlnode.Get(param, "name")))
+	}
+	if (yamlnode.Get(param, "schema") == nil) == (yamlnode.Get(param, "content") == nil) {
+		c.errorAt(param, pointer, "parameter must define exactly one of schema or content")
+	}
+}
+
+func (c *checker) checkResponse(response *yaml.Node, pointer string) {
+	if yamlnode.Get(response, "$ref") != nil || !c.checkObject(response, pointer, responseRules) {
+		return
+	}
+	if c.minor < 2 {
+		c.requireField(response, pointer, "description", responseRules.name)
+	}
+}
+
+func (c *checker) checkComponents() {
+	yamlnode.Pairs(yamlnode.Resolve(c.root, "/components/parameters"), func(key, param *yaml.Node) {
+		c.checkParameter(param, yamlnode.Join("/components/parameters", key.Value))
+	})
+	yamlnode.Pairs(yamlnode.Resolve(c.root, "/components/responses"), func(key, response *yaml.Node) {
+		c.checkResponse(response, yamlnode.Join("/components/responses", key.Value))
+	})
+	yamlnode.Pairs(yamlnode.Resolve(c.root, "/components/securitySchemes"), func(key, scheme *yaml.Node) {
+		c.checkSecurityScheme(scheme, yamlnode.Join("/components/securitySchemes", key.Value))
+	})
+}
+
+func (c *checker) checkSecurityScheme(scheme *yaml.Node, pointer string) {
+	if yamlnode.Get(scheme, "$ref") != nil || !c.checkObject(scheme, pointer, securitySchemeRules) {
+		return
+	}
+	typeNode := yamlnode.Get(scheme, "type")
+	if typeNode == nil {
+		return
+	}
+	required, ok := securitySchemeFields[typeNode.Value]
+	if !ok || typeNode.Value == "mutualTLS" && c.minor == 0 {
+		c.errorAt(typeNode, yamlnode.Join(pointer, "type"), "invalid security scheme type %q", typeNode.Value)
+		return
+	}
+	for _, field := range required {
+		c.requireField(scheme, pointer, field, typeNode.Value+" security scheme")
+	}
+	in := yamlnode.Get(scheme, "in")
+	if typeNode.Value == "apiKey" && in != nil && !slices.Contains(apiKeyLocations, in.Value) {
+		c.errorAt(in, yamlnode.Join(pointer, "in"), "invalid API key location %q, must be one of %s", in.Value, strings.Join(apiKeyLocations, ", "))
+	}
+}
+
+// checkSchema checks the keywords whose form differs between OpenAPI 3.0 and 3.1+.
+func (c *checker) checkSchema(schema *yaml.Node, pointer string) {
+	c.checkSchemaType(schema, pointer)
+	for _, keyword := range []string{"exclusiveMinimum", "exclusiveMaximum"} {
+		c.checkExclusiveBound(schema, pointer, keyword)
+	}
+	if c.minor == 0 && yamlnode.Scalar(yamlnode.Get(schema, "type")) == "array" && yamlnode.Get(schema, "items") == nil {
+		c.errorAt(schema, pointer, "array schema must define items in OpenAPI 3.0")
+	}
+	if nullable := yamlnode.GetKey(schema, "nullable"); nullable != nil && c.minor >= 1 {
+		c.warnAt(nullable, yamlnode.Join(pointer, "nullable"), "nullable is not supported in OpenAPI 3.1+, add \"null\" to type instead")
+	}
+}
+
+func (c *checker) checkSchemaType(schema *yaml.Node, pointer string) {
+	typeNode := yamlnode.Get(schema, "type")
+	if typeNode == nil {
+		return
+	}
+	typePointer := yamlnode.Join(pointer, "type")
+	allowed := schemaTypeNames
+	if c.minor >= 1 {
+		allowed = append(slices.Clone(allowed), "null")
+	}
+	if typeNode.Kind == yaml.SequenceNode && c.minor == 0 {
+		c.errorAt(typeNode, typePointer, "type must be a single string in OpenAPI 3.0")
+		return
+	}
+	names := yamlnode.Items(typeNode)
+	if typeNode.Kind == yaml.ScalarNode {
+		names = []*yaml.Node{typeNode}
+	}
+	for _, name := range names {
+		if !slices.Contains(allowed, name.Value) {
+			c.errorAt(name, typePointer, "invalid schema type %q", name.Value)
+		}
+	}
+}
+
+func (c *checker) checkExclusiveBound(schema *yaml.Node, pointer, keyword string) {
+	bound := yamlnode.Get(schema, keyword)
+	if bound == nil {
+		return
+	}
+	isBool := bound.Tag == "!!bool"
+	switch {
+	case c.minor == 0 && !isBool:
+		c.errorAt(bound, yamlnode.Join(pointer, keyword), "%s must be a boolean in OpenAPI 3.0", keyword)
+	case c.minor >= 1 && isBool:
+		c.errorAt(bound, yamlnode.Join(pointer, keyword), "%s must be a number in OpenAPI 3.1+", keyword)
+	}
+}
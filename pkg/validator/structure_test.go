@@ -0,0 +1,273 @@
+package validator
+
+import (
+	"testing"
+)
+
+func TestValidator_Structure(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantErr  string
+		wantPath string
+		wantLine int
+	}{
+		{
+			name: "unknown root field",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+definitions: {}
+`,
+			wantErr:  `unknown field "definitions" in OpenAPI object`,
+			wantPath: "/definitions",
+			wantLine: 6,
+		},
+		{
+			name: "field from a later version",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  summary: Not in 3.0
+  version: "1.0"
+paths: {}
+`,
+			wantErr:  `field "summary" in Info object requires OpenAPI 3.1 or later`,
+			wantPath: "/info/summary",
+			wantLine: 4,
+		},
+		{
+			name: "missing info version",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+paths: {}
+`,
+			wantErr:  `missing required field "version" in Info object`,
+			wantPath: "/info",
+			wantLine: 3,
+		},
+		{
+			name: "path without leading slash",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  users: {}
+`,
+			wantErr:  `path "users" must begin with /`,
+			wantPath: "/paths/users",
+			wantLine: 6,
+		},
+		{
+			name: "responses required in 3.0",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      summary: List users
+`,
+			wantErr:  `missing required field "responses" in Operation object`,
+			wantPath: "/paths/~1users/get",
+			wantLine: 8,
+		},
+		{
+			name: "invalid status code",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      responses:
+        "2XX":
+          description: OK
+        "600":
+          description: Bad
+`,
+			wantErr:  `invalid response status code "600"`,
+			wantPath: "/paths/~1users/get/responses/600",
+			wantLine: 11,
+		},
+		{
+			name: "invalid parameter location",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users:
+    get:
+      parameters:
+        - name: q
+          in: body
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`,
+			wantErr:  `invalid parameter location "body"`,
+			wantPath: "/paths/~1users/get/parameters/0/in",
+			wantLine: 10,
+		},
+		{
+			name: "optional path parameter",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          schema:
+            type: string
+      responses:
+        "200":
+          description: OK
+`,
+			wantErr:  `path parameter "id" must be required`,
+			wantPath: "/paths/~1users~1{id}/get/parameters/0",
+			wantLine: 9,
+		},
+		{
+			name: "type array in 3.0",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Name:
+      type: [string, "null"]
+`,
+			wantErr:  "type must be a single string in OpenAPI 3.0",
+			wantPath: "/components/schemas/Name/type",
+			wantLine: 9,
+		},
+		{
+			name: "boolean exclusiveMinimum in 3.1",
+			spec: `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Age:
+      type: integer
+      minimum: 0
+      exclusiveMinimum: true
+`,
+			wantErr:  "exclusiveMinimum must be a number in OpenAPI 3.1+",
+			wantPath: "/components/schemas/Age/exclusiveMinimum",
+			wantLine: 11,
+		},
+		{
+			name: "apiKey scheme without name",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  securitySchemes:
+    key:
+      type: apiKey
+      in: header
+`,
+			wantErr:  `missing required field "name" in apiKey security scheme`,
+			wantPath: "/components/securitySchemes/key",
+			wantLine: 9,
+		},
+		{
+			name: "mutualTLS in 3.0",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  securitySchemes:
+    mtls:
+      type: mutualTLS
+`,
+			wantErr:  `invalid security scheme type "mutualTLS"`,
+			wantPath: "/components/securitySchemes/mtls/type",
+			wantLine: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validate(t, tt.spec)
+			if result.Valid {
+				t.Fatal("expected invalid result")
+			}
+			e, ok := findError(result.Errors, tt.wantErr)
+			if !ok {
+				t.Fatalf("missing error %q, got %v", tt.wantErr, result.Errors)
+			}
+			if e.Path != tt.wantPath || e.Line != tt.wantLine {
+				t.Errorf("error at line %d %s, want line %d %s", e.Line, e.Path, tt.wantLine, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestValidator_StructureVersionSpecific(t *testing.T) {
+	result := validate(t, `openapi: 3.1.0
+info:
+  title: Test
+  summary: Allowed in 3.1
+  version: "1.0"
+webhooks:
+  newUser:
+    post:
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Name:
+      type: [string, "null"]
+      exclusiveMaximum: 10
+    Legacy:
+      type: string
+      nullable: true
+  securitySchemes:
+    mtls:
+      type: mutualTLS
+`)
+	if !result.Valid {
+		t.Errorf("expected valid 3.1 document, got %v", result.Errors)
+	}
+	if _, ok := findError(result.Warnings, "nullable is not supported"); !ok {
+		t.Errorf("expected nullable warning, got %v", result.Warnings)
+	}
+}
+
+func TestValidator_MissingPathsIsWarning(t *testing.T) {
+	result := validate(t, `openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+`)
+	if !result.Valid {
+		t.Errorf("expected valid result, got %v", result.Errors)
+	}
+	if _, ok := findError(result.Warnings, "none of paths, components or webhooks"); !ok {
+		t.Errorf("expected warning, got %v", result.Warnings)
+	}
+}
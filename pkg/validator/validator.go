@@ -1,22 +1,31 @@
-// Package validator provides OpenAPI specification validation using libopenapi.
+// Package validator provides OpenAPI specification validation using libopenapi,
+// extended with structural and semantic checks that report source positions.
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/index"
+	"github.com/pb33f/libopenapi/utils"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fathurrohman26/yaswag/internal/yamlnode"
 )
 
 // ValidationError represents a validation error.
 type ValidationError struct {
-	Line    int
-	Column  int
-	Message string
-	Path    string
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	Path    string `json:"path,omitempty"`
 }
 
 func (e ValidationError) Error() string {
@@ -31,10 +40,12 @@ func (e ValidationError) Error() string {
 
 // ValidationResult holds the results of validation.
 type ValidationResult struct {
-	Valid    bool
-	Errors   []ValidationError
-	Warnings []ValidationError
-	Version  string
+	Valid    bool              `json:"valid"`
+	Errors   []ValidationError `json:"errors"`
+	Warnings []ValidationError `json:"warnings"`
+	Version  string            `json:"version"`
+	// Source is the file path or URL the document was read from, shown before positions.
+	Source string `json:"source,omitempty"`
 }
 
 // Validator validates OpenAPI specifications.
@@ -51,7 +62,7 @@ func (v *Validator) ValidateFile(path string) (*ValidationResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
-	return v.Validate(data)
+	return v.validateSource(data, path)
 }
 
 // ValidateURL validates an OpenAPI specification from a URL.
@@ -71,7 +82,15 @@ func (v *Validator) ValidateURL(url string) (*ValidationResult, error) {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return v.Validate(data)
+	return v.validateSource(data, url)
+}
+
+func (v *Validator) validateSource(data []byte, source string) (*ValidationResult, error) {
+	result, err := v.Validate(data)
+	if result != nil {
+		result.Source = source
+	}
+	return result, err
 }
 
 // Validate validates OpenAPI specification bytes.
@@ -82,9 +101,14 @@ func (v *Validator) Validate(data []byte) (*ValidationResult, error) {
 	if err != nil {
 		return v.parseError(result, err), nil
 	}
+	// Node tree for positions; libopenapi accepted the document, so this only fails on exotic input
+	root, _ := yamlnode.Parse(data)
 
 	result.Version = doc.GetVersion()
-	v.validateVersion(result, doc)
+	v.validateVersion(result, doc, root)
+	if v.isOpenAPI3(result.Version) && root != nil {
+		newChecker(root, result.Version, result).run()
+	}
 
 	if len(result.Errors) > 0 {
 		result.Valid = false
@@ -94,47 +118,126 @@ func (v *Validator) Validate(data []byte) (*ValidationResult, error) {
 
 func (v *Validator) parseError(result *ValidationResult, err error) *ValidationResult {
 	result.Valid = false
-	result.Errors = append(result.Errors, ValidationError{
-		Message: fmt.Sprintf("Failed to parse OpenAPI document: %v", err),
-	})
+	e := ValidationError{Message: fmt.Sprintf("Failed to parse OpenAPI document: %v", err)}
+	e.Line, e.Column = positionFromMessage(err.Error())
+	result.Errors = append(result.Errors, e)
 	return result
 }
 
-func (v *Validator) validateVersion(result *ValidationResult, doc libopenapi.Document) {
+func (v *Validator) validateVersion(result *ValidationResult, doc libopenapi.Document, root *yaml.Node) {
 	version := result.Version
 	if v.isOpenAPI3(version) {
-		v.validateOpenAPI3(result, doc, version)
+		v.validateOpenAPI3(result, doc, root)
 		return
 	}
 	if strings.HasPrefix(version, "2") {
-		v.addError(result, "Swagger 2.0 is not supported. YaSwag only supports OpenAPI 3.x (3.0, 3.1, 3.2). Please upgrade your specification.")
+		v.addError(result, versionError(root, "/swagger", "Swagger 2.0 is not supported. YaSwag only supports OpenAPI 3.x (3.0, 3.1, 3.2). Run 'yaswag upgrade' to convert it."))
 		return
 	}
-	v.addError(result, fmt.Sprintf("Unsupported OpenAPI version: %s. YaSwag only supports OpenAPI 3.x (3.0, 3.1, 3.2)", version))
+	v.addError(result, versionError(root, "/openapi", fmt.Sprintf("Unsupported OpenAPI version: %s. YaSwag only supports OpenAPI 3.x (3.0, 3.1, 3.2)", version)))
 }
 
 func (v *Validator) isOpenAPI3(version string) bool {
 	return strings.HasPrefix(version, "3.0") || strings.HasPrefix(version, "3.1") || strings.HasPrefix(version, "3.2")
 }
 
-func (v *Validator) validateOpenAPI3(result *ValidationResult, doc libopenapi.Document, version string) {
+func (v *Validator) validateOpenAPI3(result *ValidationResult, doc libopenapi.Document, root *yaml.Node) {
 	model, err := doc.BuildV3Model()
-	if err != nil {
-		v.addError(result, fmt.Sprintf("Failed to build OpenAPI 3.x model: %v", err))
+	for _, e := range utils.UnwrapErrors(err) {
+		if root != nil && namesUnresolvedRef(root, e.Error()) {
+			// checkRefs reports it once per $ref, with the pointer of the $ref
+			continue
+		}
+		v.addError(result, modelError(root, e))
 	}
 	if model == nil && err == nil {
-		v.addError(result, "Failed to build OpenAPI model")
+		v.addError(result, ValidationError{Message: "Failed to build OpenAPI model"})
 	}
-	if strings.HasPrefix(version, "3.2") {
-		result.Warnings = append(result.Warnings, ValidationError{
-			Message: "OpenAPI 3.2.x will be automatically patched to 3.1.x when served via Swagger UI (Swagger UI does not yet support 3.2)",
-		})
+	if strings.HasPrefix(result.Version, "3.2") {
+		result.Warnings = append(result.Warnings, versionError(root, "/openapi",
+			"OpenAPI 3.2.x will be automatically patched to 3.1.x when served via Swagger UI (Swagger UI does not yet support 3.2)"))
 	}
 }
 
-func (v *Validator) addError(result *ValidationResult, message string) {
+func (v *Validator) addError(result *ValidationResult, e ValidationError) {
 	result.Valid = false
-	result.Errors = append(result.Errors, ValidationError{Message: message})
+	result.Errors = append(result.Errors, e)
+}
+
+// versionError returns a finding positioned at the version field named by pointer.
+func versionError(root *yaml.Node, pointer, message string) ValidationError {
+	node := yamlnode.Resolve(root, pointer)
+	if node == nil {
+		return ValidationError{Message: message}
+	}
+	return newPositionedError(node, pointer, message)
+}
+
+// positionPatterns match the positions libopenapi and the YAML parser embed in error messages.
+var positionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`line (\d+), col(?:umn)? (\d+)`),
+	regexp.MustCompile(`\[(\d+):(\d+)\]`),
+	regexp.MustCompile(`line (\d+)`),
+}
+
+// refPattern matches a local reference quoted in an error message.
+var refPattern = regexp.MustCompile("[`'\"](#/[^`'\"]*)[`'\"]")
+
+// modelError converts an error from building the libopenapi model, taking its position from
+// the node it carries, from its message, or from the $ref it names.
+func modelError(root *yaml.Node, err error) ValidationError {
+	e := ValidationError{Message: fmt.Sprintf("Failed to build OpenAPI 3.x model: %v", err)}
+	var indexing *index.IndexingError
+	var resolving *index.ResolvingError
+	switch {
+	case errors.As(err, &indexing) && indexing.Node != nil:
+		e.Line, e.Column, e.Path = indexing.Node.Line, indexing.Node.Column, indexing.Path
+	case errors.As(err, &resolving) && resolving.Node != nil:
+		e.Line, e.Column = resolving.Node.Line, resolving.Node.Column
+	default:
+		e.Line, e.Column = positionFromMessage(err.Error())
+	}
+	if e.Line == 0 {
+		e.Line, e.Column, e.Path = findRef(root, err.Error())
+	}
+	return e
+}
+
+// namesUnresolvedRef reports whether an error message quotes a local reference that does not resolve.
+func namesUnresolvedRef(root *yaml.Node, message string) bool {
+	m := refPattern.FindStringSubmatch(message)
+	return m != nil && yamlnode.Resolve(root, m[1]) == nil
+}
+
+func positionFromMessage(message string) (line, column int) {
+	for _, pattern := range positionPatterns {
+		m := pattern.FindStringSubmatch(message)
+		if m == nil {
+			continue
+		}
+		line, _ = strconv.Atoi(m[1])
+		column = 1
+		if len(m) > 2 {
+			column, _ = strconv.Atoi(m[2])
+		}
+		return line, column
+	}
+	return 0, 0
+}
+
+// findRef locates the first $ref in the document whose value is the local reference quoted in message.
+func findRef(root *yaml.Node, message string) (line, column int, pointer string) {
+	m := refPattern.FindStringSubmatch(message)
+	if m == nil || root == nil {
+		return 0, 0, ""
+	}
+	yamlnode.Walk(root, "", func(node *yaml.Node, at string) bool {
+		if ref := yamlnode.Get(node, "$ref"); line == 0 && ref != nil && ref.Value == m[1] {
+			line, column, pointer = ref.Line, ref.Column, yamlnode.Join(at, "$ref")
+		}
+		return line == 0
+	})
+	return line, column, pointer
 }
 
 // ValidateInput validates input from a file path or URL.
@@ -155,14 +258,14 @@ func FormatResult(result *ValidationResult) string {
 	if len(result.Errors) > 0 {
 		sb.WriteString(fmt.Sprintf("\nErrors (%d):\n", len(result.Errors)))
 		for i, err := range result.Errors {
-			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, err.Error()))
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, formatFinding(result.Source, err)))
 		}
 	}
 
 	if len(result.Warnings) > 0 {
 		sb.WriteString(fmt.Sprintf("\nWarnings (%d):\n", len(result.Warnings)))
 		for i, warn := range result.Warnings {
-			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, warn.Error()))
+			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, formatFinding(result.Source, warn)))
 		}
 	}
 
@@ -172,3 +275,15 @@ func FormatResult(result *ValidationResult) string {
 
 	return sb.String()
 }
+
+// formatFinding prints a positioned finding as file:line:col, the form editors and CI annotations link to.
+func formatFinding(source string, e ValidationError) string {
+	if e.Line == 0 || source == "" {
+		return e.Error()
+	}
+	msg := fmt.Sprintf("%s:%d:%d: %s", source, e.Line, e.Column, e.Message)
+	if e.Path != "" {
+		msg += fmt.Sprintf(" (at %s)", e.Path)
+	}
+	return msg
+}
@@ -389,3 +389,125 @@ paths: {}`
 		t.Errorf("Expected unsupported version error, got: %s", result.Errors[0].Message)
 	}
 }
+
+func TestFormatResult_Positions(t *testing.T) {
+	result := &ValidationResult{
+		Version: "3.0.3",
+		Source:  "api/openapi.yaml",
+		Errors: []ValidationError{
+			{Line: 12, Column: 7, Message: "duplicate operationId", Path: "/paths/~1users/get/operationId"},
+			{Message: "no position"},
+		},
+		Warnings: []ValidationError{{Line: 3, Column: 1, Message: "example mismatch"}},
+	}
+	output := FormatResult(result)
+	assertContains(t, output, "1. api/openapi.yaml:12:7: duplicate operationId (at /paths/~1users/get/operationId)")
+	assertContains(t, output, "2. no position")
+	assertContains(t, output, "1. api/openapi.yaml:3:1: example mismatch")
+}
+
+func TestValidator_Positions(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		wantErr    string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "YAML syntax error",
+			spec:       "openapi: 3.0.3\ninfo: [\n",
+			wantErr:    "Failed to parse OpenAPI document",
+			wantLine:   2,
+			wantColumn: 1,
+		},
+		{
+			name:       "Swagger 2.0",
+			spec:       "swagger: \"2.0\"\ninfo:\n  title: Test\n  version: \"1.0\"\npaths: {}\n",
+			wantErr:    "Swagger 2.0 is not supported",
+			wantLine:   1,
+			wantColumn: 10,
+		},
+		{
+			name: "circular reference",
+			spec: `openapi: 3.0.3
+info:
+  title: Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [child]
+      properties:
+        child:
+          $ref: "#/components/schemas/Node"
+`,
+			wantErr:  "circular reference",
+			wantLine: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validate(t, tt.spec)
+			e, ok := findError(result.Errors, tt.wantErr)
+			if !ok {
+				t.Fatalf("missing error %q, got %v", tt.wantErr, result.Errors)
+			}
+			if e.Line != tt.wantLine || tt.wantColumn != 0 && e.Column != tt.wantColumn {
+				t.Errorf("position = %d:%d, want %d:%d", e.Line, e.Column, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateFile_Source(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	spec := "openapi: 3.0.3\ninfo:\n  title: Test\n  version: \"1.0\"\npaths:\n  users: {}\n"
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := New().ValidateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Source != path {
+		t.Errorf("Source = %q, want %q", result.Source, path)
+	}
+	assertContains(t, FormatResult(result), path+":6:3: path \"users\" must begin with /")
+}
+
+func TestToReport(t *testing.T) {
+	result := &ValidationResult{
+		Source:   StdinSource,
+		Errors:   []ValidationError{{Line: 3, Column: 5, Message: "bad", Path: "/info"}},
+		Warnings: []ValidationError{{Message: "odd"}},
+	}
+	r := ToReport(result)
+	if len(r.Issues) != 2 {
+		t.Fatalf("issues = %+v", r.Issues)
+	}
+	if e := r.Issues[0]; e.RuleID != RuleError || e.Level != "error" || e.File != "" || e.Line != 3 || e.Pointer != "/info" {
+		t.Errorf("error issue = %+v", e)
+	}
+	if w := r.Issues[1]; w.RuleID != RuleWarning || w.Level != "warning" {
+		t.Errorf("warning issue = %+v", w)
+	}
+
+	result.Source = "spec.yaml"
+	if got := ToReport(result).Issues[0].File; got != "spec.yaml" {
+		t.Errorf("File = %q, want spec.yaml", got)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	data, err := FormatJSON(&ValidationResult{Valid: true, Version: "3.1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"valid": true`, `"errors": []`, `"warnings": []`, `"version": "3.1.0"`} {
+		assertContains(t, string(data), want)
+	}
+}